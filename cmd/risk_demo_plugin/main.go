@@ -0,0 +1,67 @@
+// Demo of a plugin kept running by threagile (see package plugin), providing a custom risk rule
+package main
+
+import (
+	"github.com/threagile/threagile/pkg/plugin"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type customRiskRule string
+
+func main() {
+	plugin.Serve(plugin.Plugin{
+		Name:      "risk-demo-plugin",
+		Version:   "1.0.0",
+		RiskRules: []risks.RiskRule{new(customRiskRule)},
+	})
+}
+
+func (r customRiskRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:                         "demo-plugin",
+		Title:                      "Just a Plugin Demo",
+		Description:                "Demo Description",
+		Impact:                     "Demo Impact",
+		ASVS:                       "Demo ASVS",
+		CheatSheet:                 "https://example.com",
+		Action:                     "Demo Action",
+		Mitigation:                 "Demo Mitigation",
+		Check:                      "Demo Check",
+		Function:                   types.Development,
+		STRIDE:                     types.Tampering,
+		DetectionLogic:             "Demo Detection",
+		RiskAssessment:             "Demo Risk Assessment",
+		FalsePositives:             "Demo False Positive.",
+		ModelFailurePossibleReason: false,
+		CWE:                        0,
+	}
+}
+
+func (r customRiskRule) SupportedTags() []string {
+	return []string{"demo tag"}
+}
+
+func (r customRiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks := make([]types.Risk, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if technicalAsset.OutOfScope {
+			continue
+		}
+		risk := types.Risk{
+			CategoryId:                   r.Category().Id,
+			Severity:                     types.CalculateSeverity(types.VeryLikely, types.MediumImpact),
+			ExploitationLikelihood:       types.VeryLikely,
+			ExploitationImpact:           types.MediumImpact,
+			Title:                        "<b>Demo</b> risk at <b>" + technicalAsset.Title + "</b>",
+			MostRelevantTechnicalAssetId: technicalAsset.Id,
+			DataBreachProbability:        types.Possible,
+			DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+		}
+		risk.SyntheticId = risk.CategoryId + "@" + technicalAsset.Id
+		generatedRisks = append(generatedRisks, risk)
+	}
+	plugin.Infof("generated %d demo risks", len(generatedRisks))
+	return generatedRisks
+}
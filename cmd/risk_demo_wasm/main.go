@@ -0,0 +1,107 @@
+// Demo of a custom risk rule compiled to WebAssembly (GOOS=wasip1 GOARCH=wasm), loaded like other custom risk rule plugins
+// but run in-process and sandboxed by threagile. The command given as argument mirrors the methods of the risk rule.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type customRiskRule string
+
+func main() {
+	if len(os.Args) != 2 {
+		_, _ = fmt.Fprintf(os.Stderr, "usage: %v category|supported-tags|generate-risks\n", os.Args[0])
+		os.Exit(2)
+	}
+
+	rule := new(customRiskRule)
+	var output any
+	switch os.Args[1] {
+	case "category":
+		output = rule.Category()
+
+	case "supported-tags":
+		output = rule.SupportedTags()
+
+	case "generate-risks":
+		inData, readError := io.ReadAll(os.Stdin)
+		if readError != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to read model data from stdin: %v\n", readError)
+			os.Exit(2)
+		}
+
+		var input types.ParsedModel
+		inError := json.Unmarshal(inData, &input)
+		if inError != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to parse model: %v\n", inError)
+			os.Exit(2)
+		}
+
+		output = rule.GenerateRisks(&input)
+
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		os.Exit(2)
+	}
+
+	outData, marshalError := json.Marshal(output)
+	if marshalError != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to print %v: %v\n", os.Args[1], marshalError)
+		os.Exit(2)
+	}
+
+	_, _ = os.Stdout.Write(outData)
+}
+
+func (r customRiskRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:                         "demo-wasm",
+		Title:                      "Just a WebAssembly Demo",
+		Description:                "Demo Description",
+		Impact:                     "Demo Impact",
+		ASVS:                       "Demo ASVS",
+		CheatSheet:                 "https://example.com",
+		Action:                     "Demo Action",
+		Mitigation:                 "Demo Mitigation",
+		Check:                      "Demo Check",
+		Function:                   types.Development,
+		STRIDE:                     types.Tampering,
+		DetectionLogic:             "Demo Detection",
+		RiskAssessment:             "Demo Risk Assessment",
+		FalsePositives:             "Demo False Positive.",
+		ModelFailurePossibleReason: false,
+		CWE:                        0,
+	}
+}
+
+func (r customRiskRule) SupportedTags() []string {
+	return []string{"demo tag"}
+}
+
+func (r customRiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks := make([]types.Risk, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if technicalAsset.OutOfScope {
+			continue
+		}
+		risk := types.Risk{
+			CategoryId:                   r.Category().Id,
+			Severity:                     types.CalculateSeverity(types.VeryLikely, types.MediumImpact),
+			ExploitationLikelihood:       types.VeryLikely,
+			ExploitationImpact:           types.MediumImpact,
+			Title:                        "<b>Demo</b> risk at <b>" + technicalAsset.Title + "</b>",
+			MostRelevantTechnicalAssetId: technicalAsset.Id,
+			DataBreachProbability:        types.Possible,
+			DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+		}
+		risk.SyntheticId = risk.CategoryId + "@" + technicalAsset.Id
+		generatedRisks = append(generatedRisks, risk)
+	}
+	return generatedRisks
+}
@@ -22,6 +22,8 @@ func (what *Threagile) initAnalyze() *Threagile {
 				cmd.Printf("Failed to read and analyze model: %v", err)
 				return err
 			}
+			defer model.ShutdownPlugins(r.Plugins, progressReporter)
+			defer model.CloseCustomRiskRules(r.CustomRiskRules, progressReporter)
 
 			err = report.Generate(cfg, r, commands, progressReporter)
 			if err != nil {
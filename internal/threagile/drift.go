@@ -0,0 +1,72 @@
+package threagile
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/drift"
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+func (what *Threagile) initDrift() *Threagile {
+	detectDrift := &cobra.Command{
+		Use:   common.DetectDriftCommand + " <flow-file>...",
+		Short: "Detect drift between the model and observed network flows",
+		Long: "\nCompare network flows (VPC flow logs, Hubble/Cilium json or csv of src,dst,port) with the communication links of the model and report " +
+			"observed but not modelled flows, modelled but never observed links and protocol mismatches. Addresses and names of the flows are mapped to " +
+			"technical assets by the mapping file. Optionally the missing links are written as model include named " + common.DriftIncludeFilename + " in the output directory",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			mapping, err := drift.LoadMapping(what.flags.driftMappingFlag)
+			if err != nil {
+				cmd.Printf("Unable to load mapping: %v\n", err)
+				return err
+			}
+
+			flows, err := drift.LoadFlows(args)
+			if err != nil {
+				cmd.Printf("Unable to load flows: %v\n", err)
+				return err
+			}
+
+			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model: %v\n", err)
+				return err
+			}
+			defer model.ShutdownPlugins(r.Plugins, progressReporter)
+			defer model.CloseCustomRiskRules(r.CustomRiskRules, progressReporter)
+			for _, id := range mapping.TechnicalAssetIds() {
+				if _, ok := r.ParsedModel.TechnicalAssets[id]; !ok {
+					cmd.Printf("Mapping refers to unknown technical asset %q\n", id)
+				}
+			}
+
+			result := drift.Detect(r.ParsedModel, flows, mapping)
+			result.Write(cmd.OutOrStdout(), r.ParsedModel)
+
+			if what.flags.driftIncludeFlag && len(result.Unmodelled) > 0 {
+				includeFilename := filepath.Join(cfg.OutputFolder, common.DriftIncludeFilename)
+				err = importer.WriteModel(result.Include(r.ParsedModel), includeFilename)
+				if err != nil {
+					cmd.Printf("Unable to write model include: %v\n", err)
+					return err
+				}
+				cmd.Printf("Model include with the observed but not modelled links written to %v, review it and add it to the includes of %v\n", includeFilename, cfg.InputFile)
+			}
+			return nil
+		},
+	}
+	detectDrift.Flags().StringVar(&what.flags.driftMappingFlag, importMappingFlagName, "", "mapping file (yaml) of IPs, CIDRs, hostnames or kubernetes names to technical asset IDs")
+	detectDrift.Flags().BoolVar(&what.flags.driftIncludeFlag, driftIncludeFlagName, false, "write the observed but not modelled links as model include")
+	_ = detectDrift.MarkFlagRequired(importMappingFlagName)
+	what.rootCmd.AddCommand(detectDrift)
+
+	return what
+}
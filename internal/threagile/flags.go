@@ -21,50 +21,116 @@ const (
 	serverDirFlagName  = "server-dir"
 	serverPortFlagName = "server-port"
 
-	inputFileFlagName = "model"
-	raaPluginFlagName = "raa-run"
+	inputFileFlagName    = "model"
+	raaPluginFlagName    = "raa-run"
+	raaAlgorithmFlagName = "raa-algorithm"
 
 	customRiskRulesPluginFlagName      = "custom-risk-rules-plugin"
+	customRiskRulesDirFlagName         = "custom-risk-rules-dir"
+	pluginsFlagName                    = "plugins"
 	diagramDpiFlagName                 = "diagram-dpi"
+	riskRulesParallelismFlagName       = "risk-rules-parallelism"
+	riskRuleTimeoutFlagName            = "risk-rule-timeout"
+	wasmMemoryLimitFlagName            = "wasm-memory-limit"
+	pluginTimeoutFlagName              = "plugin-timeout"
 	skipRiskRulesFlagName              = "skip-risk-rules"
+	enableRiskRulesFlagName            = "enable-risk-rules"
 	ignoreOrphanedRiskTrackingFlagName = "ignore-orphaned-risk-tracking"
+	sqliteAppendFlagName               = "sqlite-append"
 	templateFileNameFlagName           = "background"
+	osvDatabaseFlagName                = "osv-database"
+	sarifFlagName                      = "sarif"
+	sarifMappingFlagName               = "sarif-mapping"
+	networkPolicyMappingFlagName       = "network-policy-mapping"
+	ticketTemplateFlagName             = "ticket-template"
+	importMappingFlagName              = "mapping"
+	importMergeFlagName                = "merge"
+	importTargetFlagName               = "target"
+	importClientFlagName               = "client"
+	driftIncludeFlagName               = "generate-include"
 
 	generateDataFlowDiagramFlagName     = "generate-data-flow-diagram"
 	generateDataAssetDiagramFlagName    = "generate-data-asset-diagram"
+	generateMermaidDiagramFlagName      = "generate-mermaid-diagram"
+	generatePlantUMLDiagramFlagName     = "generate-plantuml-diagram"
+	generateOTMFlagName                 = "generate-otm"
+	generateNetworkPoliciesFlagName     = "generate-network-policies"
+	generateGraphMLFlagName             = "generate-graphml"
+	generateCypherFlagName              = "generate-cypher"
+	generateSQLiteFlagName              = "generate-sqlite"
+	generateJiraCSVFlagName             = "generate-jira-csv"
+	generateGitHubIssuesFlagName        = "generate-github-issues"
 	generateRisksJSONFlagName           = "generate-risks-json"
 	generateTechnicalAssetsJSONFlagName = "generate-technical-assets-json"
 	generateStatsJSONFlagName           = "generate-stats-json"
+	generateComponentsJSONFlagName      = "generate-components-json"
+	generateAttackPathsJSONFlagName     = "generate-attack-paths-json"
+	generateDataLineageJSONFlagName     = "generate-data-lineage-json"
 	generateRisksExcelFlagName          = "generate-risks-excel"
 	generateTagsExcelFlagName           = "generate-tags-excel"
+	generateComponentsExcelFlagName     = "generate-components-excel"
 	generateReportPDFFlagName           = "generate-report-pdf"
 )
 
 type Flags struct {
-	configFlag      string
-	verboseFlag     bool
-	interactiveFlag bool
-	appDirFlag      string
-	binDirFlag      string
-	outputDirFlag   string
-	tempDirFlag     string
-	inputFileFlag   string
-	raaPluginFlag   string
-	serverPortFlag  int
-	serverDirFlag   string
+	configFlag       string
+	verboseFlag      bool
+	interactiveFlag  bool
+	appDirFlag       string
+	binDirFlag       string
+	outputDirFlag    string
+	tempDirFlag      string
+	inputFileFlag    string
+	raaPluginFlag    string
+	raaAlgorithmFlag string
+	serverPortFlag   int
+	serverDirFlag    string
 
 	skipRiskRulesFlag              string
+	enableRiskRulesFlag            string
+	osvDatabaseFlag                string
+	sarifFlag                      string
+	sarifMappingFlag               string
+	networkPolicyMappingFlag       string
+	ticketTemplateFlag             string
+	ticketMappingFlag              string
 	customRiskRulesPluginFlag      string
+	customRiskRulesDirFlag         string
+	pluginsFlag                    string
 	ignoreOrphanedRiskTrackingFlag bool
+	sqliteAppendFlag               bool
 	templateFileNameFlag           string
 	diagramDpiFlag                 int
+	riskRulesParallelismFlag       int
+	riskRuleTimeoutFlag            int
+	wasmMemoryLimitFlag            int
+	pluginTimeoutFlag              int
+	importMappingFlag              string
+	importMergeFlag                string
+	importTargetFlag               string
+	importClientFlag               string
+	driftMappingFlag               string
+	driftIncludeFlag               bool
 
 	generateDataFlowDiagramFlag     bool
 	generateDataAssetDiagramFlag    bool
+	generateMermaidDiagramFlag      bool
+	generatePlantUMLDiagramFlag     bool
+	generateOTMFlag                 bool
+	generateNetworkPoliciesFlag     bool
+	generateGraphMLFlag             bool
+	generateCypherFlag              bool
+	generateSQLiteFlag              bool
+	generateJiraCSVFlag             bool
+	generateGitHubIssuesFlag        bool
 	generateRisksJSONFlag           bool
 	generateTechnicalAssetsJSONFlag bool
 	generateStatsJSONFlag           bool
+	generateComponentsJSONFlag      bool
+	generateAttackPathsJSONFlag     bool
+	generateDataLineageJSONFlag     bool
 	generateRisksExcelFlag          bool
 	generateTagsExcelFlag           bool
+	generateComponentsExcelFlag     bool
 	generateReportPDFFlag           bool
 }
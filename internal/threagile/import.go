@@ -0,0 +1,209 @@
+package threagile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/compose"
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/kubernetes"
+	"github.com/threagile/threagile/pkg/openapi"
+	"github.com/threagile/threagile/pkg/otm"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/terraform"
+	"github.com/threagile/threagile/pkg/threatdragon"
+	"github.com/threagile/threagile/pkg/tmt"
+)
+
+type importFunc func(filenames []string, mapping importer.Mapping, riskCategoryIds []string) (*input.Model, *importer.Report, error)
+
+func (what *Threagile) initImport() *Threagile {
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportOTMCommand, "<otm-file>", "an Open Threat Model (OTM) json file", cobra.ExactArgs(1),
+		func(filenames []string, mapping importer.Mapping, riskCategoryIds []string) (*input.Model, *importer.Report, error) {
+			otmModel, err := otm.LoadFile(filenames[0])
+			if err != nil {
+				return nil, nil, err
+			}
+			modelInput, report := otm.Import(otmModel, mapping, riskCategoryIds)
+			return modelInput, report, nil
+		}))
+
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportThreatDragonCommand, "<threat-dragon-file>", "an OWASP Threat Dragon json file", cobra.ExactArgs(1),
+		func(filenames []string, mapping importer.Mapping, riskCategoryIds []string) (*input.Model, *importer.Report, error) {
+			threatDragonModel, err := threatdragon.LoadFile(filenames[0])
+			if err != nil {
+				return nil, nil, err
+			}
+			modelInput, report := importer.ImportDiagram(threatdragon.Convert(threatDragonModel), mapping, riskCategoryIds)
+			return modelInput, report, nil
+		}))
+
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportTMTCommand, "<tm7-file>", "a Microsoft Threat Modeling Tool (.tm7) file", cobra.ExactArgs(1),
+		func(filenames []string, mapping importer.Mapping, riskCategoryIds []string) (*input.Model, *importer.Report, error) {
+			tmtModel, err := tmt.LoadFile(filenames[0])
+			if err != nil {
+				return nil, nil, err
+			}
+			modelInput, report := importer.ImportDiagram(tmt.Convert(tmtModel), mapping, riskCategoryIds)
+			return modelInput, report, nil
+		}))
+
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportDockerComposeCommand, "<compose-file>...", "docker compose files", cobra.MinimumNArgs(1),
+		func(filenames []string, mapping importer.Mapping, _ []string) (*input.Model, *importer.Report, error) {
+			project, err := compose.LoadFiles(filenames)
+			if err != nil {
+				return nil, nil, err
+			}
+			modelInput, report := compose.Import(project, mapping)
+			return modelInput, report, nil
+		}))
+
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportKubernetesCommand, "<manifest-file-or-directory>...", "kubernetes manifests", cobra.MinimumNArgs(1),
+		func(filenames []string, mapping importer.Mapping, _ []string) (*input.Model, *importer.Report, error) {
+			objects, err := kubernetes.LoadFiles(filenames)
+			if err != nil {
+				return nil, nil, err
+			}
+			title := strings.TrimSuffix(filepath.Base(filepath.Clean(filenames[0])), filepath.Ext(filenames[0]))
+			modelInput, report := kubernetes.Import(objects, title, mapping)
+			return modelInput, report, nil
+		}))
+
+	what.rootCmd.AddCommand(what.newImportCommand(common.ImportTerraformCommand, "<terraform-show-json-file>", "a terraform state or plan (output of 'terraform show -json')", cobra.ExactArgs(1),
+		func(filenames []string, mapping importer.Mapping, _ []string) (*input.Model, *importer.Report, error) {
+			show, err := terraform.LoadFile(filenames[0])
+			if err != nil {
+				return nil, nil, err
+			}
+			title := strings.TrimSuffix(filepath.Base(filenames[0]), filepath.Ext(filenames[0]))
+			modelInput, report := terraform.Import(show, title, mapping)
+			return modelInput, report, nil
+		}))
+
+	importOpenAPI := &cobra.Command{
+		Use:   common.ImportOpenAPICommand + " <openapi-file>",
+		Short: "Import an OpenAPI 3 spec of a technical asset",
+		Long: "\nConvert the schemas of an OpenAPI 3 spec into data assets processed by the target technical asset and its operations into a communication link from the client technical asset. " +
+			"The result is a model include named " + common.OpenAPIIncludeFilename + " in the output directory",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			includeFilename := filepath.Join(cfg.OutputFolder, common.OpenAPIIncludeFilename)
+
+			mapping, err := importer.LoadMapping(what.flags.importMappingFlag)
+			if err != nil {
+				cmd.Printf("Unable to load mapping: %v\n", err)
+				return err
+			}
+
+			existing, err := importer.LoadModel(cfg.InputFile)
+			if err == nil {
+				err = importer.MergeIncludes(existing, cfg.InputFile, includeFilename)
+			}
+			if err != nil {
+				cmd.Printf("Unable to load model: %v\n", err)
+				return err
+			}
+
+			targetTitle, ok := importer.FindTechnicalAsset(existing, what.flags.importTargetFlag)
+			if !ok {
+				err = fmt.Errorf("target technical asset %q not found in %v", what.flags.importTargetFlag, cfg.InputFile)
+				cmd.Printf("%v\n", err)
+				return err
+			}
+			clientTitle := ""
+			if len(what.flags.importClientFlag) > 0 {
+				if clientTitle, ok = importer.FindTechnicalAsset(existing, what.flags.importClientFlag); !ok {
+					err = fmt.Errorf("client technical asset %q not found in %v", what.flags.importClientFlag, cfg.InputFile)
+					cmd.Printf("%v\n", err)
+					return err
+				}
+			}
+
+			document, err := openapi.LoadFile(args[0])
+			if err != nil {
+				cmd.Printf("Unable to import OpenAPI spec: %v\n", err)
+				return err
+			}
+
+			include, report := openapi.Import(document, existing, targetTitle, clientTitle, mapping)
+			err = importer.WriteModel(include, includeFilename)
+			if err != nil {
+				cmd.Printf("Unable to write model include: %v\n", err)
+				return err
+			}
+
+			cmd.Printf("Model include written to %v, add it to the includes of %v\n", includeFilename, cfg.InputFile)
+			report.Write(cmd.OutOrStdout())
+			return nil
+		},
+	}
+	importOpenAPI.Flags().StringVar(&what.flags.importMappingFlag, importMappingFlagName, "", "mapping file (yaml or json) translating unknown values into threagile values")
+	importOpenAPI.Flags().StringVar(&what.flags.importTargetFlag, importTargetFlagName, "", "id or title of the technical asset providing the API")
+	importOpenAPI.Flags().StringVar(&what.flags.importClientFlag, importClientFlagName, "", "id or title of the technical asset calling the API")
+	_ = importOpenAPI.MarkFlagRequired(importTargetFlagName)
+	what.rootCmd.AddCommand(importOpenAPI)
+
+	return what
+}
+
+func (what *Threagile) newImportCommand(name string, argument string, description string, args cobra.PositionalArgs, load importFunc) *cobra.Command {
+	command := &cobra.Command{
+		Use:   name + " " + argument,
+		Short: "Import " + description,
+		Long:  "\nConvert " + description + " into a model named " + common.ImportedModelFilename + " in the output directory and report what could not be mapped",
+		Args:  args,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+
+			mapping, err := importer.LoadMapping(what.flags.importMappingFlag)
+			if err != nil {
+				cmd.Printf("Unable to load mapping: %v\n", err)
+				return err
+			}
+
+			riskCategoryIds := make([]string, 0)
+			for _, rule := range risks.GetBuiltInRiskRules() {
+				riskCategoryIds = append(riskCategoryIds, rule.Category().Id)
+			}
+
+			modelInput, report, err := load(args, mapping, riskCategoryIds)
+			if err != nil {
+				cmd.Printf("Unable to import model: %v\n", err)
+				return err
+			}
+
+			if len(what.flags.importMergeFlag) > 0 {
+				existing, err := importer.LoadModel(what.flags.importMergeFlag)
+				if err != nil {
+					cmd.Printf("Unable to load model to merge into: %v\n", err)
+					return err
+				}
+				importer.MergeModel(existing, modelInput)
+				modelInput = existing
+			}
+
+			return what.writeImportedModel(cmd, modelInput, report, filepath.Join(cfg.OutputFolder, common.ImportedModelFilename))
+		},
+	}
+	command.Flags().StringVar(&what.flags.importMappingFlag, importMappingFlagName, "", "mapping file (yaml or json) translating unknown values into threagile values")
+	command.Flags().StringVar(&what.flags.importMergeFlag, importMergeFlagName, "", "existing model to merge the import into, elements are matched by id and manual changes are kept")
+	return command
+}
+
+func (what *Threagile) writeImportedModel(cmd *cobra.Command, modelInput *input.Model, report *importer.Report, filename string) error {
+	err := importer.WriteModel(modelInput, filename)
+	if err != nil {
+		cmd.Printf("Unable to write imported model: %v\n", err)
+		return err
+	}
+
+	cmd.Println(fmt.Sprintf("Imported model written to %v", filename))
+	report.Write(cmd.OutOrStdout())
+	return nil
+}
@@ -6,6 +6,8 @@ package threagile
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -23,14 +25,18 @@ func (what *Threagile) initMacros() *Threagile {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
 			cmd.Println("The following model macros are available (can be extended via custom model macros):")
 			cmd.Println()
-			/* TODO finish plugin stuff
+			cmd.Println("----------------------")
 			cmd.Println("Custom model macros:")
-			for _, macros := range macros.ListCustomMacros() {
+			cmd.Println("----------------------")
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			pluginTimeout := time.Duration(what.readConfig(cmd, what.buildTimestamp).PluginTimeout) * time.Second
+			plugins := model.StartPlugins(strings.Split(what.flags.pluginsFlag, ","), pluginTimeout, make(map[string]*model.CustomRisk), progressReporter)
+			defer model.ShutdownPlugins(plugins, progressReporter)
+			for _, macros := range model.PluginMacros(plugins) {
 				details := macros.GetMacroDetails()
 				cmd.Println(details.ID, "-->", details.Title)
 			}
 			cmd.Println()
-			*/
 			cmd.Println("----------------------")
 			cmd.Println("Built-in model macros:")
 			cmd.Println("----------------------")
@@ -49,14 +55,18 @@ func (what *Threagile) initMacros() *Threagile {
 			cmd.Println(docs.Logo + "\n\n" + fmt.Sprintf(docs.VersionText, what.buildTimestamp))
 			cmd.Println("Explanation for the model macros:")
 			cmd.Println()
-			/* TODO finish plugin stuff
+			cmd.Println("----------------------")
 			cmd.Println("Custom model macros:")
-			for _, macros := range macros.ListCustomMacros() {
+			cmd.Println("----------------------")
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			pluginTimeout := time.Duration(what.readConfig(cmd, what.buildTimestamp).PluginTimeout) * time.Second
+			plugins := model.StartPlugins(strings.Split(what.flags.pluginsFlag, ","), pluginTimeout, make(map[string]*model.CustomRisk), progressReporter)
+			defer model.ShutdownPlugins(plugins, progressReporter)
+			for _, macros := range model.PluginMacros(plugins) {
 				details := macros.GetMacroDetails()
-				cmd.Println(details.ID, "-->", details.Title)
+				cmd.Printf("%v: %v\n", details.ID, details.Title)
 			}
 			cmd.Println()
-			*/
 			cmd.Println("----------------------")
 			cmd.Println("Built-in model macros:")
 			cmd.Println("----------------------")
@@ -81,9 +91,11 @@ func (what *Threagile) initMacros() *Threagile {
 			if err != nil {
 				return fmt.Errorf("unable to read and analyze model: %v", err)
 			}
+			defer model.ShutdownPlugins(r.Plugins, progressReporter)
+			defer model.CloseCustomRiskRules(r.CustomRiskRules, progressReporter)
 
 			macrosId := args[0]
-			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId)
+			err = macros.ExecuteModelMacro(r.ModelInput, cfg.InputFile, r.ParsedModel, macrosId, model.PluginMacros(r.Plugins))
 			if err != nil {
 				return fmt.Errorf("unable to execute model macro: %v", err)
 			}
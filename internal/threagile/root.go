@@ -18,6 +18,7 @@ import (
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/security/risks"
 )
 
 const (
@@ -61,26 +62,53 @@ func (what *Threagile) initRoot() *Threagile {
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.tempDirFlag, tempDirFlagName, defaultConfig.TempFolder, "temporary folder location")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.inputFileFlag, inputFileFlagName, defaultConfig.InputFile, "input model yaml file")
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA calculation run file name")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaPluginFlag, raaPluginFlagName, defaultConfig.RAAPlugin, "RAA calculation run file name (the default is calculated built-in, weighted by the attractiveness of the config, which a run file gets as -algorithm and -attractiveness flags)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.raaAlgorithmFlag, raaAlgorithmFlagName, defaultConfig.RAAAlgorithm, "algorithm of the built-in RAA calculation: attractiveness (of the technical asset and its neighbours) or pagerank (centrality in the communication graph)")
 
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.interactiveFlag, interactiveFlagName, interactiveFlagShorthand, defaultConfig.Interactive, "interactive mode")
 	what.rootCmd.PersistentFlags().BoolVarP(&what.flags.verboseFlag, verboseFlagName, verboseFlagShorthand, defaultConfig.Verbose, "verbose output")
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.configFlag, configFlagName, "", "config file")
 
-	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesPluginFlag, customRiskRulesPluginFlagName, strings.Join(defaultConfig.RiskRulesPlugins, ","), "comma-separated list of plugins file names with custom risk rules to load (*.wasm files are run sandboxed in-process)")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.customRiskRulesDirFlag, customRiskRulesDirFlagName, defaultConfig.RiskRulesFolder, "directory of yaml files with declarative custom risk rules to load")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.pluginsFlag, pluginsFlagName, strings.Join(defaultConfig.Plugins, ","), "comma-separated list of plugin file names providing custom risk rules, RAA calculation or model macros, kept running while used")
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.diagramDpiFlag, diagramDpiFlagName, defaultConfig.DiagramDPI, "DPI used to render: maximum is "+fmt.Sprintf("%d", common.MaxGraphvizDPI)+"")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.riskRulesParallelismFlag, riskRulesParallelismFlagName, defaultConfig.RiskRulesParallelism, "number of risk rules run concurrently (0 for the number of CPUs)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.riskRuleTimeoutFlag, riskRuleTimeoutFlagName, defaultConfig.RiskRuleTimeout, "timeout in seconds of each risk rule (0 for none)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.wasmMemoryLimitFlag, wasmMemoryLimitFlagName, defaultConfig.WasmMemoryLimit, "memory limit in MiB of each WebAssembly risk rule (0 for the maximum of 4096)")
+	what.rootCmd.PersistentFlags().IntVar(&what.flags.pluginTimeoutFlag, pluginTimeoutFlagName, defaultConfig.PluginTimeout, "timeout in seconds of each call of a plugin (0 for none)")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesFlag, skipRiskRulesFlagName, defaultConfig.SkipRiskRules, "comma-separated list of risk rules (by their ID) to skip")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.enableRiskRulesFlag, enableRiskRulesFlagName, defaultConfig.EnableRiskRules, "comma-separated list of opt-in risk rules (by their ID) to run, e.g. "+strings.Join(risks.GetOptInRiskRules(), ","))
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.ignoreOrphanedRiskTrackingFlag, ignoreOrphanedRiskTrackingFlagName, defaultConfig.IgnoreOrphanedRiskTracking, "ignore orphaned risk tracking (just log them) not matching a concrete risk")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.sqliteAppendFlag, sqliteAppendFlagName, defaultConfig.SQLiteAppend, "append the analysis as a new run to an existing sqlite database instead of replacing it")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.templateFileNameFlag, templateFileNameFlagName, defaultConfig.TemplateFilename, "background pdf file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.osvDatabaseFlag, osvDatabaseFlagName, defaultConfig.OSVDatabase, "OSV database export (json or zip file or a directory of those) to match the SBOM components of technical assets against")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.sarifFlag, sarifFlagName, defaultConfig.SARIF, "SARIF file (or directory of *.sarif files) with findings of scanners to attach to technical assets")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.sarifMappingFlag, sarifMappingFlagName, defaultConfig.SARIFMapping, "yaml file mapping repository paths or URLs of SARIF findings to technical asset IDs")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.networkPolicyMappingFlag, networkPolicyMappingFlagName, defaultConfig.NetworkPolicyMapping, "yaml file mapping technical asset IDs to kubernetes namespaces, pod labels and ports for the network policies")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.ticketTemplateFlag, ticketTemplateFlagName, defaultConfig.TicketTemplate, "yaml file with title and body templates (go text/template) of the tickets for unchecked risks")
 
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataFlowDiagramFlag, generateDataFlowDiagramFlagName, true, "generate data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataAssetDiagramFlag, generateDataAssetDiagramFlagName, true, "generate data asset diagram")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateMermaidDiagramFlag, generateMermaidDiagramFlagName, false, "generate data flow diagram as mermaid flowchart")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generatePlantUMLDiagramFlag, generatePlantUMLDiagramFlagName, false, "generate data flow diagram as plantuml deployment diagram")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateOTMFlag, generateOTMFlagName, false, "generate open threat model (OTM) json including the risks")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateNetworkPoliciesFlag, generateNetworkPoliciesFlagName, false, "generate kubernetes network policies allowing only the communication links of the model")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateGraphMLFlag, generateGraphMLFlagName, false, "generate graph of the model including the risks as graphml")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateCypherFlag, generateCypherFlagName, false, "generate graph of the model including the risks as cypher script for neo4j")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateSQLiteFlag, generateSQLiteFlagName, false, "generate sqlite database of the model and the risks")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateJiraCSVFlag, generateJiraCSVFlagName, false, "generate jira import csv with a ticket for each unchecked risk")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateGitHubIssuesFlag, generateGitHubIssuesFlagName, false, "generate github issues json with an issue for each unchecked risk")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksJSONFlag, generateRisksJSONFlagName, true, "generate risks json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTechnicalAssetsJSONFlag, generateTechnicalAssetsJSONFlagName, true, "generate technical assets json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateStatsJSONFlag, generateStatsJSONFlagName, true, "generate stats json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateComponentsJSONFlag, generateComponentsJSONFlagName, false, "generate component inventory json from the SBOMs of the technical assets")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateAttackPathsJSONFlag, generateAttackPathsJSONFlagName, false, "generate json of the most likely attack paths from the entry points to sensitive data assets")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataLineageJSONFlag, generateDataLineageJSONFlagName, false, "generate json of the origins, reached technical assets and inconsistencies of the data flows of each data asset")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateRisksExcelFlag, generateRisksExcelFlagName, true, "generate risks excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateTagsExcelFlag, generateTagsExcelFlagName, true, "generate tags excel")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateComponentsExcelFlag, generateComponentsExcelFlagName, false, "generate component inventory excel from the SBOMs of the technical assets")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateReportPDFFlag, generateReportPDFFlagName, true, "generate report pdf, including diagrams")
 
 	return what
@@ -205,11 +233,24 @@ func (what *Threagile) readCommands() *report.GenerateCommands {
 	commands := new(report.GenerateCommands).Defaults()
 	commands.DataFlowDiagram = what.flags.generateDataFlowDiagramFlag
 	commands.DataAssetDiagram = what.flags.generateDataAssetDiagramFlag
+	commands.DataFlowDiagramMermaid = what.flags.generateMermaidDiagramFlag
+	commands.DataFlowDiagramPlantUML = what.flags.generatePlantUMLDiagramFlag
+	commands.OTM = what.flags.generateOTMFlag
+	commands.NetworkPolicies = what.flags.generateNetworkPoliciesFlag
+	commands.GraphML = what.flags.generateGraphMLFlag
+	commands.Cypher = what.flags.generateCypherFlag
+	commands.SQLite = what.flags.generateSQLiteFlag
+	commands.JiraCSV = what.flags.generateJiraCSVFlag
+	commands.GitHubIssues = what.flags.generateGitHubIssuesFlag
 	commands.RisksJSON = what.flags.generateRisksJSONFlag
 	commands.StatsJSON = what.flags.generateStatsJSONFlag
 	commands.TechnicalAssetsJSON = what.flags.generateTechnicalAssetsJSONFlag
+	commands.ComponentsJSON = what.flags.generateComponentsJSONFlag
+	commands.AttackPathsJSON = what.flags.generateAttackPathsJSONFlag
+	commands.DataLineageJSON = what.flags.generateDataLineageJSONFlag
 	commands.RisksExcel = what.flags.generateRisksExcelFlag
 	commands.TagsExcel = what.flags.generateTagsExcelFlag
+	commands.ComponentsExcel = what.flags.generateComponentsExcelFlag
 	commands.ReportPDF = what.flags.generateReportPDFFlag
 	return commands
 }
@@ -252,22 +293,64 @@ func (what *Threagile) readConfig(cmd *cobra.Command, buildTimestamp string) *co
 	if isFlagOverridden(flags, raaPluginFlagName) {
 		cfg.RAAPlugin = what.flags.raaPluginFlag
 	}
+	if isFlagOverridden(flags, raaAlgorithmFlagName) {
+		cfg.RAAAlgorithm = what.flags.raaAlgorithmFlag
+	}
 
 	if isFlagOverridden(flags, customRiskRulesPluginFlagName) {
 		cfg.RiskRulesPlugins = strings.Split(what.flags.customRiskRulesPluginFlag, ",")
 	}
+	if isFlagOverridden(flags, customRiskRulesDirFlagName) {
+		cfg.RiskRulesFolder = cfg.CleanPath(what.flags.customRiskRulesDirFlag)
+	}
+	if isFlagOverridden(flags, pluginsFlagName) {
+		cfg.Plugins = strings.Split(what.flags.pluginsFlag, ",")
+	}
 	if isFlagOverridden(flags, skipRiskRulesFlagName) {
 		cfg.SkipRiskRules = what.flags.skipRiskRulesFlag
 	}
+	if isFlagOverridden(flags, enableRiskRulesFlagName) {
+		cfg.EnableRiskRules = what.flags.enableRiskRulesFlag
+	}
 	if isFlagOverridden(flags, ignoreOrphanedRiskTrackingFlagName) {
 		cfg.IgnoreOrphanedRiskTracking = what.flags.ignoreOrphanedRiskTrackingFlag
 	}
+	if isFlagOverridden(flags, sqliteAppendFlagName) {
+		cfg.SQLiteAppend = what.flags.sqliteAppendFlag
+	}
 	if isFlagOverridden(flags, diagramDpiFlagName) {
 		cfg.DiagramDPI = what.flags.diagramDpiFlag
 	}
+	if isFlagOverridden(flags, riskRulesParallelismFlagName) {
+		cfg.RiskRulesParallelism = what.flags.riskRulesParallelismFlag
+	}
+	if isFlagOverridden(flags, riskRuleTimeoutFlagName) {
+		cfg.RiskRuleTimeout = what.flags.riskRuleTimeoutFlag
+	}
+	if isFlagOverridden(flags, wasmMemoryLimitFlagName) {
+		cfg.WasmMemoryLimit = what.flags.wasmMemoryLimitFlag
+	}
+	if isFlagOverridden(flags, pluginTimeoutFlagName) {
+		cfg.PluginTimeout = what.flags.pluginTimeoutFlag
+	}
 	if isFlagOverridden(flags, templateFileNameFlagName) {
 		cfg.TemplateFilename = what.flags.templateFileNameFlag
 	}
+	if isFlagOverridden(flags, osvDatabaseFlagName) {
+		cfg.OSVDatabase = what.flags.osvDatabaseFlag
+	}
+	if isFlagOverridden(flags, sarifFlagName) {
+		cfg.SARIF = what.flags.sarifFlag
+	}
+	if isFlagOverridden(flags, sarifMappingFlagName) {
+		cfg.SARIFMapping = what.flags.sarifMappingFlag
+	}
+	if isFlagOverridden(flags, networkPolicyMappingFlagName) {
+		cfg.NetworkPolicyMapping = what.flags.networkPolicyMappingFlag
+	}
+	if isFlagOverridden(flags, ticketTemplateFlagName) {
+		cfg.TicketTemplate = what.flags.ticketTemplateFlag
+	}
 	return cfg
 }
 
@@ -7,6 +7,7 @@ package threagile
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
@@ -28,7 +29,16 @@ func (what *Threagile) initRules() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), model.NewWasmLimits(*cfg), progressReporter)
+			defer model.CloseCustomRiskRules(customRiskRules, progressReporter)
+			rulesError := model.LoadRiskRulesFolder(customRiskRules, what.flags.customRiskRulesDirFlag, progressReporter)
+			if rulesError != nil {
+				return fmt.Errorf("unable to load risk rules: %v", rulesError)
+			}
+			plugins := model.StartPlugins(strings.Split(what.flags.pluginsFlag, ","), time.Duration(cfg.PluginTimeout)*time.Second, customRiskRules, progressReporter)
+			defer model.ShutdownPlugins(plugins, progressReporter)
 			for id, customRule := range customRiskRules {
 				cmd.Println(id, "-->", customRule.Category.Title, "--> with tags:", customRule.Tags)
 			}
@@ -55,7 +65,16 @@ func (what *Threagile) initRules() *Threagile {
 			cmd.Println("----------------------")
 			cmd.Println("Custom risk rules:")
 			cmd.Println("----------------------")
-			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), common.DefaultProgressReporter{Verbose: what.flags.verboseFlag})
+			progressReporter := common.DefaultProgressReporter{Verbose: what.flags.verboseFlag}
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			customRiskRules := model.LoadCustomRiskRules(strings.Split(what.flags.customRiskRulesPluginFlag, ","), model.NewWasmLimits(*cfg), progressReporter)
+			defer model.CloseCustomRiskRules(customRiskRules, progressReporter)
+			rulesError := model.LoadRiskRulesFolder(customRiskRules, what.flags.customRiskRulesDirFlag, progressReporter)
+			if rulesError != nil {
+				return fmt.Errorf("unable to load risk rules: %v", rulesError)
+			}
+			plugins := model.StartPlugins(strings.Split(what.flags.pluginsFlag, ","), time.Duration(cfg.PluginTimeout)*time.Second, customRiskRules, progressReporter)
+			defer model.ShutdownPlugins(plugins, progressReporter)
 			for _, customRule := range customRiskRules {
 				cmd.Printf("%v: %v\n", customRule.Category.Id, customRule.Category.Description)
 			}
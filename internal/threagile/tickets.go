@@ -0,0 +1,59 @@
+package threagile
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/tickets"
+)
+
+func (what *Threagile) initTickets() *Threagile {
+	importTickets := &cobra.Command{
+		Use:   common.ImportTicketsCommand + " <ticket-mapping-file>",
+		Short: "Import tickets of risks into the risk tracking of the model",
+		Long: "\nSet the tickets of risks in the risk tracking of the model file (keeping a backup of it), e.g. after filing the tickets generated with --" +
+			generateJiraCSVFlagName + " or --" + generateGitHubIssuesFlagName + ". The mapping file is a yaml of risk IDs to tickets, " +
+			"a Jira csv export (with the custom field \"" + tickets.JiraRiskIdField + "\") or the json of 'gh issue list --json number,url,body'",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := what.readConfig(cmd, what.buildTimestamp)
+			progressReporter := common.DefaultProgressReporter{Verbose: cfg.Verbose}
+
+			mapping, err := tickets.LoadMapping(args[0])
+			if err != nil {
+				cmd.Printf("Unable to load ticket mapping: %v\n", err)
+				return err
+			}
+
+			r, err := model.ReadAndAnalyzeModel(*cfg, progressReporter)
+			if err != nil {
+				cmd.Printf("Failed to read and analyze model: %v\n", err)
+				return err
+			}
+			defer model.ShutdownPlugins(r.Plugins, progressReporter)
+			defer model.CloseCustomRiskRules(r.CustomRiskRules, progressReporter)
+			known := make(tickets.Mapping)
+			for riskId, ticket := range mapping {
+				if _, ok := r.ParsedModel.GeneratedRisksBySyntheticId[strings.ToLower(riskId)]; !ok {
+					cmd.Printf("Skipping ticket %v of unknown risk %q\n", ticket, riskId)
+					continue
+				}
+				known[strings.ToLower(riskId)] = ticket
+			}
+
+			changed, err := tickets.UpdateModelFile(cfg.InputFile, known)
+			if err != nil {
+				cmd.Printf("Unable to update model file: %v\n", err)
+				return err
+			}
+			cmd.Printf("Updated the ticket of %d risk tracking entries in %v\n", changed, cfg.InputFile)
+			return nil
+		},
+	}
+	what.rootCmd.AddCommand(importTickets)
+
+	return what
+}
@@ -0,0 +1,329 @@
+package attackpath
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// EntryPointTag marks technical assets (besides internet-facing ones and human clients) attacks can start from
+const EntryPointTag = "entry-point"
+
+// MaxPathsPerDataAsset is the number of most likely paths (from different entry points) kept for each sensitive data asset
+const MaxPathsPerDataAsset = 3
+
+// likelihood of getting past the authentication of a link
+var authenticationFactors = map[types.Authentication]float64{
+	types.NoneAuthentication: 1.0,
+	types.Credentials:        0.6,
+	types.SessionId:          0.6,
+	types.Token:              0.5,
+	types.Externalized:       0.5,
+	types.ClientCertificate:  0.4,
+	types.TwoFactor:          0.3,
+}
+
+// likelihood of exploiting the most severe open risk of an asset
+var severityFactors = map[types.RiskSeverity]float64{
+	types.CriticalSeverity: 0.5,
+	types.HighSeverity:     0.4,
+	types.ElevatedSeverity: 0.3,
+	types.MediumSeverity:   0.2,
+	types.LowSeverity:      0.1,
+}
+
+const (
+	encryptedFactor            = 0.8 // encrypted protocol or VPN
+	trustBoundaryFactor        = 0.7 // crossing a network trust boundary
+	reachableServiceLikelihood = 0.5 // compromising an entry point which is not a client of the attacker
+)
+
+// Hop is a step along a communication link
+type Hop struct {
+	LinkId               string  `json:"link_id"`
+	LinkTitle            string  `json:"link_title"`
+	SourceId             string  `json:"source_id"`
+	TargetId             string  `json:"target_id"`
+	Protocol             string  `json:"protocol"`
+	Authentication       string  `json:"authentication"`
+	Encrypted            bool    `json:"encrypted"`
+	CrossesTrustBoundary bool    `json:"crosses_trust_boundary"`
+	OpenRisks            int     `json:"open_risks"` // still at risk on the target
+	Likelihood           float64 `json:"likelihood"`
+}
+
+// Path is the most likely way from an entry point to a technical asset processing or storing a sensitive data asset
+type Path struct {
+	DataAssetId       string   `json:"data_asset_id"`
+	EntryPointId      string   `json:"entry_point_id"`
+	TechnicalAssetId  string   `json:"technical_asset_id"` // processing or storing the data asset
+	EntryLikelihood   float64  `json:"entry_likelihood"`
+	Likelihood        float64  `json:"likelihood"`
+	TechnicalAssetIds []string `json:"technical_asset_ids"`
+	Hops              []Hop    `json:"hops"`
+}
+
+// Result are the attack paths to all sensitive data assets, most likely first
+type Result struct {
+	EntryPointIds  []string `json:"entry_point_ids"`
+	DataAssetIds   []string `json:"data_asset_ids"` // strictly-confidential or mission-critical
+	UnreachableIds []string `json:"unreachable_data_asset_ids"`
+	Paths          []Path   `json:"paths"`
+}
+
+// FindPaths starts at internet-facing assets, human clients and assets tagged as entry point and follows the communication links
+// to every strictly-confidential or mission-critical data asset. Each hop is weighted by the authentication and encryption of the link,
+// crossed network trust boundaries and the risks still open on the target. The likelihood of a path is the product of its hops.
+func FindPaths(parsedModel *types.ParsedModel) *Result {
+	result := &Result{
+		EntryPointIds:  make([]string, 0),
+		DataAssetIds:   make([]string, 0),
+		UnreachableIds: make([]string, 0),
+		Paths:          make([]Path, 0),
+	}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if technicalAsset.Internet || technicalAsset.UsedAsClientByHuman || technicalAsset.IsTaggedWithAny(EntryPointTag) {
+			result.EntryPointIds = append(result.EntryPointIds, id)
+		}
+	}
+
+	holders := make(map[string][]string) // technical assets processing or storing a data asset
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		for _, dataAssetId := range append(append([]string{}, technicalAsset.DataAssetsProcessed...), technicalAsset.DataAssetsStored...) {
+			if !contains(holders[dataAssetId], id) {
+				holders[dataAssetId] = append(holders[dataAssetId], id)
+			}
+		}
+	}
+
+	openRisks := openRisksByTechnicalAsset(parsedModel)
+	searches := make(map[string]*search)
+	for _, entryPointId := range result.EntryPointIds {
+		searches[entryPointId] = mostLikelyPaths(parsedModel, entryPointId, openRisks)
+	}
+
+	for _, dataAssetId := range sortedKeys(parsedModel.DataAssets) {
+		dataAsset := parsedModel.DataAssets[dataAssetId]
+		if dataAsset.Confidentiality != types.StrictlyConfidential && dataAsset.Integrity != types.MissionCritical && dataAsset.Availability != types.MissionCritical {
+			continue
+		}
+		result.DataAssetIds = append(result.DataAssetIds, dataAssetId)
+
+		paths := make([]Path, 0)
+		for _, entryPointId := range result.EntryPointIds {
+			entryPoint := parsedModel.TechnicalAssets[entryPointId]
+			var best *Path
+			for _, holderId := range holders[dataAssetId] {
+				if holderId == entryPointId && entryPoint.UsedAsClientByHuman {
+					continue // data on the client of the attacker is no breach
+				}
+				path := searches[entryPointId].path(dataAssetId, holderId)
+				if path != nil && (best == nil || path.Likelihood > best.Likelihood) {
+					best = path
+				}
+			}
+			if best != nil {
+				paths = append(paths, *best)
+			}
+		}
+		if len(paths) == 0 {
+			result.UnreachableIds = append(result.UnreachableIds, dataAssetId)
+			continue
+		}
+		sort.SliceStable(paths, func(i, j int) bool {
+			return paths[i].Likelihood > paths[j].Likelihood
+		})
+		if len(paths) > MaxPathsPerDataAsset {
+			paths = paths[:MaxPathsPerDataAsset]
+		}
+		result.Paths = append(result.Paths, paths...)
+	}
+
+	sort.SliceStable(result.Paths, func(i, j int) bool {
+		return result.Paths[i].Likelihood > result.Paths[j].Likelihood
+	})
+	return result
+}
+
+// PathsTo returns the paths to the data asset, most likely first
+func (what *Result) PathsTo(dataAssetId string) []Path {
+	paths := make([]Path, 0)
+	for _, path := range what.Paths {
+		if path.DataAssetId == dataAssetId {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// search holds the most likely hops to all technical assets reachable from an entry point
+type search struct {
+	entryPointId    string
+	entryLikelihood float64
+	likelihood      map[string]float64
+	previous        map[string]Hop
+}
+
+func mostLikelyPaths(parsedModel *types.ParsedModel, entryPointId string, openRisks map[string][]types.Risk) *search {
+	entryPoint := parsedModel.TechnicalAssets[entryPointId]
+	entryLikelihood := 1.0
+	if !entryPoint.UsedAsClientByHuman {
+		entryLikelihood = exposed(reachableServiceLikelihood, openRisks[entryPointId])
+	}
+
+	result := &search{
+		entryPointId:    entryPointId,
+		entryLikelihood: entryLikelihood,
+		likelihood:      map[string]float64{entryPointId: entryLikelihood},
+		previous:        make(map[string]Hop),
+	}
+
+	// Dijkstra on -log(likelihood), i.e. maximizing the product of the likelihoods
+	queue := &priorityQueue{{id: entryPointId, likelihood: entryLikelihood}}
+	done := make(map[string]bool)
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(*queueItem)
+		if done[current.id] {
+			continue
+		}
+		done[current.id] = true
+
+		for _, link := range parsedModel.TechnicalAssets[current.id].CommunicationLinksSorted() {
+			if done[link.TargetId] {
+				continue
+			}
+			hop := newHop(parsedModel, link, openRisks[link.TargetId])
+			likelihood := current.likelihood * hop.Likelihood
+			if known, ok := result.likelihood[link.TargetId]; ok && known >= likelihood {
+				continue
+			}
+			result.likelihood[link.TargetId] = likelihood
+			result.previous[link.TargetId] = hop
+			heap.Push(queue, &queueItem{id: link.TargetId, likelihood: likelihood})
+		}
+	}
+	return result
+}
+
+func (what *search) path(dataAssetId string, technicalAssetId string) *Path {
+	likelihood, ok := what.likelihood[technicalAssetId]
+	if !ok {
+		return nil
+	}
+
+	hops := make([]Hop, 0)
+	for id := technicalAssetId; id != what.entryPointId; {
+		hop := what.previous[id]
+		hops = append([]Hop{hop}, hops...)
+		id = hop.SourceId
+	}
+	ids := []string{what.entryPointId}
+	for _, hop := range hops {
+		ids = append(ids, hop.TargetId)
+	}
+	return &Path{
+		DataAssetId:       dataAssetId,
+		EntryPointId:      what.entryPointId,
+		TechnicalAssetId:  technicalAssetId,
+		EntryLikelihood:   round(what.entryLikelihood),
+		Likelihood:        round(likelihood),
+		TechnicalAssetIds: ids,
+		Hops:              hops,
+	}
+}
+
+func newHop(parsedModel *types.ParsedModel, link types.CommunicationLink, openRisks []types.Risk) Hop {
+	hop := Hop{
+		LinkId:               link.Id,
+		LinkTitle:            link.Title,
+		SourceId:             link.SourceId,
+		TargetId:             link.TargetId,
+		Protocol:             link.Protocol.String(),
+		Authentication:       link.Authentication.String(),
+		Encrypted:            link.Protocol.IsEncrypted() || link.VPN,
+		CrossesTrustBoundary: link.IsAcrossTrustBoundaryNetworkOnly(parsedModel),
+		OpenRisks:            len(openRisks),
+	}
+
+	likelihood, ok := authenticationFactors[link.Authentication]
+	if !ok {
+		likelihood = 1.0
+	}
+	if hop.Encrypted {
+		likelihood *= encryptedFactor
+	}
+	if hop.CrossesTrustBoundary {
+		likelihood *= trustBoundaryFactor
+	}
+	hop.Likelihood = round(exposed(likelihood, openRisks))
+	return hop
+}
+
+// exposed raises the likelihood by the chance of exploiting the most severe of the open risks instead
+func exposed(likelihood float64, openRisks []types.Risk) float64 {
+	exploited := 0.0
+	for _, risk := range openRisks {
+		exploited = math.Max(exploited, severityFactors[risk.Severity])
+	}
+	return likelihood + (1-likelihood)*exploited
+}
+
+func openRisksByTechnicalAsset(parsedModel *types.ParsedModel) map[string][]types.Risk {
+	risks := make(map[string][]types.Risk)
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		if len(risk.MostRelevantTechnicalAssetId) > 0 && risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).IsStillAtRisk() {
+			risks[risk.MostRelevantTechnicalAssetId] = append(risks[risk.MostRelevantTechnicalAssetId], risk)
+		}
+	}
+	return risks
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+type queueItem struct {
+	id         string
+	likelihood float64
+}
+
+type priorityQueue []*queueItem
+
+func (what priorityQueue) Len() int { return len(what) }
+func (what priorityQueue) Less(i, j int) bool {
+	if what[i].likelihood == what[j].likelihood {
+		return what[i].id < what[j].id
+	}
+	return what[i].likelihood > what[j].likelihood
+}
+func (what priorityQueue) Swap(i, j int) { what[i], what[j] = what[j], what[i] }
+func (what *priorityQueue) Push(x any)   { *what = append(*what, x.(*queueItem)) }
+func (what *priorityQueue) Pop() any {
+	old := *what
+	item := old[len(old)-1]
+	*what = old[:len(old)-1]
+	return item
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package attackpath
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func attackModel() *types.ParsedModel {
+	login := types.CommunicationLink{Id: "browser>login", Title: "Login", SourceId: "browser", TargetId: "web", Protocol: types.HTTPS, Authentication: types.Credentials}
+	query := types.CommunicationLink{Id: "web>query", Title: "Query", SourceId: "web", TargetId: "db", Protocol: types.JDBC, Authentication: types.Credentials}
+	maintenance := types.CommunicationLink{Id: "admin>maintenance", Title: "Maintenance", SourceId: "admin", TargetId: "db", Protocol: types.SSH, Authentication: types.Token}
+	backend := types.TrustBoundary{Id: "backend", Title: "Backend", Type: types.NetworkCloudProvider, TechnicalAssetsInside: []string{"db"}}
+	return &types.ParsedModel{
+		DataAssets: map[string]types.DataAsset{
+			"customers": {Id: "customers", Confidentiality: types.StrictlyConfidential},
+			"audit":     {Id: "audit", Integrity: types.MissionCritical},
+			"docs":      {Id: "docs", Confidentiality: types.Public},
+		},
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"browser": {Id: "browser", UsedAsClientByHuman: true, Internet: true, DataAssetsProcessed: []string{"customers"}, CommunicationLinks: []types.CommunicationLink{login}},
+			"web":     {Id: "web", DataAssetsProcessed: []string{"docs"}, CommunicationLinks: []types.CommunicationLink{query}},
+			"admin":   {Id: "admin", Tags: []string{EntryPointTag}, CommunicationLinks: []types.CommunicationLink{maintenance}},
+			"db":      {Id: "db", DataAssetsStored: []string{"customers"}},
+			"vault":   {Id: "vault", DataAssetsStored: []string{"audit"}},
+		},
+		TrustBoundaries: map[string]types.TrustBoundary{"backend": backend},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]types.TrustBoundary{"db": backend},
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"open@db":       {SyntheticId: "open@db", Severity: types.HighSeverity, MostRelevantTechnicalAssetId: "db"},
+			"minor@db":      {SyntheticId: "minor@db", Severity: types.LowSeverity, MostRelevantTechnicalAssetId: "db"},
+			"mitigated@web": {SyntheticId: "mitigated@web", Severity: types.CriticalSeverity, MostRelevantTechnicalAssetId: "web"},
+		},
+		RiskTracking: map[string]types.RiskTracking{"mitigated@web": {SyntheticRiskId: "mitigated@web", Status: types.Mitigated}},
+	}
+}
+
+func TestFindPaths(t *testing.T) {
+	result := FindPaths(attackModel())
+
+	assert.Equal(t, []string{"admin", "browser"}, result.EntryPointIds)
+	assert.Equal(t, []string{"audit", "customers"}, result.DataAssetIds, "public data assets are skipped")
+	assert.Equal(t, []string{"audit"}, result.UnreachableIds)
+	require.Len(t, result.Paths, 2, "data on the client of the attacker is no breach")
+
+	viaWeb := result.Paths[0]
+	assert.Equal(t, "customers", viaWeb.DataAssetId)
+	assert.Equal(t, "browser", viaWeb.EntryPointId)
+	assert.Equal(t, "db", viaWeb.TechnicalAssetId, "stored data is reachable beyond the assets processing it")
+	assert.Equal(t, []string{"browser", "web", "db"}, viaWeb.TechnicalAssetIds)
+	assert.Equal(t, 1.0, viaWeb.EntryLikelihood)
+	assert.Equal(t, 0.3130, viaWeb.Likelihood)
+	require.Len(t, viaWeb.Hops, 2)
+	assert.Equal(t, Hop{LinkId: "browser>login", LinkTitle: "Login", SourceId: "browser", TargetId: "web", Protocol: "https",
+		Authentication: "credentials", Encrypted: true, Likelihood: 0.48}, viaWeb.Hops[0], "mitigated risks are not counted")
+	assert.Equal(t, Hop{LinkId: "web>query", LinkTitle: "Query", SourceId: "web", TargetId: "db", Protocol: "jdbc",
+		Authentication: "credentials", CrossesTrustBoundary: true, OpenRisks: 2, Likelihood: 0.652}, viaWeb.Hops[1])
+
+	viaAdmin := result.Paths[1]
+	assert.Equal(t, "admin", viaAdmin.EntryPointId)
+	assert.Equal(t, []string{"admin", "db"}, viaAdmin.TechnicalAssetIds)
+	assert.Equal(t, reachableServiceLikelihood, viaAdmin.EntryLikelihood)
+	assert.Equal(t, 0.284, viaAdmin.Likelihood)
+
+	assert.Equal(t, result.Paths, result.PathsTo("customers"))
+	assert.Empty(t, result.PathsTo("audit"))
+}
+
+func TestFindPathsKeepsMostLikely(t *testing.T) {
+	parsedModel := &types.ParsedModel{
+		DataAssets:      map[string]types.DataAsset{"secrets": {Id: "secrets", Confidentiality: types.StrictlyConfidential}},
+		TechnicalAssets: map[string]types.TechnicalAsset{"vault": {Id: "vault", DataAssetsStored: []string{"secrets"}}},
+	}
+	authentications := []types.Authentication{types.TwoFactor, types.NoneAuthentication, types.Token, types.Credentials, types.ClientCertificate}
+	for i, authentication := range authentications {
+		id := fmt.Sprintf("client-%d", i)
+		parsedModel.TechnicalAssets[id] = types.TechnicalAsset{Id: id, UsedAsClientByHuman: true, CommunicationLinks: []types.CommunicationLink{
+			{Id: id + ">vault", SourceId: id, TargetId: "vault", Protocol: types.HTTP, Authentication: authentication},
+		}}
+	}
+
+	paths := FindPaths(parsedModel).Paths
+	require.Len(t, paths, MaxPathsPerDataAsset)
+	entryPoints := make([]string, 0)
+	for _, path := range paths {
+		entryPoints = append(entryPoints, path.EntryPointId)
+	}
+	assert.Equal(t, []string{"client-1", "client-3", "client-2"}, entryPoints)
+}
+
+type exposedTest struct {
+	likelihood float64
+	severities []types.RiskSeverity
+	expected   float64
+}
+
+func TestExposed(t *testing.T) {
+	testCases := map[string]exposedTest{
+		"no risks": {
+			likelihood: 0.5,
+			expected:   0.5,
+		},
+		"low": {
+			likelihood: 0.5,
+			severities: []types.RiskSeverity{types.LowSeverity},
+			expected:   0.55,
+		},
+		"most severe counts": {
+			likelihood: 0.5,
+			severities: []types.RiskSeverity{types.LowSeverity, types.CriticalSeverity, types.MediumSeverity},
+			expected:   0.75,
+		},
+		"certain": {
+			likelihood: 1.0,
+			severities: []types.RiskSeverity{types.CriticalSeverity},
+			expected:   1.0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			risks := make([]types.Risk, 0)
+			for _, severity := range testCase.severities {
+				risks = append(risks, types.Risk{Severity: severity})
+			}
+			assert.InDelta(t, testCase.expected, exposed(testCase.likelihood, risks), 0.00001)
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package common
 
+// Attractiveness weighs the RAA calculation by the index of the fibonacci sequence (1, 2, 3, 5, 8, ...) the rating scales start at
 type Attractiveness struct {
-	Quantity        int
+	Quantity        int // fibonacci sequence base index
 	Confidentiality AttackerFocus
 	Integrity       AttackerFocus
 	Availability    AttackerFocus
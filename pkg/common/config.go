@@ -22,23 +22,53 @@ type Config struct {
 	TempFolder   string
 	KeyFolder    string
 
-	InputFile                   string
-	DataFlowDiagramFilenamePNG  string
-	DataAssetDiagramFilenamePNG string
-	DataFlowDiagramFilenameDOT  string
-	DataAssetDiagramFilenameDOT string
-	ReportFilename              string
-	ExcelRisksFilename          string
-	ExcelTagsFilename           string
-	JsonRisksFilename           string
-	JsonTechnicalAssetsFilename string
-	JsonStatsFilename           string
-	TemplateFilename            string
+	InputFile                       string
+	DataFlowDiagramFilenamePNG      string
+	DataAssetDiagramFilenamePNG     string
+	DataFlowDiagramFilenameDOT      string
+	DataAssetDiagramFilenameDOT     string
+	DataFlowDiagramFilenameMermaid  string
+	DataFlowDiagramFilenamePlantUML string
+	ReportFilename                  string
+	ExcelRisksFilename              string
+	ExcelTagsFilename               string
+	ExcelComponentsFilename         string
+	JsonRisksFilename               string
+	JsonTechnicalAssetsFilename     string
+	JsonStatsFilename               string
+	JsonComponentsFilename          string
+	JsonAttackPathsFilename         string
+	JsonDataLineageFilename         string
+	OtmFilename                     string
+	NetworkPoliciesFilename         string
+	GraphMLFilename                 string
+	CypherFilename                  string
+	SQLiteFilename                  string
+	JiraCSVFilename                 string
+	GitHubIssuesFilename            string
+	TemplateFilename                string
 
 	RAAPlugin         string
+	RAAAlgorithm      string // of the built-in RAA calculation: attractiveness or pagerank
 	RiskRulesPlugins  []string
+	RiskRulesFolder   string
+	Plugins           []string
 	SkipRiskRules     string
+	EnableRiskRules   string // opt-in risk rules to run besides the default ones
 	ExecuteModelMacro string
+	OSVDatabase       string
+	SARIF             string
+	SARIFMapping      string
+
+	NetworkPolicyMapping string
+	TicketTemplate       string
+
+	RiskSeverityMatrix map[string]map[string]string // likelihood to impact to severity, the rest is rated by the default matrix
+
+	RiskRulesParallelism int // 0 for the number of CPUs
+	RiskRuleTimeout      int // seconds, 0 for none
+	WasmMemoryLimit      int // MiB of each WebAssembly risk rule, 0 for the maximum of 4 GiB
+	PluginTimeout        int // seconds of each call of a plugin, 0 for none
 
 	ServerMode               bool
 	DiagramDPI               int
@@ -50,6 +80,7 @@ type Config struct {
 	AddModelTitle              bool
 	KeepDiagramSourceFiles     bool
 	IgnoreOrphanedRiskTracking bool
+	SQLiteAppend               bool
 
 	Attractiveness Attractiveness
 }
@@ -67,24 +98,43 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		TempFolder:   TempDir,
 		KeyFolder:    KeyDir,
 
-		InputFile:                   InputFile,
-		DataFlowDiagramFilenamePNG:  DataFlowDiagramFilenamePNG,
-		DataAssetDiagramFilenamePNG: DataAssetDiagramFilenamePNG,
-		DataFlowDiagramFilenameDOT:  DataFlowDiagramFilenameDOT,
-		DataAssetDiagramFilenameDOT: DataAssetDiagramFilenameDOT,
-		ReportFilename:              ReportFilename,
-		ExcelRisksFilename:          ExcelRisksFilename,
-		ExcelTagsFilename:           ExcelTagsFilename,
-		JsonRisksFilename:           JsonRisksFilename,
-		JsonTechnicalAssetsFilename: JsonTechnicalAssetsFilename,
-		JsonStatsFilename:           JsonStatsFilename,
-		TemplateFilename:            TemplateFilename,
-		RAAPlugin:                   RAAPluginName,
-		RiskRulesPlugins:            make([]string, 0),
-		SkipRiskRules:               "",
-		ExecuteModelMacro:           "",
-		ServerMode:                  false,
-		ServerPort:                  DefaultServerPort,
+		InputFile:                       InputFile,
+		DataFlowDiagramFilenamePNG:      DataFlowDiagramFilenamePNG,
+		DataAssetDiagramFilenamePNG:     DataAssetDiagramFilenamePNG,
+		DataFlowDiagramFilenameDOT:      DataFlowDiagramFilenameDOT,
+		DataAssetDiagramFilenameDOT:     DataAssetDiagramFilenameDOT,
+		DataFlowDiagramFilenameMermaid:  DataFlowDiagramFilenameMermaid,
+		DataFlowDiagramFilenamePlantUML: DataFlowDiagramFilenamePlantUML,
+		ReportFilename:                  ReportFilename,
+		ExcelRisksFilename:              ExcelRisksFilename,
+		ExcelTagsFilename:               ExcelTagsFilename,
+		ExcelComponentsFilename:         ExcelComponentsFilename,
+		JsonRisksFilename:               JsonRisksFilename,
+		JsonTechnicalAssetsFilename:     JsonTechnicalAssetsFilename,
+		JsonStatsFilename:               JsonStatsFilename,
+		JsonComponentsFilename:          JsonComponentsFilename,
+		JsonAttackPathsFilename:         JsonAttackPathsFilename,
+		JsonDataLineageFilename:         JsonDataLineageFilename,
+		OtmFilename:                     OtmFilename,
+		NetworkPoliciesFilename:         NetworkPoliciesFilename,
+		GraphMLFilename:                 GraphMLFilename,
+		CypherFilename:                  CypherFilename,
+		SQLiteFilename:                  SQLiteFilename,
+		JiraCSVFilename:                 JiraCSVFilename,
+		GitHubIssuesFilename:            GitHubIssuesFilename,
+		TemplateFilename:                TemplateFilename,
+		RAAPlugin:                       RAAPluginName,
+		RAAAlgorithm:                    DefaultRAAAlgorithm,
+		RiskRulesPlugins:                make([]string, 0),
+		Plugins:                         make([]string, 0),
+		SkipRiskRules:                   "",
+		EnableRiskRules:                 "",
+		ExecuteModelMacro:               "",
+		ServerMode:                      false,
+		ServerPort:                      DefaultServerPort,
+		RiskRuleTimeout:                 DefaultRiskRuleTimeout,
+		WasmMemoryLimit:                 DefaultWasmMemoryLimit,
+		PluginTimeout:                   DefaultPluginTimeout,
 
 		GraphvizDPI:              DefaultGraphvizDPI,
 		BackupHistoryFilesToKeep: DefaultBackupHistoryFilesToKeep,
@@ -92,23 +142,24 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		AddModelTitle:              false,
 		KeepDiagramSourceFiles:     false,
 		IgnoreOrphanedRiskTracking: false,
+		SQLiteAppend:               false,
 
 		Attractiveness: Attractiveness{
 			Quantity: 0,
 			Confidentiality: AttackerFocus{
-				Asset:                 0,
-				ProcessedOrStoredData: 0,
-				TransferredData:       0,
+				Asset:                 4,
+				ProcessedOrStoredData: 3,
+				TransferredData:       1,
 			},
 			Integrity: AttackerFocus{
-				Asset:                 0,
-				ProcessedOrStoredData: 0,
-				TransferredData:       0,
+				Asset:                 3,
+				ProcessedOrStoredData: 2,
+				TransferredData:       1,
 			},
 			Availability: AttackerFocus{
-				Asset:                 0,
-				ProcessedOrStoredData: 0,
-				TransferredData:       0,
+				Asset:                 3,
+				ProcessedOrStoredData: 2,
+				TransferredData:       1,
 			},
 		},
 	}
@@ -245,6 +296,14 @@ func (c *Config) Merge(config Config, values map[string]any) {
 			c.DataAssetDiagramFilenameDOT = config.DataAssetDiagramFilenameDOT
 			break
 
+		case strings.ToLower("DataFlowDiagramFilenameMermaid"):
+			c.DataFlowDiagramFilenameMermaid = config.DataFlowDiagramFilenameMermaid
+			break
+
+		case strings.ToLower("DataFlowDiagramFilenamePlantUML"):
+			c.DataFlowDiagramFilenamePlantUML = config.DataFlowDiagramFilenamePlantUML
+			break
+
 		case strings.ToLower("ReportFilename"):
 			c.ReportFilename = config.ReportFilename
 			break
@@ -257,6 +316,10 @@ func (c *Config) Merge(config Config, values map[string]any) {
 			c.ExcelTagsFilename = config.ExcelTagsFilename
 			break
 
+		case strings.ToLower("ExcelComponentsFilename"):
+			c.ExcelComponentsFilename = config.ExcelComponentsFilename
+			break
+
 		case strings.ToLower("JsonRisksFilename"):
 			c.JsonRisksFilename = config.JsonRisksFilename
 			break
@@ -269,6 +332,46 @@ func (c *Config) Merge(config Config, values map[string]any) {
 			c.JsonStatsFilename = config.JsonStatsFilename
 			break
 
+		case strings.ToLower("JsonComponentsFilename"):
+			c.JsonComponentsFilename = config.JsonComponentsFilename
+			break
+
+		case strings.ToLower("JsonAttackPathsFilename"):
+			c.JsonAttackPathsFilename = config.JsonAttackPathsFilename
+			break
+
+		case strings.ToLower("JsonDataLineageFilename"):
+			c.JsonDataLineageFilename = config.JsonDataLineageFilename
+			break
+
+		case strings.ToLower("OtmFilename"):
+			c.OtmFilename = config.OtmFilename
+			break
+
+		case strings.ToLower("NetworkPoliciesFilename"):
+			c.NetworkPoliciesFilename = config.NetworkPoliciesFilename
+			break
+
+		case strings.ToLower("GraphMLFilename"):
+			c.GraphMLFilename = config.GraphMLFilename
+			break
+
+		case strings.ToLower("CypherFilename"):
+			c.CypherFilename = config.CypherFilename
+			break
+
+		case strings.ToLower("SQLiteFilename"):
+			c.SQLiteFilename = config.SQLiteFilename
+			break
+
+		case strings.ToLower("JiraCSVFilename"):
+			c.JiraCSVFilename = config.JiraCSVFilename
+			break
+
+		case strings.ToLower("GitHubIssuesFilename"):
+			c.GitHubIssuesFilename = config.GitHubIssuesFilename
+			break
+
 		case strings.ToLower("TemplateFilename"):
 			c.TemplateFilename = config.TemplateFilename
 			break
@@ -277,18 +380,74 @@ func (c *Config) Merge(config Config, values map[string]any) {
 			c.RAAPlugin = config.RAAPlugin
 			break
 
+		case strings.ToLower("RAAAlgorithm"):
+			c.RAAAlgorithm = config.RAAAlgorithm
+			break
+
 		case strings.ToLower("RiskRulesPlugins"):
 			c.RiskRulesPlugins = config.RiskRulesPlugins
 			break
 
+		case strings.ToLower("RiskRulesFolder"):
+			c.RiskRulesFolder = config.RiskRulesFolder
+			break
+
+		case strings.ToLower("Plugins"):
+			c.Plugins = config.Plugins
+			break
+
 		case strings.ToLower("SkipRiskRules"):
 			c.SkipRiskRules = config.SkipRiskRules
 			break
 
+		case strings.ToLower("EnableRiskRules"):
+			c.EnableRiskRules = config.EnableRiskRules
+			break
+
 		case strings.ToLower("ExecuteModelMacro"):
 			c.ExecuteModelMacro = config.ExecuteModelMacro
 			break
 
+		case strings.ToLower("OSVDatabase"):
+			c.OSVDatabase = config.OSVDatabase
+			break
+
+		case strings.ToLower("SARIF"):
+			c.SARIF = config.SARIF
+			break
+
+		case strings.ToLower("SARIFMapping"):
+			c.SARIFMapping = config.SARIFMapping
+			break
+
+		case strings.ToLower("NetworkPolicyMapping"):
+			c.NetworkPolicyMapping = config.NetworkPolicyMapping
+			break
+
+		case strings.ToLower("TicketTemplate"):
+			c.TicketTemplate = config.TicketTemplate
+			break
+
+		case strings.ToLower("RiskSeverityMatrix"):
+			c.RiskSeverityMatrix = config.RiskSeverityMatrix
+			break
+
+		case strings.ToLower("RiskRulesParallelism"):
+			c.RiskRulesParallelism = config.RiskRulesParallelism
+			break
+
+		case strings.ToLower("RiskRuleTimeout"):
+			c.RiskRuleTimeout = config.RiskRuleTimeout
+			break
+
+		case strings.ToLower("WasmMemoryLimit"):
+			c.WasmMemoryLimit = config.WasmMemoryLimit
+			break
+
+		case strings.ToLower("PluginTimeout"):
+			c.PluginTimeout = config.PluginTimeout
+			break
+
 		case strings.ToLower("DiagramDPI"):
 			c.DiagramDPI = config.DiagramDPI
 			break
@@ -321,6 +480,10 @@ func (c *Config) Merge(config Config, values map[string]any) {
 			c.IgnoreOrphanedRiskTracking = config.IgnoreOrphanedRiskTracking
 			break
 
+		case strings.ToLower("SQLiteAppend"):
+			c.SQLiteAppend = config.SQLiteAppend
+			break
+
 		case strings.ToLower("Attractiveness"):
 			c.Attractiveness = config.Attractiveness
 			break
@@ -11,25 +11,45 @@ const (
 
 	DefaultServerPort = 8080
 
-	InputFile                   = "threagile.yaml"
-	ReportFilename              = "report.pdf"
-	ExcelRisksFilename          = "risks.xlsx"
-	ExcelTagsFilename           = "tags.xlsx"
-	JsonRisksFilename           = "risks.json"
-	JsonTechnicalAssetsFilename = "technical-assets.json"
-	JsonStatsFilename           = "stats.json"
-	TemplateFilename            = "background.pdf"
-	DataFlowDiagramFilenameDOT  = "data-flow-diagram.gv"
-	DataFlowDiagramFilenamePNG  = "data-flow-diagram.png"
-	DataAssetDiagramFilenameDOT = "data-asset-diagram.gv"
-	DataAssetDiagramFilenamePNG = "data-asset-diagram.png"
+	InputFile                       = "threagile.yaml"
+	ReportFilename                  = "report.pdf"
+	ExcelRisksFilename              = "risks.xlsx"
+	ExcelTagsFilename               = "tags.xlsx"
+	ExcelComponentsFilename         = "components.xlsx"
+	JsonRisksFilename               = "risks.json"
+	JsonTechnicalAssetsFilename     = "technical-assets.json"
+	JsonStatsFilename               = "stats.json"
+	JsonComponentsFilename          = "components.json"
+	JsonAttackPathsFilename         = "attack-paths.json"
+	JsonDataLineageFilename         = "data-lineage.json"
+	TemplateFilename                = "background.pdf"
+	DataFlowDiagramFilenameDOT      = "data-flow-diagram.gv"
+	DataFlowDiagramFilenamePNG      = "data-flow-diagram.png"
+	DataAssetDiagramFilenameDOT     = "data-asset-diagram.gv"
+	DataAssetDiagramFilenamePNG     = "data-asset-diagram.png"
+	DataFlowDiagramFilenameMermaid  = "data-flow-diagram.mmd"
+	DataFlowDiagramFilenamePlantUML = "data-flow-diagram.puml"
+	OtmFilename                     = "threat-model.otm.json"
+	NetworkPoliciesFilename         = "network-policies.yaml"
+	GraphMLFilename                 = "threat-model.graphml"
+	CypherFilename                  = "threat-model.cypher"
+	SQLiteFilename                  = "threat-model.sqlite"
+	JiraCSVFilename                 = "risks-jira.csv"
+	GitHubIssuesFilename            = "risks-github-issues.json"
+	ImportedModelFilename           = "threagile-imported-model.yaml"
+	OpenAPIIncludeFilename          = "threagile-openapi-include.yaml"
+	DriftIncludeFilename            = "threagile-drift-include.yaml"
 
-	RAAPluginName = "raa_calc"
+	RAAPluginName       = "raa_calc"
+	DefaultRAAAlgorithm = "attractiveness"
 
 	DefaultGraphvizDPI              = 120
 	MinGraphvizDPI                  = 20
 	MaxGraphvizDPI                  = 300
 	DefaultBackupHistoryFilesToKeep = 50
+	DefaultRiskRuleTimeout          = 300 // seconds
+	DefaultWasmMemoryLimit          = 256 // MiB
+	DefaultPluginTimeout            = 300 // seconds
 )
 
 const (
@@ -47,4 +67,13 @@ const (
 	ExplainModelMacrosCommand   = "explain-model-macros"
 	Print3rdPartyCommand        = "print-3rd-party-licenses"
 	PrintLicenseCommand         = "print-license"
+	ImportOTMCommand            = "import-otm"
+	ImportThreatDragonCommand   = "import-threat-dragon"
+	ImportTMTCommand            = "import-tmt"
+	ImportDockerComposeCommand  = "import-docker-compose"
+	ImportKubernetesCommand     = "import-kubernetes"
+	ImportTerraformCommand      = "import-terraform"
+	ImportOpenAPICommand        = "import-openapi"
+	DetectDriftCommand          = "detect-drift"
+	ImportTicketsCommand        = "import-tickets"
 )
@@ -0,0 +1,162 @@
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Compose file format (https://compose-spec.io), only the parts needed for the import
+
+type Project struct {
+	Name     string              `yaml:"name"`
+	Services map[string]*Service `yaml:"services"`
+	Networks map[string]*Network `yaml:"networks"`
+}
+
+type Service struct {
+	Image       string            `yaml:"image"`
+	Build       any               `yaml:"build"`
+	Ports       []Port            `yaml:"ports"`
+	Expose      []string          `yaml:"expose"`
+	Networks    StringSet         `yaml:"networks"`
+	DependsOn   StringSet         `yaml:"depends_on"`
+	Links       []string          `yaml:"links"`
+	Environment map[string]string `yaml:"-"`
+}
+
+type Network struct {
+	Name     string `yaml:"name"`
+	Driver   string `yaml:"driver"`
+	External bool   `yaml:"external"`
+	Internal bool   `yaml:"internal"`
+}
+
+// Port is a container port, optionally published on the host
+type Port struct {
+	HostIp    string
+	Published string
+	Random    bool // published on a random host port (short syntax without published port)
+	Target    int
+	Protocol  string
+}
+
+// StringSet holds the names of either a list or a mapping (depends_on, networks, ...)
+type StringSet []string
+
+func (what *StringSet) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := value.Decode(&names); err != nil {
+			return err
+		}
+		*what = names
+	case yaml.MappingNode:
+		names := make([]string, 0)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			names = append(names, value.Content[i].Value)
+		}
+		sort.Strings(names)
+		*what = names
+	default:
+		return fmt.Errorf("unexpected yaml node at line %d", value.Line)
+	}
+	return nil
+}
+
+func (what *Service) UnmarshalYAML(value *yaml.Node) error {
+	type plain Service
+	var service struct {
+		plain       `yaml:",inline"`
+		Environment yaml.Node `yaml:"environment"`
+	}
+	if err := value.Decode(&service); err != nil {
+		return err
+	}
+	*what = Service(service.plain)
+
+	what.Environment = make(map[string]string)
+	switch service.Environment.Kind {
+	case yaml.SequenceNode:
+		var variables []string
+		if err := service.Environment.Decode(&variables); err != nil {
+			return err
+		}
+		for _, variable := range variables {
+			name, variableValue, _ := strings.Cut(variable, "=")
+			what.Environment[name] = variableValue
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(service.Environment.Content); i += 2 {
+			what.Environment[service.Environment.Content[i].Value] = service.Environment.Content[i+1].Value
+		}
+	}
+	return nil
+}
+
+func (what *Port) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		var port struct {
+			HostIp    string `yaml:"host_ip"`
+			Published string `yaml:"published"`
+			Target    int    `yaml:"target"`
+			Protocol  string `yaml:"protocol"`
+		}
+		if err := value.Decode(&port); err != nil {
+			return err
+		}
+		*what = Port{HostIp: port.HostIp, Published: port.Published, Target: port.Target, Protocol: port.Protocol}
+		return nil
+	}
+
+	// short syntax: [[host_ip:]published:]target[/protocol]
+	text := value.Value
+	text, what.Protocol, _ = strings.Cut(text, "/")
+	parts := strings.Split(text, ":")
+	target := parts[len(parts)-1]
+	if len(parts) > 1 {
+		what.Published = parts[len(parts)-2]
+	}
+	what.Random = len(what.Published) == 0
+	if len(parts) > 2 {
+		what.HostIp = strings.Join(parts[:len(parts)-2], ":")
+	}
+
+	// port ranges are reduced to their first port
+	target, _, _ = strings.Cut(target, "-")
+	port, err := strconv.Atoi(target)
+	if err != nil {
+		return fmt.Errorf("invalid port %q at line %d", value.Value, value.Line)
+	}
+	what.Target = port
+	return nil
+}
+
+func (what Port) IsPublished() bool {
+	return len(what.Published) > 0 || what.Random
+}
+
+// IsLocalOnly tells whether a published port is only bound to the loopback interface
+func (what Port) IsLocalOnly() bool {
+	return what.HostIp == "127.0.0.1" || what.HostIp == "::1" || what.HostIp == "[::1]" || what.HostIp == "localhost"
+}
+
+// ContainerPorts returns all ports the container listens on, published or exposed
+func (what *Service) ContainerPorts() []int {
+	ports := make([]int, 0)
+	for _, port := range what.Ports {
+		ports = append(ports, port.Target)
+	}
+	for _, exposed := range what.Expose {
+		exposed, _, _ = strings.Cut(exposed, "/")
+		exposed, _, _ = strings.Cut(exposed, "-")
+		if port, err := strconv.Atoi(exposed); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
@@ -0,0 +1,271 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const defaultNetwork = "default"
+
+// LoadFiles reads one or more compose files, later files extend and override earlier ones (like docker compose -f a -f b)
+func LoadFiles(filenames []string) (*Project, error) {
+	project := &Project{Services: make(map[string]*Service), Networks: make(map[string]*Network)}
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read compose file %q: %w", filename, err)
+		}
+
+		file := new(Project)
+		err = yaml.Unmarshal(data, file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse compose file %q: %w", filename, err)
+		}
+
+		project.merge(file)
+		if len(project.Name) == 0 {
+			project.Name = filepath.Base(filepath.Dir(filepath.Clean(filename)))
+		}
+	}
+	return project, nil
+}
+
+func (what *Project) merge(other *Project) {
+	if len(other.Name) > 0 {
+		what.Name = other.Name
+	}
+	for name, network := range other.Networks {
+		if network == nil {
+			network = new(Network)
+		}
+		what.Networks[name] = network
+	}
+	for name, service := range other.Services {
+		if service == nil {
+			continue
+		}
+		existing, ok := what.Services[name]
+		if !ok {
+			what.Services[name] = service
+			continue
+		}
+		if len(service.Image) > 0 {
+			existing.Image = service.Image
+		}
+		if service.Build != nil {
+			existing.Build = service.Build
+		}
+		existing.Ports = append(existing.Ports, service.Ports...)
+		existing.Expose = append(existing.Expose, service.Expose...)
+		existing.Networks = union(existing.Networks, service.Networks)
+		existing.DependsOn = union(existing.DependsOn, service.DependsOn)
+		existing.Links = append(existing.Links, service.Links...)
+		for variable, value := range service.Environment {
+			existing.Environment[variable] = value
+		}
+	}
+}
+
+// Import turns the services of a compose project into technical assets, its networks into trust boundaries
+// and dependencies as well as published ports into communication links; guesses are tagged for review.
+func Import(project *Project, mapping importer.Mapping) (*input.Model, *importer.Report) {
+	report := new(importer.Report)
+	model := importer.NewModel(project.Name)
+	model.AppDescription.Description = "Imported from docker compose project " + project.Name
+
+	serviceNames := make([]string, 0)
+	for name := range project.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+
+	takenIds := make(map[string]bool)
+	idOf := make(map[string]string)
+	protocolOf := make(map[string]types.Protocol)
+
+	// Technical Assets ===============================================================================
+	for _, name := range serviceNames {
+		service := project.Services[name]
+		id := importer.UniqueID(name, takenIds)
+		idOf[name] = id
+
+		origin := "image " + service.Image
+		if len(service.Image) == 0 {
+			origin = "a locally built image"
+		}
+		description := fmt.Sprintf("Service %v running %v", name, origin)
+
+		technology, protocol, guessed := importer.GuessTechnology(mapping, service.Image, name, service.ContainerPorts())
+		if technology == types.ReverseProxy && len(service.DependsOn) == 0 && len(service.Links) == 0 {
+			technology = types.WebServer
+		}
+		protocolOf[name] = protocol
+
+		asset := importer.NewTechnicalAsset(id, description, technology)
+		asset.Machine = types.Container.String()
+		asset.CustomDevelopedParts = service.Build != nil
+		if !guessed {
+			importer.MarkForReview(model, &asset, fmt.Sprintf("Which technology is used by service '%v'?", name), technology.String())
+			report.Add("service '%v': unable to guess technology from %v (using %q, add it to the mapping file to fix this)", name, origin, technology.String())
+		}
+
+		model.TechnicalAssets[name] = asset
+	}
+
+	// Trust Boundaries ===============================================================================
+	networkNames := make([]string, 0)
+	for name := range project.Networks {
+		networkNames = append(networkNames, name)
+	}
+	for _, name := range serviceNames {
+		networks := project.Services[name].Networks
+		if len(networks) == 0 {
+			networks = []string{defaultNetwork}
+		}
+		networkNames = union(networkNames, networks)
+
+		if len(networks) > 1 {
+			asset := model.TechnicalAssets[name]
+			importer.MarkForReview(model, &asset, fmt.Sprintf("Service '%v' is attached to the networks %v, which trust boundary does it belong to?", name, strings.Join(networks, ", ")), networks[0])
+			model.TechnicalAssets[name] = asset
+		}
+
+		boundary, ok := model.TrustBoundaries[networks[0]]
+		if !ok {
+			boundary = input.TrustBoundary{
+				ID:          importer.UniqueID("network "+networks[0], takenIds),
+				Description: "Docker network " + networks[0],
+				Type:        types.NetworkVirtualLAN.String(),
+			}
+		}
+		boundary.TechnicalAssetsInside = append(boundary.TechnicalAssetsInside, idOf[name])
+		model.TrustBoundaries[networks[0]] = boundary
+	}
+	for _, name := range networkNames {
+		if _, ok := model.TrustBoundaries[name]; !ok && name != defaultNetwork {
+			model.TrustBoundaries[name] = input.TrustBoundary{
+				ID:          importer.UniqueID("network "+name, takenIds),
+				Description: "Docker network " + name,
+				Type:        types.NetworkVirtualLAN.String(),
+			}
+		}
+	}
+
+	// Communication Links ===============================================================================
+	for _, name := range serviceNames {
+		service := project.Services[name]
+		asset := model.TechnicalAssets[name]
+
+		reasons := make(map[string]string)
+		for _, dependency := range service.DependsOn {
+			reasons[dependency] = fmt.Sprintf("%v depends on %v", name, dependency)
+		}
+		for _, link := range service.Links {
+			target, _, _ := strings.Cut(link, ":")
+			reasons[target] = fmt.Sprintf("%v is linked to %v", name, target)
+		}
+		uncertain := make(map[string]bool)
+		for _, variable := range sortedKeys(service.Environment) {
+			for _, target := range serviceNames {
+				if _, ok := reasons[target]; ok || target == name || !refersTo(service.Environment[variable], target) {
+					continue
+				}
+				reasons[target] = fmt.Sprintf("environment variable %v of %v refers to %v", variable, name, target)
+				uncertain[target] = true
+			}
+		}
+
+		for _, target := range sortedKeys(reasons) {
+			if _, ok := project.Services[target]; !ok {
+				report.Add("service '%v': unknown service %q referenced", name, target)
+				continue
+			}
+
+			link := importer.NewCommunicationLink(idOf[target], reasons[target], protocolOf[target])
+			asset.CommunicationLinks[importer.UniqueKey(asset.CommunicationLinks, target)] = link
+			if protocolOf[target] == types.UnknownProtocol {
+				importer.MarkForReview(model, &asset, fmt.Sprintf("Which protocol is used by service '%v' to access '%v'?", name, target), "")
+			}
+			if uncertain[target] {
+				importer.MarkForReview(model, &asset, fmt.Sprintf("Does service '%v' really communicate with '%v' (%v)?", name, target, reasons[target]), "")
+			}
+		}
+		model.TechnicalAssets[name] = asset
+	}
+
+	// published ports are reachable from outside, modelled as links from an external client
+	for _, name := range serviceNames {
+		service := project.Services[name]
+		asset := model.TechnicalAssets[name]
+		for _, port := range service.Ports {
+			if !port.IsPublished() {
+				continue
+			}
+			if port.IsLocalOnly() {
+				importer.AddQuestion(model, fmt.Sprintf("Which local client accesses port %v of service '%v'?", port.Published, name), port.HostIp)
+				continue
+			}
+			asset.Internet = true
+
+			clientTitle := importer.ExternalClient(model, takenIds)
+			client := model.TechnicalAssets[clientTitle]
+
+			protocol, ok := importer.GuessProtocolFromPort(port.Target)
+			if !ok {
+				protocol = protocolOf[name]
+			}
+			hostPort, description := port.Published, fmt.Sprintf("Published port %v of %v", port.Published, name)
+			if port.Random {
+				hostPort, description = strconv.Itoa(port.Target), fmt.Sprintf("Port %v of %v published on a random host port", port.Target, name)
+			}
+			link := importer.NewCommunicationLink(asset.ID, description, protocol)
+			client.CommunicationLinks[importer.UniqueKey(client.CommunicationLinks, fmt.Sprintf("%v port %v", name, hostPort))] = link
+			model.TechnicalAssets[clientTitle] = client
+		}
+		model.TechnicalAssets[name] = asset
+	}
+
+	importer.AddQuestion(model, importer.DataAssetsQuestion, "")
+	sort.Strings(model.TagsAvailable)
+	return model, report
+}
+
+// refersTo tells whether a value (like a connection string) names a service as host
+func refersTo(value string, service string) bool {
+	return regexp.MustCompile(`(^|[/@=,;\s])` + regexp.QuoteMeta(service) + `([:/,;\s]|$)`).MatchString(value)
+}
+
+func union(values []string, others []string) []string {
+	result := append([]string{}, values...)
+	for _, other := range others {
+		found := false
+		for _, value := range result {
+			found = found || value == other
+		}
+		if !found {
+			result = append(result, other)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
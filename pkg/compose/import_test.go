@@ -0,0 +1,129 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type portTest struct {
+	yaml      string
+	expected  Port
+	published bool
+	localOnly bool
+}
+
+func TestPortUnmarshal(t *testing.T) {
+	testCases := map[string]portTest{
+		"target only": {
+			yaml:      `"3000"`,
+			expected:  Port{Random: true, Target: 3000},
+			published: true,
+		},
+		"target range": {
+			yaml:      `"3000-3005/udp"`,
+			expected:  Port{Random: true, Target: 3000, Protocol: "udp"},
+			published: true,
+		},
+		"published": {
+			yaml:      `"8080:80"`,
+			expected:  Port{Published: "8080", Target: 80},
+			published: true,
+		},
+		"host ip": {
+			yaml:      `"127.0.0.1:8080:80/tcp"`,
+			expected:  Port{HostIp: "127.0.0.1", Published: "8080", Target: 80, Protocol: "tcp"},
+			published: true,
+			localOnly: true,
+		},
+		"ipv6 host ip": {
+			yaml:      `"::1:8080:80"`,
+			expected:  Port{HostIp: "::1", Published: "8080", Target: 80},
+			published: true,
+			localOnly: true,
+		},
+		"long syntax": {
+			yaml:      "{target: 80, published: \"8080\", host_ip: 0.0.0.0, protocol: tcp}",
+			expected:  Port{HostIp: "0.0.0.0", Published: "8080", Target: 80, Protocol: "tcp"},
+			published: true,
+		},
+		"long syntax without published port": {
+			yaml:     "{target: 80}",
+			expected: Port{Target: 80},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var port Port
+			require.NoError(t, yaml.Unmarshal([]byte(testCase.yaml), &port))
+
+			assert.Equal(t, testCase.expected, port)
+			assert.Equal(t, testCase.published, port.IsPublished())
+			assert.Equal(t, testCase.localOnly, port.IsLocalOnly())
+		})
+	}
+}
+
+func TestPortUnmarshalFails(t *testing.T) {
+	var port Port
+	assert.Error(t, yaml.Unmarshal([]byte(`"http"`), &port))
+}
+
+func TestLoadFiles(t *testing.T) {
+	project, err := LoadFiles([]string{"testdata/shop/docker-compose.yml", "testdata/shop/docker-compose.override.yml"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", project.Name, "named after the directory")
+	require.Len(t, project.Services, 4)
+	backend := project.Services["backend"]
+	assert.Equal(t, StringSet{"db"}, backend.DependsOn)
+	assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://shop@db:5432/shop", "CACHE": "cache", "LOG_LEVEL": "debug"}, backend.Environment)
+	assert.Equal(t, []int{8080}, backend.ContainerPorts())
+	assert.True(t, project.Networks["backend"].Internal)
+}
+
+func TestImport(t *testing.T) {
+	project, err := LoadFiles([]string{"testdata/shop/docker-compose.yml", "testdata/shop/docker-compose.override.yml"})
+	require.NoError(t, err)
+
+	model, report := Import(project, importer.Mapping{})
+
+	proxy, backend, db, admin := model.TechnicalAssets["proxy"], model.TechnicalAssets["backend"], model.TechnicalAssets["db"], model.TechnicalAssets["admin"]
+	assert.Equal(t, types.ReverseProxy.String(), proxy.Technology)
+	assert.Equal(t, types.WebServiceREST.String(), backend.Technology)
+	assert.True(t, backend.CustomDevelopedParts)
+	assert.Equal(t, types.Database.String(), db.Technology)
+	assert.Equal(t, types.WebServiceREST.String(), admin.Technology, "unknown image speaking http")
+	assert.Contains(t, admin.Tags, importer.ReviewTag)
+
+	assert.True(t, proxy.Internet)
+	assert.True(t, admin.Internet, "ports without published port are published on a random host port")
+	assert.False(t, backend.Internet)
+	assert.False(t, db.Internet)
+
+	client := model.TechnicalAssets["External Client"]
+	assert.True(t, client.OutOfScope)
+	require.Len(t, client.CommunicationLinks, 3)
+	assert.Equal(t, types.HTTPS.String(), client.CommunicationLinks["proxy port 443"].Protocol)
+	assert.Equal(t, "Port 3000 of admin published on a random host port", client.CommunicationLinks["admin port 3000"].Description)
+	assert.Equal(t, types.HTTP.String(), client.CommunicationLinks["admin port 3000"].Protocol)
+	assert.Contains(t, client.CommunicationLinks, "admin port 19001")
+	assert.Contains(t, model.Questions, "Which local client accesses port 8081 of service 'proxy'?")
+
+	assert.Equal(t, "backend", proxy.CommunicationLinks["backend"].Target)
+	assert.Equal(t, types.HTTP.String(), proxy.CommunicationLinks["backend"].Protocol)
+	assert.Equal(t, types.SqlAccessProtocol.String(), backend.CommunicationLinks["db"].Protocol)
+	assert.Len(t, backend.CommunicationLinks, 1, "the cache service doesn't exist")
+
+	assert.Equal(t, []string{"backend", "db"}, model.TrustBoundaries["backend"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"proxy"}, model.TrustBoundaries["frontend"].TechnicalAssetsInside)
+	assert.Contains(t, model.Questions, "Service 'backend' is attached to the networks backend, frontend, which trust boundary does it belong to?")
+
+	assert.Contains(t, report.Items, "service 'admin': unknown service \"missing\" referenced")
+}
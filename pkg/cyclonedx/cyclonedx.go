@@ -0,0 +1,116 @@
+package cyclonedx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// CycloneDX SBOMs (https://cyclonedx.org/specification/overview/) in json or xml, only the parts needed for the component inventory
+
+type BOM struct {
+	BOMFormat   string      `json:"bomFormat" xml:"-"`
+	SpecVersion string      `json:"specVersion" xml:"version,attr"`
+	Components  []Component `json:"components" xml:"components>component"`
+}
+
+type Component struct {
+	Type       string          `json:"type" xml:"type,attr"`
+	Group      string          `json:"group" xml:"group"`
+	Name       string          `json:"name" xml:"name"`
+	Version    string          `json:"version" xml:"version"`
+	PURL       string          `json:"purl" xml:"purl"`
+	Licenses   []LicenseChoice `json:"licenses" xml:"licenses>license"`
+	Expression string          `json:"-" xml:"licenses>expression"`
+	Components []Component     `json:"components" xml:"components>component"`
+}
+
+// LicenseChoice is either a license or a license expression
+type LicenseChoice struct {
+	License    *License `json:"license"`
+	Expression string   `json:"expression"`
+
+	// xml has no wrapping license element
+	ID   string `json:"-" xml:"id"`
+	Name string `json:"-" xml:"name"`
+}
+
+type License struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LoadFile reads a CycloneDX SBOM, xml files are detected by their extension
+func LoadFile(filename string) (*BOM, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SBOM file %q: %w", filename, err)
+	}
+
+	bom := new(BOM)
+	if strings.EqualFold(filepath.Ext(filename), ".xml") {
+		err = xml.Unmarshal(data, bom)
+	} else {
+		err = json.Unmarshal(data, bom)
+		if err == nil && bom.BOMFormat != "CycloneDX" {
+			err = fmt.Errorf("unexpected bomFormat %q", bom.BOMFormat)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SBOM file %q: %w", filename, err)
+	}
+	return bom, nil
+}
+
+// SoftwareComponents returns all components of the SBOM including nested ones, each one only once
+func (what *BOM) SoftwareComponents() []types.SoftwareComponent {
+	result := make([]types.SoftwareComponent, 0)
+	seen := make(map[string]bool)
+	var collect func(components []Component)
+	collect = func(components []Component) {
+		for _, component := range components {
+			key := component.Group + ":" + component.Name + "@" + component.Version
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, types.SoftwareComponent{
+					Type:     component.Type,
+					Group:    component.Group,
+					Name:     component.Name,
+					Version:  component.Version,
+					PURL:     component.PURL,
+					Licenses: component.licenses(),
+				})
+			}
+			collect(component.Components)
+		}
+	}
+	collect(what.Components)
+	return result
+}
+
+func (what Component) licenses() []string {
+	licenses := make([]string, 0)
+	for _, choice := range what.Licenses {
+		switch {
+		case len(choice.Expression) > 0:
+			licenses = append(licenses, choice.Expression)
+		case choice.License != nil && len(choice.License.ID) > 0:
+			licenses = append(licenses, choice.License.ID)
+		case choice.License != nil && len(choice.License.Name) > 0:
+			licenses = append(licenses, choice.License.Name)
+		case len(choice.ID) > 0:
+			licenses = append(licenses, choice.ID)
+		case len(choice.Name) > 0:
+			licenses = append(licenses, choice.Name)
+		}
+	}
+	if len(what.Expression) > 0 {
+		licenses = append(licenses, what.Expression)
+	}
+	return licenses
+}
@@ -0,0 +1,56 @@
+package cyclonedx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type loadFileTest struct {
+	filename string
+	expected []types.SoftwareComponent
+}
+
+func TestLoadFile(t *testing.T) {
+	testCases := map[string]loadFileTest{
+		"json": {
+			filename: "testdata/bom.json",
+			expected: []types.SoftwareComponent{
+				{Type: "framework", Group: "org.springframework", Name: "spring-web", Version: "6.1.2", PURL: "pkg:maven/org.springframework/spring-web@6.1.2", Licenses: []string{"Apache-2.0"}},
+				{Type: "library", Group: "com.fasterxml.jackson.core", Name: "jackson-databind", Version: "2.16.0", Licenses: []string{"Apache-2.0 OR MIT"}},
+				{Type: "library", Name: "internal-lib", Version: "1.0", Licenses: []string{"Proprietary"}},
+			},
+		},
+		"xml": {
+			filename: "testdata/bom.xml",
+			expected: []types.SoftwareComponent{
+				{Type: "library", Group: "xerces", Name: "xercesImpl", Version: "2.12.2", PURL: "pkg:maven/xerces/xercesImpl@2.12.2", Licenses: []string{"Apache-2.0"}},
+				{Type: "library", Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			bom, err := LoadFile(testCase.filename)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, bom.SoftwareComponents())
+		})
+	}
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.json")
+	assert.Error(t, err)
+
+	filename := filepath.Join(t.TempDir(), "spdx.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"spdxVersion": "SPDX-2.3"}`), 0600))
+	_, err = LoadFile(filename)
+	assert.ErrorContains(t, err, "unexpected bomFormat")
+}
@@ -24,6 +24,25 @@ const (
 		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile -verbose -model -output app/work \n\n" +
 		"If you want to run Threagile as a server (REST API) on some port (here 8080):  \n" +
 		" docker run --rm -it --shm-size=256m  -p 8080:8080 --name --mount 'type=volume,src=threagile-storage,dst=/data,readonly=false' threagile/threagile server --server-port 8080 \n\n" +
+		"If you want to convert an Open Threat Model (OTM) file into a model yaml file (optionally translating unknown values via a mapping file): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportOTMCommand + " app/work/model.otm.json --mapping app/work/mapping.yaml -output app/work \n\n" +
+		"If you want to convert an OWASP Threat Dragon or Microsoft Threat Modeling Tool file into a model yaml stub (reporting everything that could not be mapped): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportThreatDragonCommand + " app/work/model.json -output app/work \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportTMTCommand + " app/work/model.tm7 -output app/work \n\n" +
+		"If you want to generate a model skeleton from docker compose files (guesses are tagged for review and noted as questions): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportDockerComposeCommand + " app/work/docker-compose.yml -output app/work \n\n" +
+		"If you want to generate a model skeleton from kubernetes manifests, or merge a re-import into an existing model without losing manual changes: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportKubernetesCommand + " app/work/manifests -output app/work \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportKubernetesCommand + " app/work/manifests --merge app/work/threagile.yaml -output app/work \n\n" +
+		"If you want to generate a cloud model skeleton from a terraform state or plan (terraform show -json > tf.json): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportTerraformCommand + " app/work/tf.json -output app/work \n\n" +
+		"If you want to derive data assets and a communication link from the OpenAPI spec of a technical asset (written as model include): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportOpenAPICommand + " app/work/openapi.yaml --target backend --client frontend -model app/work/threagile.yaml -output app/work \n\n" +
+		"If you want to compare observed network flows (VPC flow logs, Hubble json or csv) with the communication links of the model: \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.DetectDriftCommand + " app/work/flows.csv --mapping app/work/flow-mapping.yaml --generate-include -model app/work/threagile.yaml -output app/work \n\n" +
+		"If you want to file tickets for the unchecked risks and afterwards record their ticket IDs in the risk tracking of the model (from a Jira csv export): \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.AnalyzeModelCommand + " --generate-jira-csv -model app/work/threagile.yaml -output app/work \n" +
+		" docker run --rm -it -v \"$(pwd)\":app/work threagile/threagile " + common.ImportTicketsCommand + " app/work/jira-export.csv -model app/work/threagile.yaml -output app/work \n\n" +
 		"If you want to find out about the different enum values usable in the model yaml file: \n" +
 		" docker run --rm -it threagile/threagile " + common.ListTypesCommand + "\n\n" +
 		"If you want to use some nice editing help (syntax validation, autocompletion, and live templates) in your favourite IDE: " +
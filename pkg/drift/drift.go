@@ -0,0 +1,257 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// families of protocols, protocols of the same family can be spoken on the same ports
+var protocolFamilies = map[types.Protocol]string{
+	types.HTTP:                             "web",
+	types.WS:                               "web",
+	types.ReverseProxyWebProtocol:          "web",
+	types.HTTPS:                            "web-encrypted",
+	types.WSS:                              "web-encrypted",
+	types.ReverseProxyWebProtocolEncrypted: "web-encrypted",
+	types.JDBC:                             "sql",
+	types.ODBC:                             "sql",
+	types.SqlAccessProtocol:                "sql",
+	types.JdbcEncrypted:                    "sql-encrypted",
+	types.OdbcEncrypted:                    "sql-encrypted",
+	types.SqlAccessProtocolEncrypted:       "sql-encrypted",
+	types.NosqlAccessProtocol:              "nosql",
+	types.NosqlAccessProtocolEncrypted:     "nosql-encrypted",
+	types.SSH:                              "ssh",
+	types.SshTunnel:                        "ssh",
+	types.SFTP:                             "ssh",
+	types.SCP:                              "ssh",
+	types.SMTP:                             "smtp",
+	types.SmtpEncrypted:                    "smtp-encrypted",
+	types.POP3:                             "pop3",
+	types.Pop3Encrypted:                    "pop3-encrypted",
+	types.IMAP:                             "imap",
+	types.ImapEncrypted:                    "imap-encrypted",
+	types.FTP:                              "ftp",
+	types.FTPS:                             "ftps",
+	types.LDAP:                             "ldap",
+	types.LDAPS:                            "ldaps",
+	types.MQTT:                             "mqtt",
+	types.NFS:                              "nfs",
+	types.SMB:                              "smb",
+	types.SmbEncrypted:                     "smb-encrypted",
+}
+
+// Observation is an observed flow between two technical assets without a modelled communication link
+type Observation struct {
+	SourceId  string
+	TargetId  string
+	Ports     []string // like "8080/tcp"
+	Count     int
+	Protocol  types.Protocol // guessed from the port
+	Addresses []string
+}
+
+// Mismatch is a modelled communication link observed on a port of another protocol
+type Mismatch struct {
+	SourceId string
+	Link     types.CommunicationLink
+	Port     string
+	Observed types.Protocol
+}
+
+// Result is the drift between the model and the observed flows
+type Result struct {
+	Unmodelled []Observation
+	Unseen     []types.CommunicationLink
+	Mismatches []Mismatch
+	Unmapped   []string // endpoints of flows not mapped to a technical asset
+	Flows      int
+}
+
+// Detect compares the flows with the communication links of the model. Links are seen when a flow goes from the source
+// to the target (on any port). Only links between technical assets of the mapping can be seen, so only these are reported as unseen.
+func Detect(parsedModel *types.ParsedModel, flows []Flow, mapping Mapping) *Result {
+	result := &Result{Flows: len(flows)}
+
+	observable := make(map[string]bool)
+	for _, id := range mapping.TechnicalAssetIds() {
+		observable[id] = true
+	}
+
+	links := make(map[string][]types.CommunicationLink)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+			links[link.SourceId+">"+link.TargetId] = append(links[link.SourceId+">"+link.TargetId], link)
+		}
+	}
+
+	seen := make(map[string]bool)
+	unmapped := make(map[string]bool)
+	unmodelled := make(map[string]*Observation)
+	mismatches := make(map[string]bool)
+	for _, flow := range flows {
+		sourceId, sourceOk := mapping.Resolve(flow.Sources)
+		targetId, targetOk := mapping.Resolve(flow.Destinations)
+		if !sourceOk && len(flow.Sources) > 0 {
+			unmapped[flow.Sources[0]] = true
+		}
+		if !targetOk && len(flow.Destinations) > 0 {
+			unmapped[flow.Destinations[0]] = true
+		}
+		if !sourceOk || !targetOk || sourceId == targetId {
+			continue
+		}
+
+		port := strconv.Itoa(flow.Port) + "/" + flow.Transport
+		observed, known := importer.GuessProtocolFromPort(flow.Port)
+		pairLinks, modelled := links[sourceId+">"+targetId]
+		if !modelled {
+			key := sourceId + ">" + targetId
+			observation, ok := unmodelled[key]
+			if !ok {
+				observation = &Observation{SourceId: sourceId, TargetId: targetId, Protocol: observed}
+				unmodelled[key] = observation
+			}
+			if !contains(observation.Ports, port) {
+				observation.Ports = append(observation.Ports, port)
+			}
+			if address := flow.Sources[0] + " > " + flow.Destinations[0]; !contains(observation.Addresses, address) {
+				observation.Addresses = append(observation.Addresses, address)
+			}
+			observation.Count += flow.Count
+			continue
+		}
+
+		compatible := false
+		for _, link := range pairLinks {
+			seen[link.Id] = true
+			if family, ok := protocolFamilies[link.Protocol]; !known || !ok || family == protocolFamilies[observed] {
+				compatible = true
+			}
+		}
+		if !compatible {
+			for _, link := range pairLinks {
+				if key := link.Id + "@" + port; !mismatches[key] {
+					mismatches[key] = true
+					result.Mismatches = append(result.Mismatches, Mismatch{SourceId: sourceId, Link: link, Port: port, Observed: observed})
+				}
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(unmodelled) {
+		observation := unmodelled[key]
+		sort.Strings(observation.Ports)
+		sort.Strings(observation.Addresses)
+		result.Unmodelled = append(result.Unmodelled, *observation)
+	}
+	for _, key := range sortedKeys(links) {
+		for _, link := range links[key] {
+			if !seen[link.Id] && observable[link.SourceId] && observable[link.TargetId] && !link.Protocol.IsProcessLocal() {
+				result.Unseen = append(result.Unseen, link)
+			}
+		}
+	}
+	result.Unmapped = sortedKeys(unmapped)
+	return result
+}
+
+// IsEmpty tells whether the model matches the flows
+func (what *Result) IsEmpty() bool {
+	return len(what.Unmodelled) == 0 && len(what.Unseen) == 0 && len(what.Mismatches) == 0
+}
+
+func (what *Result) Write(writer io.Writer, parsedModel *types.ParsedModel) {
+	title := func(id string) string {
+		if technicalAsset, ok := parsedModel.TechnicalAssets[id]; ok {
+			return technicalAsset.Title
+		}
+		return id
+	}
+
+	_, _ = fmt.Fprintf(writer, "Compared %d flow(s) with the communication links of the model.\n", what.Flows)
+	if len(what.Unmodelled) > 0 {
+		_, _ = fmt.Fprintf(writer, "Observed but not modelled (%d):\n", len(what.Unmodelled))
+		for _, observation := range what.Unmodelled {
+			_, _ = fmt.Fprintf(writer, " - %v -> %v on %v (%d flows)\n", title(observation.SourceId), title(observation.TargetId),
+				strings.Join(observation.Ports, ", "), observation.Count)
+		}
+	}
+	if len(what.Unseen) > 0 {
+		_, _ = fmt.Fprintf(writer, "Modelled but never observed, candidates for removal (%d):\n", len(what.Unseen))
+		for _, link := range what.Unseen {
+			_, _ = fmt.Fprintf(writer, " - %v: %v -> %v (%v)\n", link.Title, title(link.SourceId), title(link.TargetId), link.Protocol.String())
+		}
+	}
+	if len(what.Mismatches) > 0 {
+		_, _ = fmt.Fprintf(writer, "Protocol mismatches (%d):\n", len(what.Mismatches))
+		for _, mismatch := range what.Mismatches {
+			_, _ = fmt.Fprintf(writer, " - %v: %v -> %v modelled as %v but observed on %v (%v)\n", mismatch.Link.Title, title(mismatch.SourceId),
+				title(mismatch.Link.TargetId), mismatch.Link.Protocol.String(), mismatch.Port, mismatch.Observed.String())
+		}
+	}
+	if len(what.Unmapped) > 0 {
+		_, _ = fmt.Fprintf(writer, "Not mapped to a technical asset (%d), add them to the mapping file to compare their flows: %v\n",
+			len(what.Unmapped), strings.Join(what.Unmapped, ", "))
+	}
+	if what.IsEmpty() {
+		_, _ = fmt.Fprintln(writer, "No drift detected.")
+	}
+}
+
+// Include returns a model include with a communication link for each observed but not modelled flow, tagged for review
+func (what *Result) Include(parsedModel *types.ParsedModel) *input.Model {
+	include := &input.Model{
+		Questions:       make(map[string]string),
+		TechnicalAssets: make(map[string]input.TechnicalAsset),
+	}
+	for _, observation := range what.Unmodelled {
+		source, target := parsedModel.TechnicalAssets[observation.SourceId], parsedModel.TechnicalAssets[observation.TargetId]
+		protocol := observation.Protocol
+		if protocol == types.UnknownProtocol {
+			importer.AddQuestion(include, fmt.Sprintf("Which protocol is used from '%v' to '%v' on %v?", source.Title, target.Title,
+				strings.Join(observation.Ports, ", ")), "")
+		}
+
+		link := importer.NewCommunicationLink(target.Id, fmt.Sprintf("Observed on %v (%d flows: %v)", strings.Join(observation.Ports, ", "),
+			observation.Count, strings.Join(observation.Addresses, ", ")), protocol)
+		link.Tags = []string{importer.ReviewTag}
+
+		asset := include.TechnicalAssets[source.Title]
+		if asset.CommunicationLinks == nil {
+			asset.CommunicationLinks = make(map[string]input.CommunicationLink)
+		}
+		asset.CommunicationLinks[importer.UniqueKey(asset.CommunicationLinks, "Observed traffic to "+target.Title)] = link
+		include.TechnicalAssets[source.Title] = asset
+	}
+	if len(what.Unmodelled) > 0 {
+		include.AddTagToModelInput(importer.ReviewTag, false, new([]string))
+		importer.AddQuestion(include, importer.DataAssetsQuestion, "")
+	}
+	return include
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,212 @@
+package drift
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type loadFlowsTest struct {
+	filenames []string
+	expected  []Flow
+}
+
+func TestLoadFlows(t *testing.T) {
+	testCases := map[string]loadFlowsTest{
+		"csv with header": {
+			filenames: []string{"testdata/flows.csv"},
+			expected: []Flow{
+				{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.2.5"}, Port: 5432, Transport: "tcp", Count: 2},
+				{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.3.9"}, Port: 22, Transport: "tcp", Count: 1},
+			},
+		},
+		"hubble json lines": {
+			filenames: []string{"testdata/hubble.json"},
+			expected: []Flow{
+				{Sources: []string{"shop/webshop", "webshop", "shop/webshop-5d8f-abc", "webshop-5d8f-abc", "10.1.0.4"},
+					Destinations: []string{"shop/api-0", "api-0", "10.1.0.8"}, Port: 8080, Transport: "tcp", Count: 1},
+				{Sources: []string{"10.1.0.4"}, Destinations: []string{"kube-dns.kube-system.svc.cluster.local", "10.1.0.10"}, Port: 53, Transport: "udp", Count: 1},
+			},
+		},
+		"vpc flow log with both directions": {
+			filenames: []string{"testdata/vpc-flow.log"},
+			expected: []Flow{
+				{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.2.5"}, Port: 5432, Transport: "tcp", Count: 2},
+			},
+		},
+		"aggregated over files": {
+			filenames: []string{"testdata/flows.csv", "testdata/vpc-flow.log"},
+			expected: []Flow{
+				{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.2.5"}, Port: 5432, Transport: "tcp", Count: 4},
+				{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.3.9"}, Port: 22, Transport: "tcp", Count: 1},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			flows, err := LoadFlows(testCase.filenames)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, flows)
+		})
+	}
+}
+
+func TestLoadFlowsFails(t *testing.T) {
+	_, err := LoadFlows([]string{"testdata/missing.csv"})
+	assert.Error(t, err)
+
+	_, err = parseCSV([]byte("src,dst,port\n10.0.0.1,10.0.0.2,http\n"))
+	assert.ErrorContains(t, err, "invalid port")
+
+	_, err = parseCSV([]byte("from,to,port\n10.0.0.1,10.0.0.2,80\n"))
+	assert.ErrorContains(t, err, "missing column")
+
+	_, err = parseFlowLog([]byte("version srcaddr dstport\n"))
+	assert.ErrorContains(t, err, "header without srcaddr, dstaddr and dstport")
+}
+
+type resolveTest struct {
+	names    []string
+	expected string
+	ok       bool
+}
+
+func TestMappingResolve(t *testing.T) {
+	mapping := Mapping{
+		"10.0.1.17":      "webshop",
+		"10.0.0.0/16":    "vpc",
+		"10.0.2.0/24":    "database",
+		"shop/webshop-*": "webshop",
+		"DB.example.com": "database",
+	}
+	testCases := map[string]resolveTest{
+		"exact ip": {
+			names:    []string{"10.0.1.17"},
+			expected: "webshop",
+			ok:       true,
+		},
+		"longest cidr": {
+			names:    []string{"10.0.2.5"},
+			expected: "database",
+			ok:       true,
+		},
+		"shorter cidr": {
+			names:    []string{"10.0.3.9"},
+			expected: "vpc",
+			ok:       true,
+		},
+		"pattern": {
+			names:    []string{"shop/webshop-5d8f-abc"},
+			expected: "webshop",
+			ok:       true,
+		},
+		"name ignoring case": {
+			names:    []string{"db.example.com"},
+			expected: "database",
+			ok:       true,
+		},
+		"first name wins": {
+			names:    []string{"unknown", "10.0.2.5", "10.0.1.17"},
+			expected: "database",
+			ok:       true,
+		},
+		"unmapped": {
+			names: []string{"192.168.0.1", "other"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := mapping.Resolve(testCase.names)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func driftModel() *types.ParsedModel {
+	link := func(source string, target string, title string, protocol types.Protocol) types.CommunicationLink {
+		return types.CommunicationLink{Id: source + ">" + title, Title: title, SourceId: source, TargetId: target, Protocol: protocol}
+	}
+	return &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{
+		"webshop": {Id: "webshop", Title: "Webshop", CommunicationLinks: []types.CommunicationLink{
+			link("webshop", "database", "orders", types.NosqlAccessProtocol),
+			link("webshop", "backup", "backup", types.SFTP),
+			link("webshop", "ldap", "ldap", types.LDAPS)}},
+		"database": {Id: "database", Title: "Database"},
+		"backup":   {Id: "backup", Title: "Backup Server"},
+		"ldap":     {Id: "ldap", Title: "Directory"},
+		"admin":    {Id: "admin", Title: "Admin Host"},
+	}}
+}
+
+func TestDetect(t *testing.T) {
+	mapping := Mapping{"10.0.1.17": "webshop", "10.0.2.5": "database", "10.0.3.9": "admin", "10.0.4.1": "backup"}
+	flows := []Flow{
+		{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.2.5"}, Port: 5432, Transport: "tcp", Count: 3},
+		{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.3.9"}, Port: 22, Transport: "tcp", Count: 1},
+		{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.3.9"}, Port: 2222, Transport: "tcp", Count: 2},
+		{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.1.17"}, Port: 8080, Transport: "tcp", Count: 1},
+		{Sources: []string{"192.168.0.1"}, Destinations: []string{"10.0.1.17"}, Port: 443, Transport: "tcp", Count: 1},
+	}
+
+	result := Detect(driftModel(), flows, mapping)
+
+	assert.Equal(t, 5, result.Flows)
+	assert.Equal(t, []Observation{{SourceId: "webshop", TargetId: "admin", Ports: []string{"22/tcp", "2222/tcp"}, Count: 3,
+		Protocol: types.SSH, Addresses: []string{"10.0.1.17 > 10.0.3.9"}}}, result.Unmodelled)
+	require.Len(t, result.Mismatches, 1)
+	assert.Equal(t, "webshop>orders", result.Mismatches[0].Link.Id)
+	assert.Equal(t, "5432/tcp", result.Mismatches[0].Port)
+	require.Len(t, result.Unseen, 1, "links to unmapped technical assets can't be seen")
+	assert.Equal(t, "webshop>backup", result.Unseen[0].Id)
+	assert.Equal(t, []string{"192.168.0.1"}, result.Unmapped)
+	assert.False(t, result.IsEmpty())
+
+	var output bytes.Buffer
+	result.Write(&output, driftModel())
+	assert.Contains(t, output.String(), " - Webshop -> Admin Host on 22/tcp, 2222/tcp (3 flows)\n")
+	assert.Contains(t, output.String(), " - backup: Webshop -> Backup Server (sftp)\n")
+	assert.Contains(t, output.String(), " - orders: Webshop -> Database modelled as nosql-access-protocol but observed on 5432/tcp")
+	assert.NotContains(t, output.String(), "No drift detected.")
+}
+
+func TestDetectWithoutDrift(t *testing.T) {
+	mapping := Mapping{"10.0.1.17": "webshop", "10.0.2.5": "database"}
+	flows := []Flow{{Sources: []string{"10.0.1.17"}, Destinations: []string{"10.0.2.5"}, Port: 27017, Transport: "tcp", Count: 1}}
+
+	result := Detect(driftModel(), flows, mapping)
+
+	assert.True(t, result.IsEmpty())
+	var output bytes.Buffer
+	result.Write(&output, driftModel())
+	assert.Contains(t, output.String(), "No drift detected.")
+	assert.Empty(t, result.Include(driftModel()).TechnicalAssets)
+}
+
+func TestInclude(t *testing.T) {
+	result := &Result{Unmodelled: []Observation{
+		{SourceId: "webshop", TargetId: "admin", Ports: []string{"22/tcp"}, Count: 1, Protocol: types.SSH, Addresses: []string{"10.0.1.17 > 10.0.3.9"}},
+		{SourceId: "webshop", TargetId: "database", Ports: []string{"9999/tcp"}, Count: 2, Addresses: []string{"10.0.1.17 > 10.0.2.5"}},
+	}}
+
+	include := result.Include(driftModel())
+
+	require.Contains(t, include.TechnicalAssets, "Webshop")
+	links := include.TechnicalAssets["Webshop"].CommunicationLinks
+	require.Contains(t, links, "Observed traffic to Admin Host")
+	assert.Equal(t, "admin", links["Observed traffic to Admin Host"].Target)
+	assert.Equal(t, types.SSH.String(), links["Observed traffic to Admin Host"].Protocol)
+	assert.Equal(t, []string{importer.ReviewTag}, links["Observed traffic to Admin Host"].Tags)
+	assert.Equal(t, "Observed on 9999/tcp (2 flows: 10.0.1.17 > 10.0.2.5)", links["Observed traffic to Database"].Description)
+	assert.Contains(t, include.Questions, "Which protocol is used from 'Webshop' to 'Database' on 9999/tcp?")
+	assert.Equal(t, []string{importer.ReviewTag}, include.TagsAvailable)
+}
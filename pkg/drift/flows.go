@@ -0,0 +1,303 @@
+package drift
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Flow is a connection observed from a source to a port of a destination, aggregated over all observations
+type Flow struct {
+	Sources      []string // addresses and names of the source, most specific first
+	Destinations []string // addresses and names of the destination, most specific first
+	Port         int
+	Transport    string // tcp, udp, ...
+	Count        int
+}
+
+func (what Flow) key() string {
+	return strings.Join(what.Sources, ",") + ">" + strings.Join(what.Destinations, ",") + ":" + strconv.Itoa(what.Port) + "/" + what.Transport
+}
+
+// IANA protocol numbers of VPC flow logs
+var transportsOfProtocolNumbers = map[string]string{
+	"1":  "icmp",
+	"6":  "tcp",
+	"17": "udp",
+	"58": "icmpv6",
+}
+
+// fields of the default format (version 2) of VPC flow logs
+var defaultFlowLogFields = []string{"version", "account-id", "interface-id", "srcaddr", "dstaddr", "srcport", "dstport", "protocol",
+	"packets", "bytes", "start", "end", "action", "log-status"}
+
+// LoadFlows reads network flow exports: csv files (src,dst,port and optionally protocol, with or without header),
+// Hubble/Cilium flows as json lines or array (hubble observe -o json) and VPC flow logs (default or custom format with header)
+func LoadFlows(filenames []string) ([]Flow, error) {
+	flows := make([]Flow, 0)
+	index := make(map[string]int)
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read flows %q: %w", filename, err)
+		}
+
+		var fileFlows []Flow
+		trimmed := bytes.TrimSpace(data)
+		switch {
+		case strings.EqualFold(filepath.Ext(filename), ".csv"):
+			fileFlows, err = parseCSV(data)
+		case bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")):
+			fileFlows, err = parseHubble(data)
+		default:
+			fileFlows, err = parseFlowLog(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse flows %q: %w", filename, err)
+		}
+
+		for _, flow := range fileFlows {
+			if existing, ok := index[flow.key()]; ok {
+				flows[existing].Count += flow.Count
+				continue
+			}
+			index[flow.key()] = len(flows)
+			flows = append(flows, flow)
+		}
+	}
+	return flows, nil
+}
+
+func parseCSV(data []byte) ([]Flow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{"src": 0, "dst": 1, "port": 2, "protocol": 3}
+	if len(records) > 0 && len(records[0]) > 2 {
+		if _, err := strconv.Atoi(records[0][2]); err != nil {
+			columns = make(map[string]int)
+			for i, name := range records[0] {
+				switch strings.ToLower(strings.TrimSpace(name)) {
+				case "src", "source", "srcaddr", "source_address":
+					columns["src"] = i
+				case "dst", "destination", "dstaddr", "destination_address":
+					columns["dst"] = i
+				case "port", "dport", "dstport", "destination_port":
+					columns["port"] = i
+				case "protocol", "proto", "transport":
+					columns["protocol"] = i
+				}
+			}
+			records = records[1:]
+			for _, name := range []string{"src", "dst", "port"} {
+				if _, ok := columns[name]; !ok {
+					return nil, fmt.Errorf("missing column %q", name)
+				}
+			}
+		}
+	}
+
+	flows := make([]Flow, 0)
+	for i, record := range records {
+		field := func(name string) string {
+			if index, ok := columns[name]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		port, err := strconv.Atoi(field("port"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid port %q", i+1, field("port"))
+		}
+		transport := strings.ToLower(field("protocol"))
+		if len(transport) == 0 {
+			transport = "tcp"
+		}
+		flows = append(flows, Flow{Sources: []string{field("src")}, Destinations: []string{field("dst")}, Port: port, Transport: transport, Count: 1})
+	}
+	return flows, nil
+}
+
+func parseFlowLog(data []byte) ([]Flow, error) {
+	fields := defaultFlowLogFields
+	position := func(name string) int {
+		for i, field := range fields {
+			if field == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	flows := make([]Flow, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		values := strings.Fields(scanner.Text())
+		if len(values) == 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(values[0]); err != nil { // header of custom formats
+			fields = values
+			if position("srcaddr") < 0 || position("dstaddr") < 0 || position("dstport") < 0 {
+				return nil, fmt.Errorf("line %d: header without srcaddr, dstaddr and dstport", line)
+			}
+			continue
+		}
+
+		value := func(name string) string {
+			if index := position(name); index >= 0 && index < len(values) && values[index] != "-" {
+				return values[index]
+			}
+			return ""
+		}
+		if value("action") == "REJECT" || len(value("srcaddr")) == 0 || len(value("dstaddr")) == 0 {
+			continue
+		}
+		port, err := strconv.Atoi(value("dstport"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid dstport %q", line, value("dstport"))
+		}
+		sourcePort, _ := strconv.Atoi(value("srcport"))
+		transport, ok := transportsOfProtocolNumbers[value("protocol")]
+		if !ok {
+			transport = value("protocol")
+		}
+
+		flow := Flow{Sources: []string{value("srcaddr")}, Destinations: []string{value("dstaddr")}, Port: port, Transport: transport, Count: 1}
+		// flow logs record both directions of a connection: responses go from the server port to an ephemeral port
+		if sourcePort > 0 && sourcePort < port && port >= 32768 {
+			flow.Sources, flow.Destinations, flow.Port = flow.Destinations, flow.Sources, sourcePort
+		}
+		flows = append(flows, flow)
+	}
+	return flows, scanner.Err()
+}
+
+// Hubble flows (https://github.com/cilium/cilium/blob/main/api/v1/flow/README.md), only the parts needed for drift detection
+
+type hubbleEvent struct {
+	Flow *hubbleFlow `json:"flow"`
+	hubbleFlow
+}
+
+type hubbleFlow struct {
+	Verdict          string         `json:"verdict"`
+	IsReply          bool           `json:"is_reply"`
+	IP               *hubbleIP      `json:"IP"`
+	L4               *hubbleL4      `json:"l4"`
+	Source           hubbleEndpoint `json:"source"`
+	Destination      hubbleEndpoint `json:"destination"`
+	SourceNames      []string       `json:"source_names"`
+	DestinationNames []string       `json:"destination_names"`
+}
+
+type hubbleIP struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+type hubbleL4 struct {
+	TCP  *hubblePorts `json:"TCP"`
+	UDP  *hubblePorts `json:"UDP"`
+	SCTP *hubblePorts `json:"SCTP"`
+}
+
+type hubblePorts struct {
+	DestinationPort int `json:"destination_port"`
+}
+
+type hubbleEndpoint struct {
+	Namespace string `json:"namespace"`
+	PodName   string `json:"pod_name"`
+	Workloads []struct {
+		Name string `json:"name"`
+	} `json:"workloads"`
+}
+
+func parseHubble(data []byte) ([]Flow, error) {
+	events := make([]hubbleEvent, 0)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var event hubbleEvent
+			err := decoder.Decode(&event)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+	}
+
+	flows := make([]Flow, 0)
+	for _, event := range events {
+		flow := event.hubbleFlow
+		if event.Flow != nil {
+			flow = *event.Flow
+		}
+		if flow.IsReply || flow.L4 == nil || (flow.Verdict != "" && flow.Verdict != "FORWARDED") {
+			continue
+		}
+
+		observed := Flow{Count: 1}
+		for transport, ports := range map[string]*hubblePorts{"tcp": flow.L4.TCP, "udp": flow.L4.UDP, "sctp": flow.L4.SCTP} {
+			if ports != nil {
+				observed.Transport, observed.Port = transport, ports.DestinationPort
+			}
+		}
+		if len(observed.Transport) == 0 {
+			continue
+		}
+
+		ip := hubbleIP{}
+		if flow.IP != nil {
+			ip = *flow.IP
+		}
+		observed.Sources = flow.Source.names(ip.Source, flow.SourceNames)
+		observed.Destinations = flow.Destination.names(ip.Destination, flow.DestinationNames)
+		flows = append(flows, observed)
+	}
+	return flows, nil
+}
+
+// names returns "namespace/workload", "workload", "namespace/pod", "pod", dns names and ip of an endpoint (as far as known)
+func (what hubbleEndpoint) names(ip string, dnsNames []string) []string {
+	names := make([]string, 0)
+	for _, workload := range what.Workloads {
+		if len(what.Namespace) > 0 {
+			names = append(names, what.Namespace+"/"+workload.Name)
+		}
+		names = append(names, workload.Name)
+	}
+	if len(what.PodName) > 0 {
+		if len(what.Namespace) > 0 {
+			names = append(names, what.Namespace+"/"+what.PodName)
+		}
+		names = append(names, what.PodName)
+	}
+	names = append(names, dnsNames...)
+	if len(ip) > 0 {
+		names = append(names, ip)
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package drift
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping assigns addresses and names of flows to technical assets (yaml): keys are IPs, hostnames or kubernetes names
+// ("namespace/workload"), CIDRs (like "10.0.1.0/24") or patterns (like "shop/webshop-*"), e.g.
+//
+//	10.0.1.17: apache-webserver
+//	10.0.2.0/24: erp-system
+//	shop/webshop-*: apache-webserver
+//	db.example.com: sql-database
+type Mapping map[string]string
+
+func LoadMapping(filename string) (Mapping, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read flow mapping file %q: %w", filename, err)
+	}
+
+	mapping := make(Mapping)
+	err = yaml.Unmarshal(data, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse flow mapping file %q: %w", filename, err)
+	}
+	return mapping, nil
+}
+
+// TechnicalAssetIds returns the technical asset ids the mapping refers to
+func (what Mapping) TechnicalAssetIds() []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, id := range what {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Resolve returns the technical asset of the first name matching: exact matches win over the longest matching CIDR,
+// which wins over patterns
+func (what Mapping) Resolve(names []string) (string, bool) {
+	keys := make([]string, 0, len(what))
+	for key := range what {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, name := range names {
+		for _, key := range keys {
+			if strings.EqualFold(key, name) {
+				return what[key], true
+			}
+		}
+
+		if ip := net.ParseIP(name); ip != nil {
+			technicalAssetId, longest := "", -1
+			for _, key := range keys {
+				_, network, err := net.ParseCIDR(key)
+				if err != nil || !network.Contains(ip) {
+					continue
+				}
+				if size, _ := network.Mask.Size(); size > longest {
+					technicalAssetId, longest = what[key], size
+				}
+			}
+			if longest >= 0 {
+				return technicalAssetId, true
+			}
+		}
+
+		for _, key := range keys {
+			if !strings.Contains(key, "*") {
+				continue
+			}
+			if matched, err := path.Match(strings.ToLower(key), strings.ToLower(name)); err == nil && matched {
+				return what[key], true
+			}
+		}
+	}
+	return "", false
+}
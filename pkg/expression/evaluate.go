@@ -0,0 +1,401 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type node interface {
+	evaluate(environment Environment) (any, error)
+	variables(found map[string]bool)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (what *literalNode) evaluate(Environment) (any, error) {
+	return what.value, nil
+}
+
+func (what *literalNode) variables(map[string]bool) {
+}
+
+type variableNode struct {
+	path string
+}
+
+func (what *variableNode) evaluate(environment Environment) (any, error) {
+	value, err := lookup(environment, what.path)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case []string:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+func (what *variableNode) variables(found map[string]bool) {
+	found[what.path] = true
+}
+
+type listNode struct {
+	items []node
+}
+
+func (what *listNode) evaluate(environment Environment) (any, error) {
+	items := make([]any, 0, len(what.items))
+	for _, item := range what.items {
+		value, err := item.evaluate(environment)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+func (what *listNode) variables(found map[string]bool) {
+	for _, item := range what.items {
+		item.variables(found)
+	}
+}
+
+type ternaryNode struct {
+	condition node
+	then      node
+	otherwise node
+}
+
+func (what *ternaryNode) evaluate(environment Environment) (any, error) {
+	condition, err := what.condition.evaluate(environment)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(condition) {
+		return what.then.evaluate(environment)
+	}
+	return what.otherwise.evaluate(environment)
+}
+
+func (what *ternaryNode) variables(found map[string]bool) {
+	what.condition.variables(found)
+	what.then.variables(found)
+	what.otherwise.variables(found)
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+func (what *unaryNode) evaluate(environment Environment) (any, error) {
+	value, err := what.operand.evaluate(environment)
+	if err != nil {
+		return nil, err
+	}
+	if what.operator == "!" {
+		return !truthy(value), nil
+	}
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("unable to negate %v", describe(value))
+	}
+	return -number, nil
+}
+
+func (what *unaryNode) variables(found map[string]bool) {
+	what.operand.variables(found)
+}
+
+type binaryNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (what *binaryNode) evaluate(environment Environment) (any, error) {
+	left, err := what.left.evaluate(environment)
+	if err != nil {
+		return nil, err
+	}
+	switch what.operator {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+	}
+	right, err := what.right.evaluate(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	switch what.operator {
+	case "&&", "||":
+		return truthy(right), nil
+	case "==", "!=":
+		equal, err := equals(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (what.operator == "=="), nil
+	case "<", "<=", ">", ">=":
+		comparison, err := compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch what.operator {
+		case "<":
+			return comparison < 0, nil
+		case "<=":
+			return comparison <= 0, nil
+		case ">":
+			return comparison > 0, nil
+		}
+		return comparison >= 0, nil
+	case "in", "not in":
+		contained, err := in(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return contained == (what.operator == "in"), nil
+	case "+":
+		return add(left, right)
+	case "-":
+		leftNumber, leftOk := left.(float64)
+		rightNumber, rightOk := right.(float64)
+		if !leftOk || !rightOk {
+			return nil, fmt.Errorf("unable to subtract %v from %v", describe(right), describe(left))
+		}
+		return leftNumber - rightNumber, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", what.operator)
+}
+
+func (what *binaryNode) variables(found map[string]bool) {
+	what.left.variables(found)
+	what.right.variables(found)
+}
+
+type function struct {
+	arguments int
+	call      func(arguments []any) (any, error)
+}
+
+var functions = map[string]function{
+	"len": {1, func(arguments []any) (any, error) {
+		switch v := arguments[0].(type) {
+		case []any:
+			return float64(len(v)), nil
+		case string:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("unable to get the length of %v", describe(arguments[0]))
+	}},
+	"lower": {1, func(arguments []any) (any, error) {
+		return strings.ToLower(toString(arguments[0])), nil
+	}},
+	"upper": {1, func(arguments []any) (any, error) {
+		return strings.ToUpper(toString(arguments[0])), nil
+	}},
+	"starts_with": {2, func(arguments []any) (any, error) {
+		return strings.HasPrefix(toString(arguments[0]), toString(arguments[1])), nil
+	}},
+	"ends_with": {2, func(arguments []any) (any, error) {
+		return strings.HasSuffix(toString(arguments[0]), toString(arguments[1])), nil
+	}},
+	"contains": {2, func(arguments []any) (any, error) {
+		return in(arguments[1], arguments[0])
+	}},
+}
+
+type callNode struct {
+	name      string
+	arguments []node
+}
+
+func (what *callNode) evaluate(environment Environment) (any, error) {
+	arguments := make([]any, 0, len(what.arguments))
+	for _, argument := range what.arguments {
+		value, err := argument.evaluate(environment)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+	return functions[what.name].call(arguments)
+}
+
+func (what *callNode) variables(found map[string]bool) {
+	for _, argument := range what.arguments {
+		argument.variables(found)
+	}
+}
+
+// enumOrdinal returns the ordinal of the string within the enum or an error if it is none of its names
+func enumOrdinal(enum Enum, value string) (int, error) {
+	for i, name := range enum.Names {
+		if name == value {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown value %q, expected one of: %v", value, strings.Join(enum.Names, ", "))
+}
+
+func equals(left any, right any) (bool, error) {
+	switch l := left.(type) {
+	case Enum:
+		switch r := right.(type) {
+		case string:
+			if _, err := enumOrdinal(l, r); err != nil {
+				return false, err
+			}
+			return l.Name == r, nil
+		case Enum:
+			return l.Name == r.Name, nil
+		}
+	case string:
+		if _, ok := right.(Enum); ok {
+			return equals(right, left)
+		}
+	case []any:
+		r, ok := right.([]any)
+		if !ok || len(l) != len(r) {
+			return false, nil
+		}
+		for i := range l {
+			equal, err := equals(l[i], r[i])
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	if !isComparable(left) || !isComparable(right) {
+		return false, fmt.Errorf("unable to compare %v with %v", describe(left), describe(right))
+	}
+	return left == right, nil // values of different types (including enums and lists) are never equal
+}
+
+// isComparable tells whether the value is one of the types of the expression language, as others (e.g. maps) might panic with ==
+func isComparable(value any) bool {
+	switch value.(type) {
+	case bool, float64, string, Enum, []any, nil:
+		return true
+	}
+	return false
+}
+
+func compare(left any, right any) (int, error) {
+	switch l := left.(type) {
+	case Enum:
+		leftOrdinal := l.Ordinal()
+		var rightOrdinal int
+		switch r := right.(type) {
+		case string:
+			ordinal, err := enumOrdinal(l, r)
+			if err != nil {
+				return 0, err
+			}
+			rightOrdinal = ordinal
+		case Enum:
+			ordinal, err := enumOrdinal(l, r.Name)
+			if err != nil {
+				return 0, err
+			}
+			rightOrdinal = ordinal
+		default:
+			return 0, fmt.Errorf("unable to compare %v with %v", describe(left), describe(right))
+		}
+		return leftOrdinal - rightOrdinal, nil
+	case string:
+		switch r := right.(type) {
+		case Enum:
+			comparison, err := compare(r, l)
+			return -comparison, err
+		case string:
+			return strings.Compare(l, r), nil
+		}
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("unable to compare %v with %v", describe(left), describe(right))
+}
+
+func in(item any, container any) (bool, error) {
+	switch c := container.(type) {
+	case []any:
+		for _, candidate := range c {
+			equal, err := equals(candidate, item)
+			if err != nil {
+				return false, err
+			}
+			if equal {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		return strings.Contains(c, toString(item)), nil
+	}
+	return false, fmt.Errorf("unable to look for %v in %v", describe(item), describe(container))
+}
+
+func add(left any, right any) (any, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return l + r, nil
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return append(append(make([]any, 0, len(l)+len(r)), l...), r...), nil
+		}
+	}
+	_, leftList := left.([]any)
+	_, rightList := right.([]any)
+	if leftList || rightList {
+		return nil, fmt.Errorf("unable to add %v and %v", describe(left), describe(right))
+	}
+	return toString(left) + toString(right), nil
+}
+
+func describe(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case float64:
+		return fmt.Sprintf("number %v", v)
+	case bool:
+		return fmt.Sprintf("boolean %v", v)
+	case Enum:
+		return fmt.Sprintf("value %q", v.Name)
+	case []any:
+		return fmt.Sprintf("list [%v]", toString(v))
+	case Environment, map[string]any:
+		return "object"
+	case nil:
+		return "nothing"
+	}
+	return fmt.Sprintf("%v", value)
+}
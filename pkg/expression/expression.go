@@ -0,0 +1,171 @@
+/*
+Package expression implements the small expression language of declarative risk rules, e.g.
+
+	asset.technology == "database" && !asset.encryption && asset.confidentiality >= "confidential"
+
+Values are booleans, numbers, strings, lists and enums. Enums compare by their position also against the names of
+the other values of the same enum. The operators are (by increasing precedence) ?:, ||, &&, the comparisons
+==, !=, <, <=, >, >=, in and "not in", + (adding numbers, concatenating strings and lists) and the unary ! and -.
+Lists are written as [a, b] and the functions len, lower, upper, starts_with, ends_with and contains are available.
+*/
+package expression
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Enum is a value out of an ordered list of names
+type Enum struct {
+	Name  string
+	Names []string
+}
+
+func (what Enum) Ordinal() int {
+	for i, name := range what.Names {
+		if name == what.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (what Enum) String() string {
+	return what.Name
+}
+
+// Environment maps the variables usable in expressions to their values,
+// nested values are maps themselves (accessed with a dot)
+type Environment map[string]any
+
+type Expression struct {
+	source string
+	root   node
+}
+
+func Compile(source string) (*Expression, error) {
+	p := &parser{source: source}
+	tokenizeError := p.tokenize()
+	if tokenizeError != nil {
+		return nil, tokenizeError
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	root, parseError := p.parseTernary()
+	if parseError != nil {
+		return nil, parseError
+	}
+	if p.position < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.position].text)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (what *Expression) String() string {
+	return what.source
+}
+
+// Variables returns the sorted (dotted) paths of all variables the expression refers to
+func (what *Expression) Variables() []string {
+	found := make(map[string]bool)
+	what.root.variables(found)
+	variables := make([]string, 0, len(found))
+	for variable := range found {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// Check returns an error for the first variable of the expression not defined in the environment
+func (what *Expression) Check(environment Environment) error {
+	for _, variable := range what.Variables() {
+		if _, err := lookup(environment, variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (what *Expression) Evaluate(environment Environment) (any, error) {
+	value, err := what.root.evaluate(environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %q: %w", what.source, err)
+	}
+	return value, nil
+}
+
+func (what *Expression) EvaluateBool(environment Environment) (bool, error) {
+	value, err := what.Evaluate(environment)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+func (what *Expression) EvaluateString(environment Environment) (string, error) {
+	value, err := what.Evaluate(environment)
+	if err != nil {
+		return "", err
+	}
+	return toString(value), nil
+}
+
+func lookup(environment Environment, path string) (any, error) {
+	var value any = environment
+	for _, name := range strings.Split(path, ".") {
+		values, ok := value.(Environment)
+		if !ok {
+			values, ok = value.(map[string]any)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", path)
+		}
+		value, ok = values[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q", path)
+		}
+	}
+	return value, nil
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return len(v) > 0
+	case []any:
+		return len(v) > 0
+	case Enum:
+		return v.Ordinal() > 0
+	case nil:
+		return false
+	}
+	return true
+}
+
+func toString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case Enum:
+		return v.Name
+	case float64:
+		return fmt.Sprintf("%v", v)
+	case []any:
+		texts := make([]string, 0, len(v))
+		for _, item := range v {
+			texts = append(texts, toString(item))
+		}
+		return strings.Join(texts, ", ")
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,202 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEnvironment() Environment {
+	confidentiality := []string{"public", "internal", "restricted", "confidential", "strictly-confidential"}
+	return Environment{
+		"asset": map[string]any{
+			"id":              "web",
+			"internet":        true,
+			"raa":             42.5,
+			"replicas":        3,
+			"tags":            []string{"aws", "linux"},
+			"confidentiality": Enum{Name: "confidential", Names: confidentiality},
+			"integrity":       Enum{Name: "public", Names: confidentiality},
+			"nested":          map[string]any{"name": "inner"},
+		},
+	}
+}
+
+type evaluateTest struct {
+	source   string
+	expected any
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := map[string]evaluateTest{
+		"number":                {source: "1.5", expected: 1.5},
+		"string":                {source: `'it\'s'`, expected: "it's"},
+		"variable":              {source: "asset.id", expected: "web"},
+		"nested variable":       {source: "asset.nested.name", expected: "inner"},
+		"integer variable":      {source: "asset.replicas", expected: 3.0},
+		"string list variable":  {source: "asset.tags", expected: []any{"aws", "linux"}},
+		"and":                   {source: "asset.internet && asset.raa > 40", expected: true},
+		"or short circuit":      {source: "true || unknown", expected: true},
+		"and short circuit":     {source: "false && unknown", expected: false},
+		"not":                   {source: "!asset.internet", expected: false},
+		"negate":                {source: "-asset.raa", expected: -42.5},
+		"precedence":            {source: "1 + 2 == 3 && 2 - 1 < 2", expected: true},
+		"ternary":               {source: "asset.raa >= 50 ? 'high' : asset.raa >= 20 ? 'medium' : 'low'", expected: "medium"},
+		"enum equals string":    {source: "asset.confidentiality == 'confidential'", expected: true},
+		"string equals enum":    {source: "'internal' != asset.confidentiality", expected: true},
+		"enum greater":          {source: "asset.confidentiality >= 'restricted'", expected: true},
+		"string less than enum": {source: "'strictly-confidential' < asset.confidentiality", expected: false},
+		"enums":                 {source: "asset.integrity < asset.confidentiality", expected: true},
+		"strings":               {source: "'abc' < 'abd'", expected: true},
+		"different types":       {source: "asset.id == 1", expected: false},
+		"list equals":           {source: "asset.tags == ['aws', 'linux']", expected: true},
+		"list differs":          {source: "asset.tags == ['aws']", expected: false},
+		"list and value":        {source: "'aws' == asset.tags", expected: false},
+		"in list":               {source: "'linux' in asset.tags", expected: true},
+		"not in list":           {source: "'windows' not in asset.tags", expected: true},
+		"enum in list":          {source: "asset.confidentiality in ['confidential', 'strictly-confidential']", expected: true},
+		"in string":             {source: "'eb' in asset.id", expected: true},
+		"add numbers":           {source: "asset.raa + 0.5", expected: 43.0},
+		"concatenate":           {source: "asset.id + '-' + asset.confidentiality", expected: "web-confidential"},
+		"concatenate lists":     {source: "asset.tags + ['arm']", expected: []any{"aws", "linux", "arm"}},
+		"len":                   {source: "len(asset.tags) + len('abc')", expected: 5.0},
+		"lower and upper":       {source: "lower('AbC') + upper('d')", expected: "abcD"},
+		"starts and ends with":  {source: "starts_with(asset.id, 'w') && ends_with(asset.id, 'b')", expected: true},
+		"contains":              {source: "contains(asset.tags, 'aws')", expected: true},
+		"empty list":            {source: "[]", expected: []any{}},
+		"parentheses":           {source: "(1 + 2) - (3 - 1)", expected: 1.0},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			compiled, err := Compile(testCase.source)
+			require.NoError(t, err)
+			value, err := compiled.Evaluate(testEnvironment())
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, value)
+		})
+	}
+}
+
+type failTest struct {
+	source   string
+	expected string
+}
+
+func TestCompileFails(t *testing.T) {
+	testCases := map[string]failTest{
+		"empty":                 {source: " ", expected: "empty expression"},
+		"unterminated string":   {source: "'abc", expected: "unterminated string at position 1"},
+		"unexpected character":  {source: "a # b", expected: `unexpected character '#' at position 3`},
+		"missing operand":       {source: "a &&", expected: "unexpected end of expression at position 5"},
+		"missing parenthesis":   {source: "(a", expected: `expected ")" but expression ended`},
+		"missing else":          {source: "a ? b", expected: `expected ":" but expression ended`},
+		"trailing tokens":       {source: "a b", expected: `unexpected "b" at position 3`},
+		"not without in":        {source: "a not b", expected: `expected "in" at position 7`},
+		"invalid number":        {source: "1.2.3", expected: `invalid number "1.2.3" at position 1`},
+		"missing name":          {source: "asset.", expected: `expected name after "asset."`},
+		"unknown function":      {source: "trim(a)", expected: `unknown function "trim"`},
+		"wrong argument count":  {source: "len(a, b)", expected: `function "len" expects 1 argument(s) but got 2`},
+		"unexpected operator":   {source: "a == )", expected: `unexpected ")" at position 6`},
+		"unterminated list":     {source: "[1, 2", expected: `expected "]" but expression ended`},
+		"unexpected in list":    {source: "[1 2]", expected: `expected "]" at position 4`},
+		"missing ternary value": {source: "a ? : b", expected: `unexpected ":" at position 5`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(testCase.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expected)
+		})
+	}
+}
+
+func TestEvaluateFails(t *testing.T) {
+	testCases := map[string]failTest{
+		"unknown variable":        {source: "asset.unknown", expected: `unknown variable "asset.unknown"`},
+		"unknown nested variable": {source: "asset.id.name", expected: `unknown variable "asset.id.name"`},
+		"negate string":           {source: "-asset.id", expected: `unable to negate string "web"`},
+		"subtract string":         {source: "asset.raa - asset.id", expected: `unable to subtract string "web" from number 42.5`},
+		"compare number and text": {source: "asset.raa < 'high'", expected: `unable to compare number 42.5 with string "high"`},
+		"compare enum and number": {source: "asset.confidentiality > 1", expected: `unable to compare value "confidential" with number 1`},
+		"unknown enum value":      {source: "asset.confidentiality == 'secret'", expected: `unknown value "secret", expected one of: public, internal`},
+		"unknown enum in list":    {source: "asset.confidentiality in ['secret']", expected: `unknown value "secret"`},
+		"compare objects":         {source: "asset == asset", expected: "unable to compare object with object"},
+		"compare object":          {source: "asset.nested != 'inner'", expected: `unable to compare object with string "inner"`},
+		"object in list":          {source: "asset.nested in [1]", expected: "unable to compare number 1 with object"},
+		"in number":               {source: "1 in 2", expected: "unable to look for number 1 in number 2"},
+		"add list and number":     {source: "asset.tags + 1", expected: "unable to add list [aws, linux] and number 1"},
+		"length of number":        {source: "len(asset.raa)", expected: "unable to get the length of number 42.5"},
+		"error in ternary":        {source: "asset.internet ? asset.unknown : 1", expected: `unknown variable "asset.unknown"`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			compiled, err := Compile(testCase.source)
+			require.NoError(t, err)
+			_, err = compiled.Evaluate(testEnvironment())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expected)
+			assert.Contains(t, err.Error(), "failed to evaluate")
+		})
+	}
+}
+
+type conversionTest struct {
+	source   string
+	asBool   bool
+	asString string
+}
+
+func TestEvaluateConversions(t *testing.T) {
+	testCases := map[string]conversionTest{
+		"true":             {source: "asset.internet", asBool: true, asString: "true"},
+		"zero":             {source: "0", asBool: false, asString: "0"},
+		"number":           {source: "asset.raa", asBool: true, asString: "42.5"},
+		"empty string":     {source: "''", asBool: false, asString: ""},
+		"list":             {source: "asset.tags", asBool: true, asString: "aws, linux"},
+		"empty list":       {source: "[]", asBool: false, asString: ""},
+		"first enum value": {source: "asset.integrity", asBool: false, asString: "public"},
+		"enum value":       {source: "asset.confidentiality", asBool: true, asString: "confidential"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			compiled, err := Compile(testCase.source)
+			require.NoError(t, err)
+
+			asBool, err := compiled.EvaluateBool(testEnvironment())
+			require.NoError(t, err)
+			assert.Equal(t, testCase.asBool, asBool)
+
+			asString, err := compiled.EvaluateString(testEnvironment())
+			require.NoError(t, err)
+			assert.Equal(t, testCase.asString, asString)
+		})
+	}
+}
+
+func TestVariablesAndCheck(t *testing.T) {
+	compiled, err := Compile("asset.raa > 10 && contains(asset.tags, asset.id) ? asset.id : asset.unknown.name")
+	require.NoError(t, err)
+
+	assert.Equal(t, "asset.raa > 10 && contains(asset.tags, asset.id) ? asset.id : asset.unknown.name", compiled.String())
+	assert.Equal(t, []string{"asset.id", "asset.raa", "asset.tags", "asset.unknown.name"}, compiled.Variables())
+
+	err = compiled.Check(testEnvironment())
+	require.Error(t, err)
+	assert.Equal(t, `unknown variable "asset.unknown.name"`, err.Error())
+
+	environment := testEnvironment()
+	environment["asset"].(map[string]any)["unknown"] = map[string]any{"name": "known"}
+	assert.NoError(t, compiled.Check(environment))
+}
+
+func TestEnumOrdinal(t *testing.T) {
+	enum := Enum{Name: "b", Names: []string{"a", "b", "c"}}
+	assert.Equal(t, 1, enum.Ordinal())
+	assert.Equal(t, "b", enum.String())
+	assert.Equal(t, -1, Enum{Name: "d", Names: enum.Names}.Ordinal())
+}
@@ -0,0 +1,287 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	operatorToken tokenKind = iota
+	identifierToken
+	stringToken
+	numberToken
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+type parser struct {
+	source   string
+	tokens   []token
+	position int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "?", ":", "(", ")", "[", "]", ",", "."}
+
+func (p *parser) tokenize() error {
+	runes := []rune(p.source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			text := strings.Builder{}
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				text.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return fmt.Errorf("unterminated string at position %d of %q", start+1, p.source)
+			}
+			i++
+			p.tokens = append(p.tokens, token{kind: stringToken, text: text.String(), position: start})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: numberToken, text: string(runes[start:i]), position: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: identifierToken, text: string(runes[start:i]), position: start})
+
+		default:
+			found := false
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[i:]), operator) {
+					p.tokens = append(p.tokens, token{kind: operatorToken, text: operator, position: i})
+					i += len([]rune(operator))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("unexpected character %q at position %d of %q", r, i+1, p.source)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, a ...any) error {
+	position := len([]rune(p.source))
+	if p.position < len(p.tokens) {
+		position = p.tokens[p.position].position
+	}
+	return fmt.Errorf("%v at position %d of %q", fmt.Sprintf(format, a...), position+1, p.source)
+}
+
+func (p *parser) peek(texts ...string) bool {
+	if p.position >= len(p.tokens) {
+		return false
+	}
+	next := p.tokens[p.position]
+	if next.kind != operatorToken && next.kind != identifierToken {
+		return false
+	}
+	for _, text := range texts {
+		if next.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.peek(text) {
+		if p.position >= len(p.tokens) {
+			return p.errorf("expected %q but expression ended", text)
+		}
+		return p.errorf("expected %q", text)
+	}
+	p.position++
+	return nil
+}
+
+func (p *parser) parseTernary() (node, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek("?") {
+		return condition, nil
+	}
+	p.position++
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryNode{condition: condition, then: then, otherwise: otherwise}, nil
+}
+
+// binary operators by increasing precedence
+var precedences = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">=", "in", "not"},
+	{"+", "-"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level >= len(precedences) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(precedences[level]...) {
+		operator := p.tokens[p.position].text
+		p.position++
+		if operator == "not" {
+			if err = p.expect("in"); err != nil {
+				return nil, err
+			}
+			operator = "not in"
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek("!", "-") {
+		operator := p.tokens[p.position].text
+		p.position++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operator: operator, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.position >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of expression")
+	}
+	next := p.tokens[p.position]
+	p.position++
+	switch next.kind {
+	case stringToken:
+		return &literalNode{value: next.text}, nil
+
+	case numberToken:
+		number, err := strconv.ParseFloat(next.text, 64)
+		if err != nil {
+			p.position--
+			return nil, p.errorf("invalid number %q", next.text)
+		}
+		return &literalNode{value: number}, nil
+
+	case identifierToken:
+		switch next.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		if p.peek("(") {
+			return p.parseCall(next.text)
+		}
+		path := next.text
+		for p.peek(".") {
+			p.position++
+			if p.position >= len(p.tokens) || p.tokens[p.position].kind != identifierToken {
+				return nil, p.errorf("expected name after %q", path+".")
+			}
+			path += "." + p.tokens[p.position].text
+			p.position++
+		}
+		return &variableNode{path: path}, nil
+	}
+
+	switch next.text {
+	case "(":
+		inner, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+
+	case "[":
+		items, err := p.parseList("]")
+		if err != nil {
+			return nil, err
+		}
+		return &listNode{items: items}, nil
+	}
+
+	p.position--
+	return nil, p.errorf("unexpected %q", next.text)
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	function, ok := functions[name]
+	if !ok {
+		return nil, p.errorf("unknown function %q", name)
+	}
+	p.position++
+	arguments, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(arguments) != function.arguments {
+		return nil, fmt.Errorf("function %q expects %d argument(s) but got %d in %q", name, function.arguments, len(arguments), p.source)
+	}
+	return &callNode{name: name, arguments: arguments}, nil
+}
+
+func (p *parser) parseList(end string) ([]node, error) {
+	items := make([]node, 0)
+	if p.peek(end) {
+		p.position++
+		return items, nil
+	}
+	for {
+		item, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if !p.peek(",") {
+			break
+		}
+		p.position++
+	}
+	return items, p.expect(end)
+}
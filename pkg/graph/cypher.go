@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+var cypherEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// WriteCypher writes the graph of the model as a Cypher script to be run against an (empty) Neo4j database,
+// e.g. with "cypher-shell -f threat-model.cypher"
+func WriteCypher(parsedModel *types.ParsedModel, filename string) error {
+	graph := Build(parsedModel)
+	nodes := graph.NodeByKey()
+
+	var script strings.Builder
+	script.WriteString("// threat model graph generated by threagile\n")
+	for _, label := range []string{TechnicalAssetLabel, DataAssetLabel, TrustBoundaryLabel, SharedRuntimeLabel, RiskLabel} {
+		script.WriteString(fmt.Sprintf("CREATE CONSTRAINT IF NOT EXISTS FOR (n:%v) REQUIRE n.id IS UNIQUE;\n", label))
+	}
+	for _, node := range graph.Nodes {
+		script.WriteString(fmt.Sprintf("CREATE (:%v %v);\n", node.Label, cypherProperties(node.Properties)))
+	}
+	for _, edge := range graph.Edges {
+		source, sourceOk := nodes[edge.Source]
+		target, targetOk := nodes[edge.Target]
+		if !sourceOk || !targetOk {
+			continue
+		}
+		relationship := ":" + edge.Type
+		if len(edge.Properties) > 0 {
+			relationship += " " + cypherProperties(edge.Properties)
+		}
+		script.WriteString(fmt.Sprintf("MATCH (a:%v {id: %v}), (b:%v {id: %v}) CREATE (a)-[%v]->(b);\n",
+			source.Label, cypherValue(source.Properties["id"]), target.Label, cypherValue(target.Properties["id"]), relationship))
+	}
+
+	return os.WriteFile(filepath.Clean(filename), []byte(script.String()), 0600)
+}
+
+func cypherProperties(properties map[string]any) string {
+	values := make([]string, 0, len(properties))
+	for _, name := range sortedKeys(properties) {
+		values = append(values, name+": "+cypherValue(properties[name]))
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
+
+func cypherValue(value any) string {
+	switch typedValue := value.(type) {
+	case string:
+		return "'" + cypherEscaper.Replace(typedValue) + "'"
+	case []string:
+		values := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			values = append(values, cypherValue(item))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case bool:
+		return strconv.FormatBool(typedValue)
+	case int:
+		return strconv.Itoa(typedValue)
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return cypherValue(fmt.Sprint(value))
+	}
+}
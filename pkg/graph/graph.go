@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// labels of the nodes
+const (
+	TechnicalAssetLabel = "TechnicalAsset"
+	DataAssetLabel      = "DataAsset"
+	TrustBoundaryLabel  = "TrustBoundary"
+	SharedRuntimeLabel  = "SharedRuntime"
+	RiskLabel           = "Risk"
+)
+
+// types of the edges
+const (
+	CommunicatesWith = "COMMUNICATES_WITH"
+	Processes        = "PROCESSES"
+	Stores           = "STORES"
+	Contains         = "CONTAINS"
+	Runs             = "RUNS"
+	Affects          = "AFFECTS"
+	CanBreach        = "CAN_BREACH"
+)
+
+// Node is an element of the model, properties are strings, bools, ints, floats or string lists
+type Node struct {
+	Key        string // unique within the graph, like "TechnicalAsset:apache-webserver"
+	Label      string
+	Properties map[string]any
+}
+
+// Edge is a typed relationship between two nodes (given by their keys)
+type Edge struct {
+	Source     string
+	Target     string
+	Type       string
+	Properties map[string]any
+}
+
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+var formattingTags = regexp.MustCompile(`</?[a-zA-Z]+>`)
+
+// Build returns the graph of the parsed model: technical assets, data assets, trust boundaries, shared runtimes and generated risks
+// are nodes, communication links, containment, processing, storing and the elements affected by the risks are edges
+func Build(parsedModel *types.ParsedModel) *Graph {
+	graph := new(Graph)
+
+	for _, id := range sortedKeys(parsedModel.DataAssets) {
+		dataAsset := parsedModel.DataAssets[id]
+		graph.Nodes = append(graph.Nodes, Node{Key: key(DataAssetLabel, id), Label: DataAssetLabel, Properties: map[string]any{
+			"id":              dataAsset.Id,
+			"title":           dataAsset.Title,
+			"description":     dataAsset.Description,
+			"usage":           dataAsset.Usage.String(),
+			"tags":            dataAsset.Tags,
+			"origin":          dataAsset.Origin,
+			"owner":           dataAsset.Owner,
+			"quantity":        dataAsset.Quantity.String(),
+			"confidentiality": dataAsset.Confidentiality.String(),
+			"integrity":       dataAsset.Integrity.String(),
+			"availability":    dataAsset.Availability.String(),
+		}})
+	}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		dataFormats := make([]string, 0)
+		for _, format := range technicalAsset.DataFormatsAccepted {
+			dataFormats = append(dataFormats, format.String())
+		}
+		graph.Nodes = append(graph.Nodes, Node{Key: key(TechnicalAssetLabel, id), Label: TechnicalAssetLabel, Properties: map[string]any{
+			"id":                      technicalAsset.Id,
+			"title":                   technicalAsset.Title,
+			"description":             technicalAsset.Description,
+			"usage":                   technicalAsset.Usage.String(),
+			"type":                    technicalAsset.Type.String(),
+			"size":                    technicalAsset.Size.String(),
+			"technology":              technicalAsset.Technology.String(),
+			"machine":                 technicalAsset.Machine.String(),
+			"internet":                technicalAsset.Internet,
+			"multi_tenant":            technicalAsset.MultiTenant,
+			"redundant":               technicalAsset.Redundant,
+			"custom_developed_parts":  technicalAsset.CustomDevelopedParts,
+			"out_of_scope":            technicalAsset.OutOfScope,
+			"used_as_client_by_human": technicalAsset.UsedAsClientByHuman,
+			"encryption":              technicalAsset.Encryption.String(),
+			"owner":                   technicalAsset.Owner,
+			"confidentiality":         technicalAsset.Confidentiality.String(),
+			"integrity":               technicalAsset.Integrity.String(),
+			"availability":            technicalAsset.Availability.String(),
+			"highest_confidentiality": technicalAsset.HighestConfidentiality(parsedModel).String(),
+			"highest_integrity":       technicalAsset.HighestIntegrity(parsedModel).String(),
+			"highest_availability":    technicalAsset.HighestAvailability(parsedModel).String(),
+			"tags":                    technicalAsset.Tags,
+			"data_formats_accepted":   dataFormats,
+			"raa":                     technicalAsset.RAA,
+		}})
+
+		for _, dataAssetId := range technicalAsset.DataAssetsProcessed {
+			graph.Edges = append(graph.Edges, Edge{Source: key(TechnicalAssetLabel, id), Target: key(DataAssetLabel, dataAssetId), Type: Processes})
+		}
+		for _, dataAssetId := range technicalAsset.DataAssetsStored {
+			graph.Edges = append(graph.Edges, Edge{Source: key(TechnicalAssetLabel, id), Target: key(DataAssetLabel, dataAssetId), Type: Stores})
+		}
+		for _, link := range technicalAsset.CommunicationLinksSorted() {
+			graph.Edges = append(graph.Edges, Edge{Source: key(TechnicalAssetLabel, link.SourceId), Target: key(TechnicalAssetLabel, link.TargetId), Type: CommunicatesWith,
+				Properties: map[string]any{
+					"id":                   link.Id,
+					"title":                link.Title,
+					"description":          link.Description,
+					"protocol":             link.Protocol.String(),
+					"tags":                 link.Tags,
+					"vpn":                  link.VPN,
+					"ip_filtered":          link.IpFiltered,
+					"readonly":             link.Readonly,
+					"authentication":       link.Authentication.String(),
+					"authorization":        link.Authorization.String(),
+					"usage":                link.Usage.String(),
+					"data_assets_sent":     link.DataAssetsSent,
+					"data_assets_received": link.DataAssetsReceived,
+				}})
+		}
+	}
+
+	for _, id := range sortedKeys(parsedModel.TrustBoundaries) {
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		graph.Nodes = append(graph.Nodes, Node{Key: key(TrustBoundaryLabel, id), Label: TrustBoundaryLabel, Properties: map[string]any{
+			"id":          trustBoundary.Id,
+			"title":       trustBoundary.Title,
+			"description": trustBoundary.Description,
+			"type":        trustBoundary.Type.String(),
+			"tags":        trustBoundary.Tags,
+		}})
+		for _, technicalAssetId := range trustBoundary.TechnicalAssetsInside {
+			graph.Edges = append(graph.Edges, Edge{Source: key(TrustBoundaryLabel, id), Target: key(TechnicalAssetLabel, technicalAssetId), Type: Contains})
+		}
+		for _, nestedId := range trustBoundary.TrustBoundariesNested {
+			graph.Edges = append(graph.Edges, Edge{Source: key(TrustBoundaryLabel, id), Target: key(TrustBoundaryLabel, nestedId), Type: Contains})
+		}
+	}
+
+	for _, id := range sortedKeys(parsedModel.SharedRuntimes) {
+		sharedRuntime := parsedModel.SharedRuntimes[id]
+		graph.Nodes = append(graph.Nodes, Node{Key: key(SharedRuntimeLabel, id), Label: SharedRuntimeLabel, Properties: map[string]any{
+			"id":          sharedRuntime.Id,
+			"title":       sharedRuntime.Title,
+			"description": sharedRuntime.Description,
+			"tags":        sharedRuntime.Tags,
+		}})
+		for _, technicalAssetId := range sharedRuntime.TechnicalAssetsRunning {
+			graph.Edges = append(graph.Edges, Edge{Source: key(SharedRuntimeLabel, id), Target: key(TechnicalAssetLabel, technicalAssetId), Type: Runs})
+		}
+	}
+
+	for _, categoryId := range sortedKeys(parsedModel.GeneratedRisksByCategory) {
+		category := types.GetRiskCategory(parsedModel, categoryId)
+		if category == nil {
+			continue
+		}
+		risks := append([]types.Risk{}, parsedModel.GeneratedRisksByCategory[categoryId]...)
+		sort.Slice(risks, func(i, j int) bool {
+			return risks[i].SyntheticId < risks[j].SyntheticId
+		})
+		for _, risk := range risks {
+			graph.addRisk(parsedModel, *category, risk)
+		}
+	}
+	return graph
+}
+
+func (what *Graph) addRisk(parsedModel *types.ParsedModel, category types.RiskCategory, risk types.Risk) {
+	cwe := category.CWE
+	if risk.CWE > 0 {
+		cwe = risk.CWE
+	}
+	riskKey := key(RiskLabel, risk.SyntheticId)
+	what.Nodes = append(what.Nodes, Node{Key: riskKey, Label: RiskLabel, Properties: map[string]any{
+		"id":                      risk.SyntheticId,
+		"category":                category.Id,
+		"category_title":          category.Title,
+		"title":                   formattingTags.ReplaceAllString(risk.Title, ""),
+		"severity":                risk.Severity.String(),
+		"exploitation_likelihood": risk.ExploitationLikelihood.String(),
+		"exploitation_impact":     risk.ExploitationImpact.String(),
+		"data_breach_probability": risk.DataBreachProbability.String(),
+		"risk_status":             risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String(),
+		"function":                category.Function.String(),
+		"stride":                  category.STRIDE.String(),
+		"cwe":                     cwe,
+	}})
+
+	if len(risk.MostRelevantTechnicalAssetId) > 0 {
+		what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(TechnicalAssetLabel, risk.MostRelevantTechnicalAssetId), Type: Affects})
+	}
+	if len(risk.MostRelevantDataAssetId) > 0 {
+		what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(DataAssetLabel, risk.MostRelevantDataAssetId), Type: Affects})
+	}
+	if len(risk.MostRelevantTrustBoundaryId) > 0 {
+		what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(TrustBoundaryLabel, risk.MostRelevantTrustBoundaryId), Type: Affects})
+	}
+	if len(risk.MostRelevantSharedRuntimeId) > 0 {
+		what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(SharedRuntimeLabel, risk.MostRelevantSharedRuntimeId), Type: Affects})
+	}
+	if len(risk.MostRelevantCommunicationLinkId) > 0 {
+		// links are edges, so the risk affects their source asset
+		if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok {
+			what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(TechnicalAssetLabel, link.SourceId), Type: Affects,
+				Properties: map[string]any{"communication_link": link.Id}})
+		}
+	}
+	for _, technicalAssetId := range risk.DataBreachTechnicalAssetIDs {
+		what.Edges = append(what.Edges, Edge{Source: riskKey, Target: key(TechnicalAssetLabel, technicalAssetId), Type: CanBreach})
+	}
+}
+
+// NodeByKey returns all nodes by their key
+func (what *Graph) NodeByKey() map[string]Node {
+	nodes := make(map[string]Node)
+	for _, node := range what.Nodes {
+		nodes[node.Key] = node
+	}
+	return nodes
+}
+
+func key(label string, id string) string {
+	return label + ":" + id
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
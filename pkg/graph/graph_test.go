@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func graphModel() *types.ParsedModel {
+	link := types.CommunicationLink{Id: "web>orders", Title: "Orders", SourceId: "web", TargetId: "db", Protocol: types.JDBC, DataAssetsSent: []string{"orders"}}
+	risk := types.Risk{
+		CategoryId:                      "sql-nosql-injection",
+		SyntheticId:                     "sql-nosql-injection@db@web@web>orders",
+		Title:                           "<b>SQL Injection</b> risk at <b>Web</b>",
+		Severity:                        types.ElevatedSeverity,
+		MostRelevantTechnicalAssetId:    "db",
+		MostRelevantCommunicationLinkId: link.Id,
+		DataBreachTechnicalAssetIDs:     []string{"db"},
+	}
+	return &types.ParsedModel{
+		DataAssets: map[string]types.DataAsset{"orders": {Id: "orders", Title: "Orders", Confidentiality: types.Confidential}},
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web", Technology: types.WebServer, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []types.CommunicationLink{link}},
+			"db":  {Id: "db", Title: "It's the \"DB\"", Technology: types.Database, DataAssetsStored: []string{"orders"}, Tags: []string{"b", "a"}, RAA: 42.5},
+		},
+		TrustBoundaries: map[string]types.TrustBoundary{
+			"network": {Id: "network", Title: "Network", TechnicalAssetsInside: []string{"web"}, TrustBoundariesNested: []string{"backend"}},
+			"backend": {Id: "backend", Title: "Backend", TechnicalAssetsInside: []string{"db"}},
+		},
+		SharedRuntimes:     map[string]types.SharedRuntime{"cluster": {Id: "cluster", Title: "Cluster", TechnicalAssetsRunning: []string{"web", "db"}}},
+		CommunicationLinks: map[string]types.CommunicationLink{link.Id: link},
+		BuiltInRiskCategories: map[string]types.RiskCategory{"sql-nosql-injection": {Id: "sql-nosql-injection", Title: "SQL/NoSQL-Injection",
+			Function: types.Development, STRIDE: types.Tampering, CWE: 89}},
+		GeneratedRisksByCategory: map[string][]types.Risk{"sql-nosql-injection": {risk}, "unknown-category": {{SyntheticId: "unknown"}}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	graph := Build(graphModel())
+
+	keys := make([]string, 0)
+	for _, node := range graph.Nodes {
+		keys = append(keys, node.Key)
+	}
+	assert.Equal(t, []string{"DataAsset:orders", "TechnicalAsset:db", "TechnicalAsset:web", "TrustBoundary:backend", "TrustBoundary:network",
+		"SharedRuntime:cluster", "Risk:sql-nosql-injection@db@web@web>orders"}, keys, "risks of unknown categories are skipped")
+
+	edges := make([]string, 0)
+	for _, edge := range graph.Edges {
+		edges = append(edges, edge.Source+" "+edge.Type+" "+edge.Target)
+	}
+	risk := "Risk:sql-nosql-injection@db@web@web>orders"
+	assert.Equal(t, []string{
+		"TechnicalAsset:db STORES DataAsset:orders",
+		"TechnicalAsset:web PROCESSES DataAsset:orders",
+		"TechnicalAsset:web COMMUNICATES_WITH TechnicalAsset:db",
+		"TrustBoundary:backend CONTAINS TechnicalAsset:db",
+		"TrustBoundary:network CONTAINS TechnicalAsset:web",
+		"TrustBoundary:network CONTAINS TrustBoundary:backend",
+		"SharedRuntime:cluster RUNS TechnicalAsset:web",
+		"SharedRuntime:cluster RUNS TechnicalAsset:db",
+		risk + " AFFECTS TechnicalAsset:db",
+		risk + " AFFECTS TechnicalAsset:web",
+		risk + " CAN_BREACH TechnicalAsset:db",
+	}, edges)
+
+	nodes := graph.NodeByKey()
+	assert.Equal(t, "SQL Injection risk at Web", nodes[risk].Properties["title"], "formatting is removed")
+	assert.Equal(t, 89, nodes[risk].Properties["cwe"])
+	assert.Equal(t, types.Unchecked.String(), nodes[risk].Properties["risk_status"])
+	assert.Equal(t, types.Confidential.String(), nodes["TechnicalAsset:web"].Properties["highest_confidentiality"])
+	assert.Equal(t, "web>orders", graph.Edges[9].Properties["communication_link"])
+	assert.Equal(t, []string{"orders"}, graph.Edges[2].Properties["data_assets_sent"])
+}
+
+func TestWriteCypher(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.cypher")
+
+	require.NoError(t, WriteCypher(graphModel(), filename))
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	script := string(data)
+	assert.Contains(t, script, "CREATE CONSTRAINT IF NOT EXISTS FOR (n:TechnicalAsset) REQUIRE n.id IS UNIQUE;\n")
+	assert.Contains(t, script, `id: 'db', integrity: 'archive', internet: false, machine: 'physical', multi_tenant: false, out_of_scope: false, owner: '', raa: 42.5`)
+	assert.Contains(t, script, `tags: ['b', 'a'], technology: 'database', title: 'It\'s the "DB"'`)
+	assert.Contains(t, script, "MATCH (a:TechnicalAsset {id: 'web'}), (b:TechnicalAsset {id: 'db'}) CREATE (a)-[:COMMUNICATES_WITH {authentication: 'none'")
+	assert.Contains(t, script, "MATCH (a:SharedRuntime {id: 'cluster'}), (b:TechnicalAsset {id: 'db'}) CREATE (a)-[:RUNS]->(b);\n")
+	for _, line := range strings.Split(strings.TrimSpace(script), "\n")[1:] {
+		assert.True(t, strings.HasSuffix(line, ";"), line)
+	}
+}
+
+type cypherValueTest struct {
+	value    any
+	expected string
+}
+
+func TestCypherValue(t *testing.T) {
+	testCases := map[string]cypherValueTest{
+		"string":       {value: "it's\na \\ test", expected: `'it\'s\na \\ test'`},
+		"string list":  {value: []string{"a", "b"}, expected: `['a', 'b']`},
+		"empty list":   {value: []string{}, expected: `[]`},
+		"bool":         {value: true, expected: `true`},
+		"int":          {value: 42, expected: `42`},
+		"float":        {value: 0.25, expected: `0.25`},
+		"other values": {value: types.HTTPS, expected: `'https'`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, cypherValue(testCase.value))
+		})
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.graphml")
+
+	require.NoError(t, WriteGraphML(graphModel(), filename))
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	document := new(graphmlDocument)
+	require.NoError(t, xml.Unmarshal(data, document))
+	assert.Len(t, document.Graph.Nodes, 7)
+	assert.Len(t, document.Graph.Edges, 11)
+
+	keyTypes := make(map[string]string)
+	for _, key := range document.Keys {
+		keyTypes[key.Id] = key.Type
+	}
+	assert.Equal(t, "double", keyTypes["n_raa"])
+	assert.Equal(t, "boolean", keyTypes["n_internet"])
+	assert.Equal(t, "int", keyTypes["n_cwe"])
+	assert.Equal(t, "string", keyTypes["e_type"])
+
+	values := make(map[string]string)
+	for _, data := range document.Graph.Nodes[1].Data {
+		values[data.Key] = data.Value
+	}
+	assert.Equal(t, "TechnicalAsset", values["n_label"])
+	assert.Equal(t, "a,b", values["n_tags"], "lists are sorted and comma separated")
+	assert.Equal(t, `It's the "DB"`, values["n_title"])
+}
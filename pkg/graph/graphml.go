@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type graphmlDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	Id   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphmlGraph struct {
+	Id          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	Id   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Id     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph of the model as GraphML (for yEd, Gephi, networkx, ...): the label of a node is in its
+// "label" attribute, the type of an edge in its "type" attribute, lists are comma separated
+func WriteGraphML(parsedModel *types.ParsedModel, filename string) error {
+	graph := Build(parsedModel)
+
+	document := graphmlDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphmlGraph{Id: "threat-model", EdgeDefault: "directed"},
+	}
+	nodeKeys := map[string]string{"label": "string"}
+	edgeKeys := map[string]string{"type": "string"}
+
+	for _, node := range graph.Nodes {
+		data := []graphmlData{{Key: "n_label", Value: node.Label}}
+		for _, name := range sortedKeys(node.Properties) {
+			nodeKeys[name] = graphmlType(node.Properties[name])
+			data = append(data, graphmlData{Key: "n_" + name, Value: graphmlValue(node.Properties[name])})
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, graphmlNode{Id: node.Key, Data: data})
+	}
+	for i, edge := range graph.Edges {
+		data := []graphmlData{{Key: "e_type", Value: edge.Type}}
+		for _, name := range sortedKeys(edge.Properties) {
+			edgeKeys[name] = graphmlType(edge.Properties[name])
+			data = append(data, graphmlData{Key: "e_" + name, Value: graphmlValue(edge.Properties[name])})
+		}
+		document.Graph.Edges = append(document.Graph.Edges, graphmlEdge{Id: "e" + strconv.Itoa(i), Source: edge.Source, Target: edge.Target, Data: data})
+	}
+
+	for _, name := range sortedKeys(nodeKeys) {
+		document.Keys = append(document.Keys, graphmlKey{Id: "n_" + name, For: "node", Name: name, Type: nodeKeys[name]})
+	}
+	for _, name := range sortedKeys(edgeKeys) {
+		document.Keys = append(document.Keys, graphmlKey{Id: "e_" + name, For: "edge", Name: name, Type: edgeKeys[name]})
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to create graphml: %w", err)
+	}
+	return os.WriteFile(filepath.Clean(filename), append([]byte(xml.Header), append(data, '\n')...), 0600)
+}
+
+func graphmlType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int:
+		return "int"
+	case float64:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+func graphmlValue(value any) string {
+	switch typedValue := value.(type) {
+	case []string:
+		values := append([]string{}, typedValue...)
+		sort.Strings(values)
+		return strings.Join(values, ",")
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,307 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Diagram is the common denominator of data flow diagram based threat modelling tools
+// (processes, data stores, external entities, flows and boundaries), which is converted into a model stub.
+type Diagram struct {
+	Title       string
+	Description string
+	Owner       string
+	Elements    []Element
+	Flows       []Flow
+	Boundaries  []Boundary
+	Threats     []Threat
+	Properties  []Property
+}
+
+type ElementKind int
+
+const (
+	ProcessElement ElementKind = iota
+	DataStoreElement
+	ExternalEntityElement
+)
+
+func (what ElementKind) String() string {
+	return [...]string{"process", "data store", "external entity"}[what]
+}
+
+func (what ElementKind) TechnicalAssetType() types.TechnicalAssetType {
+	return [...]types.TechnicalAssetType{types.Process, types.Datastore, types.ExternalEntity}[what]
+}
+
+type Element struct {
+	Id               string
+	Name             string
+	Description      string
+	Kind             ElementKind
+	Type             string                         // foreign type, resolved via the technology mapping
+	Technology       types.TechnicalAssetTechnology // used when the foreign type can't be mapped
+	Bounds           *Bounds
+	OutOfScope       bool
+	OutOfScopeReason string
+	Internet         bool
+	Encryption       types.EncryptionStyle
+	Properties       []Property // foreign properties without equivalent, they become questions
+}
+
+type Flow struct {
+	Id             string
+	Name           string
+	Description    string
+	SourceId       string
+	TargetId       string
+	Type           string         // foreign type, resolved via the protocol mapping
+	Protocol       types.Protocol // used when the foreign type can't be mapped
+	Authentication types.Authentication
+	Bidirectional  bool
+	OutOfScope     bool
+	Properties     []Property
+}
+
+type Boundary struct {
+	Id          string
+	Name        string
+	Description string
+	Type        string  // foreign type, resolved via the trust boundary type mapping
+	Bounds      *Bounds // nil for boundaries drawn as lines, which don't enclose anything
+}
+
+type Threat struct {
+	ElementId   string // id of the element or flow the threat belongs to
+	Title       string
+	Category    string
+	State       string
+	Description string
+}
+
+type Property struct {
+	Name  string
+	Value string
+}
+
+type Bounds struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+func (what Bounds) ContainsCenterOf(other Bounds) bool {
+	x := other.X + other.Width/2
+	y := other.Y + other.Height/2
+	return x >= what.X && x <= what.X+what.Width && y >= what.Y && y <= what.Y+what.Height
+}
+
+func (what Bounds) Area() float64 {
+	return what.Width * what.Height
+}
+
+// ImportDiagram converts a diagram into a model stub; everything without an equivalent either becomes a question
+// in the model or ends up in the report.
+func ImportDiagram(diagram *Diagram, mapping Mapping, riskCategoryIds []string) (*input.Model, *Report) {
+	report := new(Report)
+	model := NewModel(withDefault(diagram.Title, "Imported Model"))
+	model.AppDescription.Description = diagram.Description
+	model.Author.Name = diagram.Owner
+	addQuestions(model, "Model", diagram.Properties)
+
+	takenIds := make(map[string]bool)
+	idOf := make(map[string]string)
+	titleOf := make(map[string]string)
+
+	// Technical Assets ===============================================================================
+	for _, element := range diagram.Elements {
+		name := withDefault(element.Name, element.Kind.String())
+		where := fmt.Sprintf("%v '%v'", element.Kind, name)
+		id := UniqueID(name, takenIds)
+		idOf[element.Id] = id
+
+		technology := element.Technology
+		if technologyName, ok := mapping.Resolve(MappingTechnology, element.Type, EnumNames(types.TechnicalAssetTechnologyValues())); ok {
+			technology, _ = types.ParseTechnicalAssetTechnology(technologyName)
+		} else if technology == types.UnknownTechnology {
+			report.Add("%v: unable to map %v %q (using %q, add it to the mapping file to fix this)", where, MappingTechnology, element.Type, technology.String())
+		}
+
+		asset := NewTechnicalAsset(id, withDefault(element.Description, "Imported "+element.Kind.String()), technology)
+		asset.Type = element.Kind.TechnicalAssetType().String()
+		asset.Internet = element.Internet
+		asset.Encryption = element.Encryption.String()
+		asset.OutOfScope = element.OutOfScope
+		asset.JustificationOutOfScope = element.OutOfScopeReason
+
+		title := UniqueKey(model.TechnicalAssets, name)
+		titleOf[element.Id] = title
+		model.TechnicalAssets[title] = asset
+		addQuestions(model, fmt.Sprintf("Technical asset '%v'", title), element.Properties)
+	}
+
+	// Communication Links ===============================================================================
+	linkIdOf := make(map[string]string)
+	for _, flow := range diagram.Flows {
+		sourceTitle, sourceOk := titleOf[flow.SourceId]
+		targetTitle, targetOk := titleOf[flow.TargetId]
+		name := withDefault(flow.Name, sourceTitle+" to "+targetTitle)
+		where := fmt.Sprintf("flow '%v'", name)
+		if !sourceOk || !targetOk {
+			report.Add("%v: source and target must both be connected to processes, data stores or external entities", where)
+			continue
+		}
+
+		protocol := flow.Protocol
+		if protocolName, ok := mapping.Resolve(MappingProtocol, flow.Type, EnumNames(types.ProtocolValues())); ok {
+			protocol, _ = types.ParseProtocol(protocolName)
+		} else if protocol == types.UnknownProtocol {
+			report.Add("%v: unable to map %v %q (using %q, add it to the mapping file to fix this)", where, MappingProtocol, flow.Type, protocol.String())
+		}
+
+		link := NewCommunicationLink(idOf[flow.TargetId], withDefault(flow.Description, name), protocol)
+		link.Authentication = flow.Authentication.String()
+
+		source := model.TechnicalAssets[sourceTitle]
+		linkTitle := UniqueKey(source.CommunicationLinks, name)
+		source.CommunicationLinks[linkTitle] = link
+		model.TechnicalAssets[sourceTitle] = source
+		linkIdOf[flow.Id] = source.ID + ">" + types.MakeID(linkTitle)
+
+		properties := flow.Properties
+		if flow.Bidirectional {
+			properties = append(properties, Property{Name: "Bidirectional", Value: "true"})
+		}
+		if flow.OutOfScope {
+			properties = append(properties, Property{Name: "Out of scope", Value: "true"})
+		}
+		addQuestions(model, fmt.Sprintf("Communication link '%v' of '%v'", linkTitle, sourceTitle), properties)
+	}
+
+	// Trust Boundaries ===============================================================================
+	boundaryTitleOf := make(map[string]string)
+	boundsOf := make(map[string]Bounds)
+	for _, boundary := range diagram.Boundaries {
+		name := withDefault(boundary.Name, "Trust Boundary")
+		where := fmt.Sprintf("trust boundary '%v'", name)
+		title := UniqueKey(model.TrustBoundaries, name)
+		boundaryTitleOf[boundary.Id] = title
+		model.TrustBoundaries[title] = input.TrustBoundary{
+			ID:          UniqueID(name, takenIds),
+			Description: withDefault(boundary.Description, "Imported trust boundary"),
+			Type: mapping.ResolveOrDefault(MappingTrustBoundaryType, boundary.Type,
+				EnumNames(types.TrustBoundaryTypeValues()), types.NetworkOnPrem.String(), report, where),
+		}
+
+		if boundary.Bounds == nil {
+			report.Add("%v: drawn as a line instead of an enclosing shape, assign the technical assets inside manually", where)
+			continue
+		}
+		boundsOf[boundary.Id] = *boundary.Bounds
+	}
+
+	for _, boundary := range diagram.Boundaries {
+		if boundary.Bounds == nil {
+			continue
+		}
+		if parentId, ok := smallestContaining(boundsOf, *boundary.Bounds, boundary.Id); ok {
+			parent := model.TrustBoundaries[boundaryTitleOf[parentId]]
+			parent.TrustBoundariesNested = append(parent.TrustBoundariesNested, model.TrustBoundaries[boundaryTitleOf[boundary.Id]].ID)
+			model.TrustBoundaries[boundaryTitleOf[parentId]] = parent
+		}
+	}
+
+	for _, element := range diagram.Elements {
+		if element.Bounds == nil {
+			continue
+		}
+		if boundaryId, ok := smallestContaining(boundsOf, *element.Bounds, ""); ok {
+			boundary := model.TrustBoundaries[boundaryTitleOf[boundaryId]]
+			boundary.TechnicalAssetsInside = append(boundary.TechnicalAssetsInside, idOf[element.Id])
+			model.TrustBoundaries[boundaryTitleOf[boundaryId]] = boundary
+		}
+	}
+
+	// Risk Tracking ===============================================================================
+	for _, threat := range diagram.Threats {
+		where := fmt.Sprintf("threat '%v'", threat.Title)
+		elementId, ok := idOf[threat.ElementId]
+		if !ok {
+			elementId, ok = linkIdOf[threat.ElementId]
+		}
+		if !ok {
+			report.Add("%v: not attached to any imported element or flow", where)
+			continue
+		}
+		where = fmt.Sprintf("%v of '%v'", where, elementId)
+
+		var categoryId string
+		for _, candidate := range []string{threat.Category, threat.Title} {
+			if resolved, ok := mapping.Resolve(MappingRiskCategory, candidate, riskCategoryIds); ok {
+				categoryId = resolved
+				break
+			}
+		}
+		if len(categoryId) == 0 {
+			report.Add("%v: no matching risk category for %q (add it to the mapping file to fix this)", where, withDefault(threat.Category, threat.Title))
+			continue
+		}
+
+		status, ok := mapping.ResolveRiskStatus(threat.State)
+		if !ok {
+			report.Add("%v: unable to map state %q (using %q)", where, threat.State, status.String())
+		}
+		if status != types.Unchecked {
+			report.AddThreat(where, categoryId, status)
+		}
+	}
+
+	if len(model.DataAssets) == 0 {
+		AddQuestion(model, DataAssetsQuestion, "")
+	}
+
+	return model, report
+}
+
+// addQuestions keeps foreign properties without equivalent as questions (answered with their original value)
+func addQuestions(model *input.Model, subject string, properties []Property) {
+	for _, property := range properties {
+		if len(strings.TrimSpace(property.Value)) == 0 {
+			continue
+		}
+		AddQuestion(model, fmt.Sprintf("%v: %v?", subject, property.Name), property.Value)
+	}
+}
+
+// smallestContaining returns the id of the smallest bounds containing the center of the given bounds
+func smallestContaining(boundsById map[string]Bounds, bounds Bounds, excludeId string) (string, bool) {
+	ids := make([]string, 0)
+	for id := range boundsById {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var found string
+	for _, id := range ids {
+		candidate := boundsById[id]
+		if id == excludeId || candidate.Area() <= bounds.Area() || !candidate.ContainsCenterOf(bounds) {
+			continue
+		}
+		if len(found) == 0 || candidate.Area() < boundsById[found].Area() {
+			found = id
+		}
+	}
+	return found, len(found) > 0
+}
+
+func withDefault(value string, defaultWhenEmpty string) string {
+	if len(strings.TrimSpace(value)) > 0 {
+		return strings.TrimSpace(value)
+	}
+	return defaultWhenEmpty
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestImportDiagram(t *testing.T) {
+	diagram := &Diagram{
+		Title: "Webshop",
+		Owner: "Jane Doe",
+		Elements: []Element{
+			{Id: "1", Name: "Customer", Kind: ExternalEntityElement, Technology: types.Browser, Internet: true, Bounds: &Bounds{X: 10, Y: 10, Width: 10, Height: 10}},
+			{Id: "2", Name: "Shop", Kind: ProcessElement, Type: "Web Application", Bounds: &Bounds{X: 110, Y: 10, Width: 10, Height: 10}},
+			{Id: "3", Name: "Orders", Kind: DataStoreElement, Type: "SQL", Technology: types.Database, Bounds: &Bounds{X: 160, Y: 10, Width: 10, Height: 10},
+				Properties: []Property{{Name: "Stores credentials", Value: "true"}}},
+			{Id: "4", Name: "Mainframe", Kind: ProcessElement, Type: "COBOL"},
+		},
+		Flows: []Flow{
+			{Id: "5", Name: "Order", SourceId: "1", TargetId: "2", Type: "HTTPS"},
+			{Id: "6", SourceId: "2", TargetId: "3", Type: "DB Link", Protocol: types.JDBC, Authentication: types.Credentials},
+			{Id: "7", Name: "Dangling", SourceId: "2", TargetId: "99"},
+		},
+		Boundaries: []Boundary{
+			{Id: "8", Name: "Data Center", Type: "DC", Bounds: &Bounds{X: 100, Y: 0, Width: 100, Height: 100}},
+			{Id: "9", Name: "Database Zone", Bounds: &Bounds{X: 150, Y: 0, Width: 40, Height: 40}},
+			{Id: "10", Name: "Internet Line"},
+		},
+		Threats: []Threat{
+			{ElementId: "6", Title: "Injection", Category: "Tampering", State: "Mitigated"},
+			{ElementId: "2", Title: "Spoofing", Category: "Spoofing", State: "NotStarted"},
+			{ElementId: "3", Title: "Unknown", Category: "Repudiation", State: "Mitigated"},
+			{ElementId: "99", Title: "Orphan"},
+		},
+	}
+	mapping := Mapping{
+		MappingTechnology:        {"Web Application": types.WebApplication.String()},
+		MappingTrustBoundaryType: {"DC": types.NetworkDedicatedHoster.String()},
+		MappingRiskCategory:      {"Tampering": "sql-nosql-injection", "Spoofing": "missing-authentication"},
+	}
+
+	model, report := ImportDiagram(diagram, mapping, []string{"sql-nosql-injection", "missing-authentication"})
+
+	assert.Equal(t, "Webshop", model.Title)
+	require.Len(t, model.TechnicalAssets, 4)
+	customer, shop, orders := model.TechnicalAssets["Customer"], model.TechnicalAssets["Shop"], model.TechnicalAssets["Orders"]
+	assert.Equal(t, "customer", customer.ID)
+	assert.True(t, customer.Internet)
+	assert.Equal(t, types.ExternalEntity.String(), customer.Type)
+	assert.Equal(t, types.WebApplication.String(), shop.Technology)
+	assert.Equal(t, types.Datastore.String(), orders.Type)
+	assert.Equal(t, types.UnknownTechnology.String(), model.TechnicalAssets["Mainframe"].Technology)
+	assert.Contains(t, model.Questions, "Technical asset 'Orders': Stores credentials?")
+
+	require.Contains(t, customer.CommunicationLinks, "Order")
+	assert.Equal(t, "shop", customer.CommunicationLinks["Order"].Target)
+	assert.Equal(t, types.HTTPS.String(), customer.CommunicationLinks["Order"].Protocol)
+	require.Contains(t, shop.CommunicationLinks, "Shop to Orders")
+	assert.Equal(t, types.JDBC.String(), shop.CommunicationLinks["Shop to Orders"].Protocol)
+	assert.Equal(t, types.Credentials.String(), shop.CommunicationLinks["Shop to Orders"].Authentication)
+
+	dataCenter, databaseZone := model.TrustBoundaries["Data Center"], model.TrustBoundaries["Database Zone"]
+	assert.Equal(t, types.NetworkDedicatedHoster.String(), dataCenter.Type)
+	assert.Equal(t, []string{"shop"}, dataCenter.TechnicalAssetsInside)
+	assert.Equal(t, []string{"database-zone"}, dataCenter.TrustBoundariesNested)
+	assert.Equal(t, []string{"orders"}, databaseZone.TechnicalAssetsInside)
+
+	// threats can't become risk tracking, their synthetic risk id is only known once analyzed
+	assert.Empty(t, model.RiskTracking)
+	assertReported(t, report, "threat 'Injection' of 'shop>shop-to-orders': risk category \"sql-nosql-injection\" with status \"mitigated\"")
+	assertReported(t, report, "threat 'Unknown' of 'orders': no matching risk category")
+	assertReported(t, report, "threat 'Orphan': not attached to any imported element or flow")
+	assertReported(t, report, "process 'Mainframe': unable to map technology \"COBOL\"")
+	assertReported(t, report, "flow 'Dangling': source and target must both be connected")
+	assertReported(t, report, "trust boundary 'Internet Line': drawn as a line")
+	for _, item := range report.Items {
+		assert.NotContains(t, item, "'Spoofing'", "unchecked threats need no risk tracking")
+	}
+}
+
+func assertReported(t *testing.T, report *Report, prefix string) {
+	t.Helper()
+	for _, item := range report.Items {
+		if strings.HasPrefix(item, prefix) {
+			return
+		}
+	}
+	t.Errorf("report misses %q in %q", prefix, report.Items)
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const (
+	MappingTechnology         = "technology"
+	MappingTechnicalAssetType = "type"
+	MappingProtocol           = "protocol"
+	MappingAuthentication     = "authentication"
+	MappingAuthorization      = "authorization"
+	MappingDataFormat         = "data_format"
+	MappingTrustBoundaryType  = "trust_boundary_type"
+	MappingRiskStatus         = "risk_status"
+	MappingRiskCategory       = "risk_category"
+)
+
+// well known threat states of other tools, anything else has to be mapped via the mapping file
+var riskStatusAliases = map[string]types.RiskStatus{
+	"identified":         types.Unchecked,
+	"open":               types.Unchecked,
+	"new":                types.Unchecked,
+	"expose":             types.Unchecked,
+	"autogenerated":      types.Unchecked,
+	"notstarted":         types.Unchecked,
+	"needsinvestigation": types.InDiscussion,
+	"not-applicable":     types.FalsePositive,
+	"not applicable":     types.FalsePositive,
+	"notapplicable":      types.FalsePositive,
+	"na":                 types.FalsePositive,
+	"n/a":                types.FalsePositive,
+	"accept":             types.Accepted,
+	"implemented":        types.Mitigated,
+	"mitigated":          types.Mitigated,
+	"mitigate":           types.InProgress,
+}
+
+// Mapping translates values of foreign formats into Threagile values, grouped by kind (yaml or json), e.g.
+//
+//	technology:
+//	  web-service: web-service-rest
+//	  CD-MSSQL: database
+//	protocol:
+//	  HTTPS: https
+type Mapping map[string]map[string]string
+
+func LoadMapping(filename string) (Mapping, error) {
+	mapping := make(Mapping)
+	if len(filename) == 0 {
+		return mapping, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read mapping file %q: %w", filename, err)
+	}
+
+	err = yaml.Unmarshal(data, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse mapping file %q: %w", filename, err)
+	}
+
+	return mapping, nil
+}
+
+// Resolve returns the Threagile value for a foreign value: explicitly mapped values win, otherwise
+// the foreign value is accepted if it (case-insensitively) already is one of the valid values.
+func (what Mapping) Resolve(kind string, value string, validValues []string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if mapped, ok := what.lookup(kind, value); ok {
+		value = mapped
+	}
+
+	for _, validValue := range validValues {
+		if strings.EqualFold(validValue, value) {
+			return validValue, true
+		}
+	}
+
+	return "", false
+}
+
+// ResolveOrDefault is like Resolve, but falls back to a default value and notes the unmapped value in the report.
+func (what Mapping) ResolveOrDefault(kind string, value string, validValues []string, fallback string, report *Report, where string) string {
+	resolved, ok := what.Resolve(kind, value, validValues)
+	if ok {
+		return resolved
+	}
+
+	if len(strings.TrimSpace(value)) > 0 {
+		report.Add("%v: unable to map %v %q (using %q, add it to the mapping file to fix this)", where, kind, value, fallback)
+	}
+
+	return fallback
+}
+
+// ResolveRiskStatus maps the state of a foreign threat onto a risk status, an empty state means unchecked.
+func (what Mapping) ResolveRiskStatus(state string) (types.RiskStatus, bool) {
+	if len(strings.TrimSpace(state)) == 0 {
+		return types.Unchecked, true
+	}
+	if statusName, ok := what.Resolve(MappingRiskStatus, state, EnumNames(types.RiskStatusValues())); ok {
+		status, _ := types.ParseRiskStatus(statusName)
+		return status, true
+	}
+	if status, ok := riskStatusAliases[strings.ToLower(strings.TrimSpace(state))]; ok {
+		return status, true
+	}
+	return types.Unchecked, false
+}
+
+func (what Mapping) lookup(kind string, value string) (string, bool) {
+	values, ok := what[kind]
+	if !ok {
+		return "", false
+	}
+
+	if mapped, ok := values[value]; ok {
+		return mapped, true
+	}
+
+	for key, mapped := range values {
+		if strings.EqualFold(key, value) {
+			return mapped, true
+		}
+	}
+
+	return "", false
+}
+
+func EnumNames(values []types.TypeEnum) []string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, value.String())
+	}
+	return names
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type resolveTest struct {
+	value    string
+	expected string
+	ok       bool
+}
+
+func TestResolve(t *testing.T) {
+	mapping := Mapping{MappingProtocol: {"HTTP/2": types.HTTPS.String(), "Broken": "no-such-protocol"}}
+	testCases := map[string]resolveTest{
+		"valid value": {
+			value:    "jdbc",
+			expected: types.JDBC.String(),
+			ok:       true,
+		},
+		"valid value ignoring case": {
+			value:    " JDBC ",
+			expected: types.JDBC.String(),
+			ok:       true,
+		},
+		"mapped value": {
+			value:    "HTTP/2",
+			expected: types.HTTPS.String(),
+			ok:       true,
+		},
+		"mapped value ignoring case": {
+			value:    "http/2",
+			expected: types.HTTPS.String(),
+			ok:       true,
+		},
+		"mapped onto invalid value": {
+			value: "Broken",
+		},
+		"unknown value": {
+			value: "carrier-pigeon",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := mapping.Resolve(MappingProtocol, testCase.value, EnumNames(types.ProtocolValues()))
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func TestResolveOrDefaultReports(t *testing.T) {
+	report := new(Report)
+
+	assert.Equal(t, types.UnknownProtocol.String(), Mapping{}.ResolveOrDefault(MappingProtocol, "carrier-pigeon", EnumNames(types.ProtocolValues()), types.UnknownProtocol.String(), report, "flow 'x'"))
+	assert.Equal(t, types.UnknownProtocol.String(), Mapping{}.ResolveOrDefault(MappingProtocol, "", EnumNames(types.ProtocolValues()), types.UnknownProtocol.String(), report, "flow 'y'"))
+	assert.Equal(t, []string{"flow 'x': unable to map protocol \"carrier-pigeon\" (using \"unknown-protocol\", add it to the mapping file to fix this)"}, report.Items)
+}
+
+type resolveRiskStatusTest struct {
+	state    string
+	expected types.RiskStatus
+	ok       bool
+}
+
+func TestResolveRiskStatus(t *testing.T) {
+	mapping := Mapping{MappingRiskStatus: {"Done": types.Mitigated.String()}}
+	testCases := map[string]resolveRiskStatusTest{
+		"empty": {
+			state:    "",
+			expected: types.Unchecked,
+			ok:       true,
+		},
+		"risk status": {
+			state:    "false-positive",
+			expected: types.FalsePositive,
+			ok:       true,
+		},
+		"mapped": {
+			state:    "done",
+			expected: types.Mitigated,
+			ok:       true,
+		},
+		"alias": {
+			state:    "Not Applicable",
+			expected: types.FalsePositive,
+			ok:       true,
+		},
+		"unknown": {
+			state:    "whatever",
+			expected: types.Unchecked,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := mapping.ResolveRiskStatus(testCase.state)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func TestUniqueID(t *testing.T) {
+	taken := make(map[string]bool)
+
+	assert.Equal(t, "web-server", UniqueID("Web Server", taken))
+	assert.Equal(t, "web-server-2", UniqueID("web server", taken))
+	assert.Equal(t, "unnamed", UniqueID("", taken))
+}
+
+func TestUniqueKey(t *testing.T) {
+	values := map[string]int{"Database": 1, "Database 2": 2}
+
+	assert.Equal(t, "Web Server", UniqueKey(values, "Web Server"))
+	assert.Equal(t, "Database 3", UniqueKey(values, "Database"))
+}
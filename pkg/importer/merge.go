@@ -0,0 +1,191 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+// LoadModel reads a model without resolving its includes, so it can be written back as it was
+func LoadModel(filename string) (*input.Model, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read model file %q: %w", filename, err)
+	}
+
+	model := new(input.Model).Defaults()
+	err = yaml.Unmarshal(data, model)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse model file %q: %w", filename, err)
+	}
+
+	return model, nil
+}
+
+// MergeModel merges an imported model into an existing one. Elements are matched by id and values of the existing model
+// always win, so manually added or changed data survives a re-import: only missing elements, links, tags, data assets,
+// questions and risk tracking entries are added.
+func MergeModel(existing *input.Model, imported *input.Model) {
+	for _, tag := range imported.TagsAvailable {
+		existing.AddTagToModelInput(tag, false, new([]string))
+	}
+	sort.Strings(existing.TagsAvailable)
+
+	for question, answer := range imported.Questions {
+		if _, ok := existing.Questions[question]; !ok {
+			existing.Questions[question] = answer
+		}
+	}
+
+	for id, tracking := range imported.RiskTracking {
+		if _, ok := existing.RiskTracking[id]; !ok {
+			existing.RiskTracking[id] = tracking
+		}
+	}
+
+	dataAssetTitles := make(map[string]string)
+	for title, dataAsset := range existing.DataAssets {
+		dataAssetTitles[dataAsset.ID] = title
+	}
+	for _, title := range sortedKeys(imported.DataAssets) {
+		dataAsset := imported.DataAssets[title]
+		if _, ok := dataAssetTitles[dataAsset.ID]; !ok {
+			existing.DataAssets[UniqueKey(existing.DataAssets, title)] = dataAsset
+		}
+	}
+
+	technicalAssetTitles := make(map[string]string)
+	for title, technicalAsset := range existing.TechnicalAssets {
+		technicalAssetTitles[technicalAsset.ID] = title
+	}
+	for _, title := range sortedKeys(imported.TechnicalAssets) {
+		importedAsset := imported.TechnicalAssets[title]
+		existingTitle, ok := technicalAssetTitles[importedAsset.ID]
+		if !ok {
+			existing.TechnicalAssets[UniqueKey(existing.TechnicalAssets, title)] = importedAsset
+			continue
+		}
+
+		technicalAsset := existing.TechnicalAssets[existingTitle]
+		technicalAsset.Tags = new(input.Strings).MergeUniqueSlice(technicalAsset.Tags, importedAsset.Tags)
+		technicalAsset.DataAssetsProcessed = new(input.Strings).MergeUniqueSlice(technicalAsset.DataAssetsProcessed, importedAsset.DataAssetsProcessed)
+		technicalAsset.DataAssetsStored = new(input.Strings).MergeUniqueSlice(technicalAsset.DataAssetsStored, importedAsset.DataAssetsStored)
+		technicalAsset.DataFormatsAccepted = new(input.Strings).MergeUniqueSlice(technicalAsset.DataFormatsAccepted, importedAsset.DataFormatsAccepted)
+		if technicalAsset.CommunicationLinks == nil {
+			technicalAsset.CommunicationLinks = make(map[string]input.CommunicationLink)
+		}
+		for _, linkTitle := range sortedKeys(importedAsset.CommunicationLinks) {
+			link := importedAsset.CommunicationLinks[linkTitle]
+			if !hasLinkTo(technicalAsset, link.Target) {
+				technicalAsset.CommunicationLinks[UniqueKey(technicalAsset.CommunicationLinks, linkTitle)] = link
+			}
+		}
+		existing.TechnicalAssets[existingTitle] = technicalAsset
+	}
+
+	// technical assets and nested trust boundaries may only be placed in one trust boundary
+	placed := make(map[string]bool)
+	boundaryTitles := make(map[string]string)
+	for title, trustBoundary := range existing.TrustBoundaries {
+		boundaryTitles[trustBoundary.ID] = title
+		for _, id := range append(trustBoundary.TechnicalAssetsInside, trustBoundary.TrustBoundariesNested...) {
+			placed[id] = true
+		}
+	}
+	for _, title := range sortedKeys(imported.TrustBoundaries) {
+		importedBoundary := imported.TrustBoundaries[title]
+		existingTitle, ok := boundaryTitles[importedBoundary.ID]
+		if !ok {
+			existingTitle = UniqueKey(existing.TrustBoundaries, title)
+			trustBoundary := importedBoundary
+			trustBoundary.TechnicalAssetsInside, trustBoundary.TrustBoundariesNested = nil, nil
+			existing.TrustBoundaries[existingTitle] = trustBoundary
+			boundaryTitles[trustBoundary.ID] = existingTitle
+		}
+
+		trustBoundary := existing.TrustBoundaries[existingTitle]
+		for _, id := range importedBoundary.TechnicalAssetsInside {
+			if !placed[id] {
+				trustBoundary.TechnicalAssetsInside = append(trustBoundary.TechnicalAssetsInside, id)
+				placed[id] = true
+			}
+		}
+		for _, id := range importedBoundary.TrustBoundariesNested {
+			if !placed[id] {
+				trustBoundary.TrustBoundariesNested = append(trustBoundary.TrustBoundariesNested, id)
+				placed[id] = true
+			}
+		}
+		existing.TrustBoundaries[existingTitle] = trustBoundary
+	}
+
+	runtimeTitles := make(map[string]string)
+	for title, sharedRuntime := range existing.SharedRuntimes {
+		runtimeTitles[sharedRuntime.ID] = title
+	}
+	for _, title := range sortedKeys(imported.SharedRuntimes) {
+		importedRuntime := imported.SharedRuntimes[title]
+		existingTitle, ok := runtimeTitles[importedRuntime.ID]
+		if !ok {
+			existing.SharedRuntimes[UniqueKey(existing.SharedRuntimes, title)] = importedRuntime
+			continue
+		}
+
+		sharedRuntime := existing.SharedRuntimes[existingTitle]
+		sharedRuntime.TechnicalAssetsRunning = new(input.Strings).MergeUniqueSlice(sharedRuntime.TechnicalAssetsRunning, importedRuntime.TechnicalAssetsRunning)
+		existing.SharedRuntimes[existingTitle] = sharedRuntime
+	}
+}
+
+func hasLinkTo(technicalAsset input.TechnicalAsset, target string) bool {
+	for _, link := range technicalAsset.CommunicationLinks {
+		if link.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MergeIncludes merges the includes of a model read by LoadModel, except for the given file (like a re-generated include)
+func MergeIncludes(model *input.Model, filename string, except string) error {
+	exceptPath, _ := filepath.Abs(except)
+	dir := filepath.Dir(filename)
+	for _, include := range model.Includes {
+		includePath, _ := filepath.Abs(filepath.Join(dir, include))
+		if includePath == exceptPath {
+			continue
+		}
+		err := model.Merge(dir, include)
+		if err != nil {
+			return fmt.Errorf("unable to merge model include %q: %w", include, err)
+		}
+	}
+	return nil
+}
+
+// FindTechnicalAsset returns the title of the technical asset with the given id or title
+func FindTechnicalAsset(model *input.Model, idOrTitle string) (string, bool) {
+	if _, ok := model.TechnicalAssets[idOrTitle]; ok {
+		return idOrTitle, true
+	}
+	for title, technicalAsset := range model.TechnicalAssets {
+		if technicalAsset.ID == idOrTitle {
+			return title, true
+		}
+	}
+	return "", false
+}
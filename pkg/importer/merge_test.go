@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestMergeModel(t *testing.T) {
+	existing := NewModel("Shop")
+	web := NewTechnicalAsset("web", "Manually described web server", types.WebServer)
+	web.Tags = []string{"manual"}
+	web.CommunicationLinks["Orders"] = NewCommunicationLink("db", "Manually described link", types.JDBC)
+	existing.TechnicalAssets["Web"] = web
+	existing.DataAssets["Orders"] = NewDataAsset("orders", "Manually described orders")
+	existing.TrustBoundaries["Backend"] = input.TrustBoundary{ID: "backend", TechnicalAssetsInside: []string{"web"}}
+	existing.Questions["Who operates the shop?"] = "Ops"
+	existing.RiskTracking["some-risk@web"] = input.RiskTracking{Status: types.Accepted.String()}
+
+	imported := NewModel("Imported")
+	importedWeb := NewTechnicalAsset("web", "Imported web server", types.ReverseProxy)
+	importedWeb.Tags = []string{ReviewTag}
+	importedWeb.DataAssetsProcessed = []string{"orders"}
+	importedWeb.CommunicationLinks["db"] = NewCommunicationLink("db", "Imported link", types.HTTP)
+	importedWeb.CommunicationLinks["cache"] = NewCommunicationLink("cache", "Imported link", types.HTTP)
+	imported.TechnicalAssets["web"] = importedWeb
+	imported.TechnicalAssets["Web"] = NewTechnicalAsset("web-2", "Imported web server with a taken title", types.WebServer)
+	imported.TechnicalAssets["cache"] = NewTechnicalAsset("cache", "Imported cache", types.Database)
+	imported.DataAssets["Orders"] = NewDataAsset("orders", "Imported orders")
+	imported.TrustBoundaries["Network backend"] = input.TrustBoundary{ID: "backend", TechnicalAssetsInside: []string{"web", "cache"}}
+	imported.TrustBoundaries["Network frontend"] = input.TrustBoundary{ID: "frontend", TechnicalAssetsInside: []string{"web", "web-2"}}
+	imported.SharedRuntimes["Runtime"] = input.SharedRuntime{ID: "runtime", TechnicalAssetsRunning: []string{"web", "cache"}}
+	imported.Questions["Who operates the shop?"] = ""
+	imported.Questions["Which data assets are processed?"] = ""
+	imported.RiskTracking["some-risk@web"] = input.RiskTracking{Status: types.Unchecked.String()}
+	imported.TagsAvailable = []string{ReviewTag}
+
+	MergeModel(existing, imported)
+
+	// existing values win
+	web = existing.TechnicalAssets["Web"]
+	assert.Equal(t, "Manually described web server", web.Description)
+	assert.Equal(t, types.WebServer.String(), web.Technology)
+	assert.Equal(t, []string{"manual", ReviewTag}, web.Tags)
+	assert.Equal(t, []string{"orders"}, web.DataAssetsProcessed)
+	assert.Len(t, web.CommunicationLinks, 2, "links to targets already linked are skipped")
+	assert.Equal(t, "Manually described link", web.CommunicationLinks["Orders"].Description)
+	assert.Equal(t, "cache", web.CommunicationLinks["cache"].Target)
+	assert.Equal(t, "Manually described orders", existing.DataAssets["Orders"].Description)
+	assert.Len(t, existing.DataAssets, 1)
+	assert.Equal(t, "Ops", existing.Questions["Who operates the shop?"])
+	assert.Equal(t, types.Accepted.String(), existing.RiskTracking["some-risk@web"].Status)
+
+	// missing elements are added
+	require.Contains(t, existing.TechnicalAssets, "cache")
+	require.Contains(t, existing.TechnicalAssets, "Web 2")
+	assert.Equal(t, "web-2", existing.TechnicalAssets["Web 2"].ID)
+	assert.Contains(t, existing.Questions, "Which data assets are processed?")
+	assert.Contains(t, existing.TagsAvailable, ReviewTag)
+	assert.Equal(t, []string{"web", "cache"}, existing.TrustBoundaries["Backend"].TechnicalAssetsInside)
+	require.Contains(t, existing.TrustBoundaries, "Network frontend")
+	assert.Equal(t, []string{"web-2"}, existing.TrustBoundaries["Network frontend"].TechnicalAssetsInside, "technical assets are only placed once")
+	assert.Equal(t, []string{"web", "cache"}, existing.SharedRuntimes["Runtime"].TechnicalAssetsRunning)
+}
+
+type findTechnicalAssetTest struct {
+	idOrTitle string
+	expected  string
+	ok        bool
+}
+
+func TestFindTechnicalAsset(t *testing.T) {
+	model := NewModel("Shop")
+	model.TechnicalAssets["Web Server"] = NewTechnicalAsset("web", "Web server", types.WebServer)
+
+	testCases := map[string]findTechnicalAssetTest{
+		"by title": {
+			idOrTitle: "Web Server",
+			expected:  "Web Server",
+			ok:        true,
+		},
+		"by id": {
+			idOrTitle: "web",
+			expected:  "Web Server",
+			ok:        true,
+		},
+		"unknown": {
+			idOrTitle: "database",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := FindTechnicalAsset(model, testCase.idOrTitle)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/docs"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// NewModel creates an empty model with all mandatory values set, ready to be filled by an importer.
+func NewModel(title string) *input.Model {
+	model := new(input.Model).Defaults()
+	model.ThreagileVersion = docs.ThreagileVersion
+	model.Title = title
+	model.Date = time.Now().Format("2006-01-02")
+	model.BusinessCriticality = types.Important.String()
+	return model
+}
+
+// NewTechnicalAsset creates a technical asset with conservative defaults for everything an importer usually can't know.
+func NewTechnicalAsset(id string, description string, technology types.TechnicalAssetTechnology) input.TechnicalAsset {
+	assetType := GuessTechnicalAssetType(technology)
+	return input.TechnicalAsset{
+		ID:                  id,
+		Description:         description,
+		Type:                assetType.String(),
+		Usage:               types.Business.String(),
+		UsedAsClientByHuman: technology == types.Browser || technology == types.Desktop || technology == types.MobileApp,
+		Size:                types.Service.String(),
+		Technology:          technology.String(),
+		Machine:             types.Virtual.String(),
+		Encryption:          types.NoneEncryption.String(),
+		Confidentiality:     types.Internal.String(),
+		Integrity:           types.Operational.String(),
+		Availability:        types.Operational.String(),
+		CommunicationLinks:  make(map[string]input.CommunicationLink),
+	}
+}
+
+func GuessTechnicalAssetType(technology types.TechnicalAssetTechnology) types.TechnicalAssetType {
+	switch {
+	case technology.IsClient():
+		return types.ExternalEntity
+	case technology == types.Database || technology == types.FileServer || technology == types.LocalFileSystem ||
+		technology == types.BlockStorage || technology == types.DataLake || technology == types.SearchIndex ||
+		technology == types.IdentityStoreDatabase || technology == types.IdentityStoreLDAP:
+		return types.Datastore
+	default:
+		return types.Process
+	}
+}
+
+func NewDataAsset(id string, description string) input.DataAsset {
+	return input.DataAsset{
+		ID:              id,
+		Description:     description,
+		Usage:           types.Business.String(),
+		Quantity:        types.Few.String(),
+		Confidentiality: types.Internal.String(),
+		Integrity:       types.Operational.String(),
+		Availability:    types.Operational.String(),
+	}
+}
+
+func NewCommunicationLink(target string, description string, protocol types.Protocol) input.CommunicationLink {
+	return input.CommunicationLink{
+		Target:         target,
+		Description:    description,
+		Protocol:       protocol.String(),
+		Authentication: types.NoneAuthentication.String(),
+		Authorization:  types.NoneAuthorization.String(),
+		Usage:          types.Business.String(),
+	}
+}
+
+// ReviewTag marks imported elements whose values are guessed and need to be reviewed by the modeller
+const ReviewTag = "review"
+
+const DataAssetsQuestion = "Which data assets are processed, stored and transferred by the technical assets?"
+
+// AddQuestion records something the importer couldn't decide, optionally answered with what it found
+func AddQuestion(model *input.Model, question string, answer string) {
+	model.Questions[UniqueKey(model.Questions, question)] = answer
+}
+
+// MarkForReview tags the technical asset for review and records the reason as question
+func MarkForReview(model *input.Model, asset *input.TechnicalAsset, question string, answer string) {
+	if !contains(asset.Tags, ReviewTag) {
+		asset.Tags = append(asset.Tags, ReviewTag)
+		model.AddTagToModelInput(ReviewTag, false, new([]string))
+	}
+	AddQuestion(model, question, answer)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ExternalClient returns the title of the (out of scope) client representing everybody on the internet, created on first use
+func ExternalClient(model *input.Model, takenIds map[string]bool) string {
+	title := "External Client"
+	if _, ok := model.TechnicalAssets[title]; !ok {
+		client := NewTechnicalAsset(UniqueID(title, takenIds), "Any client reaching the system from the internet", types.ClientSystem)
+		client.Internet = true
+		client.OutOfScope = true
+		client.JustificationOutOfScope = "Owned by the users"
+		model.TechnicalAssets[title] = client
+		AddQuestion(model, "Which clients access the system from the internet?", "")
+	}
+	return title
+}
+
+// UniqueKey returns the key itself or, if already taken in the map, the key with a numeric suffix.
+func UniqueKey[T any](values map[string]T, key string) string {
+	if _, exists := values[key]; !exists {
+		return key
+	}
+
+	for i := 2; ; i++ {
+		candidate := key + " " + strconv.Itoa(i)
+		if _, exists := values[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// UniqueID returns a valid Threagile id for a foreign name, made unique against the ids already taken.
+func UniqueID(name string, taken map[string]bool) string {
+	id := types.MakeID(name)
+	if len(id) == 0 {
+		id = "unnamed"
+	}
+
+	candidate := id
+	for i := 2; taken[candidate]; i++ {
+		candidate = id + "-" + strconv.Itoa(i)
+	}
+
+	taken[candidate] = true
+	return candidate
+}
+
+func WriteModel(model *input.Model, filename string) error {
+	yamlBytes, err := yaml.Marshal(model)
+	if err != nil {
+		return fmt.Errorf("unable to marshal model: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Clean(filename), yamlBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write model file %q: %w", filename, err)
+	}
+
+	return nil
+}
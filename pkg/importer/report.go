@@ -0,0 +1,39 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Report collects everything an importer was unable to map, so the modeller can finish the job manually.
+type Report struct {
+	Items []string
+}
+
+func (what *Report) Add(format string, a ...any) {
+	what.Items = append(what.Items, fmt.Sprintf(format, a...))
+}
+
+// AddThreat notes a threat mapped to a risk category, it can't be imported as risk tracking since the rules build
+// the synthetic risk ids from all elements involved, which are only known once the model is analyzed
+func (what *Report) AddThreat(where string, categoryId string, status types.RiskStatus) {
+	what.Add("%v: risk category %q with status %q, add the risk tracking once the risk id is known from the analysis", where, categoryId, status.String())
+}
+
+func (what *Report) IsEmpty() bool {
+	return len(what.Items) == 0
+}
+
+func (what *Report) Write(writer io.Writer) {
+	if what.IsEmpty() {
+		_, _ = fmt.Fprintln(writer, "Everything could be mapped.")
+		return
+	}
+
+	_, _ = fmt.Fprintf(writer, "The following %d item(s) could not be mapped and need to be completed manually:\n", len(what.Items))
+	for _, item := range what.Items {
+		_, _ = fmt.Fprintln(writer, " -", item)
+	}
+}
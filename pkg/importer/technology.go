@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type imageGuess struct {
+	names      []string
+	technology types.TechnicalAssetTechnology
+	protocol   types.Protocol
+}
+
+// well known container images, matched against the image name without registry, path and tag
+var imageGuesses = []imageGuess{
+	{[]string{"postgres", "postgis", "mysql", "mariadb", "mssql", "sqlserver", "oracle", "cockroach", "db2"}, types.Database, types.SqlAccessProtocol},
+	{[]string{"mongo", "cassandra", "couchdb", "couchbase", "redis", "valkey", "memcached", "neo4j", "influxdb", "dynamodb", "scylla"}, types.Database, types.NosqlAccessProtocol},
+	{[]string{"elasticsearch", "opensearch", "solr", "meilisearch", "typesense"}, types.SearchEngine, types.HTTP},
+	{[]string{"nginx", "traefik", "haproxy", "envoy", "caddy", "ingress"}, types.ReverseProxy, types.HTTP},
+	{[]string{"httpd", "apache", "lighttpd"}, types.WebServer, types.HTTP},
+	{[]string{"tomcat", "jetty", "wildfly", "jboss", "websphere", "weblogic", "payara", "glassfish"}, types.ApplicationServer, types.HTTP},
+	{[]string{"wordpress", "drupal", "joomla", "ghost", "strapi"}, types.CMS, types.HTTP},
+	{[]string{"kafka", "rabbitmq", "activemq", "artemis", "nats", "pulsar", "zookeeper"}, types.MessageQueue, types.BINARY},
+	{[]string{"mosquitto", "emqx", "hivemq", "vernemq"}, types.MessageQueue, types.MQTT},
+	{[]string{"keycloak", "dex", "authelia", "authentik", "hydra", "zitadel"}, types.IdentityProvider, types.HTTPS},
+	{[]string{"openldap", "ldap"}, types.LDAPServer, types.LDAP},
+	{[]string{"vault", "openbao"}, types.Vault, types.HTTPS},
+	{[]string{"prometheus", "grafana", "loki", "jaeger", "zipkin", "kibana", "logstash", "fluentd", "fluent-bit", "otel", "splunk"}, types.Monitoring, types.HTTP},
+	{[]string{"jenkins", "drone", "gocd", "tekton"}, types.BuildPipeline, types.HTTPS},
+	{[]string{"gitlab", "gitea", "gogs", "forgejo", "bitbucket"}, types.SourcecodeRepository, types.HTTPS},
+	{[]string{"nexus", "artifactory", "registry", "harbor"}, types.ArtifactRegistry, types.HTTPS},
+	{[]string{"sonarqube", "sonar"}, types.CodeInspectionPlatform, types.HTTPS},
+	{[]string{"minio", "seaweedfs", "samba", "nfs", "ftp"}, types.FileServer, types.HTTP},
+	{[]string{"postfix", "mailhog", "mailpit", "exim", "dovecot"}, types.MailServer, types.SMTP},
+	{[]string{"spark", "flink", "storm"}, types.StreamProcessing, types.BINARY},
+	{[]string{"hadoop", "hive", "presto", "trino", "clickhouse"}, types.BigDataPlatform, types.BINARY},
+	{[]string{"airflow", "cron", "ofelia"}, types.Scheduler, types.HTTP},
+	{[]string{"consul", "eureka", "etcd", "schema-registry"}, types.ServiceRegistry, types.HTTP},
+	{[]string{"istio", "linkerd"}, types.ServiceMesh, types.HTTPS},
+	{[]string{"modsecurity", "coraza"}, types.WAF, types.HTTP},
+}
+
+// protocols of well known ports, used when the image is unknown
+var portProtocols = map[int]types.Protocol{
+	21:    types.FTP,
+	22:    types.SSH,
+	25:    types.SMTP,
+	80:    types.HTTP,
+	110:   types.POP3,
+	143:   types.IMAP,
+	389:   types.LDAP,
+	443:   types.HTTPS,
+	445:   types.SMB,
+	465:   types.SmtpEncrypted,
+	587:   types.SMTP,
+	636:   types.LDAPS,
+	993:   types.ImapEncrypted,
+	995:   types.Pop3Encrypted,
+	1433:  types.SqlAccessProtocol,
+	1521:  types.SqlAccessProtocol,
+	1883:  types.MQTT,
+	2049:  types.NFS,
+	3000:  types.HTTP,
+	3306:  types.SqlAccessProtocol,
+	5432:  types.SqlAccessProtocol,
+	5671:  types.BinaryEncrypted,
+	5672:  types.BINARY,
+	6379:  types.NosqlAccessProtocol,
+	8000:  types.HTTP,
+	8080:  types.HTTP,
+	8443:  types.HTTPS,
+	8883:  types.MQTT,
+	9042:  types.NosqlAccessProtocol,
+	9092:  types.BINARY,
+	9200:  types.HTTP,
+	15672: types.HTTP,
+	27017: types.NosqlAccessProtocol,
+}
+
+// GuessTechnology guesses technology and protocol of a container: the mapping (by image or by name) wins over well known
+// images, the protocol falls back to well known ports of the container; the result is false if it's only a fallback
+func GuessTechnology(mapping Mapping, image string, name string, ports []int) (types.TechnicalAssetTechnology, types.Protocol, bool) {
+	technology, protocol, guessed := GuessTechnologyFromImage(image)
+	for _, candidate := range []string{image, name} {
+		if technologyName, ok := mapping.Resolve(MappingTechnology, candidate, EnumNames(types.TechnicalAssetTechnologyValues())); ok {
+			technology, _ = types.ParseTechnicalAssetTechnology(technologyName)
+			guessed = true
+			break
+		}
+	}
+
+	for _, port := range ports {
+		if portProtocol, ok := GuessProtocolFromPort(port); ok && protocol == types.UnknownProtocol {
+			protocol = portProtocol
+		}
+	}
+
+	// most unknown images speaking http are custom developed services
+	if !guessed && (protocol == types.HTTP || protocol == types.HTTPS) {
+		technology = types.WebServiceREST
+	}
+	return technology, protocol, guessed
+}
+
+// GuessTechnologyFromImage guesses technology and protocol of a container by its image name, e.g. "docker.io/library/postgres:16"
+func GuessTechnologyFromImage(image string) (types.TechnicalAssetTechnology, types.Protocol, bool) {
+	name := strings.ToLower(image)
+	if index := strings.LastIndex(name, "@"); index >= 0 {
+		name = name[:index]
+	}
+	if index := strings.LastIndex(name, "/"); index >= 0 {
+		name = name[index+1:]
+	}
+	if index := strings.Index(name, ":"); index >= 0 {
+		name = name[:index]
+	}
+	if len(name) == 0 {
+		return types.UnknownTechnology, types.UnknownProtocol, false
+	}
+
+	for _, guess := range imageGuesses {
+		for _, candidate := range guess.names {
+			if name == candidate || strings.HasPrefix(name, candidate+"-") || strings.HasPrefix(name, candidate+"_") ||
+				strings.HasSuffix(name, "-"+candidate) || strings.HasSuffix(name, "_"+candidate) {
+				return guess.technology, guess.protocol, true
+			}
+		}
+	}
+	return types.UnknownTechnology, types.UnknownProtocol, false
+}
+
+// GuessProtocolFromPort guesses the protocol spoken on a well known port
+func GuessProtocolFromPort(port int) (types.Protocol, bool) {
+	protocol, ok := portProtocols[port]
+	return protocol, ok
+}
@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type guessTechnologyFromImageTest struct {
+	image              string
+	expectedTechnology types.TechnicalAssetTechnology
+	expectedProtocol   types.Protocol
+	guessed            bool
+}
+
+func TestGuessTechnologyFromImage(t *testing.T) {
+	testCases := map[string]guessTechnologyFromImageTest{
+		"plain": {
+			image:              "postgres",
+			expectedTechnology: types.Database,
+			expectedProtocol:   types.SqlAccessProtocol,
+			guessed:            true,
+		},
+		"registry, path, tag and digest": {
+			image:              "localhost:5000/library/Redis:7-alpine@sha256:1234",
+			expectedTechnology: types.Database,
+			expectedProtocol:   types.NosqlAccessProtocol,
+			guessed:            true,
+		},
+		"prefix": {
+			image:              "bitnami/keycloak-config-cli:5",
+			expectedTechnology: types.IdentityProvider,
+			expectedProtocol:   types.HTTPS,
+			guessed:            true,
+		},
+		"suffix": {
+			image:              "company/shop_nginx",
+			expectedTechnology: types.ReverseProxy,
+			expectedProtocol:   types.HTTP,
+			guessed:            true,
+		},
+		"unknown": {
+			image:              "company/shop-backend:1.0",
+			expectedTechnology: types.UnknownTechnology,
+			expectedProtocol:   types.UnknownProtocol,
+		},
+		"empty": {
+			image:              "",
+			expectedTechnology: types.UnknownTechnology,
+			expectedProtocol:   types.UnknownProtocol,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			technology, protocol, guessed := GuessTechnologyFromImage(testCase.image)
+
+			assert.Equal(t, testCase.expectedTechnology, technology)
+			assert.Equal(t, testCase.expectedProtocol, protocol)
+			assert.Equal(t, testCase.guessed, guessed)
+		})
+	}
+}
+
+type guessTechnologyTest struct {
+	image              string
+	name               string
+	ports              []int
+	expectedTechnology types.TechnicalAssetTechnology
+	expectedProtocol   types.Protocol
+	guessed            bool
+}
+
+func TestGuessTechnology(t *testing.T) {
+	mapping := Mapping{MappingTechnology: {"shop-erp": types.ERP.String()}}
+	testCases := map[string]guessTechnologyTest{
+		"by image": {
+			image:              "mysql:8",
+			expectedTechnology: types.Database,
+			expectedProtocol:   types.SqlAccessProtocol,
+			guessed:            true,
+		},
+		"mapped by name": {
+			image:              "company/erp",
+			name:               "shop-erp",
+			ports:              []int{8443},
+			expectedTechnology: types.ERP,
+			expectedProtocol:   types.HTTPS,
+			guessed:            true,
+		},
+		"unknown image speaking http": {
+			image:              "company/backend",
+			ports:              []int{1234, 8080},
+			expectedTechnology: types.WebServiceREST,
+			expectedProtocol:   types.HTTP,
+		},
+		"unknown image and port": {
+			image:              "company/backend",
+			ports:              []int{1234},
+			expectedTechnology: types.UnknownTechnology,
+			expectedProtocol:   types.UnknownProtocol,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			technology, protocol, guessed := GuessTechnology(mapping, testCase.image, testCase.name, testCase.ports)
+
+			assert.Equal(t, testCase.expectedTechnology, technology)
+			assert.Equal(t, testCase.expectedProtocol, protocol)
+			assert.Equal(t, testCase.guessed, guessed)
+		})
+	}
+}
@@ -40,7 +40,9 @@ type Model struct { // TODO: Eventually remove this and directly use ParsedModel
 	TrustBoundaries                               map[string]TrustBoundary          `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
 	SharedRuntimes                                map[string]SharedRuntime          `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
 	IndividualRiskCategories                      map[string]IndividualRiskCategory `yaml:"individual_risk_categories,omitempty" json:"individual_risk_categories,omitempty"`
+	IndividualRiskRules                           map[string]IndividualRiskRule     `yaml:"individual_risk_rules,omitempty" json:"individual_risk_rules,omitempty"`
 	RiskTracking                                  map[string]RiskTracking           `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
+	RiskRatingOverrides                           map[string]RiskRatingOverride     `yaml:"risk_rating_overrides,omitempty" json:"risk_rating_overrides,omitempty"`
 	DiagramTweakNodesep                           int                               `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty"`
 	DiagramTweakRanksep                           int                               `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty"`
 	DiagramTweakEdgeLayout                        string                            `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty"`
@@ -60,7 +62,9 @@ func (model *Model) Defaults() *Model {
 		TrustBoundaries:          make(map[string]TrustBoundary),
 		SharedRuntimes:           make(map[string]SharedRuntime),
 		IndividualRiskCategories: make(map[string]IndividualRiskCategory),
+		IndividualRiskRules:      make(map[string]IndividualRiskRule),
 		RiskTracking:             make(map[string]RiskTracking),
+		RiskRatingOverrides:      make(map[string]RiskRatingOverride),
 	}
 
 	return model
@@ -244,6 +248,13 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 			}
 			break
 
+		case strings.ToLower("individual_risk_rules"):
+			model.IndividualRiskRules, mergeError = new(IndividualRiskRule).MergeMap(model.IndividualRiskRules, includedModel.IndividualRiskRules)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge risk rules: %v", mergeError)
+			}
+			break
+
 		case strings.ToLower("risk_tracking"):
 			model.RiskTracking, mergeError = new(RiskTracking).MergeMap(model.RiskTracking, includedModel.RiskTracking)
 			if mergeError != nil {
@@ -251,6 +262,13 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 			}
 			break
 
+		case strings.ToLower("risk_rating_overrides"):
+			model.RiskRatingOverrides, mergeError = new(RiskRatingOverride).MergeMap(model.RiskRatingOverrides, includedModel.RiskRatingOverrides)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge risk rating overrides: %v", mergeError)
+			}
+			break
+
 		case "diagram_tweak_nodesep":
 			model.DiagramTweakNodesep = includedModel.DiagramTweakNodesep
 			break
@@ -0,0 +1,53 @@
+package input
+
+import "fmt"
+
+type RiskRatingOverride struct {
+	Category               string   `yaml:"category,omitempty" json:"category,omitempty"`
+	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	ExploitationLikelihood string   `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
+	ExploitationImpact     string   `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
+	Justification          string   `yaml:"justification,omitempty" json:"justification,omitempty"`
+}
+
+func (what *RiskRatingOverride) Merge(other RiskRatingOverride) error {
+	var mergeError error
+	what.Category, mergeError = new(Strings).MergeSingleton(what.Category, other.Category)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge category: %v", mergeError)
+	}
+
+	what.Tags = new(Strings).MergeUniqueSlice(what.Tags, other.Tags)
+
+	what.ExploitationLikelihood, mergeError = new(Strings).MergeSingleton(what.ExploitationLikelihood, other.ExploitationLikelihood)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge exploitation_likelihood: %v", mergeError)
+	}
+
+	what.ExploitationImpact, mergeError = new(Strings).MergeSingleton(what.ExploitationImpact, other.ExploitationImpact)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge exploitation_impact: %v", mergeError)
+	}
+
+	what.Justification = new(Strings).MergeMultiline(what.Justification, other.Justification)
+
+	return nil
+}
+
+func (what *RiskRatingOverride) MergeMap(first map[string]RiskRatingOverride, second map[string]RiskRatingOverride) (map[string]RiskRatingOverride, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge risk rating override %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
@@ -0,0 +1,190 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IndividualRiskRule declares a risk category and the elements of the model having this risk by expressions
+type IndividualRiskRule struct {
+	ID                         string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Description                string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Impact                     string   `yaml:"impact,omitempty" json:"impact,omitempty"`
+	ASVS                       string   `yaml:"asvs,omitempty" json:"asvs,omitempty"`
+	CheatSheet                 string   `yaml:"cheat_sheet,omitempty" json:"cheat_sheet,omitempty"`
+	Action                     string   `yaml:"action,omitempty" json:"action,omitempty"`
+	Mitigation                 string   `yaml:"mitigation,omitempty" json:"mitigation,omitempty"`
+	Check                      string   `yaml:"check,omitempty" json:"check,omitempty"`
+	Function                   string   `yaml:"function,omitempty" json:"function,omitempty"`
+	STRIDE                     string   `yaml:"stride,omitempty" json:"stride,omitempty"`
+	DetectionLogic             string   `yaml:"detection_logic,omitempty" json:"detection_logic,omitempty"`
+	RiskAssessment             string   `yaml:"risk_assessment,omitempty" json:"risk_assessment,omitempty"`
+	FalsePositives             string   `yaml:"false_positives,omitempty" json:"false_positives,omitempty"`
+	ModelFailurePossibleReason bool     `yaml:"model_failure_possible_reason,omitempty" json:"model_failure_possible_reason,omitempty"`
+	CWE                        int      `yaml:"cwe,omitempty" json:"cwe,omitempty"`
+	SupportedTags              []string `yaml:"supported_tags,omitempty" json:"supported_tags,omitempty"`
+	Match                      string   `yaml:"match,omitempty" json:"match,omitempty"`
+	Condition                  string   `yaml:"condition,omitempty" json:"condition,omitempty"`
+	Title                      string   `yaml:"title,omitempty" json:"title,omitempty"`
+	ExploitationLikelihood     string   `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
+	ExploitationImpact         string   `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
+	DataBreachProbability      string   `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
+	SyntheticId                []string `yaml:"synthetic_id,omitempty" json:"synthetic_id,omitempty"`
+}
+
+// LoadRiskRules reads the rules of a yaml file containing a map of rule titles to rules
+func LoadRiskRules(filename string) (map[string]IndividualRiskRule, error) {
+	data, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, readError
+	}
+
+	rules := make(map[string]IndividualRiskRule)
+	unmarshalError := yaml.Unmarshal(data, &rules)
+	if unmarshalError != nil {
+		return nil, unmarshalError
+	}
+
+	return rules, nil
+}
+
+// LoadRiskRulesFolder reads the rules of all yaml files of the folder
+func LoadRiskRulesFolder(folder string) (map[string]IndividualRiskRule, error) {
+	filenames := make([]string, 0)
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, globError := filepath.Glob(filepath.Join(folder, pattern))
+		if globError != nil {
+			return nil, globError
+		}
+		filenames = append(filenames, matches...)
+	}
+	sort.Strings(filenames)
+
+	rules := make(map[string]IndividualRiskRule)
+	for _, filename := range filenames {
+		fileRules, loadError := LoadRiskRules(filename)
+		if loadError != nil {
+			return nil, fmt.Errorf("failed to load risk rules from %q: %v", filename, loadError)
+		}
+
+		for title, rule := range fileRules {
+			if _, exists := rules[title]; exists {
+				return nil, fmt.Errorf("duplicate risk rule %q in %q", title, filename)
+			}
+			rules[title] = rule
+		}
+	}
+
+	return rules, nil
+}
+
+func (what *IndividualRiskRule) Merge(other IndividualRiskRule) error {
+	var mergeError error
+	what.ID, mergeError = new(Strings).MergeSingleton(what.ID, other.ID)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge id: %v", mergeError)
+	}
+
+	what.Description = new(Strings).MergeMultiline(what.Description, other.Description)
+	what.Impact = new(Strings).MergeMultiline(what.Impact, other.Impact)
+
+	what.ASVS, mergeError = new(Strings).MergeSingleton(what.ASVS, other.ASVS)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge asvs: %v", mergeError)
+	}
+
+	what.CheatSheet, mergeError = new(Strings).MergeSingleton(what.CheatSheet, other.CheatSheet)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge cheat_sheet: %v", mergeError)
+	}
+
+	what.Action, mergeError = new(Strings).MergeSingleton(what.Action, other.Action)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge action: %v", mergeError)
+	}
+
+	what.Mitigation = new(Strings).MergeMultiline(what.Mitigation, other.Mitigation)
+	what.Check = new(Strings).MergeMultiline(what.Check, other.Check)
+
+	what.Function, mergeError = new(Strings).MergeSingleton(what.Function, other.Function)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge function: %v", mergeError)
+	}
+
+	what.STRIDE, mergeError = new(Strings).MergeSingleton(what.STRIDE, other.STRIDE)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge STRIDE: %v", mergeError)
+	}
+
+	what.DetectionLogic = new(Strings).MergeMultiline(what.DetectionLogic, other.DetectionLogic)
+	what.RiskAssessment = new(Strings).MergeMultiline(what.RiskAssessment, other.RiskAssessment)
+	what.FalsePositives = new(Strings).MergeMultiline(what.FalsePositives, other.FalsePositives)
+
+	if what.ModelFailurePossibleReason == false {
+		what.ModelFailurePossibleReason = other.ModelFailurePossibleReason
+	}
+
+	if what.CWE == 0 {
+		what.CWE = other.CWE
+	}
+
+	what.SupportedTags = new(Strings).MergeUniqueSlice(what.SupportedTags, other.SupportedTags)
+
+	what.Match, mergeError = new(Strings).MergeSingleton(what.Match, other.Match)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge match: %v", mergeError)
+	}
+
+	what.Condition, mergeError = new(Strings).MergeSingleton(what.Condition, other.Condition)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge condition: %v", mergeError)
+	}
+
+	what.Title, mergeError = new(Strings).MergeSingleton(what.Title, other.Title)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge title: %v", mergeError)
+	}
+
+	what.ExploitationLikelihood, mergeError = new(Strings).MergeSingleton(what.ExploitationLikelihood, other.ExploitationLikelihood)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge exploitation_likelihood: %v", mergeError)
+	}
+
+	what.ExploitationImpact, mergeError = new(Strings).MergeSingleton(what.ExploitationImpact, other.ExploitationImpact)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge exploitation_impact: %v", mergeError)
+	}
+
+	what.DataBreachProbability, mergeError = new(Strings).MergeSingleton(what.DataBreachProbability, other.DataBreachProbability)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge data_breach_probability: %v", mergeError)
+	}
+
+	if len(what.SyntheticId) == 0 {
+		what.SyntheticId = other.SyntheticId
+	}
+
+	return nil
+}
+
+func (what *IndividualRiskRule) MergeMap(first map[string]IndividualRiskRule, second map[string]IndividualRiskRule) (map[string]IndividualRiskRule, error) {
+	for mapKey, mapValue := range second {
+		mapItem, ok := first[mapKey]
+		if ok {
+			mergeError := mapItem.Merge(mapValue)
+			if mergeError != nil {
+				return first, fmt.Errorf("failed to merge risk rule %q: %v", mapKey, mergeError)
+			}
+
+			first[mapKey] = mapItem
+		} else {
+			first[mapKey] = mapValue
+		}
+	}
+
+	return first, nil
+}
@@ -29,6 +29,7 @@ type TechnicalAsset struct {
 	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty"`
 	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty"`
 	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
+	SBOM                    string                       `yaml:"sbom,omitempty" json:"sbom,omitempty"`
 }
 
 func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
@@ -133,6 +134,11 @@ func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
 		what.DiagramTweakOrder = other.DiagramTweakOrder
 	}
 
+	what.SBOM, mergeError = new(Strings).MergeSingleton(what.SBOM, other.SBOM)
+	if mergeError != nil {
+		return fmt.Errorf("failed to merge sbom: %v", mergeError)
+	}
+
 	what.CommunicationLinks, mergeError = new(CommunicationLink).MergeMap(what.CommunicationLinks, other.CommunicationLinks)
 	if mergeError != nil {
 		return fmt.Errorf("failed to merge communication_links: %v", mergeError)
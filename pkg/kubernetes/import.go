@@ -0,0 +1,440 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const defaultNamespace = "default"
+
+// label set on every namespace since kubernetes 1.21, used to resolve namespace selectors
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+type workload struct {
+	object   *Object
+	title    string
+	id       string
+	protocol types.Protocol
+}
+
+type converter struct {
+	model     *input.Model
+	mapping   importer.Mapping
+	report    *importer.Report
+	takenIds  map[string]bool
+	workloads []*workload
+	services  map[string][]*workload
+	runtime   string
+}
+
+// Import turns workloads (deployments, stateful sets and daemon sets) into technical assets running in a shared runtime,
+// namespaces into trust boundaries, services referenced by environment variables, ingresses and network policies into
+// communication links and referenced secrets into data assets; guesses are tagged for review.
+func Import(objects []Object, title string, mapping importer.Mapping) (*input.Model, *importer.Report) {
+	what := &converter{
+		model:    importer.NewModel(title),
+		mapping:  mapping,
+		report:   new(importer.Report),
+		takenIds: make(map[string]bool),
+		services: make(map[string][]*workload),
+	}
+	what.model.AppDescription.Description = "Imported from kubernetes manifests " + title
+
+	byKind := make(map[string][]*Object)
+	for i := range objects {
+		byKind[objects[i].Kind] = append(byKind[objects[i].Kind], &objects[i])
+	}
+	for _, kind := range sortedKeys(byKind) {
+		sort.SliceStable(byKind[kind], func(i, j int) bool {
+			return byKind[kind][i].namespace()+"/"+byKind[kind][i].Metadata.Name < byKind[kind][j].namespace()+"/"+byKind[kind][j].Metadata.Name
+		})
+		switch kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "Service", "Ingress", "NetworkPolicy", "Secret":
+		default:
+			what.report.Add("ignored %d object(s) of kind %v", len(byKind[kind]), kind)
+		}
+	}
+
+	cluster := importer.NewTechnicalAsset(importer.UniqueID("kubernetes cluster", what.takenIds), "Kubernetes cluster (control plane) running the workloads and storing their secrets", types.ContainerPlatform)
+	what.model.TechnicalAssets["Kubernetes Cluster"] = cluster
+	what.runtime = "Kubernetes Cluster"
+	what.model.SharedRuntimes[what.runtime] = input.SharedRuntime{
+		ID:          importer.UniqueID("kubernetes runtime", what.takenIds),
+		Description: "Kubernetes cluster running the workloads",
+	}
+
+	what.addWorkloads(append(append(byKind["Deployment"], byKind["StatefulSet"]...), byKind["DaemonSet"]...))
+	what.addServices(byKind["Service"])
+	what.addEnvironmentLinks()
+	what.addIngresses(byKind["Ingress"])
+	what.addNetworkPolicies(byKind["NetworkPolicy"])
+	what.addSecrets(byKind["Secret"])
+
+	importer.AddQuestion(what.model, importer.DataAssetsQuestion, "")
+	sort.Strings(what.model.TagsAvailable)
+	return what.model, what.report
+}
+
+// Technical Assets and Trust Boundaries ===============================================================================
+
+func (what *converter) addWorkloads(objects []*Object) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].namespace()+"/"+objects[i].Metadata.Name < objects[j].namespace()+"/"+objects[j].Metadata.Name
+	})
+
+	nameCount := make(map[string]int)
+	for _, object := range objects {
+		nameCount[object.Metadata.Name]++
+	}
+
+	for _, object := range objects {
+		name, namespace := object.Metadata.Name, object.namespace()
+		if len(object.Spec.Template.Spec.Containers) == 0 {
+			what.report.Add("%v '%v': no containers found", object.Kind, name)
+			continue
+		}
+
+		title, qualifiedName := name, name
+		if namespace != defaultNamespace {
+			qualifiedName = namespace + " " + name
+		}
+		if nameCount[name] > 1 {
+			title = fmt.Sprintf("%v (%v)", name, namespace)
+		}
+		title = importer.UniqueKey(what.model.TechnicalAssets, title)
+
+		images := make([]string, 0)
+		for _, container := range object.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		description := fmt.Sprintf("%v %v in namespace %v running image %v", object.Kind, name, namespace, strings.Join(images, ", "))
+
+		technology, protocol, guessed := importer.GuessTechnology(what.mapping, images[0], name, object.containerPorts())
+		current := &workload{object: object, title: title, id: importer.UniqueID(qualifiedName, what.takenIds), protocol: protocol}
+		what.workloads = append(what.workloads, current)
+
+		asset := importer.NewTechnicalAsset(current.id, description, technology)
+		asset.Machine = types.Container.String()
+		asset.Redundant = object.Kind == "DaemonSet" || object.replicas() > 1
+		asset.CustomDevelopedParts = !guessed
+		if !guessed {
+			importer.MarkForReview(what.model, &asset, fmt.Sprintf("Which technology is used by %v '%v'?", strings.ToLower(object.Kind), title), technology.String())
+			what.report.Add("%v '%v': unable to guess technology from image %v (using %q, add it to the mapping file to fix this)", object.Kind, title, images[0], technology.String())
+		}
+		what.model.TechnicalAssets[title] = asset
+		what.run(current.id)
+
+		boundaryTitle := "Namespace " + namespace
+		boundary, ok := what.model.TrustBoundaries[boundaryTitle]
+		if !ok {
+			boundary = input.TrustBoundary{
+				ID:          importer.UniqueID("namespace "+namespace, what.takenIds),
+				Description: "Kubernetes namespace " + namespace,
+				Type:        types.NetworkPolicyNamespaceIsolation.String(),
+			}
+		}
+		boundary.TechnicalAssetsInside = append(boundary.TechnicalAssetsInside, current.id)
+		what.model.TrustBoundaries[boundaryTitle] = boundary
+	}
+}
+
+func (what *converter) run(id string) {
+	runtime := what.model.SharedRuntimes[what.runtime]
+	runtime.TechnicalAssetsRunning = append(runtime.TechnicalAssetsRunning, id)
+	what.model.SharedRuntimes[what.runtime] = runtime
+}
+
+// Communication Links ===============================================================================
+
+func (what *converter) addServices(objects []*Object) {
+	for _, object := range objects {
+		name, namespace := object.Metadata.Name, object.namespace()
+		if object.Spec.Selector.IsEmpty() {
+			what.report.Add("service '%v': no selector, backing workloads are unknown", name)
+			continue
+		}
+
+		targets := make([]*workload, 0)
+		for _, current := range what.workloads {
+			if current.object.namespace() == namespace && object.Spec.Selector.Matches(current.object.Spec.Template.Metadata.Labels) {
+				targets = append(targets, current)
+			}
+		}
+		if len(targets) == 0 {
+			what.report.Add("service '%v': no workload found matching its selector", name)
+			continue
+		}
+		what.services[namespace+"/"+name] = targets
+
+		// load balancers and node ports are reachable from outside the cluster
+		if object.Spec.Type != "LoadBalancer" && object.Spec.Type != "NodePort" {
+			continue
+		}
+		clientTitle := importer.ExternalClient(what.model, what.takenIds)
+		for _, target := range targets {
+			asset := what.model.TechnicalAssets[target.title]
+			asset.Internet = true
+			what.model.TechnicalAssets[target.title] = asset
+
+			what.link(clientTitle, target, fmt.Sprintf("%v service %v", object.Spec.Type, name), what.protocolOf(target, servicePorts(object)))
+		}
+		what.ask(fmt.Sprintf("Is service '%v' of type %v reachable from the internet?", name, object.Spec.Type), "")
+	}
+}
+
+// addEnvironmentLinks links workloads to the services their environment variables refer to (like connection strings)
+func (what *converter) addEnvironmentLinks() {
+	for _, source := range what.workloads {
+		for _, container := range source.object.Spec.Template.Spec.Containers {
+			for _, env := range container.Env {
+				for _, service := range sortedKeys(what.services) {
+					if !refersTo(env.Value, service, source.object.namespace()) {
+						continue
+					}
+					for _, target := range what.services[service] {
+						if target != source {
+							what.link(source.title, target, fmt.Sprintf("environment variable %v of %v refers to service %v", env.Name, source.title, service), target.protocol)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func (what *converter) addIngresses(objects []*Object) {
+	for _, object := range objects {
+		name, namespace := object.Metadata.Name, object.namespace()
+
+		controllerTitle := "Ingress Controller"
+		if len(object.Spec.IngressClassName) > 0 {
+			controllerTitle += " " + object.Spec.IngressClassName
+		}
+		if _, ok := what.model.TechnicalAssets[controllerTitle]; !ok {
+			controller := importer.NewTechnicalAsset(importer.UniqueID(controllerTitle, what.takenIds), "Ingress controller routing requests from the internet to the services of the cluster", types.ReverseProxy)
+			controller.Machine = types.Container.String()
+			controller.Internet = true
+			what.model.TechnicalAssets[controllerTitle] = controller
+			what.run(controller.ID)
+		}
+
+		protocol := types.HTTP
+		if len(object.Spec.TLS) > 0 {
+			protocol = types.HTTPS
+		}
+		clientTitle := importer.ExternalClient(what.model, what.takenIds)
+		what.linkTo(clientTitle, what.model.TechnicalAssets[controllerTitle].ID, controllerTitle, "Ingress "+name, protocol)
+
+		for _, backend := range object.backends() {
+			targets, ok := what.services[namespace+"/"+backend.serviceName()]
+			if !ok {
+				what.report.Add("ingress '%v': unknown service %q referenced", name, backend.serviceName())
+				continue
+			}
+			for _, target := range targets {
+				backendProtocol := types.HTTP
+				if port, ok := backend.servicePort().(int); ok && port == 443 {
+					backendProtocol = types.HTTPS
+				}
+				what.link(controllerTitle, target, fmt.Sprintf("Ingress %v routes to service %v", name, backend.serviceName()), backendProtocol)
+			}
+		}
+	}
+}
+
+func (what *converter) addNetworkPolicies(objects []*Object) {
+	for _, object := range objects {
+		name, namespace := object.Metadata.Name, object.namespace()
+		selected := what.selectWorkloads(namespace, object.Spec.PodSelector)
+
+		for _, rule := range object.Spec.Ingress {
+			sources := what.peers(name, "from", namespace, rule.From)
+			for _, source := range sources {
+				for _, target := range selected {
+					if source != target {
+						what.link(source.title, target, "Allowed by network policy "+name, what.protocolOf(target, policyPorts(rule)))
+					}
+				}
+			}
+		}
+
+		for _, rule := range object.Spec.Egress {
+			targets := what.peers(name, "to", namespace, rule.To)
+			for _, source := range selected {
+				for _, target := range targets {
+					if source != target {
+						what.link(source.title, target, "Allowed by network policy "+name, what.protocolOf(target, policyPorts(rule)))
+					}
+				}
+			}
+		}
+	}
+}
+
+// peers resolves the workloads of network policy peers, peers which can't be resolved to workloads become questions
+func (what *converter) peers(policy string, direction string, namespace string, peers []NetworkPolicyPeer) []*workload {
+	if len(peers) == 0 {
+		what.ask(fmt.Sprintf("Network policy '%v' allows traffic %v everywhere, which systems do communicate?", policy, direction), "")
+		return nil
+	}
+
+	workloads := make([]*workload, 0)
+	for _, peer := range peers {
+		switch {
+		case peer.IPBlock != nil:
+			what.ask(fmt.Sprintf("Which systems in %v are allowed by network policy '%v'?", peer.IPBlock.CIDR, policy), "")
+
+		case peer.NamespaceSelector != nil && peer.NamespaceSelector.MatchLabels[namespaceNameLabel] == "":
+			what.ask(fmt.Sprintf("Which workloads of the namespaces selected by network policy '%v' do communicate?", policy), "")
+
+		case peer.PodSelector.IsEmpty():
+			peerNamespace := namespace
+			if peer.NamespaceSelector != nil {
+				peerNamespace = peer.NamespaceSelector.MatchLabels[namespaceNameLabel]
+			}
+			what.ask(fmt.Sprintf("Network policy '%v' allows traffic %v all pods of namespace %v, which workloads do communicate?", policy, direction, peerNamespace), "")
+
+		default:
+			peerNamespace := namespace
+			if peer.NamespaceSelector != nil {
+				peerNamespace = peer.NamespaceSelector.MatchLabels[namespaceNameLabel]
+			}
+			workloads = append(workloads, what.selectWorkloads(peerNamespace, peer.PodSelector)...)
+		}
+	}
+	return workloads
+}
+
+func (what *converter) selectWorkloads(namespace string, selector *Selector) []*workload {
+	workloads := make([]*workload, 0)
+	for _, current := range what.workloads {
+		if current.object.namespace() == namespace && selector.Matches(current.object.Spec.Template.Metadata.Labels) {
+			workloads = append(workloads, current)
+		}
+	}
+	return workloads
+}
+
+func (what *converter) link(sourceTitle string, target *workload, description string, protocol types.Protocol) {
+	what.linkTo(sourceTitle, target.id, target.title, description, protocol)
+}
+
+// linkTo adds a communication link unless the source already has one to the target
+func (what *converter) linkTo(sourceTitle string, targetId string, targetTitle string, description string, protocol types.Protocol) {
+	source := what.model.TechnicalAssets[sourceTitle]
+	for _, existing := range source.CommunicationLinks {
+		if existing.Target == targetId {
+			return
+		}
+	}
+
+	source.CommunicationLinks[importer.UniqueKey(source.CommunicationLinks, targetTitle)] = importer.NewCommunicationLink(targetId, description, protocol)
+	if protocol == types.UnknownProtocol {
+		importer.MarkForReview(what.model, &source, fmt.Sprintf("Which protocol is used by '%v' to access '%v'?", sourceTitle, targetTitle), "")
+	}
+	what.model.TechnicalAssets[sourceTitle] = source
+}
+
+// protocolOf prefers the protocol guessed from the image over the one guessed from the ports
+func (what *converter) protocolOf(target *workload, ports []int) types.Protocol {
+	if target.protocol != types.UnknownProtocol {
+		return target.protocol
+	}
+	for _, port := range ports {
+		if protocol, ok := importer.GuessProtocolFromPort(port); ok {
+			return protocol
+		}
+	}
+	return types.UnknownProtocol
+}
+
+func (what *converter) ask(question string, answer string) {
+	if _, ok := what.model.Questions[question]; !ok {
+		importer.AddQuestion(what.model, question, answer)
+	}
+}
+
+// Data Assets ===============================================================================
+
+// addSecrets adds the secrets referenced by workloads as well as declared ones as data assets stored in the cluster
+func (what *converter) addSecrets(objects []*Object) {
+	clusterTitle := "Kubernetes Cluster"
+	secretIds := make(map[string]string)
+	secretId := func(namespace string, name string) string {
+		key := namespace + "/" + name
+		if _, ok := secretIds[key]; !ok {
+			title, qualifiedName := "Secret "+name, name
+			if namespace != defaultNamespace {
+				title, qualifiedName = fmt.Sprintf("Secret %v (%v)", name, namespace), namespace+" "+name
+			}
+
+			dataAsset := importer.NewDataAsset(importer.UniqueID("secret "+qualifiedName, what.takenIds), fmt.Sprintf("Kubernetes secret %v in namespace %v", name, namespace))
+			dataAsset.Confidentiality = types.Confidential.String()
+			dataAsset.Integrity = types.Critical.String()
+			what.model.DataAssets[importer.UniqueKey(what.model.DataAssets, title)] = dataAsset
+			secretIds[key] = dataAsset.ID
+
+			cluster := what.model.TechnicalAssets[clusterTitle]
+			cluster.DataAssetsStored = append(cluster.DataAssetsStored, dataAsset.ID)
+			cluster.Confidentiality = types.Confidential.String()
+			cluster.Integrity = types.Critical.String()
+			what.model.TechnicalAssets[clusterTitle] = cluster
+		}
+		return secretIds[key]
+	}
+
+	for _, current := range what.workloads {
+		asset := what.model.TechnicalAssets[current.title]
+		for _, name := range current.object.secrets() {
+			asset.DataAssetsProcessed = append(asset.DataAssetsProcessed, secretId(current.object.namespace(), name))
+		}
+		what.model.TechnicalAssets[current.title] = asset
+	}
+	for _, object := range objects {
+		secretId(object.namespace(), object.Metadata.Name)
+	}
+}
+
+// refersTo tells whether a value (like a connection string) names a service as host, services of other namespaces
+// have to be qualified by their namespace (service.namespace, service.namespace.svc or service.namespace.svc.cluster.local)
+func refersTo(value string, service string, namespace string) bool {
+	serviceNamespace, name, _ := strings.Cut(service, "/")
+	qualifier := `(\.` + regexp.QuoteMeta(serviceNamespace) + `(\.svc(\.cluster\.local)?)?)`
+	if serviceNamespace == namespace {
+		qualifier += "?"
+	}
+	return regexp.MustCompile(`(^|[/@=,;\s])` + regexp.QuoteMeta(name) + qualifier + `([:/,;\s]|$)`).MatchString(value)
+}
+
+func servicePorts(object *Object) []int {
+	ports := make([]int, 0)
+	for _, port := range object.Spec.Ports {
+		ports = append(ports, port.Port)
+		if targetPort, ok := port.TargetPort.(int); ok {
+			ports = append(ports, targetPort)
+		}
+	}
+	return ports
+}
+
+func policyPorts(rule NetworkPolicyRule) []int {
+	ports := make([]int, 0)
+	for _, port := range rule.Ports {
+		switch value := port.Port.(type) {
+		case int:
+			ports = append(ports, value)
+		case string:
+			if number, err := strconv.Atoi(value); err == nil {
+				ports = append(ports, number)
+			}
+		}
+	}
+	return ports
+}
@@ -0,0 +1,182 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestLoadFiles(t *testing.T) {
+	objects, err := LoadFiles([]string{"testdata/shop"})
+	require.NoError(t, err)
+
+	kinds := make([]string, 0)
+	for _, object := range objects {
+		kinds = append(kinds, object.Kind)
+	}
+	// lists are flattened, files are read in order of their names
+	assert.Equal(t, []string{"Service", "Service", "Service", "ConfigMap", "Ingress", "NetworkPolicy", "Secret", "Deployment", "StatefulSet", "DaemonSet"}, kinds)
+}
+
+func TestLoadFilesFails(t *testing.T) {
+	_, err := LoadFiles([]string{"testdata/missing"})
+	assert.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	objects, err := LoadFiles([]string{"testdata/shop"})
+	require.NoError(t, err)
+
+	model, report := Import(objects, "shop", importer.Mapping{})
+
+	assert.Equal(t, "shop", model.Title)
+	require.Contains(t, model.TechnicalAssets, "api")
+	require.Contains(t, model.TechnicalAssets, "db")
+	api, db := model.TechnicalAssets["api"], model.TechnicalAssets["db"]
+	assert.Equal(t, types.WebServiceREST.String(), api.Technology)
+	assert.Contains(t, api.Tags, importer.ReviewTag)
+	assert.True(t, api.Internet)
+	assert.True(t, api.Redundant)
+	assert.Equal(t, types.Database.String(), db.Technology)
+	assert.False(t, db.Redundant)
+	assert.NotContains(t, model.TechnicalAssets, "agent", "workloads without containers are skipped")
+
+	require.Contains(t, api.CommunicationLinks, "db")
+	assert.Equal(t, "db", api.CommunicationLinks["db"].Target)
+	assert.Equal(t, types.SqlAccessProtocol.String(), api.CommunicationLinks["db"].Protocol)
+
+	client := model.TechnicalAssets["External Client"]
+	require.Contains(t, client.CommunicationLinks, "api")
+	assert.Equal(t, types.HTTP.String(), client.CommunicationLinks["api"].Protocol)
+	require.Contains(t, client.CommunicationLinks, "Ingress Controller nginx")
+	assert.Equal(t, types.HTTPS.String(), client.CommunicationLinks["Ingress Controller nginx"].Protocol)
+	controller := model.TechnicalAssets["Ingress Controller nginx"]
+	assert.Equal(t, types.ReverseProxy.String(), controller.Technology)
+	require.Contains(t, controller.CommunicationLinks, "api")
+
+	require.Contains(t, model.TrustBoundaries, "Namespace default")
+	assert.Equal(t, []string{"api", "db"}, model.TrustBoundaries["Namespace default"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"api", "db", "ingress-controller-nginx"}, model.SharedRuntimes["Kubernetes Cluster"].TechnicalAssetsRunning)
+
+	require.Contains(t, model.DataAssets, "Secret db-credentials")
+	require.Contains(t, model.DataAssets, "Secret tls-certificate")
+	assert.Equal(t, []string{"secret-db-credentials"}, api.DataAssetsProcessed)
+	assert.Equal(t, []string{"secret-db-credentials"}, db.DataAssetsProcessed)
+	assert.Equal(t, []string{"secret-db-credentials", "secret-tls-certificate"}, model.TechnicalAssets["Kubernetes Cluster"].DataAssetsStored)
+
+	assert.Contains(t, model.Questions, "Which systems in 10.0.0.0/8 are allowed by network policy 'db'?")
+	assert.Contains(t, model.Questions, "Is service 'api' of type LoadBalancer reachable from the internet?")
+
+	assertReported(t, report, "ignored 1 object(s) of kind ConfigMap")
+	assertReported(t, report, "DaemonSet 'agent': no containers found")
+	assertReported(t, report, "service 'external': no selector")
+	assertReported(t, report, "ingress 'shop': unknown service \"legacy\" referenced")
+}
+
+type selectorTest struct {
+	selector string
+	labels   map[string]string
+	empty    bool
+	matches  bool
+}
+
+func TestSelector(t *testing.T) {
+	testCases := map[string]selectorTest{
+		"plain labels matching": {
+			selector: "app: api",
+			labels:   map[string]string{"app": "api", "tier": "backend"},
+			matches:  true,
+		},
+		"plain labels not matching": {
+			selector: "app: api",
+			labels:   map[string]string{"app": "db"},
+		},
+		"match labels": {
+			selector: "matchLabels: {app: api}",
+			labels:   map[string]string{"app": "api"},
+			matches:  true,
+		},
+		"match expressions only": {
+			selector: "matchExpressions: [{key: app, operator: Exists}]",
+			labels:   map[string]string{"app": "api"},
+			matches:  true,
+		},
+		"empty": {
+			selector: "{}",
+			empty:    true,
+			matches:  true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			selector := new(Selector)
+			require.NoError(t, yaml.Unmarshal([]byte(testCase.selector), selector))
+
+			assert.Equal(t, testCase.empty, selector.IsEmpty())
+			assert.Equal(t, testCase.matches, selector.Matches(testCase.labels))
+		})
+	}
+}
+
+type refersToTest struct {
+	value     string
+	service   string
+	namespace string
+	expected  bool
+}
+
+func TestRefersTo(t *testing.T) {
+	testCases := map[string]refersToTest{
+		"connection string": {
+			value:     "postgres://db:5432/shop",
+			service:   "default/db",
+			namespace: "default",
+			expected:  true,
+		},
+		"host only": {
+			value:     "db",
+			service:   "default/db",
+			namespace: "default",
+			expected:  true,
+		},
+		"part of another name": {
+			value:     "postgres://db-replica:5432/shop",
+			service:   "default/db",
+			namespace: "default",
+		},
+		"other namespace unqualified": {
+			value:     "http://api:8080",
+			service:   "shop/api",
+			namespace: "default",
+		},
+		"other namespace qualified": {
+			value:     "http://api.shop.svc.cluster.local:8080",
+			service:   "shop/api",
+			namespace: "default",
+			expected:  true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, refersTo(testCase.value, testCase.service, testCase.namespace))
+		})
+	}
+}
+
+func assertReported(t *testing.T, report *importer.Report, prefix string) {
+	t.Helper()
+	for _, item := range report.Items {
+		if strings.HasPrefix(item, prefix) {
+			return
+		}
+	}
+	t.Errorf("report misses %q in %q", prefix, report.Items)
+}
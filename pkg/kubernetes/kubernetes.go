@@ -0,0 +1,354 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubernetes manifests, only the parts of the kinds needed for the import
+
+type Object struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   Metadata `yaml:"metadata"`
+	Spec       Spec     `yaml:"spec"`
+	Items      []Object `yaml:"items"`
+}
+
+type Metadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+// Spec holds the fields of all supported kinds, they don't overlap
+type Spec struct {
+	// Deployment, StatefulSet, DaemonSet
+	Replicas *int        `yaml:"replicas"`
+	Template PodTemplate `yaml:"template"`
+
+	// Deployment, StatefulSet, DaemonSet and Service
+	Selector *Selector `yaml:"selector"`
+
+	// Service
+	Type  string        `yaml:"type"`
+	Ports []ServicePort `yaml:"ports"`
+
+	// Ingress
+	IngressClassName string          `yaml:"ingressClassName"`
+	DefaultBackend   *IngressBackend `yaml:"defaultBackend"`
+	Backend          *IngressBackend `yaml:"backend"`
+	Rules            []IngressRule   `yaml:"rules"`
+	TLS              []any           `yaml:"tls"`
+
+	// NetworkPolicy
+	PodSelector *Selector           `yaml:"podSelector"`
+	PolicyTypes []string            `yaml:"policyTypes"`
+	Ingress     []NetworkPolicyRule `yaml:"ingress"`
+	Egress      []NetworkPolicyRule `yaml:"egress"`
+}
+
+type PodTemplate struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     PodSpec  `yaml:"spec"`
+}
+
+type PodSpec struct {
+	Containers     []Container `yaml:"containers"`
+	InitContainers []Container `yaml:"initContainers"`
+	Volumes        []Volume    `yaml:"volumes"`
+}
+
+type Container struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+	Ports []struct {
+		ContainerPort int    `yaml:"containerPort"`
+		Name          string `yaml:"name"`
+	} `yaml:"ports"`
+	Env []struct {
+		Name      string `yaml:"name"`
+		Value     string `yaml:"value"`
+		ValueFrom *struct {
+			SecretKeyRef *SecretReference `yaml:"secretKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	EnvFrom []struct {
+		SecretRef *SecretReference `yaml:"secretRef"`
+	} `yaml:"envFrom"`
+}
+
+type SecretReference struct {
+	Name string `yaml:"name"`
+}
+
+type Volume struct {
+	Name   string `yaml:"name"`
+	Secret *struct {
+		SecretName string `yaml:"secretName"`
+	} `yaml:"secret"`
+}
+
+type ServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort any    `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+type IngressRule struct {
+	Host string `yaml:"host"`
+	HTTP *struct {
+		Paths []struct {
+			Path    string         `yaml:"path"`
+			Backend IngressBackend `yaml:"backend"`
+		} `yaml:"paths"`
+	} `yaml:"http"`
+}
+
+// IngressBackend supports networking.k8s.io/v1 (service) as well as v1beta1 (serviceName and servicePort)
+type IngressBackend struct {
+	Service *struct {
+		Name string `yaml:"name"`
+		Port struct {
+			Number int    `yaml:"number"`
+			Name   string `yaml:"name"`
+		} `yaml:"port"`
+	} `yaml:"service"`
+	ServiceName string `yaml:"serviceName"`
+	ServicePort any    `yaml:"servicePort"`
+}
+
+type NetworkPolicyRule struct {
+	From  []NetworkPolicyPeer `yaml:"from"`
+	To    []NetworkPolicyPeer `yaml:"to"`
+	Ports []struct {
+		Protocol string `yaml:"protocol"`
+		Port     any    `yaml:"port"`
+	} `yaml:"ports"`
+}
+
+type NetworkPolicyPeer struct {
+	PodSelector       *Selector `yaml:"podSelector"`
+	NamespaceSelector *Selector `yaml:"namespaceSelector"`
+	IPBlock           *struct {
+		CIDR string `yaml:"cidr"`
+	} `yaml:"ipBlock"`
+}
+
+// Selector is either a label selector (matchLabels, matchExpressions) or a plain map of labels like in services
+type Selector struct {
+	MatchLabels      map[string]string
+	MatchExpressions bool
+}
+
+func (what *Selector) UnmarshalYAML(value *yaml.Node) error {
+	var selector struct {
+		MatchLabels      map[string]string `yaml:"matchLabels"`
+		MatchExpressions []any             `yaml:"matchExpressions"`
+	}
+	if err := value.Decode(&selector); err != nil {
+		return err
+	}
+	if selector.MatchLabels != nil || selector.MatchExpressions != nil {
+		what.MatchLabels = selector.MatchLabels
+		what.MatchExpressions = len(selector.MatchExpressions) > 0
+		return nil
+	}
+	return value.Decode(&what.MatchLabels)
+}
+
+// IsEmpty tells whether the selector selects everything
+func (what *Selector) IsEmpty() bool {
+	return what == nil || (len(what.MatchLabels) == 0 && !what.MatchExpressions)
+}
+
+// Matches tells whether the labels are selected, match expressions are not evaluated
+func (what *Selector) Matches(labels map[string]string) bool {
+	if what == nil {
+		return true
+	}
+	for key, value := range what.MatchLabels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (what *Object) namespace() string {
+	if len(what.Metadata.Namespace) == 0 {
+		return defaultNamespace
+	}
+	return what.Metadata.Namespace
+}
+
+func (what *Object) replicas() int {
+	if what.Spec.Replicas == nil {
+		return 1
+	}
+	return *what.Spec.Replicas
+}
+
+// containerPorts returns all ports the containers of a workload listen on
+func (what *Object) containerPorts() []int {
+	ports := make([]int, 0)
+	for _, container := range what.Spec.Template.Spec.Containers {
+		for _, port := range container.Ports {
+			ports = append(ports, port.ContainerPort)
+		}
+	}
+	return ports
+}
+
+// secrets returns the names of all secrets referenced by the pods of a workload
+func (what *Object) secrets() []string {
+	names := make(map[string]bool)
+	podSpec := what.Spec.Template.Spec
+	for _, container := range append(append([]Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names[envFrom.SecretRef.Name] = true
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			names[volume.Secret.SecretName] = true
+		}
+	}
+	return sortedKeys(names)
+}
+
+// backends returns the services an ingress routes to
+func (what *Object) backends() []IngressBackend {
+	backends := make([]IngressBackend, 0)
+	for _, backend := range []*IngressBackend{what.Spec.DefaultBackend, what.Spec.Backend} {
+		if backend != nil {
+			backends = append(backends, *backend)
+		}
+	}
+	for _, rule := range what.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backends = append(backends, path.Backend)
+		}
+	}
+	return backends
+}
+
+func (what IngressBackend) serviceName() string {
+	if what.Service != nil {
+		return what.Service.Name
+	}
+	return what.ServiceName
+}
+
+func (what IngressBackend) servicePort() any {
+	if what.Service != nil {
+		if what.Service.Port.Number > 0 {
+			return what.Service.Port.Number
+		}
+		return what.Service.Port.Name
+	}
+	return what.ServicePort
+}
+
+// LoadFiles reads all objects of manifest files or directories containing manifest files (*.yaml, *.yml),
+// multi document files and lists (kubectl get -o yaml) are supported
+func LoadFiles(paths []string) ([]Object, error) {
+	objects := make([]Object, 0)
+	for _, path := range paths {
+		filenames, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filename := range filenames {
+			fileObjects, err := loadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, fileObjects...)
+		}
+	}
+	return objects, nil
+}
+
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifests %q: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	filenames := make([]string, 0)
+	err = filepath.WalkDir(path, func(filename string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		extension := strings.ToLower(filepath.Ext(filename))
+		if !entry.IsDir() && (extension == ".yaml" || extension == ".yml") {
+			filenames = append(filenames, filename)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifests %q: %w", path, err)
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+func loadFile(filename string) ([]Object, error) {
+	file, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest file %q: %w", filename, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	objects := make([]Object, 0)
+	decoder := yaml.NewDecoder(file)
+	for {
+		var object Object
+		err = decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse manifest file %q: %w", filename, err)
+		}
+
+		if strings.HasSuffix(object.Kind, "List") {
+			objects = append(objects, object.Items...)
+		} else if len(object.Kind) > 0 {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,339 @@
+package kubernetes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// tags of technical assets for the network policy export, like "k8s-namespace:shop", "k8s-label:app=web" and "k8s-port:8080"
+const (
+	namespaceTag = "k8s-namespace"
+	labelTag     = "k8s-label"
+	portTag      = "k8s-port"
+)
+
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// Pod tells how the pods of a technical asset are selected and which ports they listen on,
+// technical assets outside the cluster are given by their CIDR instead
+type Pod struct {
+	Namespace string            `yaml:"namespace"`
+	Labels    map[string]string `yaml:"labels"`
+	Ports     []int             `yaml:"ports"`
+	CIDR      string            `yaml:"cidr"`
+}
+
+// PodMapping maps technical asset ids to their pods (yaml), e.g.
+//
+//	apache-webserver:
+//	  namespace: shop
+//	  labels:
+//	    app: webshop
+//	  ports: [8080]
+//	payment-provider:
+//	  cidr: 203.0.113.0/24
+//	  ports: [443]
+type PodMapping map[string]Pod
+
+func LoadPodMapping(filename string) (PodMapping, error) {
+	mapping := make(PodMapping)
+	if len(filename) == 0 {
+		return mapping, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read network policy mapping file %q: %w", filename, err)
+	}
+
+	err = yaml.Unmarshal(data, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse network policy mapping file %q: %w", filename, err)
+	}
+	return mapping, nil
+}
+
+// NetworkPolicy (networking.k8s.io/v1) as written by the export
+
+type NetworkPolicy struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   PolicyMetadata    `yaml:"metadata"`
+	Spec       NetworkPolicySpec `yaml:"spec"`
+}
+
+type PolicyMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type NetworkPolicySpec struct {
+	PodSelector LabelSelector `yaml:"podSelector"`
+	PolicyTypes []string      `yaml:"policyTypes"`
+	Ingress     []PolicyRule  `yaml:"ingress,omitempty"`
+	Egress      []PolicyRule  `yaml:"egress,omitempty"`
+}
+
+type PolicyRule struct {
+	From  []PolicyPeer `yaml:"from,omitempty"`
+	To    []PolicyPeer `yaml:"to,omitempty"`
+	Ports []PolicyPort `yaml:"ports,omitempty"`
+}
+
+type PolicyPeer struct {
+	PodSelector       *LabelSelector `yaml:"podSelector,omitempty"`
+	NamespaceSelector *LabelSelector `yaml:"namespaceSelector,omitempty"`
+	IPBlock           *IPBlock       `yaml:"ipBlock,omitempty"`
+}
+
+type IPBlock struct {
+	CIDR string `yaml:"cidr"`
+}
+
+type PolicyPort struct {
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port"`
+}
+
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
+}
+
+// NetworkPolicies returns a default deny policy for each namespace and a policy allowing the modelled communication links for each
+// technical asset running as pod. Technical assets are pods when they are mapped, tagged with k8s-namespace or k8s-label or inside of a
+// trust boundary of type network-policy-namespace-isolation (its id without "namespace-" prefix being the namespace, like import-kubernetes
+// creates them). Pods are selected by the "app" label with the asset id as value unless labels are given. Communication links with
+// technical assets not running as pods are allowed to and from everywhere unless their CIDR (and ports) are mapped, but only on the
+// ports of the pod when given.
+func NetworkPolicies(parsedModel *types.ParsedModel, mapping PodMapping) []NetworkPolicy {
+	pods := make(map[string]Pod)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if pod, ok := podOf(parsedModel, parsedModel.TechnicalAssets[id], mapping); ok {
+			pods[id] = pod
+		}
+	}
+
+	namespaces := make(map[string]bool)
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+	}
+	policies := make([]NetworkPolicy, 0)
+	for _, namespace := range sortedKeys(namespaces) {
+		policies = append(policies, defaultDenyPolicy(namespace))
+	}
+
+	incomingLinks := make(map[string][]types.CommunicationLink)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+			incomingLinks[link.TargetId] = append(incomingLinks[link.TargetId], link)
+		}
+	}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		pod, ok := pods[id]
+		if !ok {
+			continue
+		}
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		policy := NetworkPolicy{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+			Metadata: PolicyMetadata{
+				Name:        "threagile-" + id,
+				Namespace:   pod.Namespace,
+				Labels:      map[string]string{managedByLabel: "threagile"},
+				Annotations: map[string]string{"threagile.io/technical-asset": technicalAsset.Title},
+			},
+			Spec: NetworkPolicySpec{
+				PodSelector: LabelSelector{MatchLabels: pod.Labels},
+				PolicyTypes: []string{"Ingress", "Egress"},
+			},
+		}
+
+		seen := make(map[string]bool)
+		for _, link := range incomingLinks[id] {
+			if link.Protocol.IsProcessLocal() || link.SourceId == id {
+				continue
+			}
+			rule := PolicyRule{Ports: portsOf(pod.Ports)}
+			if source, ok := pods[link.SourceId]; ok {
+				rule.From = []PolicyPeer{peerOf(source, pod.Namespace)}
+			} else if source, ok := mapping[link.SourceId]; ok && len(source.CIDR) > 0 {
+				rule.From = []PolicyPeer{{IPBlock: &IPBlock{CIDR: source.CIDR}}}
+			}
+			if key := ruleKey(rule); !seen[key] {
+				seen[key] = true
+				policy.Spec.Ingress = append(policy.Spec.Ingress, rule)
+			}
+		}
+
+		seen = make(map[string]bool)
+		for _, link := range technicalAsset.CommunicationLinksSorted() {
+			if link.Protocol.IsProcessLocal() || link.TargetId == id {
+				continue
+			}
+			rule := PolicyRule{}
+			if target, ok := pods[link.TargetId]; ok {
+				rule.To = []PolicyPeer{peerOf(target, pod.Namespace)}
+				rule.Ports = portsOf(target.Ports)
+			} else if target, ok := mapping[link.TargetId]; ok && len(target.CIDR) > 0 {
+				rule.To = []PolicyPeer{{IPBlock: &IPBlock{CIDR: target.CIDR}}}
+				rule.Ports = portsOf(target.Ports)
+			}
+			if key := ruleKey(rule); !seen[key] {
+				seen[key] = true
+				policy.Spec.Egress = append(policy.Spec.Egress, rule)
+			}
+		}
+
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// WriteNetworkPolicies writes the network policies as multi document yaml file
+func WriteNetworkPolicies(parsedModel *types.ParsedModel, mapping PodMapping, filename string) error {
+	var builder strings.Builder
+	builder.WriteString("# Network policies generated by Threagile from the communication links of model " + parsedModel.Title + "\n")
+	encoder := yaml.NewEncoder(&builder)
+	encoder.SetIndent(2)
+	for _, policy := range NetworkPolicies(parsedModel, mapping) {
+		if err := encoder.Encode(policy); err != nil {
+			return fmt.Errorf("failed to marshal network policies: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal network policies: %w", err)
+	}
+
+	err := os.WriteFile(filename, []byte(builder.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write network policies file: %w", err)
+	}
+	return nil
+}
+
+// podOf returns the pod of a technical asset from the mapping, its tags or its namespace trust boundary
+func podOf(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, mapping PodMapping) (Pod, bool) {
+	pod, found := mapping[technicalAsset.Id]
+	if len(pod.CIDR) > 0 {
+		return Pod{}, false
+	}
+	labels := make(map[string]string)
+	for key, value := range pod.Labels {
+		labels[key] = value
+	}
+	pod.Labels = labels
+	for _, tag := range technicalAsset.Tags {
+		base, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			continue
+		}
+		switch base {
+		case namespaceTag:
+			if len(pod.Namespace) == 0 {
+				pod.Namespace = value
+			}
+			found = true
+		case labelTag:
+			if key, labelValue, ok := strings.Cut(value, "="); ok {
+				if _, exists := pod.Labels[key]; !exists {
+					pod.Labels[key] = labelValue
+				}
+			}
+			found = true
+		case portTag:
+			if port, err := strconv.Atoi(value); err == nil {
+				pod.Ports = append(pod.Ports, port)
+			}
+		}
+	}
+	if len(pod.Namespace) == 0 {
+		if namespace, ok := namespaceOf(parsedModel, technicalAsset); ok {
+			pod.Namespace = namespace
+			found = true
+		}
+	}
+
+	if !found {
+		return Pod{}, false
+	}
+	if len(pod.Namespace) == 0 {
+		pod.Namespace = defaultNamespace
+	}
+	if len(pod.Labels) == 0 {
+		pod.Labels["app"] = technicalAsset.Id
+	}
+	return pod, true
+}
+
+// namespaceOf looks for a trust boundary of type network-policy-namespace-isolation containing the technical asset
+func namespaceOf(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset) (string, bool) {
+	boundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[technicalAsset.Id]
+	if !ok {
+		return "", false
+	}
+	for _, id := range append([]string{boundary.Id}, boundary.AllParentTrustBoundaryIDs(parsedModel)...) {
+		if parsedModel.TrustBoundaries[id].Type == types.NetworkPolicyNamespaceIsolation {
+			return strings.TrimPrefix(id, "namespace-"), true
+		}
+	}
+	return "", false
+}
+
+func peerOf(pod Pod, namespace string) PolicyPeer {
+	peer := PolicyPeer{PodSelector: &LabelSelector{MatchLabels: pod.Labels}}
+	if pod.Namespace != namespace {
+		peer.NamespaceSelector = &LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: pod.Namespace}}
+	}
+	return peer
+}
+
+func ruleKey(rule PolicyRule) string {
+	data, _ := yaml.Marshal(rule)
+	return string(data)
+}
+
+func portsOf(ports []int) []PolicyPort {
+	result := make([]PolicyPort, 0)
+	for _, port := range ports {
+		result = append(result, PolicyPort{Protocol: "TCP", Port: port})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Port < result[j].Port
+	})
+	return result
+}
+
+// defaultDenyPolicy blocks all traffic of the pods in the namespace not allowed by other policies, except for DNS lookups
+func defaultDenyPolicy(namespace string) NetworkPolicy {
+	return NetworkPolicy{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "NetworkPolicy",
+		Metadata: PolicyMetadata{
+			Name:      "threagile-default-deny",
+			Namespace: namespace,
+			Labels:    map[string]string{managedByLabel: "threagile"},
+		},
+		Spec: NetworkPolicySpec{
+			PolicyTypes: []string{"Ingress", "Egress"},
+			Egress: []PolicyRule{{
+				To: []PolicyPeer{{
+					NamespaceSelector: &LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "kube-system"}},
+					PodSelector:       &LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+				}},
+				Ports: []PolicyPort{{Protocol: "UDP", Port: 53}, {Protocol: "TCP", Port: 53}},
+			}},
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func policyModel() *types.ParsedModel {
+	link := func(source string, target string, protocol types.Protocol) types.CommunicationLink {
+		return types.CommunicationLink{Id: source + ">" + target, Title: target, SourceId: source, TargetId: target, Protocol: protocol}
+	}
+	namespace := types.TrustBoundary{Id: "namespace-shop", Type: types.NetworkPolicyNamespaceIsolation, TechnicalAssetsInside: []string{"web", "api"}}
+	return &types.ParsedModel{
+		Title: "Shop",
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"browser": {Id: "browser", Title: "Browser", CommunicationLinks: []types.CommunicationLink{link("browser", "web", types.HTTPS)}},
+			"web":     {Id: "web", Title: "Web", CommunicationLinks: []types.CommunicationLink{link("web", "api", types.HTTP)}},
+			"api": {Id: "api", Title: "API", CommunicationLinks: []types.CommunicationLink{
+				link("api", "db", types.JDBC), link("api", "payment", types.HTTPS), link("api", "cache", types.InProcessLibraryCall)}},
+			"db":      {Id: "db", Title: "Database", Tags: []string{"k8s-namespace:data", "k8s-port:5432"}},
+			"payment": {Id: "payment", Title: "Payment Provider"},
+			"cache":   {Id: "cache", Title: "Cache", Tags: []string{"k8s-label:app=cache"}},
+		},
+		TrustBoundaries: map[string]types.TrustBoundary{namespace.Id: namespace},
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: map[string]types.TrustBoundary{"web": namespace, "api": namespace},
+	}
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	mapping := PodMapping{
+		"web":     {Labels: map[string]string{"app": "shop-web"}, Ports: []int{8080}},
+		"payment": {CIDR: "203.0.113.0/24", Ports: []int{443}},
+	}
+
+	policies := NetworkPolicies(policyModel(), mapping)
+
+	names := make([]string, 0)
+	for _, policy := range policies {
+		names = append(names, policy.Metadata.Namespace+"/"+policy.Metadata.Name)
+	}
+	require.Equal(t, []string{"data/threagile-default-deny", "default/threagile-default-deny", "shop/threagile-default-deny",
+		"shop/threagile-api", "default/threagile-cache", "data/threagile-db", "shop/threagile-web"}, names)
+
+	api, db, web := policies[3], policies[5], policies[6]
+	assert.Equal(t, map[string]string{"app": "api"}, api.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []PolicyRule{{From: []PolicyPeer{{PodSelector: &LabelSelector{MatchLabels: map[string]string{"app": "shop-web"}}}}, Ports: []PolicyPort{}}}, api.Spec.Ingress)
+	assert.ElementsMatch(t, []PolicyRule{
+		{To: []PolicyPeer{{PodSelector: &LabelSelector{MatchLabels: map[string]string{"app": "db"}}, NamespaceSelector: &LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "data"}}}},
+			Ports: []PolicyPort{{Protocol: "TCP", Port: 5432}}},
+		{To: []PolicyPeer{{IPBlock: &IPBlock{CIDR: "203.0.113.0/24"}}}, Ports: []PolicyPort{{Protocol: "TCP", Port: 443}}},
+	}, api.Spec.Egress, "process local links need no rule")
+
+	assert.Equal(t, []PolicyRule{{
+		From:  []PolicyPeer{{PodSelector: &LabelSelector{MatchLabels: map[string]string{"app": "api"}}, NamespaceSelector: &LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "shop"}}}},
+		Ports: []PolicyPort{{Protocol: "TCP", Port: 5432}},
+	}}, db.Spec.Ingress)
+	assert.Empty(t, db.Spec.Egress)
+
+	// links from assets not running as pods are allowed from everywhere on the ports of the pod
+	assert.Equal(t, []PolicyRule{{Ports: []PolicyPort{{Protocol: "TCP", Port: 8080}}}}, web.Spec.Ingress)
+	assert.Equal(t, "Web", web.Metadata.Annotations["threagile.io/technical-asset"])
+}
+
+func TestWriteNetworkPolicies(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "policies.yaml")
+
+	require.NoError(t, WriteNetworkPolicies(policyModel(), PodMapping{}, filename))
+
+	objects, err := LoadFiles([]string{filename})
+	require.NoError(t, err)
+	require.Len(t, objects, 7)
+	for _, object := range objects {
+		assert.Equal(t, "NetworkPolicy", object.Kind)
+	}
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Network policies generated by Threagile from the communication links of model Shop\n")
+}
+
+func TestLoadPodMapping(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pods.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("web:\n  namespace: shop\n  ports: [8080]\n"), 0600))
+
+	mapping, err := LoadPodMapping(filename)
+	require.NoError(t, err)
+	assert.Equal(t, PodMapping{"web": {Namespace: "shop", Ports: []int{8080}}}, mapping)
+
+	mapping, err = LoadPodMapping("")
+	require.NoError(t, err)
+	assert.Empty(t, mapping)
+
+	_, err = LoadPodMapping(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
@@ -38,14 +38,9 @@ func ListBuiltInMacros() []Macros {
 	}
 }
 
-func ListCustomMacros() []Macros {
-	// TODO: implement
-	return []Macros{}
-}
-
-func GetMacroByID(id string) (Macros, error) {
+// GetMacroByID returns the built-in or custom macro (e.g. provided by a plugin) with the id
+func GetMacroByID(id string, customMacros []Macros) (Macros, error) {
 	builtinMacros := ListBuiltInMacros()
-	customMacros := ListCustomMacros()
 	allMacros := append(builtinMacros, customMacros...)
 	for _, macro := range allMacros {
 		if macro.GetMacroDetails().ID == id {
@@ -55,8 +50,8 @@ func GetMacroByID(id string) (Macros, error) {
 	return nil, errors.New("unknown macro id: " + id)
 }
 
-func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, macroID string) error {
-	macros, err := GetMacroByID(macroID)
+func ExecuteModelMacro(modelInput *input.Model, inputFile string, parsedModel *types.ParsedModel, macroID string, customMacros []Macros) error {
+	macros, err := GetMacroByID(macroID, customMacros)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,479 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/expression"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// kinds of model elements declarative risk rules match
+const (
+	matchTechnicalAsset    = "technical-asset"
+	matchCommunicationLink = "communication-link"
+	matchDataAsset         = "data-asset"
+	matchTrustBoundary     = "trust-boundary"
+)
+
+// variables of the matched element (and the source and target of communication links) usable in the expressions
+var matchVariables = map[string][]string{
+	matchTechnicalAsset:    {"asset"},
+	matchCommunicationLink: {"link", "source", "target"},
+	matchDataAsset:         {"data"},
+	matchTrustBoundary:     {"boundary"},
+}
+
+// declarativeRiskRule is a risk rule declared in yaml, creating a risk for each element of the model matching its condition
+type declarativeRiskRule struct {
+	category              types.RiskCategory
+	supportedTags         []string
+	match                 string
+	condition             *expression.Expression
+	title                 *expression.Expression
+	likelihood            *expression.Expression
+	impact                *expression.Expression
+	dataBreachProbability *expression.Expression
+	syntheticId           []string
+}
+
+// LoadRiskRulesFolder adds the declarative risk rules of the yaml files in the folder to the custom risk rules
+func LoadRiskRulesFolder(customRiskRules map[string]*CustomRisk, folder string, reporter progressReporter) error {
+	if len(folder) == 0 {
+		return nil
+	}
+
+	reporter.Info("Loading risk rules from:", folder)
+	rules, loadError := input.LoadRiskRulesFolder(folder)
+	if loadError != nil {
+		return loadError
+	}
+
+	return addDeclarativeRiskRules(customRiskRules, rules, reporter)
+}
+
+func addDeclarativeRiskRules(customRiskRules map[string]*CustomRisk, rules map[string]input.IndividualRiskRule, reporter progressReporter) error {
+	titles := make([]string, 0, len(rules))
+	for title := range rules {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	for _, title := range titles {
+		rule, compileError := compileRiskRule(title, rules[title])
+		if compileError != nil {
+			return fmt.Errorf("invalid risk rule %q: %v", title, compileError)
+		}
+		if _, exists := customRiskRules[rule.category.Id]; exists {
+			return errors.New("duplicate id used: " + rule.category.Id)
+		}
+
+		customRiskRules[rule.category.Id] = &CustomRisk{
+			ID:       rule.category.Id,
+			Category: rule.category,
+			Tags:     rule.supportedTags,
+			Rule:     rule,
+		}
+		reporter.Info("Risk rule loaded:", rule.category.Id)
+	}
+
+	return nil
+}
+
+func compileRiskRule(title string, rule input.IndividualRiskRule) (*declarativeRiskRule, error) {
+	idError := checkIdSyntax(rule.ID)
+	if idError != nil {
+		return nil, idError
+	}
+	function, err := types.ParseRiskFunction(rule.Function)
+	if err != nil {
+		return nil, fmt.Errorf("unknown 'function' value: %v", rule.Function)
+	}
+	stride, err := types.ParseSTRIDE(rule.STRIDE)
+	if err != nil {
+		return nil, fmt.Errorf("unknown 'stride' value: %v", rule.STRIDE)
+	}
+
+	compiled := &declarativeRiskRule{
+		category: types.RiskCategory{
+			Id:                         rule.ID,
+			Title:                      title,
+			Description:                withDefault(rule.Description, title),
+			Impact:                     rule.Impact,
+			ASVS:                       rule.ASVS,
+			CheatSheet:                 rule.CheatSheet,
+			Action:                     rule.Action,
+			Mitigation:                 rule.Mitigation,
+			Check:                      rule.Check,
+			DetectionLogic:             withDefault(rule.DetectionLogic, rule.Condition),
+			RiskAssessment:             rule.RiskAssessment,
+			FalsePositives:             rule.FalsePositives,
+			Function:                   function,
+			STRIDE:                     stride,
+			ModelFailurePossibleReason: rule.ModelFailurePossibleReason,
+			CWE:                        rule.CWE,
+		},
+		supportedTags: lowerCaseAndTrim(rule.SupportedTags),
+		match:         strings.TrimSpace(rule.Match),
+		syntheticId:   rule.SyntheticId,
+	}
+
+	variables, ok := matchVariables[compiled.match]
+	if !ok {
+		return nil, fmt.Errorf("unknown 'match' value %q (expected one of: %v, %v, %v, %v)", rule.Match,
+			matchTechnicalAsset, matchCommunicationLink, matchDataAsset, matchTrustBoundary)
+	}
+	if len(compiled.syntheticId) == 0 {
+		compiled.syntheticId = variables[:1]
+	}
+	for _, variable := range compiled.syntheticId {
+		if !contains(variables, variable) {
+			return nil, fmt.Errorf("unknown 'synthetic_id' element %q (expected any of: %v)", variable, strings.Join(variables, ", "))
+		}
+	}
+
+	if len(strings.TrimSpace(rule.Condition)) == 0 {
+		return nil, errors.New("missing 'condition'")
+	}
+	environment := compiled.environment(new(types.ParsedModel), types.TechnicalAsset{}, types.CommunicationLink{}, types.DataAsset{}, types.TrustBoundary{})
+	compile := func(name string, source string, defaultValue string, parse func(string) error) (*expression.Expression, error) {
+		source = strings.TrimSpace(source)
+		if len(source) == 0 {
+			source = defaultValue
+		}
+		if parse != nil && parse(source) == nil {
+			source = strconv.Quote(source)
+		}
+		compiledExpression, compileError := expression.Compile(source)
+		if compileError != nil {
+			return nil, fmt.Errorf("invalid '%v': %v", name, compileError)
+		}
+		checkError := compiledExpression.Check(environment)
+		if checkError != nil {
+			return nil, fmt.Errorf("invalid '%v': %v", name, checkError)
+		}
+		return compiledExpression, nil
+	}
+
+	compiled.condition, err = compile("condition", rule.Condition, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(rule.Title)) > 0 {
+		compiled.title, err = compile("title", rule.Title, "", nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	compiled.likelihood, err = compile("exploitation_likelihood", rule.ExploitationLikelihood, types.Likely.String(), func(value string) error {
+		_, parseError := types.ParseRiskExploitationLikelihood(value)
+		return parseError
+	})
+	if err != nil {
+		return nil, err
+	}
+	compiled.impact, err = compile("exploitation_impact", rule.ExploitationImpact, types.MediumImpact.String(), func(value string) error {
+		_, parseError := types.ParseRiskExploitationImpact(value)
+		return parseError
+	})
+	if err != nil {
+		return nil, err
+	}
+	compiled.dataBreachProbability, err = compile("data_breach_probability", rule.DataBreachProbability, types.Possible.String(), func(value string) error {
+		_, parseError := types.ParseDataBreachProbability(value)
+		return parseError
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return compiled, nil
+}
+
+func (r *declarativeRiskRule) Category() types.RiskCategory {
+	return r.category
+}
+
+func (r *declarativeRiskRule) SupportedTags() []string {
+	return r.supportedTags
+}
+
+func (r *declarativeRiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks, err := r.TryGenerateRisks(parsedModel)
+	if err != nil {
+		log.Fatalf("Failed to generate risks for risk rule %q: %v\n", r.category.Id, err)
+	}
+	return generatedRisks
+}
+
+func (r *declarativeRiskRule) TryGenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	generatedRisks := make([]types.Risk, 0)
+	add := func(risk types.Risk, environment expression.Environment) error {
+		matches, err := r.condition.EvaluateBool(environment)
+		if err != nil || !matches {
+			return err
+		}
+		risk, err = r.rate(risk, environment)
+		if err != nil {
+			return err
+		}
+		generatedRisks = append(generatedRisks, risk)
+		return nil
+	}
+
+	switch r.match {
+	case matchTechnicalAsset:
+		for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+			technicalAsset := parsedModel.TechnicalAssets[id]
+			if technicalAsset.OutOfScope {
+				continue
+			}
+			risk := types.Risk{
+				Title:                        "<b>" + r.category.Title + "</b> risk at <b>" + technicalAsset.Title + "</b>",
+				MostRelevantTechnicalAssetId: technicalAsset.Id,
+				DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+			}
+			err := add(risk, r.environment(parsedModel, technicalAsset, types.CommunicationLink{}, types.DataAsset{}, types.TrustBoundary{}))
+			if err != nil {
+				return nil, fmt.Errorf("technical asset %q: %w", id, err)
+			}
+		}
+
+	case matchCommunicationLink:
+		for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+			for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+				source := parsedModel.TechnicalAssets[link.SourceId]
+				target := parsedModel.TechnicalAssets[link.TargetId]
+				if source.OutOfScope && target.OutOfScope {
+					continue
+				}
+				risk := types.Risk{
+					Title: "<b>" + r.category.Title + "</b> risk at <b>" + link.Title + "</b> from <b>" + source.Title +
+						"</b> to <b>" + target.Title + "</b>",
+					MostRelevantTechnicalAssetId:    source.Id,
+					MostRelevantCommunicationLinkId: link.Id,
+					DataBreachTechnicalAssetIDs:     []string{target.Id},
+				}
+				err := add(risk, r.environment(parsedModel, types.TechnicalAsset{}, link, types.DataAsset{}, types.TrustBoundary{}))
+				if err != nil {
+					return nil, fmt.Errorf("communication link %q: %w", link.Id, err)
+				}
+			}
+		}
+
+	case matchDataAsset:
+		ids := make([]string, 0, len(parsedModel.DataAssets))
+		for id := range parsedModel.DataAssets {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			dataAsset := parsedModel.DataAssets[id]
+			dataBreachTechnicalAssetIDs := make([]string, 0)
+			for _, technicalAssetId := range parsedModel.SortedTechnicalAssetIDs() {
+				if parsedModel.TechnicalAssets[technicalAssetId].ProcessesOrStoresDataAsset(id) {
+					dataBreachTechnicalAssetIDs = append(dataBreachTechnicalAssetIDs, technicalAssetId)
+				}
+			}
+			risk := types.Risk{
+				Title:                       "<b>" + r.category.Title + "</b> risk of <b>" + dataAsset.Title + "</b>",
+				MostRelevantDataAssetId:     dataAsset.Id,
+				DataBreachTechnicalAssetIDs: dataBreachTechnicalAssetIDs,
+			}
+			err := add(risk, r.environment(parsedModel, types.TechnicalAsset{}, types.CommunicationLink{}, dataAsset, types.TrustBoundary{}))
+			if err != nil {
+				return nil, fmt.Errorf("data asset %q: %w", id, err)
+			}
+		}
+
+	case matchTrustBoundary:
+		ids := make([]string, 0, len(parsedModel.TrustBoundaries))
+		for id := range parsedModel.TrustBoundaries {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			trustBoundary := parsedModel.TrustBoundaries[id]
+			risk := types.Risk{
+				Title:                       "<b>" + r.category.Title + "</b> risk at <b>" + trustBoundary.Title + "</b>",
+				MostRelevantTrustBoundaryId: trustBoundary.Id,
+				DataBreachTechnicalAssetIDs: trustBoundary.RecursivelyAllTechnicalAssetIDsInside(parsedModel),
+			}
+			err := add(risk, r.environment(parsedModel, types.TechnicalAsset{}, types.CommunicationLink{}, types.DataAsset{}, trustBoundary))
+			if err != nil {
+				return nil, fmt.Errorf("trust boundary %q: %w", id, err)
+			}
+		}
+	}
+
+	return generatedRisks, nil
+}
+
+func (r *declarativeRiskRule) rate(risk types.Risk, environment expression.Environment) (types.Risk, error) {
+	var err error
+	if r.title != nil {
+		risk.Title, err = r.title.EvaluateString(environment)
+		if err != nil {
+			return risk, err
+		}
+	}
+
+	value, err := r.likelihood.EvaluateString(environment)
+	if err != nil {
+		return risk, err
+	}
+	risk.ExploitationLikelihood, err = types.ParseRiskExploitationLikelihood(value)
+	if err != nil {
+		return risk, fmt.Errorf("unknown exploitation likelihood %q", value)
+	}
+
+	value, err = r.impact.EvaluateString(environment)
+	if err != nil {
+		return risk, err
+	}
+	risk.ExploitationImpact, err = types.ParseRiskExploitationImpact(value)
+	if err != nil {
+		return risk, fmt.Errorf("unknown exploitation impact %q", value)
+	}
+
+	value, err = r.dataBreachProbability.EvaluateString(environment)
+	if err != nil {
+		return risk, err
+	}
+	risk.DataBreachProbability, err = types.ParseDataBreachProbability(value)
+	if err != nil {
+		return risk, fmt.Errorf("unknown data breach probability %q", value)
+	}
+
+	risk.CategoryId = r.category.Id
+	risk.Severity = types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+	risk.SyntheticId = risk.CategoryId
+	for _, variable := range r.syntheticId {
+		element, _ := environment[variable].(map[string]any)
+		risk.SyntheticId += "@" + fmt.Sprintf("%v", element["id"])
+	}
+	return risk, nil
+}
+
+// environment returns the variables of the elements matched by the rule
+func (r *declarativeRiskRule) environment(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset,
+	link types.CommunicationLink, dataAsset types.DataAsset, trustBoundary types.TrustBoundary) expression.Environment {
+	switch r.match {
+	case matchTechnicalAsset:
+		return expression.Environment{"asset": technicalAssetVariables(parsedModel, technicalAsset)}
+	case matchCommunicationLink:
+		return expression.Environment{
+			"link":   communicationLinkVariables(parsedModel, link),
+			"source": technicalAssetVariables(parsedModel, parsedModel.TechnicalAssets[link.SourceId]),
+			"target": technicalAssetVariables(parsedModel, parsedModel.TechnicalAssets[link.TargetId]),
+		}
+	case matchDataAsset:
+		return expression.Environment{"data": dataAssetVariables(dataAsset)}
+	case matchTrustBoundary:
+		return expression.Environment{"boundary": trustBoundaryVariables(parsedModel, trustBoundary)}
+	}
+	return expression.Environment{}
+}
+
+func enum(value types.TypeEnum, values []types.TypeEnum) expression.Enum {
+	names := make([]string, 0, len(values))
+	for _, candidate := range values {
+		names = append(names, candidate.String())
+	}
+	return expression.Enum{Name: value.String(), Names: names}
+}
+
+func technicalAssetVariables(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset) map[string]any {
+	dataFormats := make([]string, 0, len(technicalAsset.DataFormatsAccepted))
+	for _, dataFormat := range technicalAsset.DataFormatsAccepted {
+		dataFormats = append(dataFormats, dataFormat.String())
+	}
+	return map[string]any{
+		"id":                      technicalAsset.Id,
+		"title":                   technicalAsset.Title,
+		"description":             technicalAsset.Description,
+		"usage":                   enum(technicalAsset.Usage, types.UsageValues()),
+		"type":                    enum(technicalAsset.Type, types.TechnicalAssetTypeValues()),
+		"size":                    enum(technicalAsset.Size, types.TechnicalAssetSizeValues()),
+		"technology":              enum(technicalAsset.Technology, types.TechnicalAssetTechnologyValues()),
+		"machine":                 enum(technicalAsset.Machine, types.TechnicalAssetMachineValues()),
+		"internet":                technicalAsset.Internet,
+		"multi_tenant":            technicalAsset.MultiTenant,
+		"redundant":               technicalAsset.Redundant,
+		"custom_developed_parts":  technicalAsset.CustomDevelopedParts,
+		"out_of_scope":            technicalAsset.OutOfScope,
+		"used_as_client_by_human": technicalAsset.UsedAsClientByHuman,
+		"encryption":              enum(technicalAsset.Encryption, types.EncryptionStyleValues()),
+		"owner":                   technicalAsset.Owner,
+		"confidentiality":         enum(technicalAsset.Confidentiality, types.ConfidentialityValues()),
+		"integrity":               enum(technicalAsset.Integrity, types.CriticalityValues()),
+		"availability":            enum(technicalAsset.Availability, types.CriticalityValues()),
+		"highest_confidentiality": enum(technicalAsset.HighestConfidentiality(parsedModel), types.ConfidentialityValues()),
+		"highest_integrity":       enum(technicalAsset.HighestIntegrity(parsedModel), types.CriticalityValues()),
+		"highest_availability":    enum(technicalAsset.HighestAvailability(parsedModel), types.CriticalityValues()),
+		"tags":                    technicalAsset.Tags,
+		"data_assets_processed":   technicalAsset.DataAssetsProcessed,
+		"data_assets_stored":      technicalAsset.DataAssetsStored,
+		"data_formats_accepted":   dataFormats,
+		"trust_boundary":          technicalAsset.GetTrustBoundaryId(parsedModel),
+		"raa":                     technicalAsset.RAA,
+	}
+}
+
+func communicationLinkVariables(parsedModel *types.ParsedModel, link types.CommunicationLink) map[string]any {
+	return map[string]any{
+		"id":                      link.Id,
+		"title":                   link.Title,
+		"description":             link.Description,
+		"protocol":                enum(link.Protocol, types.ProtocolValues()),
+		"tags":                    link.Tags,
+		"vpn":                     link.VPN,
+		"ip_filtered":             link.IpFiltered,
+		"readonly":                link.Readonly,
+		"authentication":          enum(link.Authentication, types.AuthenticationValues()),
+		"authorization":           enum(link.Authorization, types.AuthorizationValues()),
+		"usage":                   enum(link.Usage, types.UsageValues()),
+		"data_assets_sent":        link.DataAssetsSent,
+		"data_assets_received":    link.DataAssetsReceived,
+		"across_trust_boundary":   link.IsAcrossTrustBoundary(parsedModel),
+		"highest_confidentiality": enum(link.HighestConfidentiality(parsedModel), types.ConfidentialityValues()),
+		"highest_integrity":       enum(link.HighestIntegrity(parsedModel), types.CriticalityValues()),
+		"highest_availability":    enum(link.HighestAvailability(parsedModel), types.CriticalityValues()),
+	}
+}
+
+func dataAssetVariables(dataAsset types.DataAsset) map[string]any {
+	return map[string]any{
+		"id":              dataAsset.Id,
+		"title":           dataAsset.Title,
+		"description":     dataAsset.Description,
+		"usage":           enum(dataAsset.Usage, types.UsageValues()),
+		"tags":            dataAsset.Tags,
+		"origin":          dataAsset.Origin,
+		"owner":           dataAsset.Owner,
+		"quantity":        enum(dataAsset.Quantity, types.QuantityValues()),
+		"confidentiality": enum(dataAsset.Confidentiality, types.ConfidentialityValues()),
+		"integrity":       enum(dataAsset.Integrity, types.CriticalityValues()),
+		"availability":    enum(dataAsset.Availability, types.CriticalityValues()),
+	}
+}
+
+func trustBoundaryVariables(parsedModel *types.ParsedModel, trustBoundary types.TrustBoundary) map[string]any {
+	return map[string]any{
+		"id":                      trustBoundary.Id,
+		"title":                   trustBoundary.Title,
+		"description":             trustBoundary.Description,
+		"type":                    enum(trustBoundary.Type, types.TrustBoundaryTypeValues()),
+		"tags":                    trustBoundary.Tags,
+		"technical_assets_inside": trustBoundary.RecursivelyAllTechnicalAssetIDsInside(parsedModel),
+		"trust_boundaries_nested": trustBoundary.TrustBoundariesNested,
+		"highest_confidentiality": enum(trustBoundary.HighestConfidentiality(parsedModel), types.ConfidentialityValues()),
+		"highest_integrity":       enum(trustBoundary.HighestIntegrity(parsedModel), types.CriticalityValues()),
+		"highest_availability":    enum(trustBoundary.HighestAvailability(parsedModel), types.CriticalityValues()),
+	}
+}
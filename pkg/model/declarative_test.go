@@ -0,0 +1,343 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type testReporter struct {
+	warnings []string
+}
+
+func (r *testReporter) Info(...any) {
+}
+
+func (r *testReporter) Warn(a ...any) {
+	r.warnings = append(r.warnings, fmt.Sprint(a...))
+}
+
+func (r *testReporter) Error(a ...any) {
+	r.warnings = append(r.warnings, fmt.Sprint(a...))
+}
+
+// declarativeModel has an internet facing web server storing orders in a database (both in the internal trust boundary)
+// and an out-of-scope legacy database the web server also sends orders to
+func declarativeModel() *types.ParsedModel {
+	webToDb := types.CommunicationLink{Id: "web>orders", Title: "Orders", SourceId: "web", TargetId: "db", Protocol: types.JDBC,
+		DataAssetsSent: []string{"orders"}}
+	webToLegacy := types.CommunicationLink{Id: "web>legacy", Title: "Legacy", SourceId: "web", TargetId: "legacy", Protocol: types.HTTP,
+		DataAssetsSent: []string{"orders"}}
+	legacyToSelf := types.CommunicationLink{Id: "legacy>sync", Title: "Sync", SourceId: "legacy", TargetId: "legacy", Protocol: types.HTTP}
+	return &types.ParsedModel{
+		DataAssets: map[string]types.DataAsset{
+			"orders":  {Id: "orders", Title: "Orders", Confidentiality: types.Confidential},
+			"catalog": {Id: "catalog", Title: "Catalog", Confidentiality: types.Public},
+		},
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web Server", Type: types.Process, Internet: true, Tags: []string{"aws"},
+				DataAssetsProcessed: []string{"orders", "catalog"}, CommunicationLinks: []types.CommunicationLink{webToDb, webToLegacy}},
+			"db": {Id: "db", Title: "Database", Type: types.Datastore, DataAssetsProcessed: []string{"orders"}, DataAssetsStored: []string{"orders"}},
+			"legacy": {Id: "legacy", Title: "Legacy Database", Type: types.Datastore, OutOfScope: true,
+				DataAssetsProcessed: []string{"orders"}, DataAssetsStored: []string{"orders"}, CommunicationLinks: []types.CommunicationLink{legacyToSelf}},
+		},
+		CommunicationLinks: map[string]types.CommunicationLink{webToDb.Id: webToDb, webToLegacy.Id: webToLegacy, legacyToSelf.Id: legacyToSelf},
+		TrustBoundaries: map[string]types.TrustBoundary{
+			"internal": {Id: "internal", Title: "Internal", Type: types.NetworkOnPrem, TechnicalAssetsInside: []string{"web", "db"}},
+			"empty":    {Id: "empty", Title: "Empty", Type: types.ExecutionEnvironment},
+		},
+	}
+}
+
+func declarativeRule(match string, condition string) input.IndividualRiskRule {
+	return input.IndividualRiskRule{
+		ID:        "test",
+		Function:  "architecture",
+		STRIDE:    "tampering",
+		Match:     match,
+		Condition: condition,
+	}
+}
+
+type declarativeMatchTest struct {
+	match        string
+	condition    string
+	syntheticId  []string
+	expectedIds  []string
+	expectedRisk types.Risk
+}
+
+func TestDeclarativeRiskRuleMatch(t *testing.T) {
+	testCases := map[string]declarativeMatchTest{
+		"technical asset": {
+			match:       "technical-asset",
+			condition:   `asset.type == "datastore"`,
+			expectedIds: []string{"test@db"},
+			expectedRisk: types.Risk{
+				Title:                        "<b>Test</b> risk at <b>Database</b>",
+				MostRelevantTechnicalAssetId: "db",
+				DataBreachTechnicalAssetIDs:  []string{"db"},
+			},
+		},
+		"technical asset skips out of scope": {
+			match:       "technical-asset",
+			condition:   `true`,
+			expectedIds: []string{"test@db", "test@web"},
+		},
+		"communication link": {
+			match:       "communication-link",
+			condition:   `link.protocol == "jdbc" && source.internet && "orders" in link.data_assets_sent`,
+			expectedIds: []string{"test@web>orders"},
+			expectedRisk: types.Risk{
+				Title:                           "<b>Test</b> risk at <b>Orders</b> from <b>Web Server</b> to <b>Database</b>",
+				MostRelevantTechnicalAssetId:    "web",
+				MostRelevantCommunicationLinkId: "web>orders",
+				DataBreachTechnicalAssetIDs:     []string{"db"},
+			},
+		},
+		"communication link skips links between out of scope assets": {
+			match:       "communication-link",
+			condition:   `true`,
+			expectedIds: []string{"test@web>orders", "test@web>legacy"},
+		},
+		"communication link with custom synthetic id": {
+			match:       "communication-link",
+			condition:   `target.type == "datastore"`,
+			syntheticId: []string{"source", "target"},
+			expectedIds: []string{"test@web@db", "test@web@legacy"},
+		},
+		"data asset": {
+			match:       "data-asset",
+			condition:   `data.confidentiality >= "confidential"`,
+			expectedIds: []string{"test@orders"},
+			expectedRisk: types.Risk{
+				Title:                       "<b>Test</b> risk of <b>Orders</b>",
+				MostRelevantDataAssetId:     "orders",
+				DataBreachTechnicalAssetIDs: []string{"db", "legacy", "web"},
+			},
+		},
+		"trust boundary": {
+			match:       "trust-boundary",
+			condition:   `len(boundary.technical_assets_inside) > 0`,
+			expectedIds: []string{"test@internal"},
+			expectedRisk: types.Risk{
+				Title:                       "<b>Test</b> risk at <b>Internal</b>",
+				MostRelevantTrustBoundaryId: "internal",
+				DataBreachTechnicalAssetIDs: []string{"web", "db"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			declaration := declarativeRule(testCase.match, testCase.condition)
+			declaration.SyntheticId = testCase.syntheticId
+			rule, err := compileRiskRule("Test", declaration)
+			require.NoError(t, err)
+
+			generatedRisks, err := rule.TryGenerateRisks(declarativeModel())
+			require.NoError(t, err)
+			ids := make([]string, 0)
+			for _, risk := range generatedRisks {
+				ids = append(ids, risk.SyntheticId)
+			}
+			assert.Equal(t, testCase.expectedIds, ids)
+
+			if len(testCase.expectedRisk.Title) > 0 {
+				risk := generatedRisks[0]
+				assert.Equal(t, testCase.expectedRisk.Title, risk.Title)
+				assert.Equal(t, "test", risk.CategoryId)
+				assert.Equal(t, testCase.expectedRisk.MostRelevantTechnicalAssetId, risk.MostRelevantTechnicalAssetId)
+				assert.Equal(t, testCase.expectedRisk.MostRelevantCommunicationLinkId, risk.MostRelevantCommunicationLinkId)
+				assert.Equal(t, testCase.expectedRisk.MostRelevantDataAssetId, risk.MostRelevantDataAssetId)
+				assert.Equal(t, testCase.expectedRisk.MostRelevantTrustBoundaryId, risk.MostRelevantTrustBoundaryId)
+				assert.ElementsMatch(t, testCase.expectedRisk.DataBreachTechnicalAssetIDs, risk.DataBreachTechnicalAssetIDs)
+			}
+		})
+	}
+}
+
+type declarativeRatingTest struct {
+	title                         string
+	likelihood                    string
+	impact                        string
+	dataBreachProbability         string
+	expectedTitles                []string
+	expectedLikelihood            []types.RiskExploitationLikelihood
+	expectedImpact                []types.RiskExploitationImpact
+	expectedDataBreachProbability []types.DataBreachProbability
+	expectedError                 string
+}
+
+func TestDeclarativeRiskRuleRating(t *testing.T) {
+	testCases := map[string]declarativeRatingTest{
+		"defaults": {
+			expectedTitles:                []string{"<b>Test</b> risk at <b>Database</b>", "<b>Test</b> risk at <b>Web Server</b>"},
+			expectedLikelihood:            []types.RiskExploitationLikelihood{types.Likely, types.Likely},
+			expectedImpact:                []types.RiskExploitationImpact{types.MediumImpact, types.MediumImpact},
+			expectedDataBreachProbability: []types.DataBreachProbability{types.Possible, types.Possible},
+		},
+		"constant strings": {
+			likelihood:                    "very-likely",
+			impact:                        " high ",
+			dataBreachProbability:         "probable",
+			expectedLikelihood:            []types.RiskExploitationLikelihood{types.VeryLikely, types.VeryLikely},
+			expectedImpact:                []types.RiskExploitationImpact{types.HighImpact, types.HighImpact},
+			expectedDataBreachProbability: []types.DataBreachProbability{types.Probable, types.Probable},
+		},
+		"expressions": {
+			title:                         `"Test at " + asset.title`,
+			likelihood:                    `asset.internet ? "frequent" : "unlikely"`,
+			impact:                        `asset.type == "datastore" ? "very-high" : "low"`,
+			dataBreachProbability:         `'aws' in asset.tags ? 'improbable' : 'probable'`,
+			expectedTitles:                []string{"Test at Database", "Test at Web Server"},
+			expectedLikelihood:            []types.RiskExploitationLikelihood{types.Unlikely, types.Frequent},
+			expectedImpact:                []types.RiskExploitationImpact{types.VeryHighImpact, types.LowImpact},
+			expectedDataBreachProbability: []types.DataBreachProbability{types.Probable, types.Improbable},
+		},
+		"expression of unknown value": {
+			likelihood:    `asset.internet ? "always" : "unlikely"`,
+			expectedError: `technical asset "web": unknown exploitation likelihood "always"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			declaration := declarativeRule("technical-asset", "true")
+			declaration.Title = testCase.title
+			declaration.ExploitationLikelihood = testCase.likelihood
+			declaration.ExploitationImpact = testCase.impact
+			declaration.DataBreachProbability = testCase.dataBreachProbability
+			rule, err := compileRiskRule("Test", declaration)
+			require.NoError(t, err)
+
+			generatedRisks, err := rule.TryGenerateRisks(declarativeModel())
+			if len(testCase.expectedError) > 0 {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, generatedRisks, 2)
+			for i, risk := range generatedRisks {
+				if len(testCase.expectedTitles) > 0 {
+					assert.Equal(t, testCase.expectedTitles[i], risk.Title)
+				}
+				assert.Equal(t, testCase.expectedLikelihood[i], risk.ExploitationLikelihood)
+				assert.Equal(t, testCase.expectedImpact[i], risk.ExploitationImpact)
+				assert.Equal(t, testCase.expectedDataBreachProbability[i], risk.DataBreachProbability)
+				assert.Equal(t, types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact), risk.Severity)
+			}
+		})
+	}
+}
+
+type compileRiskRuleTest struct {
+	change        func(rule *input.IndividualRiskRule)
+	expectedError string
+}
+
+func TestCompileRiskRuleFails(t *testing.T) {
+	testCases := map[string]compileRiskRuleTest{
+		"invalid id": {
+			change:        func(rule *input.IndividualRiskRule) { rule.ID = "a b" },
+			expectedError: "invalid id syntax used (only letters, numbers, and hyphen allowed): a b",
+		},
+		"unknown function": {
+			change:        func(rule *input.IndividualRiskRule) { rule.Function = "testing" },
+			expectedError: "unknown 'function' value: testing",
+		},
+		"unknown match": {
+			change:        func(rule *input.IndividualRiskRule) { rule.Match = "shared-runtime" },
+			expectedError: `unknown 'match' value "shared-runtime"`,
+		},
+		"unknown synthetic id": {
+			change:        func(rule *input.IndividualRiskRule) { rule.SyntheticId = []string{"link"} },
+			expectedError: `unknown 'synthetic_id' element "link" (expected any of: asset)`,
+		},
+		"missing condition": {
+			change:        func(rule *input.IndividualRiskRule) { rule.Condition = " " },
+			expectedError: "missing 'condition'",
+		},
+		"unknown variable": {
+			change:        func(rule *input.IndividualRiskRule) { rule.Condition = "link.vpn" },
+			expectedError: "invalid 'condition'",
+		},
+		"invalid likelihood expression": {
+			change:        func(rule *input.IndividualRiskRule) { rule.ExploitationLikelihood = "sometimes" },
+			expectedError: "invalid 'exploitation_likelihood'",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rule := declarativeRule("technical-asset", "true")
+			testCase.change(&rule)
+			_, err := compileRiskRule("Test", rule)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expectedError)
+		})
+	}
+}
+
+func TestAddDeclarativeRiskRulesDuplicateId(t *testing.T) {
+	rule := declarativeRule("technical-asset", "true")
+
+	customRiskRules := make(map[string]*CustomRisk)
+	err := addDeclarativeRiskRules(customRiskRules, map[string]input.IndividualRiskRule{"First": rule, "Second": rule}, &testReporter{})
+	assert.EqualError(t, err, "duplicate id used: test")
+
+	customRiskRules = map[string]*CustomRisk{"test": {ID: "test"}}
+	err = addDeclarativeRiskRules(customRiskRules, map[string]input.IndividualRiskRule{"First": rule}, &testReporter{})
+	assert.EqualError(t, err, "duplicate id used: test")
+
+	customRiskRules = make(map[string]*CustomRisk)
+	err = addDeclarativeRiskRules(customRiskRules, map[string]input.IndividualRiskRule{"First": rule}, &testReporter{})
+	require.NoError(t, err)
+	require.Contains(t, customRiskRules, "test")
+	assert.Equal(t, "First", customRiskRules["test"].Category.Title)
+}
+
+func TestReadAndAnalyzeModelIndividualRiskRules(t *testing.T) {
+	example, err := os.ReadFile("../../demo/example/threagile.yaml")
+	require.NoError(t, err)
+	rules := `
+individual_risk_rules:
+  Unencrypted Datastore:
+    id: unencrypted-datastore
+    function: operations
+    stride: information-disclosure
+    match: technical-asset
+    condition: asset.type == "datastore" && asset.encryption == "none"
+    exploitation_impact: 'asset.highest_confidentiality == "strictly-confidential" ? "high" : "medium"'
+`
+
+	config := new(common.Config).Defaults("")
+	config.InputFile = filepath.Join(t.TempDir(), "threagile.yaml")
+	config.OutputFolder = t.TempDir()
+	config.RiskRulesFolder = ""
+	config.IgnoreOrphanedRiskTracking = true
+	require.NoError(t, os.WriteFile(config.InputFile, append(example, []byte(rules)...), 0600))
+
+	result, err := ReadAndAnalyzeModel(*config, &testReporter{})
+	require.NoError(t, err)
+	require.Contains(t, result.CustomRiskRules, "unencrypted-datastore")
+	assert.Equal(t, "Unencrypted Datastore", result.ParsedModel.IndividualRiskCategories["unencrypted-datastore"].Title)
+
+	generatedRisks := result.ParsedModel.GeneratedRisksByCategory["unencrypted-datastore"]
+	require.NotEmpty(t, generatedRisks)
+	for _, risk := range generatedRisks {
+		technicalAsset := result.ParsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]
+		assert.Equal(t, types.Datastore, technicalAsset.Type, risk.SyntheticId)
+		assert.Equal(t, types.NoneEncryption, technicalAsset.Encryption, risk.SyntheticId)
+		assert.False(t, technicalAsset.OutOfScope, risk.SyntheticId)
+		assert.Contains(t, result.ParsedModel.GeneratedRisksBySyntheticId, risk.SyntheticId)
+	}
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// riskRuleRun is a risk rule to run concurrently with others, rules only read the parsed model
+type riskRuleRun struct {
+	id       string
+	tags     []string
+	generate func(ctx context.Context, parsedModel *types.ParsedModel) ([]types.Risk, error)
+	risks    []types.Risk
+	profile  types.RiskRuleProfile
+}
+
+func newBuiltinRiskRuleRun(rule risks.RiskRule) *riskRuleRun {
+	return &riskRuleRun{
+		id:   rule.Category().Id,
+		tags: rule.SupportedTags(),
+		generate: func(_ context.Context, parsedModel *types.ParsedModel) ([]types.Risk, error) {
+			return rule.GenerateRisks(parsedModel), nil
+		},
+	}
+}
+
+func newCustomRiskRuleRun(rule *CustomRisk) *riskRuleRun {
+	return &riskRuleRun{
+		id:       rule.Category.Id,
+		tags:     rule.Tags,
+		generate: rule.TryGenerateRisksContext,
+	}
+}
+
+// runRiskRules runs the rules with the given parallelism (0 for the number of CPUs), a rule timing out is abandoned
+// and its context is cancelled, which stops plugins and WebAssembly modules.
+// The rules get a snapshot of the parsed model, so abandoned rules never read what is changed afterward.
+func runRiskRules(parsedModel *types.ParsedModel, runs []*riskRuleRun, parallelism int, timeout time.Duration) {
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	snapshot, snapshotError := snapshotOf(parsedModel)
+	if snapshotError != nil {
+		for _, run := range runs {
+			run.risks = nil
+			run.profile = types.RiskRuleProfile{RiskRuleId: run.id, Failure: snapshotError.Error()}
+		}
+		return
+	}
+
+	slots := make(chan struct{}, parallelism)
+	var wait sync.WaitGroup
+	for _, run := range runs {
+		wait.Add(1)
+		slots <- struct{}{}
+		go func(run *riskRuleRun) {
+			defer wait.Done()
+			defer func() { <-slots }()
+			run.execute(snapshot, timeout)
+		}(run)
+	}
+	wait.Wait()
+}
+
+func (what *riskRuleRun) execute(parsedModel *types.ParsedModel, timeout time.Duration) {
+	type result struct {
+		risks []types.Risk
+		err   error
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- result{err: fmt.Errorf("panic: %v", recovered)}
+			}
+		}()
+		generatedRisks, err := what.generate(ctx, parsedModel)
+		done <- result{risks: generatedRisks, err: err}
+	}()
+
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+
+	var err error
+	select {
+	case finished := <-done:
+		what.risks, err = finished.risks, finished.err
+	case <-timedOut:
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+
+	what.profile = types.RiskRuleProfile{RiskRuleId: what.id, Duration: time.Since(start).Seconds()}
+	if err != nil {
+		what.risks = nil
+		what.profile.Failure = err.Error()
+	}
+	what.profile.Risks = len(what.risks)
+}
+
+// snapshotOf copies the parsed model deeply the way plugins receive it, i.e. as JSON
+func snapshotOf(parsedModel *types.ParsedModel) (*types.ParsedModel, error) {
+	data, err := json.Marshal(parsedModel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to snapshot the model: %w", err)
+	}
+
+	snapshot := new(types.ParsedModel)
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to snapshot the model: %w", err)
+	}
+	return snapshot, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
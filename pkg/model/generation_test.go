@@ -0,0 +1,195 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// testRiskRule generates a risk titled by the given function of the model, it fails, panics or hangs when asked to
+type testRiskRule struct {
+	id    string
+	title func(parsedModel *types.ParsedModel) string
+	err   error
+	panic bool
+	hang  time.Duration
+}
+
+func (r *testRiskRule) Category() types.RiskCategory {
+	return types.RiskCategory{Id: r.id}
+}
+
+func (r *testRiskRule) SupportedTags() []string {
+	return []string{r.id}
+}
+
+func (r *testRiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks, err := r.TryGenerateRisks(parsedModel)
+	if err != nil {
+		panic(err)
+	}
+	return generatedRisks
+}
+
+func (r *testRiskRule) TryGenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	time.Sleep(r.hang)
+	if r.panic {
+		panic("broken rule")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	title := r.id
+	if r.title != nil {
+		title = r.title(parsedModel)
+	}
+	return []types.Risk{{CategoryId: r.id, Title: title, SyntheticId: r.id + "@web", MostRelevantTechnicalAssetId: "web",
+		Severity: types.MediumSeverity, ExploitationLikelihood: types.Likely, ExploitationImpact: types.MediumImpact}}, nil
+}
+
+func generationModel() *types.ParsedModel {
+	return &types.ParsedModel{
+		Title:                       "generation",
+		TechnicalAssets:             map[string]types.TechnicalAsset{"web": {Id: "web", Title: "Web"}},
+		AllSupportedTags:            make(map[string]bool),
+		GeneratedRisksByCategory:    make(map[string][]types.Risk),
+		GeneratedRisksBySyntheticId: make(map[string]types.Risk),
+	}
+}
+
+type riskRuleRunTest struct {
+	rule     *testRiskRule
+	builtin  bool
+	timeout  time.Duration
+	risks    int
+	expected string
+}
+
+func TestRiskRuleRunExecute(t *testing.T) {
+	testCases := map[string]riskRuleRunTest{
+		"builtin":         {rule: &testRiskRule{id: "rule"}, builtin: true, risks: 1},
+		"custom":          {rule: &testRiskRule{id: "rule"}, risks: 1},
+		"no timeout":      {rule: &testRiskRule{id: "rule", hang: 20 * time.Millisecond}, risks: 1},
+		"failed":          {rule: &testRiskRule{id: "rule", err: errors.New("unable to run")}, expected: "unable to run"},
+		"builtin panics":  {rule: &testRiskRule{id: "rule", panic: true}, builtin: true, expected: "panic: broken rule"},
+		"custom panics":   {rule: &testRiskRule{id: "rule", panic: true}, expected: "panic: broken rule"},
+		"timed out":       {rule: &testRiskRule{id: "rule", hang: time.Second}, timeout: 20 * time.Millisecond, expected: "timed out after 20ms"},
+		"within timeout":  {rule: &testRiskRule{id: "rule", hang: 10 * time.Millisecond}, timeout: time.Second, risks: 1},
+		"panics in time":  {rule: &testRiskRule{id: "rule", panic: true}, timeout: time.Second, expected: "panic: broken rule"},
+		"failed in time":  {rule: &testRiskRule{id: "rule", err: errors.New("unable to run")}, timeout: time.Second, expected: "unable to run"},
+		"builtin timeout": {rule: &testRiskRule{id: "rule", hang: time.Second}, builtin: true, timeout: 20 * time.Millisecond, expected: "timed out after 20ms"},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			run := newCustomRiskRuleRun(&CustomRisk{ID: testCase.rule.id, Category: testCase.rule.Category(), Rule: testCase.rule})
+			if testCase.builtin {
+				run = newBuiltinRiskRuleRun(testCase.rule)
+			}
+
+			run.execute(generationModel(), testCase.timeout)
+			assert.Len(t, run.risks, testCase.risks)
+			assert.Equal(t, "rule", run.profile.RiskRuleId)
+			assert.Equal(t, testCase.risks, run.profile.Risks)
+			assert.Equal(t, testCase.expected, run.profile.Failure)
+			assert.GreaterOrEqual(t, run.profile.Duration, 0.0)
+		})
+	}
+}
+
+func TestRunRiskRules(t *testing.T) {
+	runs := make([]*riskRuleRun, 0)
+	for i := 0; i < 20; i++ {
+		runs = append(runs, newBuiltinRiskRuleRun(&testRiskRule{id: fmt.Sprintf("rule-%02d", i), hang: time.Duration(20-i) * time.Millisecond}))
+	}
+	runs = append(runs, newBuiltinRiskRuleRun(&testRiskRule{id: "hanging", hang: time.Second}))
+
+	runRiskRules(generationModel(), runs, 4, 200*time.Millisecond)
+	for i, run := range runs[:20] {
+		require.Len(t, run.risks, 1)
+		assert.Equal(t, fmt.Sprintf("rule-%02d", i), run.risks[0].Title, "the results stay with their rules")
+		assert.Empty(t, run.profile.Failure)
+	}
+	assert.Empty(t, runs[20].risks)
+	assert.Equal(t, "timed out after 200ms", runs[20].profile.Failure)
+}
+
+func TestRunRiskRulesOnSnapshot(t *testing.T) {
+	parsedModel := generationModel()
+	var models sync.Map
+	title := func(ruleModel *types.ParsedModel) string {
+		models.Store(ruleModel, true)
+		return ruleModel.Title + " " + ruleModel.TechnicalAssets["web"].Title
+	}
+	abandoned := make(chan string, 1)
+	hanging := &testRiskRule{id: "hanging", hang: 100 * time.Millisecond, title: func(ruleModel *types.ParsedModel) string {
+		abandoned <- title(ruleModel)
+		return ""
+	}}
+	runs := []*riskRuleRun{
+		newBuiltinRiskRuleRun(&testRiskRule{id: "first", title: title}),
+		newBuiltinRiskRuleRun(&testRiskRule{id: "second", title: title}),
+		newBuiltinRiskRuleRun(hanging),
+	}
+
+	runRiskRules(parsedModel, runs, 0, 20*time.Millisecond)
+	parsedModel.Title = "changed"
+	parsedModel.TechnicalAssets["web"] = types.TechnicalAsset{Id: "web", Title: "Changed"}
+	parsedModel.GeneratedRisksByCategory["first"] = runs[0].risks
+
+	assert.Equal(t, "generation Web", runs[0].risks[0].Title)
+	assert.Equal(t, "generation Web", runs[1].risks[0].Title)
+	assert.Equal(t, "generation Web", <-abandoned, "the abandoned rule still reads the model as it was")
+
+	count := 0
+	models.Range(func(ruleModel, _ any) bool {
+		count++
+		assert.NotSame(t, parsedModel, ruleModel)
+		return true
+	})
+	assert.Equal(t, 1, count, "all rules share one snapshot")
+}
+
+func TestApplyRiskGeneration(t *testing.T) {
+	parsedModel := generationModel()
+	builtinRiskRules := map[string]risks.RiskRule{
+		"b-rule":  &testRiskRule{id: "b-rule"},
+		"a-rule":  &testRiskRule{id: "a-rule"},
+		"skipped": &testRiskRule{id: "skipped"},
+		"broken":  &testRiskRule{id: "broken", panic: true},
+	}
+	customRiskRules := map[string]*CustomRisk{
+		"custom":  {ID: "custom", Category: types.RiskCategory{Id: "custom"}, Tags: []string{"custom"}, Rule: &testRiskRule{id: "custom"}},
+		"failing": {ID: "failing", Category: types.RiskCategory{Id: "failing"}, Tags: []string{"failing"}, Rule: &testRiskRule{id: "failing", err: errors.New("unable to run")}},
+	}
+	parsedModel.BuiltInRiskCategories = map[string]types.RiskCategory{"a-rule": {Id: "a-rule"}, "b-rule": {Id: "b-rule"}}
+	parsedModel.IndividualRiskCategories = map[string]types.RiskCategory{"custom": {Id: "custom"}}
+	reporter := &testReporter{}
+
+	applyRiskGeneration(parsedModel, customRiskRules, builtinRiskRules, "skipped,unknown", 2, time.Second, nil, reporter)
+
+	assert.Equal(t, []string{"a-rule", "b-rule", "custom"}, sortedKeys(parsedModel.GeneratedRisksByCategory))
+	assert.Equal(t, []string{"a-rule@web", "b-rule@web", "custom@web"}, sortedKeys(parsedModel.GeneratedRisksBySyntheticId))
+	assert.Equal(t, map[string]bool{"a-rule": true, "b-rule": true, "broken": true, "custom": true, "failing": true}, parsedModel.AllSupportedTags)
+
+	ids := make([]string, 0)
+	for _, profile := range parsedModel.RiskRuleProfiles {
+		ids = append(ids, profile.RiskRuleId)
+	}
+	assert.Equal(t, []string{"a-rule", "b-rule", "broken", "custom", "failing"}, ids, "builtin rules first, each sorted by ID")
+	assert.Equal(t, "panic: broken rule", parsedModel.RiskRuleProfiles[2].Failure)
+	assert.Equal(t, "unable to run", parsedModel.RiskRuleProfiles[4].Failure)
+	assert.Equal(t, []string{
+		`WARNING: risk rule "broken" failed: panic: broken rule`,
+		`WARNING: risk rule "failing" failed: unable to run`,
+	}, reporter.warnings)
+}
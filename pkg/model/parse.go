@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -351,6 +352,7 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 			DataFormatsAccepted:     dataFormatsAccepted,
 			CommunicationLinks:      communicationLinks,
 			DiagramTweakOrder:       asset.DiagramTweakOrder,
+			SBOM:                    asset.SBOM,
 		}
 	}
 
@@ -681,6 +683,57 @@ func ParseModel(modelInput *input.Model, builtinRiskRules map[string]risks.RiskR
 		parsedModel.RiskTracking[syntheticRiskId] = tracking
 	}
 
+	// Risk Rating Overrides ===============================================================================
+	parsedModel.RiskRatingOverrides = make([]types.RiskRatingOverride, 0)
+	overrideTitles := make([]string, 0)
+	for title := range modelInput.RiskRatingOverrides {
+		overrideTitles = append(overrideTitles, title)
+	}
+	sort.Strings(overrideTitles)
+	for _, title := range overrideTitles {
+		riskRatingOverride := modelInput.RiskRatingOverrides[title]
+		categoryId := strings.TrimSpace(riskRatingOverride.Category)
+		_, isBuiltIn := parsedModel.BuiltInRiskCategories[categoryId]
+		_, isIndividual := parsedModel.IndividualRiskCategories[categoryId]
+		if !isBuiltIn && !isIndividual {
+			return nil, errors.New("unknown 'category' value of risk rating override '" + title + "': " + riskRatingOverride.Category)
+		}
+
+		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(riskRatingOverride.Tags), "risk rating override '"+title+"'")
+		if err != nil {
+			return nil, err
+		}
+
+		override := types.RiskRatingOverride{
+			Title:         title,
+			CategoryId:    categoryId,
+			Tags:          tags,
+			Justification: strings.TrimSpace(riskRatingOverride.Justification),
+		}
+		if len(strings.TrimSpace(riskRatingOverride.ExploitationLikelihood)) > 0 {
+			likelihood, err := types.ParseRiskExploitationLikelihood(riskRatingOverride.ExploitationLikelihood)
+			if err != nil {
+				return nil, errors.New("unknown 'exploitation_likelihood' value of risk rating override '" + title + "': " + riskRatingOverride.ExploitationLikelihood)
+			}
+			override.ExploitationLikelihood = &likelihood
+		}
+		if len(strings.TrimSpace(riskRatingOverride.ExploitationImpact)) > 0 {
+			impact, err := types.ParseRiskExploitationImpact(riskRatingOverride.ExploitationImpact)
+			if err != nil {
+				return nil, errors.New("unknown 'exploitation_impact' value of risk rating override '" + title + "': " + riskRatingOverride.ExploitationImpact)
+			}
+			override.ExploitationImpact = &impact
+		}
+		if override.ExploitationLikelihood == nil && override.ExploitationImpact == nil {
+			return nil, errors.New("risk rating override '" + title + "' sets neither 'exploitation_likelihood' nor 'exploitation_impact'")
+		}
+		if len(override.Justification) == 0 {
+			return nil, errors.New("missing 'justification' of risk rating override '" + title + "'")
+		}
+
+		parsedModel.RiskRatingOverrides = append(parsedModel.RiskRatingOverrides, override)
+	}
+
 	// ====================== model consistency check (linking)
 	for _, technicalAsset := range parsedModel.TechnicalAssets {
 		for _, commLink := range technicalAsset.CommunicationLinks {
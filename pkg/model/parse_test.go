@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/threagile/threagile/pkg/input"
 	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/risks/builtin"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -186,3 +187,78 @@ func createDataAsset(confidentiality types.Confidentiality, integrity types.Crit
 		Availability:    availability.String(),
 	}
 }
+
+type parseRiskRatingOverridesTest struct {
+	overrides     map[string]input.RiskRatingOverride
+	expected      []types.RiskRatingOverride
+	expectedError string
+}
+
+func TestParseRiskRatingOverrides(t *testing.T) {
+	rule := builtin.NewSqlNoSqlInjectionRule()
+	categoryId := rule.Category().Id
+	unlikely := types.Unlikely
+	highImpact := types.HighImpact
+
+	testCases := map[string]parseRiskRatingOverridesTest{
+		"sorted by title": {
+			overrides: map[string]input.RiskRatingOverride{
+				"WAF": {
+					Category:               " " + categoryId + " ",
+					Tags:                   []string{" Web "},
+					ExploitationLikelihood: "unlikely",
+					Justification:          " blocked by the WAF ",
+				},
+				"Payments": {
+					Category:           categoryId,
+					ExploitationImpact: "high",
+					Justification:      "payment data",
+				},
+			},
+			expected: []types.RiskRatingOverride{
+				{Title: "Payments", CategoryId: categoryId, Tags: []string{}, ExploitationImpact: &highImpact, Justification: "payment data"},
+				{Title: "WAF", CategoryId: categoryId, Tags: []string{"web"}, ExploitationLikelihood: &unlikely, Justification: "blocked by the WAF"},
+			},
+		},
+		"unknown category": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: "unknown", ExploitationLikelihood: "unlikely", Justification: "blocked"}},
+			expectedError: "unknown 'category' value of risk rating override 'WAF': unknown",
+		},
+		"unknown tag": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: categoryId, Tags: []string{"unknown"}, ExploitationLikelihood: "unlikely", Justification: "blocked"}},
+			expectedError: "missing referenced tag in overall tag list at risk rating override 'WAF': unknown",
+		},
+		"unknown likelihood": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: categoryId, ExploitationLikelihood: "sometimes", Justification: "blocked"}},
+			expectedError: "unknown 'exploitation_likelihood' value of risk rating override 'WAF': sometimes",
+		},
+		"unknown impact": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: categoryId, ExploitationImpact: "huge", Justification: "blocked"}},
+			expectedError: "unknown 'exploitation_impact' value of risk rating override 'WAF': huge",
+		},
+		"neither likelihood nor impact": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: categoryId, Justification: "blocked"}},
+			expectedError: "risk rating override 'WAF' sets neither 'exploitation_likelihood' nor 'exploitation_impact'",
+		},
+		"missing justification": {
+			overrides:     map[string]input.RiskRatingOverride{"WAF": {Category: categoryId, ExploitationLikelihood: "unlikely"}},
+			expectedError: "missing 'justification' of risk rating override 'WAF'",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			modelInput := createInputModel(make(map[string]input.TechnicalAsset), make(map[string]input.DataAsset))
+			modelInput.TagsAvailable = []string{"web"}
+			modelInput.RiskRatingOverrides = testCase.overrides
+
+			parsedModel, err := ParseModel(modelInput, map[string]risks.RiskRule{categoryId: rule}, make(map[string]*CustomRisk))
+			if len(testCase.expectedError) > 0 {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, parsedModel.RiskRatingOverrides)
+		})
+	}
+}
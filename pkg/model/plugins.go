@@ -0,0 +1,71 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/plugin"
+)
+
+// StartPlugins starts the plugins and registers their custom risk rules, plugins failing to start are skipped
+func StartPlugins(pluginFiles []string, timeout time.Duration, customRiskRules map[string]*CustomRisk, reporter progressReporter) []*plugin.Client {
+	plugins := make([]*plugin.Client, 0)
+	for _, pluginFile := range pluginFiles {
+		if len(pluginFile) == 0 {
+			continue
+		}
+
+		client, startError := plugin.Start(pluginFile, timeout, reporter)
+		if startError != nil {
+			reporter.Warn(fmt.Sprintf("WARNING: Plugin %q not started: %v\n", pluginFile, startError))
+			continue
+		}
+		reporter.Info("Plugin started:", client.Plugin.Name, client.Plugin.Version, "with capabilities", client.Plugin.Capabilities)
+
+		if client.HasCapability(plugin.RiskRulesCapability) {
+			for _, rule := range client.RiskRules() {
+				customRiskRules[rule.Category().Id] = &CustomRisk{
+					ID:       rule.Category().Id,
+					Category: rule.Category(),
+					Tags:     rule.SupportedTags(),
+					Rule:     rule,
+				}
+				reporter.Info("Custom risk rule loaded:", rule.Category().Id)
+			}
+		}
+		plugins = append(plugins, client)
+	}
+
+	return plugins
+}
+
+func ShutdownPlugins(plugins []*plugin.Client, reporter progressReporter) {
+	for _, client := range plugins {
+		shutdownError := client.Shutdown()
+		if shutdownError != nil {
+			reporter.Warn(fmt.Sprintf("WARNING: Plugin %q not shut down cleanly: %v\n", client.Filename, shutdownError))
+		}
+	}
+}
+
+// PluginMacros returns the model macros of the plugins
+func PluginMacros(plugins []*plugin.Client) []macros.Macros {
+	pluginMacros := make([]macros.Macros, 0)
+	for _, client := range plugins {
+		if client.HasCapability(plugin.MacrosCapability) {
+			pluginMacros = append(pluginMacros, client.Macros()...)
+		}
+	}
+	return pluginMacros
+}
+
+// raaPlugin returns the first plugin calculating the RAA (if any)
+func raaPlugin(plugins []*plugin.Client) *plugin.Client {
+	for _, client := range plugins {
+		if client.HasCapability(plugin.RAACapability) {
+			return client
+		}
+	}
+	return nil
+}
@@ -1,13 +1,23 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/cyclonedx"
 	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/osv"
+	"github.com/threagile/threagile/pkg/plugin"
+	"github.com/threagile/threagile/pkg/raa"
+	"github.com/threagile/threagile/pkg/sarif"
 	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/risks/builtin"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -23,10 +33,11 @@ type ReadResult struct {
 	IntroTextRAA     string
 	BuiltinRiskRules map[string]risks.RiskRule
 	CustomRiskRules  map[string]*CustomRisk
+	Plugins          []*plugin.Client // to be shut down by the caller
 }
 
 // TODO: consider about splitting this function into smaller ones for better reusability
-func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter) (*ReadResult, error) {
+func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter) (result *ReadResult, resultError error) {
 	progressReporter.Info("Writing into output directory:", config.OutputFolder)
 	progressReporter.Info("Parsing model:", config.InputFile)
 
@@ -34,7 +45,22 @@ func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter
 	for _, rule := range risks.GetBuiltInRiskRules() {
 		builtinRiskRules[rule.Category().Id] = rule
 	}
-	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, progressReporter)
+	customRiskRules := LoadCustomRiskRules(config.RiskRulesPlugins, NewWasmLimits(config), progressReporter)
+	defer func() {
+		if resultError != nil {
+			CloseCustomRiskRules(customRiskRules, progressReporter)
+		}
+	}()
+	rulesError := LoadRiskRulesFolder(customRiskRules, config.RiskRulesFolder, progressReporter)
+	if rulesError != nil {
+		return nil, fmt.Errorf("unable to load risk rules: %v", rulesError)
+	}
+	plugins := StartPlugins(config.Plugins, time.Duration(config.PluginTimeout)*time.Second, customRiskRules, progressReporter)
+	defer func() {
+		if resultError != nil {
+			ShutdownPlugins(plugins, progressReporter)
+		}
+	}()
 
 	modelInput := new(input.Model).Defaults()
 	loadError := modelInput.Load(config.InputFile)
@@ -42,15 +68,45 @@ func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter
 		return nil, fmt.Errorf("unable to load model yaml: %v", loadError)
 	}
 
+	rulesError = addDeclarativeRiskRules(customRiskRules, modelInput.IndividualRiskRules, progressReporter)
+	if rulesError != nil {
+		return nil, fmt.Errorf("unable to parse model yaml: %v", rulesError)
+	}
+
 	parsedModel, parseError := ParseModel(modelInput, builtinRiskRules, customRiskRules)
 	if parseError != nil {
 		return nil, fmt.Errorf("unable to parse model yaml: %v", parseError)
 	}
 
-	introTextRAA := applyRAA(parsedModel, config.BinFolder, config.RAAPlugin, progressReporter)
+	sbomError := loadSBOMs(parsedModel, filepath.Dir(config.InputFile), progressReporter)
+	if sbomError != nil {
+		return nil, fmt.Errorf("unable to load SBOM: %v", sbomError)
+	}
+
+	if len(config.OSVDatabase) > 0 {
+		osvError := matchVulnerabilities(parsedModel, config.OSVDatabase, progressReporter)
+		if osvError != nil {
+			return nil, fmt.Errorf("unable to match vulnerabilities: %v", osvError)
+		}
+	}
+
+	if len(config.SARIF) > 0 {
+		sarifError := loadFindings(parsedModel, config.SARIF, config.SARIFMapping, progressReporter)
+		if sarifError != nil {
+			return nil, fmt.Errorf("unable to load SARIF findings: %v", sarifError)
+		}
+	}
+
+	severityMatrix, matrixError := types.ParseSeverityMatrix(config.RiskSeverityMatrix)
+	if matrixError != nil {
+		return nil, fmt.Errorf("unable to parse risk severity matrix: %v", matrixError)
+	}
+
+	introTextRAA := applyRAA(parsedModel, config.BinFolder, config.RAAPlugin, config.RAAAlgorithm, config.Attractiveness, raaPlugin(plugins), progressReporter)
 
 	applyRiskGeneration(parsedModel, customRiskRules, builtinRiskRules,
-		config.SkipRiskRules, progressReporter)
+		SkippedRiskRules(config), config.RiskRulesParallelism, time.Duration(config.RiskRuleTimeout)*time.Second,
+		severityMatrix, progressReporter)
 	err := parsedModel.ApplyWildcardRiskTrackingEvaluation(config.IgnoreOrphanedRiskTracking, progressReporter)
 	if err != nil {
 		return nil, fmt.Errorf("unable to apply wildcard risk tracking evaluation: %v", err)
@@ -67,33 +123,169 @@ func ReadAndAnalyzeModel(config common.Config, progressReporter progressReporter
 		IntroTextRAA:     introTextRAA,
 		BuiltinRiskRules: builtinRiskRules,
 		CustomRiskRules:  customRiskRules,
+		Plugins:          plugins,
 	}, nil
 }
 
-func applyRisk(parsedModel *types.ParsedModel, rule risks.RiskRule, skippedRules *map[string]bool) {
-	id := rule.Category().Id
-	_, ok := (*skippedRules)[id]
+// SkippedRiskRules returns the comma-separated risk rules to skip: the configured ones and the opt-in ones not enabled
+func SkippedRiskRules(config common.Config) string {
+	skipped := make([]string, 0)
+	if len(config.SkipRiskRules) > 0 {
+		skipped = append(skipped, strings.Split(config.SkipRiskRules, ",")...)
+	}
+	enabled := strings.Split(config.EnableRiskRules, ",")
+	for _, id := range risks.GetOptInRiskRules() {
+		if !contains(enabled, id) && !contains(skipped, id) {
+			skipped = append(skipped, id)
+		}
+	}
+	return strings.Join(skipped, ",")
+}
+
+// loadSBOMs attaches the components of the SBOM files (relative to the model file) to the technical assets
+func loadSBOMs(parsedModel *types.ParsedModel, modelFolder string, progressReporter progressReporter) error {
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if len(technicalAsset.SBOM) == 0 {
+			continue
+		}
 
-	if ok {
-		fmt.Printf("Skipping risk rule %q\n", rule.Category().Id)
-		delete(*skippedRules, rule.Category().Id)
-	} else {
-		parsedModel.AddToListOfSupportedTags(rule.SupportedTags())
-		generatedRisks := rule.GenerateRisks(parsedModel)
-		if generatedRisks != nil {
-			if len(generatedRisks) > 0 {
-				parsedModel.GeneratedRisksByCategory[rule.Category().Id] = generatedRisks
+		filename := technicalAsset.SBOM
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(modelFolder, filename)
+		}
+		bom, err := cyclonedx.LoadFile(filename)
+		if err != nil {
+			return fmt.Errorf("technical asset %q: %w", id, err)
+		}
+		technicalAsset.Components = bom.SoftwareComponents()
+		parsedModel.TechnicalAssets[id] = technicalAsset
+		progressReporter.Info("Loaded SBOM of technical asset", id+":", len(technicalAsset.Components), "components")
+	}
+	return nil
+}
+
+// matchVulnerabilities attaches the known vulnerabilities of an offline OSV database to the SBOM components of the technical assets
+func matchVulnerabilities(parsedModel *types.ParsedModel, databasePath string, progressReporter progressReporter) error {
+	database, err := osv.LoadDatabase(databasePath)
+	if err != nil {
+		return err
+	}
+	progressReporter.Info("Loaded OSV database:", database.Count(), "advisories")
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		count := 0
+		for i, component := range technicalAsset.Components {
+			technicalAsset.Components[i].Vulnerabilities = database.Vulnerabilities(component)
+			count += len(technicalAsset.Components[i].Vulnerabilities)
+		}
+		if count > 0 {
+			progressReporter.Info("Matched vulnerabilities of technical asset", id+":", count)
+		}
+	}
+	return nil
+}
+
+// loadFindings attaches the findings of SARIF files to the technical assets given by the mapping of repository paths or URLs
+func loadFindings(parsedModel *types.ParsedModel, path string, mappingFilename string, progressReporter progressReporter) error {
+	if len(mappingFilename) == 0 {
+		return fmt.Errorf("missing mapping of repository paths or URLs to technical assets")
+	}
+	mapping, err := sarif.LoadMapping(mappingFilename)
+	if err != nil {
+		return err
+	}
+	for _, id := range mapping.TechnicalAssetIds() {
+		if _, ok := parsedModel.TechnicalAssets[id]; !ok {
+			return fmt.Errorf("mapping refers to unknown technical asset %q", id)
+		}
+	}
+
+	logs, err := sarif.LoadFiles(path)
+	if err != nil {
+		return err
+	}
+	filenames := make([]string, 0)
+	for filename := range logs {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		findings, unmapped := logs[filename].Findings(mapping)
+		if unmapped > 0 {
+			progressReporter.Warn(fmt.Sprintf("WARNING: %d findings of %q not mapped to a technical asset", unmapped, filename))
+		}
+		for id, assetFindings := range findings {
+			technicalAsset := parsedModel.TechnicalAssets[id]
+			technicalAsset.Findings = append(technicalAsset.Findings, assetFindings...)
+			parsedModel.TechnicalAssets[id] = technicalAsset
+		}
+	}
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if count := len(parsedModel.TechnicalAssets[id].Findings); count > 0 {
+			progressReporter.Info("Loaded findings of technical asset", id+":", count)
+		}
+	}
+	return nil
+}
+
+// raiseLikelihoodOfFindings raises the exploitation likelihood of (already rated) risks at technical assets with external findings
+// of the same CWE, recorded as rating adjustment; these findings are not reported as external findings of their own then
+func raiseLikelihoodOfFindings(parsedModel *types.ParsedModel, builtinRiskRules map[string]risks.RiskRule, severityMatrix types.SeverityMatrix) {
+	externalFindingsCategoryId := builtin.NewExternalFindingsRule().Category().Id
+	confirmed := make(map[string]bool)
+	for categoryId, categoryRisks := range parsedModel.GeneratedRisksByCategory {
+		rule, ok := builtinRiskRules[categoryId]
+		if !ok || categoryId == externalFindingsCategoryId || rule.Category().CWE == 0 {
+			continue
+		}
+		cwe := rule.Category().CWE
+		for i, risk := range categoryRisks {
+			technicalAsset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]
+			if !ok || len(technicalAsset.FindingsOfCWE(cwe)) == 0 {
+				continue
+			}
+			if risk.RatingAdjustment == nil {
+				risk.RatingAdjustment = &types.RiskRatingAdjustment{
+					OriginalSeverity:               risk.Severity,
+					OriginalExploitationLikelihood: risk.ExploitationLikelihood,
+					OriginalExploitationImpact:     risk.ExploitationImpact,
+					Reasons:                        make([]string, 0),
+				}
 			}
-		} else {
-			fmt.Printf("Failed to generate risks for %q\n", id)
+			risk.RatingAdjustment.Reasons = append(risk.RatingAdjustment.Reasons, "confirmed by external findings of CWE-"+strconv.Itoa(cwe))
+			if risk.ExploitationLikelihood < types.Frequent {
+				risk.ExploitationLikelihood++
+			}
+			risk.Severity = severityMatrix.Severity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+			risk.Title += " (confirmed by external findings)"
+			categoryRisks[i] = risk
+			confirmed[technicalAsset.Id+"@"+strconv.Itoa(cwe)] = true
+		}
+	}
+
+	remaining := make([]types.Risk, 0)
+	for _, risk := range parsedModel.GeneratedRisksByCategory[externalFindingsCategoryId] {
+		if !confirmed[risk.MostRelevantTechnicalAssetId+"@"+strconv.Itoa(risk.CWE)] {
+			remaining = append(remaining, risk)
 		}
 	}
+	if len(remaining) > 0 {
+		parsedModel.GeneratedRisksByCategory[externalFindingsCategoryId] = remaining
+	} else {
+		delete(parsedModel.GeneratedRisksByCategory, externalFindingsCategoryId)
+	}
 }
 
 // TODO: refactor skipRiskRules to be a string array instead of a comma-separated string
 func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[string]*CustomRisk,
 	builtinRiskRules map[string]risks.RiskRule,
 	skipRiskRules string,
+	parallelism int,
+	timeout time.Duration,
+	severityMatrix types.SeverityMatrix,
 	progressReporter progressReporter) {
 	progressReporter.Info("Applying risk generation")
 
@@ -104,26 +296,26 @@ func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[str
 		}
 	}
 
-	for _, rule := range builtinRiskRules {
-		applyRisk(parsedModel, rule, &skippedRules)
+	builtinRuns := make([]*riskRuleRun, 0)
+	for _, id := range sortedKeys(builtinRiskRules) {
+		if skippedRules[id] {
+			fmt.Printf("Skipping risk rule %q\n", id)
+			delete(skippedRules, id)
+			continue
+		}
+		builtinRuns = append(builtinRuns, newBuiltinRiskRuleRun(builtinRiskRules[id]))
 	}
 
 	// NOW THE CUSTOM RISK RULES (if any)
-	for id, customRule := range customRiskRules {
-		_, ok := skippedRules[id]
-		if ok {
+	customRuns := make([]*riskRuleRun, 0)
+	for _, id := range sortedKeys(customRiskRules) {
+		if skippedRules[id] {
 			progressReporter.Info("Skipping custom risk rule:", id)
 			delete(skippedRules, id)
-		} else {
-			progressReporter.Info("Executing custom risk rule:", id)
-			parsedModel.AddToListOfSupportedTags(customRule.Tags)
-			customRisks := customRule.GenerateRisks(parsedModel)
-			if len(customRisks) > 0 {
-				parsedModel.GeneratedRisksByCategory[customRule.Category.Id] = customRisks
-			}
-
-			progressReporter.Info("Added custom risks:", len(customRisks))
+			continue
 		}
+		progressReporter.Info("Executing custom risk rule:", id)
+		customRuns = append(customRuns, newCustomRiskRuleRun(customRiskRules[id]))
 	}
 
 	if len(skippedRules) > 0 {
@@ -136,6 +328,39 @@ func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[str
 		}
 	}
 
+	allRuns := append(append(make([]*riskRuleRun, 0), builtinRuns...), customRuns...)
+	for _, run := range allRuns {
+		parsedModel.AddToListOfSupportedTags(run.tags)
+	}
+	runRiskRules(parsedModel, allRuns, parallelism, timeout)
+
+	// merged in the order of the rules, not as they finish
+	for _, run := range builtinRuns {
+		if len(run.risks) > 0 {
+			parsedModel.GeneratedRisksByCategory[run.id] = run.risks
+		}
+	}
+	for _, run := range customRuns {
+		if len(run.risks) > 0 {
+			parsedModel.GeneratedRisksByCategory[run.id] = run.risks
+		}
+		if len(run.profile.Failure) == 0 {
+			progressReporter.Info("Added custom risks:", len(run.risks))
+		}
+	}
+
+	parsedModel.RiskRuleProfiles = make([]types.RiskRuleProfile, 0)
+	for _, run := range allRuns {
+		parsedModel.RiskRuleProfiles = append(parsedModel.RiskRuleProfiles, run.profile)
+		if len(run.profile.Failure) > 0 {
+			progressReporter.Warn(fmt.Sprintf("WARNING: risk rule %q failed: %v", run.id, run.profile.Failure))
+		}
+		progressReporter.Info(fmt.Sprintf("Risk rule %q took %.3fs for %d risks", run.id, run.profile.Duration, run.profile.Risks))
+	}
+
+	rateRisks(parsedModel, customRiskRules, builtinRiskRules, severityMatrix)
+	raiseLikelihoodOfFindings(parsedModel, builtinRiskRules, severityMatrix)
+
 	// save also in map keyed by synthetic risk-id
 	for _, category := range types.SortedRiskCategories(parsedModel) {
 		someRisks := types.SortedRisksOfCategory(parsedModel, category)
@@ -145,7 +370,72 @@ func applyRiskGeneration(parsedModel *types.ParsedModel, customRiskRules map[str
 	}
 }
 
-func applyRAA(parsedModel *types.ParsedModel, binFolder, raaPlugin string, progressReporter progressReporter) string {
+// rateRisks applies the rating overrides of the model and rates the risks of the rules by the severity matrix (if configured),
+// individual risks of the model keep their severity unless overridden
+func rateRisks(parsedModel *types.ParsedModel, customRiskRules map[string]*CustomRisk, builtinRiskRules map[string]risks.RiskRule,
+	severityMatrix types.SeverityMatrix) {
+	for categoryId, categoryRisks := range parsedModel.GeneratedRisksByCategory {
+		_, isBuiltIn := builtinRiskRules[categoryId]
+		_, isCustom := customRiskRules[categoryId]
+		for i, risk := range categoryRisks {
+			adjustment := types.RiskRatingAdjustment{
+				OriginalSeverity:               risk.Severity,
+				OriginalExploitationLikelihood: risk.ExploitationLikelihood,
+				OriginalExploitationImpact:     risk.ExploitationImpact,
+				Reasons:                        make([]string, 0),
+			}
+			for _, override := range parsedModel.RiskRatingOverrides {
+				if !override.Matches(parsedModel, risk) {
+					continue
+				}
+				if override.ExploitationLikelihood != nil {
+					risk.ExploitationLikelihood = *override.ExploitationLikelihood
+				}
+				if override.ExploitationImpact != nil {
+					risk.ExploitationImpact = *override.ExploitationImpact
+				}
+				adjustment.Reasons = append(adjustment.Reasons, override.Title+": "+override.Justification)
+			}
+			if len(adjustment.Reasons) == 0 && (len(severityMatrix) == 0 || (!isBuiltIn && !isCustom)) {
+				continue
+			}
+
+			risk.Severity = severityMatrix.Severity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+			if len(adjustment.Reasons) == 0 && risk.Severity != types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact) {
+				adjustment.Reasons = append(adjustment.Reasons, "severity matrix of the configuration")
+			}
+			if len(adjustment.Reasons) > 0 {
+				risk.RatingAdjustment = &adjustment
+			}
+			categoryRisks[i] = risk
+		}
+	}
+}
+
+// applyRAA runs the built-in RAA calculation unless another one is configured as run file or provided by a plugin.
+// A run file gets the algorithm and the attractiveness (as JSON) of the config as -algorithm and -attractiveness flags.
+func applyRAA(parsedModel *types.ParsedModel, binFolder, raaPlugin, raaAlgorithm string, attractiveness common.Attractiveness, raaClient *plugin.Client,
+	progressReporter progressReporter) string {
+	if raaClient != nil {
+		progressReporter.Info("Applying RAA calculation of plugin:", raaClient.Plugin.Name)
+		introText, calculateError := raaClient.CalculateRAA(parsedModel)
+		if calculateError != nil {
+			progressReporter.Warn(fmt.Sprintf("WARNING: raa of plugin %q not applied: %v\n", raaClient.Filename, calculateError))
+			return ""
+		}
+		return introText
+	}
+
+	if len(raaPlugin) == 0 || raaPlugin == common.RAAPluginName {
+		calculator, algorithmError := raa.NewCalculator(attractiveness, raaAlgorithm)
+		if algorithmError != nil {
+			progressReporter.Warn(fmt.Sprintf("WARNING: raa not applied: %v\n", algorithmError))
+			return ""
+		}
+		progressReporter.Info("Applying built-in RAA calculation:", raaAlgorithm)
+		return calculator.Calculate(parsedModel)
+	}
+
 	progressReporter.Info("Applying RAA calculation:", raaPlugin)
 
 	runner, loadError := new(runner).Load(filepath.Join(binFolder, raaPlugin))
@@ -154,7 +444,13 @@ func applyRAA(parsedModel *types.ParsedModel, binFolder, raaPlugin string, progr
 		return ""
 	}
 
-	runError := runner.Run(parsedModel, parsedModel)
+	attractivenessData, marshalError := json.Marshal(attractiveness)
+	if marshalError != nil {
+		progressReporter.Warn(fmt.Sprintf("WARNING: raa %q not applied: %v\n", raaPlugin, marshalError))
+		return ""
+	}
+
+	runError := runner.Run(parsedModel, parsedModel, "-algorithm", raaAlgorithm, "-attractiveness", string(attractivenessData))
 	if runError != nil {
 		progressReporter.Warn(fmt.Sprintf("WARNING: raa %q not applied: %v\n", raaPlugin, runError))
 		return ""
@@ -0,0 +1,295 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/raa"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/risks/builtin"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type skippedRiskRulesTest struct {
+	skip     string
+	enable   string
+	expected string
+}
+
+func TestSkippedRiskRules(t *testing.T) {
+	testCases := map[string]skippedRiskRulesTest{
+		"opt-in rules by default": {
+			expected: "inconsistent-data-lineage",
+		},
+		"configured and opt-in rules": {
+			skip:     "missing-waf,missing-vault",
+			expected: "missing-waf,missing-vault,inconsistent-data-lineage",
+		},
+		"enabled": {
+			skip:     "missing-waf",
+			enable:   "unknown-rule,inconsistent-data-lineage",
+			expected: "missing-waf",
+		},
+		"enabled but skipped": {
+			skip:     "inconsistent-data-lineage",
+			enable:   "inconsistent-data-lineage",
+			expected: "inconsistent-data-lineage",
+		},
+		"skipped once": {
+			skip:     "inconsistent-data-lineage",
+			expected: "inconsistent-data-lineage",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := common.Config{SkipRiskRules: testCase.skip, EnableRiskRules: testCase.enable}
+			assert.Equal(t, testCase.expected, SkippedRiskRules(config))
+		})
+	}
+}
+
+type applyRAATest struct {
+	raaPlugin string
+	runFile   string
+	algorithm string
+	raa       float64
+	introText string
+	warning   string
+}
+
+func TestApplyRAA(t *testing.T) {
+	testCases := map[string]applyRAATest{
+		"built-in":         {raa: 100},
+		"built-in by name": {raaPlugin: common.RAAPluginName, raa: 100},
+		"pagerank":         {algorithm: raa.PageRankAlgorithm, raa: 100},
+		"unknown algorithm": {
+			algorithm: "betweenness",
+			raa:       0,
+			warning:   `WARNING: raa not applied: unknown RAA algorithm "betweenness" (supported: attractiveness, pagerank)` + "\n",
+		},
+		"run file": {
+			raaPlugin: "raa_echo",
+			runFile:   "#!/bin/sh\necho \"$@\" >&2\ncat\n",
+			algorithm: raa.PageRankAlgorithm,
+			raa:       0,
+			introText: `-algorithm pagerank -attractiveness {"Quantity":`,
+		},
+		"missing run file": {
+			raaPlugin: "missing",
+			raa:       0,
+			warning:   `WARNING: raa "missing" not loaded`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsedModel := &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{
+				"db":  {Id: "db", Type: types.Datastore, Confidentiality: types.StrictlyConfidential},
+				"web": {Id: "web", CommunicationLinks: []types.CommunicationLink{{Id: "web>db", SourceId: "web", TargetId: "db"}}},
+			}}
+			reporter := &testReporter{}
+			binFolder := t.TempDir()
+			if len(testCase.runFile) > 0 {
+				if runtime.GOOS == "windows" {
+					t.Skip("needs a shell script as run file")
+				}
+				require.NoError(t, os.WriteFile(filepath.Join(binFolder, testCase.raaPlugin), []byte(testCase.runFile), 0700)) // #nosec G306
+			}
+
+			introText := applyRAA(parsedModel, binFolder, testCase.raaPlugin, testCase.algorithm, new(common.Config).Defaults("").Attractiveness, nil, reporter)
+			assert.Equal(t, testCase.raa, parsedModel.TechnicalAssets["db"].RAA)
+			if len(testCase.warning) > 0 {
+				assert.Empty(t, introText)
+				require.Len(t, reporter.warnings, 1)
+				assert.Contains(t, reporter.warnings[0], testCase.warning)
+				return
+			}
+			assert.NotEmpty(t, introText)
+			assert.Contains(t, introText, testCase.introText, "a run file gets the algorithm and attractiveness of the config")
+			assert.Empty(t, reporter.warnings)
+		})
+	}
+}
+
+type rateRisksTest struct {
+	categoryId string
+	overrides  []types.RiskRatingOverride
+	matrix     types.SeverityMatrix
+	severity   types.RiskSeverity
+	reasons    []string
+}
+
+func TestRateRisks(t *testing.T) {
+	unlikely := types.Unlikely
+	highImpact := types.HighImpact
+	matrix := types.SeverityMatrix{types.Likely: {types.MediumImpact: types.CriticalSeverity}}
+
+	testCases := map[string]rateRisksTest{
+		"unchanged": {
+			categoryId: "builtin",
+			severity:   types.ElevatedSeverity,
+		},
+		"severity matrix": {
+			categoryId: "builtin",
+			matrix:     matrix,
+			severity:   types.CriticalSeverity,
+			reasons:    []string{"severity matrix of the configuration"},
+		},
+		"severity matrix of custom rule": {
+			categoryId: "custom",
+			matrix:     matrix,
+			severity:   types.CriticalSeverity,
+			reasons:    []string{"severity matrix of the configuration"},
+		},
+		"individual risk keeps severity": {
+			categoryId: "individual",
+			matrix:     matrix,
+			severity:   types.ElevatedSeverity,
+		},
+		"override": {
+			categoryId: "builtin",
+			overrides: []types.RiskRatingOverride{
+				{Title: "WAF", CategoryId: "builtin", ExploitationLikelihood: &unlikely, Justification: "blocked"},
+				{Title: "Other", CategoryId: "custom", ExploitationImpact: &highImpact},
+			},
+			severity: types.CalculateSeverity(types.Unlikely, types.MediumImpact),
+			reasons:  []string{"WAF: blocked"},
+		},
+		"override of individual risk": {
+			categoryId: "individual",
+			overrides:  []types.RiskRatingOverride{{Title: "Impact", CategoryId: "individual", ExploitationImpact: &highImpact, Justification: "payments"}},
+			severity:   types.CalculateSeverity(types.Likely, types.HighImpact),
+			reasons:    []string{"Impact: payments"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsedModel := &types.ParsedModel{
+				RiskRatingOverrides: testCase.overrides,
+				GeneratedRisksByCategory: map[string][]types.Risk{testCase.categoryId: {{
+					CategoryId: testCase.categoryId, Severity: types.ElevatedSeverity, ExploitationLikelihood: types.Likely, ExploitationImpact: types.MediumImpact,
+				}}},
+			}
+			builtinRiskRules := map[string]risks.RiskRule{"builtin": builtin.NewSqlNoSqlInjectionRule()}
+			customRiskRules := map[string]*CustomRisk{"custom": {ID: "custom"}}
+
+			rateRisks(parsedModel, customRiskRules, builtinRiskRules, testCase.matrix)
+			risk := parsedModel.GeneratedRisksByCategory[testCase.categoryId][0]
+			assert.Equal(t, testCase.severity, risk.Severity)
+			if len(testCase.reasons) == 0 {
+				assert.Nil(t, risk.RatingAdjustment)
+				return
+			}
+			require.NotNil(t, risk.RatingAdjustment)
+			assert.Equal(t, testCase.reasons, risk.RatingAdjustment.Reasons)
+			assert.Equal(t, types.ElevatedSeverity, risk.RatingAdjustment.OriginalSeverity)
+		})
+	}
+}
+
+func TestRaiseLikelihoodOfFindings(t *testing.T) {
+	externalFindingsCategoryId := builtin.NewExternalFindingsRule().Category().Id
+	rule := builtin.NewSqlNoSqlInjectionRule()
+	cwe := rule.Category().CWE
+	unlikely := types.Unlikely
+	parsedModel := &types.ParsedModel{
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web":   {Id: "web", Findings: []types.ExternalFinding{{CWE: cwe}, {CWE: 79}}},
+			"api":   {Id: "api", Findings: []types.ExternalFinding{{CWE: cwe}}},
+			"other": {Id: "other"},
+		},
+		RiskRatingOverrides: []types.RiskRatingOverride{
+			{Title: "WAF", CategoryId: rule.Category().Id, ExploitationLikelihood: &unlikely, Justification: "blocked"},
+		},
+		GeneratedRisksByCategory: map[string][]types.Risk{
+			rule.Category().Id: {
+				{CategoryId: rule.Category().Id, Title: "injection", MostRelevantTechnicalAssetId: "web", Severity: types.ElevatedSeverity,
+					ExploitationLikelihood: types.Likely, ExploitationImpact: types.MediumImpact},
+				{CategoryId: rule.Category().Id, Title: "injection", MostRelevantTechnicalAssetId: "other", Severity: types.ElevatedSeverity,
+					ExploitationLikelihood: types.Likely, ExploitationImpact: types.MediumImpact},
+			},
+			externalFindingsCategoryId: {
+				{CWE: cwe, MostRelevantTechnicalAssetId: "web"},
+				{CWE: 79, MostRelevantTechnicalAssetId: "web"},
+			},
+		},
+	}
+	builtinRiskRules := map[string]risks.RiskRule{rule.Category().Id: rule, externalFindingsCategoryId: builtin.NewExternalFindingsRule()}
+	matrix := types.SeverityMatrix{types.Likely: {types.MediumImpact: types.HighSeverity}}
+
+	// rated first, the findings raise the likelihood set by the override
+	rateRisks(parsedModel, nil, builtinRiskRules, matrix)
+	raiseLikelihoodOfFindings(parsedModel, builtinRiskRules, matrix)
+	confirmed := parsedModel.GeneratedRisksByCategory[rule.Category().Id]
+	assert.Equal(t, types.Likely, confirmed[0].ExploitationLikelihood)
+	assert.Equal(t, "injection (confirmed by external findings)", confirmed[0].Title)
+	assert.Equal(t, types.HighSeverity, confirmed[0].Severity, "severity matrix")
+	require.NotNil(t, confirmed[0].RatingAdjustment)
+	assert.Equal(t, types.ElevatedSeverity, confirmed[0].RatingAdjustment.OriginalSeverity)
+	assert.Equal(t, types.Likely, confirmed[0].RatingAdjustment.OriginalExploitationLikelihood)
+	assert.Equal(t, []string{"WAF: blocked", "confirmed by external findings of CWE-" + strconv.Itoa(cwe)}, confirmed[0].RatingAdjustment.Reasons)
+	assert.Equal(t, types.Unlikely, confirmed[1].ExploitationLikelihood, "no findings at other")
+	assert.Equal(t, []string{"WAF: blocked"}, confirmed[1].RatingAdjustment.Reasons)
+	assert.Equal(t, []types.Risk{{CWE: 79, MostRelevantTechnicalAssetId: "web"}}, parsedModel.GeneratedRisksByCategory[externalFindingsCategoryId],
+		"findings confirming a risk are not reported on their own")
+
+	// without any other adjustment
+	parsedModel.RiskRatingOverrides = nil
+	parsedModel.GeneratedRisksByCategory = map[string][]types.Risk{
+		rule.Category().Id: {{CategoryId: rule.Category().Id, Title: "injection", MostRelevantTechnicalAssetId: "api", Severity: types.ElevatedSeverity,
+			ExploitationLikelihood: types.Frequent, ExploitationImpact: types.MediumImpact}},
+	}
+	raiseLikelihoodOfFindings(parsedModel, builtinRiskRules, nil)
+	risk := parsedModel.GeneratedRisksByCategory[rule.Category().Id][0]
+	assert.Equal(t, types.Frequent, risk.ExploitationLikelihood, "frequent is the highest likelihood")
+	assert.Equal(t, types.CalculateSeverity(types.Frequent, types.MediumImpact), risk.Severity)
+	require.NotNil(t, risk.RatingAdjustment)
+	assert.Equal(t, types.ElevatedSeverity, risk.RatingAdjustment.OriginalSeverity)
+	assert.Equal(t, []string{"confirmed by external findings of CWE-" + strconv.Itoa(cwe)}, risk.RatingAdjustment.Reasons)
+	_, ok := parsedModel.GeneratedRisksByCategory[externalFindingsCategoryId]
+	assert.False(t, ok, "no external findings left")
+}
+
+func TestReadAndAnalyzeModel(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.InputFile = "../../demo/example/threagile.yaml"
+	config.OutputFolder = t.TempDir()
+	config.RiskRulesFolder = ""
+	config.IgnoreOrphanedRiskTracking = true
+	reporter := &testReporter{}
+
+	result, err := ReadAndAnalyzeModel(*config, reporter)
+	require.NoError(t, err)
+	assert.Empty(t, result.Plugins)
+	assert.NotEmpty(t, result.IntroTextRAA)
+	assert.Equal(t, 100.0, result.ParsedModel.TechnicalAssets["sql-database"].RAA)
+	assert.NotEmpty(t, result.ParsedModel.GeneratedRisksByCategory)
+	assert.NotContains(t, result.ParsedModel.GeneratedRisksByCategory, "inconsistent-data-lineage", "opt-in rules are skipped")
+	assert.Len(t, result.ParsedModel.RiskRuleProfiles, len(result.BuiltinRiskRules)-len(risks.GetOptInRiskRules()))
+	for _, profile := range result.ParsedModel.RiskRuleProfiles {
+		assert.Empty(t, profile.Failure, profile.RiskRuleId)
+	}
+	for syntheticId, risk := range result.ParsedModel.GeneratedRisksBySyntheticId {
+		assert.Contains(t, result.ParsedModel.GeneratedRisksByCategory, risk.CategoryId, syntheticId)
+	}
+}
+
+func TestReadAndAnalyzeModelFails(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	config.InputFile = filepath.Join(t.TempDir(), "threagile.yaml")
+	config.RiskRulesFolder = ""
+	require.NoError(t, os.WriteFile(config.InputFile, []byte("data_assets:\n  Orders:\n    id: orders\n    confidentiality: secret\n"), 0600))
+
+	_, err := ReadAndAnalyzeModel(*config, &testReporter{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse model yaml")
+}
@@ -1,10 +1,13 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
 
+	"github.com/threagile/threagile/pkg/security/risks"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -13,30 +16,80 @@ type CustomRisk struct {
 	Category types.RiskCategory
 	Tags     []string
 	Runner   *runner
+	Rule     risks.RiskRule `json:"-"` // evaluated in-process instead of running a plugin, e.g. rules declared in yaml
 }
 
-func (r *CustomRisk) GenerateRisks(m *types.ParsedModel) []types.Risk {
-	if r.Runner == nil {
-		return nil
-	}
+// fallibleRiskRule is a risk rule reporting errors instead of exiting, e.g. when running a plugin
+type fallibleRiskRule interface {
+	TryGenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error)
+}
 
-	risks := make([]types.Risk, 0)
-	runError := r.Runner.Run(m, &risks, "-generate-risks")
+// cancellableRiskRule is a risk rule that stops generating risks when its context is done, e.g. a WebAssembly module
+type cancellableRiskRule interface {
+	TryGenerateRisksContext(ctx context.Context, parsedModel *types.ParsedModel) ([]types.Risk, error)
+}
+
+// closableRiskRule is a risk rule holding resources to release when it is no longer used, e.g. a WebAssembly runtime
+type closableRiskRule interface {
+	Close() error
+}
+
+func (r *CustomRisk) GenerateRisks(m *types.ParsedModel) []types.Risk {
+	risks, runError := r.TryGenerateRisks(m)
 	if runError != nil {
-		log.Fatalf("Failed to generate risks for custom risk rule %q: %v\n", r.Runner.Filename, runError)
+		log.Fatalf("Failed to generate risks for custom risk rule %q: %v\n", r.ID, runError)
 	}
 
 	return risks
 }
 
-func LoadCustomRiskRules(pluginFiles []string, reporter progressReporter) map[string]*CustomRisk {
+func (r *CustomRisk) TryGenerateRisks(m *types.ParsedModel) ([]types.Risk, error) {
+	return r.TryGenerateRisksContext(context.Background(), m)
+}
+
+// TryGenerateRisksContext kills the plugin or stops the WebAssembly module when the context is done
+func (r *CustomRisk) TryGenerateRisksContext(ctx context.Context, m *types.ParsedModel) ([]types.Risk, error) {
+	if rule, ok := r.Rule.(cancellableRiskRule); ok {
+		return rule.TryGenerateRisksContext(ctx, m)
+	}
+	if rule, ok := r.Rule.(fallibleRiskRule); ok {
+		return rule.TryGenerateRisks(m)
+	}
+	if r.Rule != nil {
+		return r.Rule.GenerateRisks(m), nil
+	}
+	if r.Runner == nil {
+		return nil, nil
+	}
+
+	risks := make([]types.Risk, 0)
+	runError := r.Runner.RunContext(ctx, m, &risks, "-generate-risks")
+	return risks, runError
+}
+
+func LoadCustomRiskRules(pluginFiles []string, wasmLimits WasmLimits, reporter progressReporter) map[string]*CustomRisk {
 	customRiskRuleList := make([]string, 0)
 	customRiskRules := make(map[string]*CustomRisk)
 	if len(pluginFiles) > 0 {
 		reporter.Info("Loading custom risk rules:", strings.Join(pluginFiles, ", "))
 
 		for _, pluginFile := range pluginFiles {
-			if len(pluginFile) > 0 {
+			if len(pluginFile) > 0 && strings.EqualFold(filepath.Ext(pluginFile), WasmPluginExtension) {
+				rule, loadError := loadWasmRiskRule(pluginFile, wasmLimits)
+				if loadError != nil {
+					reporter.Error(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
+					continue
+				}
+
+				customRiskRules[rule.category.Id] = &CustomRisk{
+					ID:       rule.category.Id,
+					Category: rule.category,
+					Tags:     rule.supportedTags,
+					Rule:     rule,
+				}
+				customRiskRuleList = append(customRiskRuleList, rule.category.Id)
+				reporter.Info("Custom risk rule loaded:", rule.category.Id)
+			} else if len(pluginFile) > 0 {
 				runner, loadError := new(runner).Load(pluginFile)
 				if loadError != nil {
 					reporter.Error(fmt.Sprintf("WARNING: Custom risk rule %q not loaded: %v\n", pluginFile, loadError))
@@ -60,3 +113,15 @@ func LoadCustomRiskRules(pluginFiles []string, reporter progressReporter) map[st
 
 	return customRiskRules
 }
+
+// CloseCustomRiskRules releases the resources of the custom risk rules (if any), call it when dropping the rules
+func CloseCustomRiskRules(customRiskRules map[string]*CustomRisk, reporter progressReporter) {
+	for id, customRiskRule := range customRiskRules {
+		if rule, ok := customRiskRule.Rule.(closableRiskRule); ok {
+			closeError := rule.Close()
+			if closeError != nil {
+				reporter.Warn(fmt.Sprintf("WARNING: Custom risk rule %q not closed cleanly: %v\n", id, closeError))
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package model
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -35,6 +36,11 @@ func (p *runner) Load(filename string) (*runner, error) {
 }
 
 func (p *runner) Run(in any, out any, parameters ...string) error {
+	return p.RunContext(context.Background(), in, out, parameters...)
+}
+
+// RunContext kills the run file when the context is done before it exits
+func (p *runner) RunContext(ctx context.Context, in any, out any, parameters ...string) error {
 	*p = runner{
 		Filename:   p.Filename,
 		Parameters: parameters,
@@ -42,7 +48,7 @@ func (p *runner) Run(in any, out any, parameters ...string) error {
 		Out:        out,
 	}
 
-	plugin := exec.Command(p.Filename, p.Parameters...) // #nosec G204
+	plugin := exec.CommandContext(ctx, p.Filename, p.Parameters...) // #nosec G204
 	stdin, stdinError := plugin.StdinPipe()
 	if stdinError != nil {
 		return stdinError
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunnerRunContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as run file")
+	}
+
+	filename := filepath.Join(t.TempDir(), "plugin")
+	require.NoError(t, os.WriteFile(filename, []byte("#!/bin/sh\nexec sleep 10\n"), 0700)) // #nosec G306
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	plugin, err := new(runner).Load(filename)
+	require.NoError(t, err)
+
+	start := time.Now()
+	var out any
+	err = plugin.RunContext(ctx, nil, &out)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "the run file is killed when the context is done")
+}
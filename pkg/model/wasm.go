@@ -0,0 +1,178 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// WasmPluginExtension marks custom risk rules compiled to WebAssembly (WASI), run in-process instead of as a process
+const WasmPluginExtension = ".wasm"
+
+const (
+	wasmPagesPerMiB    = 16 // of 64 KiB each
+	wasmMaxMemoryLimit = 4096
+)
+
+// WasmLimits are the resources each call of a WebAssembly risk rule may use
+type WasmLimits struct {
+	Timeout     time.Duration // 0 for none
+	MemoryLimit int           // MiB, 0 for the maximum of 4 GiB
+}
+
+func NewWasmLimits(config common.Config) WasmLimits {
+	return WasmLimits{
+		Timeout:     time.Duration(config.RiskRuleTimeout) * time.Second,
+		MemoryLimit: config.WasmMemoryLimit,
+	}
+}
+
+// wasmRiskRule runs a WASI command module in a sandbox without filesystem and network access. Mirroring the RiskRule
+// interface, the module gets "category", "supported-tags" or "generate-risks" as argument and writes the JSON of the
+// risk category, the supported tags or the generated risks (for the JSON of the parsed model on stdin) to stdout.
+type wasmRiskRule struct {
+	filename      string
+	runtime       wazero.Runtime
+	module        wazero.CompiledModule
+	category      types.RiskCategory
+	supportedTags []string
+	timeout       time.Duration
+}
+
+func loadWasmRiskRule(filename string, limits WasmLimits) (*wasmRiskRule, error) {
+	if limits.MemoryLimit < 0 || limits.MemoryLimit > wasmMaxMemoryLimit {
+		return nil, fmt.Errorf("memory limit of %d MiB is not between 0 and %d MiB", limits.MemoryLimit, wasmMaxMemoryLimit)
+	}
+
+	code, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, readError
+	}
+
+	ctx := context.Background()
+	runtimeConfig := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if limits.MemoryLimit > 0 {
+		runtimeConfig = runtimeConfig.WithMemoryLimitPages(uint32(limits.MemoryLimit * wasmPagesPerMiB))
+	}
+	rule := &wasmRiskRule{
+		filename: filename,
+		runtime:  wazero.NewRuntimeWithConfig(ctx, runtimeConfig),
+		timeout:  limits.Timeout,
+	}
+	_, wasiError := wasi_snapshot_preview1.Instantiate(ctx, rule.runtime)
+	if wasiError != nil {
+		_ = rule.Close()
+		return nil, wasiError
+	}
+
+	var compileError error
+	rule.module, compileError = rule.runtime.CompileModule(ctx, code)
+	if compileError != nil {
+		_ = rule.Close()
+		return nil, fmt.Errorf("failed to compile: %v", compileError)
+	}
+
+	categoryError := rule.run(ctx, "category", nil, &rule.category)
+	if categoryError != nil {
+		_ = rule.Close()
+		return nil, categoryError
+	}
+
+	tagsError := rule.run(ctx, "supported-tags", nil, &rule.supportedTags)
+	if tagsError != nil {
+		_ = rule.Close()
+		return nil, tagsError
+	}
+
+	return rule, nil
+}
+
+// Close releases the runtime and the compiled module, the rule can't be run afterwards
+func (r *wasmRiskRule) Close() error {
+	return r.runtime.Close(context.Background())
+}
+
+func (r *wasmRiskRule) Category() types.RiskCategory {
+	return r.category
+}
+
+func (r *wasmRiskRule) SupportedTags() []string {
+	return r.supportedTags
+}
+
+// GenerateRisks returns no risks if the module fails, see TryGenerateRisks for the error
+func (r *wasmRiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks, runError := r.TryGenerateRisks(parsedModel)
+	if runError != nil {
+		return make([]types.Risk, 0)
+	}
+	return generatedRisks
+}
+
+func (r *wasmRiskRule) TryGenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	return r.TryGenerateRisksContext(context.Background(), parsedModel)
+}
+
+func (r *wasmRiskRule) TryGenerateRisksContext(ctx context.Context, parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	generatedRisks := make([]types.Risk, 0)
+	runError := r.run(ctx, "generate-risks", parsedModel, &generatedRisks)
+	return generatedRisks, runError
+}
+
+// run instantiates a fresh module for each call, so no state is kept between calls. The module is stopped when the
+// context is done or the timeout of the rule is exceeded.
+func (r *wasmRiskRule) run(ctx context.Context, command string, in any, out any) error {
+	var stdin []byte
+	if in != nil {
+		var marshalError error
+		stdin, marshalError = json.Marshal(in)
+		if marshalError != nil {
+			return marshalError
+		}
+	}
+
+	runCtx := ctx
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(filepath.Base(r.filename), command).
+		WithStdin(bytes.NewReader(stdin)).
+		WithStdout(&stdout).
+		WithStderr(&stderr)
+	module, runError := r.runtime.InstantiateModule(runCtx, r.module, config)
+	if module != nil {
+		defer func() { _ = module.Close(runCtx) }()
+	}
+	if runError != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%q stopped: %v", command, ctx.Err())
+		}
+		if runCtx.Err() != nil {
+			return fmt.Errorf("%q timed out after %v", command, r.timeout)
+		}
+		return fmt.Errorf("%q failed: %v: %v", command, runError, stderr.String())
+	}
+
+	unmarshalError := json.Unmarshal(stdout.Bytes(), out)
+	if unmarshalError != nil {
+		return fmt.Errorf("invalid output of %q: %v", command, unmarshalError)
+	}
+
+	return nil
+}
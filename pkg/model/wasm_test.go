@@ -0,0 +1,196 @@
+package model
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// wasmModule assembles a WebAssembly binary of the sections, each given by its id followed by its content
+func wasmModule(sections ...[]byte) []byte {
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	for _, section := range sections {
+		module = append(module, section[0], byte(len(section)-1))
+		module = append(module, section[1:]...)
+	}
+	return module
+}
+
+func wasmName(name string) []byte {
+	return append([]byte{byte(len(name))}, name...)
+}
+
+func concat(parts ...[]byte) []byte {
+	result := make([]byte, 0)
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+var (
+	// _start loops forever
+	loopingWasm = wasmModule(
+		[]byte{0x01, 0x01, 0x60, 0x00, 0x00},                               // type ()->()
+		[]byte{0x03, 0x01, 0x00},                                           // function of type 0
+		concat([]byte{0x07, 0x01}, wasmName("_start"), []byte{0x00, 0x00}), // export function 0
+		[]byte{0x0a, 0x01, 0x07, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x0b}, // loop br 0 end
+	)
+
+	// _start writes "null" to stdout, i.e. an empty category, no tags and no risks
+	nullWasm = wasmModule(
+		[]byte{0x01, 0x02, 0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7f, 0x60, 0x00, 0x00}, // fd_write and ()->()
+		concat([]byte{0x02, 0x01}, wasmName("wasi_snapshot_preview1"), wasmName("fd_write"), []byte{0x00, 0x00}),
+		[]byte{0x03, 0x01, 0x01},       // function of type 1
+		[]byte{0x05, 0x01, 0x00, 0x01}, // memory of one page
+		concat([]byte{0x07, 0x02}, wasmName("memory"), []byte{0x02, 0x00}, wasmName("_start"), []byte{0x00, 0x01}),
+		[]byte{0x0a, 0x01, 0x0d, 0x00, 0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, 0x1a, 0x0b}, // fd_write(1, 0, 1, 8)
+		concat([]byte{0x0b, 0x02},
+			[]byte{0x00, 0x41, 0x00, 0x0b, 0x08, 0x10, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00}, // io vector at 0 of "null" at 16
+			concat([]byte{0x00, 0x41, 0x10, 0x0b}, wasmName("null"))),
+	)
+
+	// requires a memory of 32 pages (2 MiB)
+	largeMemoryWasm = wasmModule(
+		[]byte{0x05, 0x01, 0x00, 0x20},
+	)
+)
+
+func writeWasm(t *testing.T, code []byte) string {
+	filename := filepath.Join(t.TempDir(), "rule.wasm")
+	require.NoError(t, os.WriteFile(filename, code, 0600))
+	return filename
+}
+
+type loadWasmTest struct {
+	code     []byte
+	limits   WasmLimits
+	expected string
+}
+
+func TestLoadWasmRiskRule(t *testing.T) {
+	testCases := map[string]loadWasmTest{
+		"loaded": {
+			code:   nullWasm,
+			limits: WasmLimits{Timeout: time.Minute, MemoryLimit: 1},
+		},
+		"timed out": {
+			code:     loopingWasm,
+			limits:   WasmLimits{Timeout: 50 * time.Millisecond},
+			expected: `"category" timed out after 50ms`,
+		},
+		"memory limit exceeded": {
+			code:     largeMemoryWasm,
+			limits:   WasmLimits{MemoryLimit: 1},
+			expected: "failed to compile",
+		},
+		"maximum memory": {
+			code:     largeMemoryWasm,
+			expected: `invalid output of "category"`,
+		},
+		"invalid memory limit": {
+			code:     nullWasm,
+			limits:   WasmLimits{MemoryLimit: 4097},
+			expected: "memory limit of 4097 MiB is not between 0 and 4096 MiB",
+		},
+		"no module": {
+			code:     []byte("#!/bin/sh"),
+			expected: "failed to compile",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rule, err := loadWasmRiskRule(writeWasm(t, testCase.code), testCase.limits)
+			if len(testCase.expected) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, types.RiskCategory{}, rule.Category())
+			assert.Empty(t, rule.SupportedTags())
+			assert.NoError(t, rule.Close())
+		})
+	}
+}
+
+func TestWasmRiskRuleGenerateRisks(t *testing.T) {
+	rule, err := loadWasmRiskRule(writeWasm(t, nullWasm), WasmLimits{Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	generatedRisks, err := rule.TryGenerateRisks(&types.ParsedModel{})
+	require.NoError(t, err)
+	assert.Empty(t, generatedRisks)
+
+	rule.module, err = rule.runtime.CompileModule(context.Background(), loopingWasm)
+	require.NoError(t, err)
+	_, err = rule.TryGenerateRisks(&types.ParsedModel{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"generate-risks" timed out after 50ms`)
+	assert.Empty(t, rule.GenerateRisks(&types.ParsedModel{}), "failing modules generate no risks instead of exiting")
+}
+
+func TestNewWasmLimits(t *testing.T) {
+	config := new(common.Config).Defaults("")
+	assert.Equal(t, WasmLimits{Timeout: common.DefaultRiskRuleTimeout * time.Second, MemoryLimit: common.DefaultWasmMemoryLimit}, NewWasmLimits(*config))
+
+	config.RiskRuleTimeout = 0
+	config.WasmMemoryLimit = 0
+	assert.Equal(t, WasmLimits{}, NewWasmLimits(*config))
+}
+
+func TestCloseCustomRiskRules(t *testing.T) {
+	rule, err := loadWasmRiskRule(writeWasm(t, nullWasm), WasmLimits{})
+	require.NoError(t, err)
+	customRiskRules := map[string]*CustomRisk{
+		"wasm":        {ID: "wasm", Rule: rule},
+		"declarative": {ID: "declarative", Rule: &declarativeRiskRule{}},
+		"plugin":      {ID: "plugin"},
+	}
+
+	reporter := &testReporter{}
+	CloseCustomRiskRules(customRiskRules, reporter)
+	assert.Empty(t, reporter.warnings)
+
+	_, err = rule.TryGenerateRisks(&types.ParsedModel{})
+	assert.Error(t, err, "closed rules can't be run")
+}
+
+func TestWasmRiskRuleGenerateRisksCancelled(t *testing.T) {
+	rule, err := loadWasmRiskRule(writeWasm(t, nullWasm), WasmLimits{})
+	require.NoError(t, err)
+	defer func() { _ = rule.Close() }()
+	rule.module, err = rule.runtime.CompileModule(context.Background(), loopingWasm)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = rule.TryGenerateRisksContext(ctx, &types.ParsedModel{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"generate-risks" stopped: context deadline exceeded`)
+
+	stopped := make(chan error, 1)
+	run := newCustomRiskRuleRun(&CustomRisk{ID: "looping", Rule: rule})
+	run.generate = func(ctx context.Context, parsedModel *types.ParsedModel) ([]types.Risk, error) {
+		generatedRisks, err := rule.TryGenerateRisksContext(ctx, parsedModel)
+		stopped <- err
+		return generatedRisks, err
+	}
+	run.execute(&types.ParsedModel{}, 50*time.Millisecond)
+	assert.Equal(t, "timed out after 50ms", run.profile.Failure)
+	select {
+	case err = <-stopped:
+		assert.Contains(t, err.Error(), "context canceled", "the module of a timed out rule is stopped")
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the module of a timed out rule keeps running")
+	}
+}
@@ -0,0 +1,345 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type confidentialityGuess struct {
+	confidentiality types.Confidentiality
+	contains        []string // matched within normalized field names
+	exact           []string // matched against whole normalized field names, for short and ambiguous words
+}
+
+// field names hinting at sensitive data, strongest first
+var confidentialityGuesses = []confidentialityGuess{
+	{types.StrictlyConfidential,
+		[]string{"password", "passwd", "passphrase", "secret", "privatekey", "cvv", "cvc", "socialsecurity", "cardnumber", "creditcard", "apikey", "accesstoken", "refreshtoken"},
+		[]string{"pin", "ssn", "pan", "token"}},
+	{types.Confidential,
+		[]string{"iban", "accountnumber", "routingnumber", "dateofbirth", "birthdate", "salary", "income", "taxid", "passport", "nationalid", "diagnosis", "health", "medical", "biometric"},
+		[]string{"bic", "dob"}},
+	{types.Restricted,
+		[]string{"email", "phone", "mobile", "address", "street", "postalcode", "zipcode", "firstname", "lastname", "fullname", "gender", "birthplace"},
+		[]string{"zip", "ip"}},
+}
+
+// data formats of content types, matched within the content type
+var dataFormatsOfContentTypes = []struct {
+	contains   string
+	dataFormat types.DataFormat
+}{
+	{"json", types.JSON},
+	{"xml", types.XML},
+	{"java-serialized", types.Serialization},
+	{"pickle", types.Serialization},
+	{"csv", types.CSV},
+	{"multipart/", types.File},
+	{"octet-stream", types.File},
+	{"application/pdf", types.File},
+	{"image/", types.File},
+}
+
+// Import turns an OpenAPI document into a model include: object schemas become data assets processed by the target asset,
+// content types its accepted data formats and the operations a communication link from the client asset (if any) using
+// the authentication of the security schemes. The existing model provides titles and ids, existing data assets are reused.
+func Import(document *Document, existing *input.Model, targetTitle string, clientTitle string, mapping importer.Mapping) (*input.Model, *importer.Report) {
+	report := new(importer.Report)
+	include := &input.Model{
+		Questions:       make(map[string]string),
+		DataAssets:      make(map[string]input.DataAsset),
+		TechnicalAssets: make(map[string]input.TechnicalAsset),
+	}
+
+	apiTitle := document.Info.Title
+	if len(apiTitle) == 0 {
+		apiTitle = "API"
+	}
+
+	takenIds := make(map[string]bool)
+	for _, dataAsset := range existing.DataAssets {
+		takenIds[dataAsset.ID] = true
+	}
+	for _, technicalAsset := range existing.TechnicalAssets {
+		takenIds[technicalAsset.ID] = true
+	}
+
+	// Data Assets ===============================================================================
+	dataAssetIds := make(map[string]string)
+	for _, name := range sortedKeys(document.Components.Schemas) {
+		schema := document.Components.Schemas[name]
+		if !schema.IsObject() {
+			continue
+		}
+		if dataAsset, ok := existing.DataAssets[name]; ok {
+			dataAssetIds[name] = dataAsset.ID
+			continue
+		}
+
+		description := schema.Description
+		if len(description) == 0 {
+			description = fmt.Sprintf("Schema %v of %v", name, apiTitle)
+		}
+		dataAsset := importer.NewDataAsset(importer.UniqueID(name, takenIds), description)
+		confidentiality, fields := guessConfidentiality(schema)
+		dataAsset.Confidentiality = confidentiality.String()
+		if len(fields) > 0 {
+			dataAsset.JustificationCiaRating = "Confidentiality guessed from the fields " + strings.Join(fields, ", ")
+		}
+		include.DataAssets[name] = dataAsset
+		dataAssetIds[name] = dataAsset.ID
+	}
+
+	// Operations ===============================================================================
+	sent, received := make([]string, 0), make([]string, 0)
+	dataFormats := make([]string, 0)
+	schemes := make([]string, 0) // the default of the document first, as it is the most likely one used by the client
+	for _, requirement := range document.Security {
+		schemes = appendUnique(schemes, sortedKeys(requirement)...)
+	}
+	readonly := true
+	for _, operation := range document.Operations() {
+		readonly = readonly && operation.IsReadOnly()
+
+		requirements := operation.Security
+		if requirements == nil {
+			requirements = document.Security
+		}
+		for _, requirement := range requirements {
+			schemes = appendUnique(schemes, sortedKeys(requirement)...)
+		}
+
+		bodies := []*Body{operation.RequestBody}
+		for _, status := range sortedKeys(operation.Responses) {
+			if strings.HasPrefix(status, "2") || status == "default" {
+				bodies = append(bodies, operation.Responses[status])
+			}
+		}
+		for i, body := range bodies {
+			body = document.body(body)
+			if body == nil {
+				continue
+			}
+			for _, contentType := range sortedKeys(body.Content) {
+				if dataFormat, ok := guessDataFormat(contentType, mapping); ok {
+					dataFormats = appendUnique(dataFormats, dataFormat.String())
+				} else if i == 0 {
+					report.Add("operation %v: unknown data format of content type %q (add it to the mapping file to fix this)", operation.Name(), contentType)
+				}
+
+				schema := body.Content[contentType].Schema
+				ids := make([]string, 0)
+				for _, name := range document.referencedSchemas(schema, make(map[string]bool)) {
+					if id, ok := dataAssetIds[name]; ok {
+						ids = append(ids, id)
+					}
+				}
+				if len(ids) == 0 && schema.IsObject() {
+					report.Add("operation %v: inline schema of %v is not imported as data asset, move it to the components to fix this", operation.Name(), contentType)
+				}
+				if i == 0 {
+					sent = appendUnique(sent, ids...)
+				} else {
+					received = appendUnique(received, ids...)
+				}
+			}
+		}
+	}
+	sort.Strings(sent)
+	sort.Strings(received)
+	sort.Strings(dataFormats)
+
+	processed := make([]string, 0)
+	for _, id := range dataAssetIds {
+		processed = append(processed, id)
+	}
+	sort.Strings(processed)
+
+	include.TechnicalAssets[targetTitle] = input.TechnicalAsset{
+		DataAssetsProcessed: processed,
+		DataFormatsAccepted: dataFormats,
+	}
+
+	// Communication Link ===============================================================================
+	if len(clientTitle) == 0 {
+		report.Add("no client given, add the communication link to %v manually", targetTitle)
+	} else {
+		authentication, authorization := authenticationOf(document, schemes, mapping, report)
+		if len(schemes) > 1 {
+			importer.AddQuestion(include, fmt.Sprintf("Which of the security schemes %v is used by '%v' to access '%v'?", strings.Join(schemes, ", "), clientTitle, targetTitle), authentication.String())
+		}
+
+		link := importer.NewCommunicationLink(existing.TechnicalAssets[targetTitle].ID, fmt.Sprintf("Calls the operations of %v", apiTitle), protocolOf(document))
+		link.Authentication = authentication.String()
+		link.Authorization = authorization.String()
+		link.Readonly = readonly
+		link.DataAssetsSent = sent
+		link.DataAssetsReceived = received
+		include.TechnicalAssets[clientTitle] = input.TechnicalAsset{
+			CommunicationLinks: map[string]input.CommunicationLink{apiTitle: link},
+		}
+	}
+
+	if len(include.Questions) == 0 {
+		include.Questions = nil
+	}
+	return include, report
+}
+
+// authenticationOf derives authentication and authorization of the link from the first security scheme used
+func authenticationOf(document *Document, schemes []string, mapping importer.Mapping, report *importer.Report) (types.Authentication, types.Authorization) {
+	if len(schemes) == 0 {
+		return types.NoneAuthentication, types.NoneAuthorization
+	}
+
+	scheme, ok := document.Components.SecuritySchemes[schemes[0]]
+	if !ok {
+		report.Add("security scheme %q is not defined", schemes[0])
+		return types.NoneAuthentication, types.NoneAuthorization
+	}
+
+	for _, candidate := range []string{schemes[0], scheme.Type + " " + scheme.Scheme, scheme.Type} {
+		if name, ok := mapping.Resolve(importer.MappingAuthentication, candidate, importer.EnumNames(types.AuthenticationValues())); ok {
+			authentication, _ := types.ParseAuthentication(name)
+			return authentication, types.TechnicalUser
+		}
+	}
+
+	switch strings.ToLower(scheme.Type) {
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "bearer") {
+			return types.Token, types.TechnicalUser
+		}
+		return types.Credentials, types.TechnicalUser
+	case "apikey":
+		if strings.EqualFold(scheme.In, "cookie") {
+			return types.SessionId, types.TechnicalUser
+		}
+		return types.Credentials, types.TechnicalUser
+	case "oauth2", "openidconnect":
+		return types.Token, types.EndUserIdentityPropagation
+	case "mutualtls":
+		return types.ClientCertificate, types.TechnicalUser
+	}
+
+	report.Add("security scheme %q: unknown type %q (add it to the mapping file to fix this)", schemes[0], scheme.Type)
+	return types.NoneAuthentication, types.NoneAuthorization
+}
+
+// protocolOf is https unless all servers are plain http
+func protocolOf(document *Document) types.Protocol {
+	for _, server := range document.Servers {
+		if !strings.HasPrefix(strings.ToLower(server.URL), "http://") {
+			return types.HTTPS
+		}
+	}
+	if len(document.Servers) > 0 {
+		return types.HTTP
+	}
+	return types.HTTPS
+}
+
+// referencedSchemas returns the names of all component schemas a schema refers to, directly or through other schemas
+func (what *Document) referencedSchemas(schema *Schema, visited map[string]bool) []string {
+	if schema == nil {
+		return nil
+	}
+
+	names := make([]string, 0)
+	if name := SchemaName(schema.Ref); len(name) > 0 {
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+		names = append(names, name)
+		schema = what.Components.Schemas[name]
+		if schema == nil {
+			return names
+		}
+	}
+
+	children := append(append(append([]*Schema{schema.Items}, schema.AllOf...), schema.OneOf...), schema.AnyOf...)
+	for _, property := range sortedKeys(schema.Properties) {
+		children = append(children, schema.Properties[property])
+	}
+	for _, child := range children {
+		names = append(names, what.referencedSchemas(child, visited)...)
+	}
+	return names
+}
+
+// guessConfidentiality rates a schema by the names of its own fields (including inline objects), referenced schemas are rated on their own
+func guessConfidentiality(schema *Schema) (types.Confidentiality, []string) {
+	fieldNames := make([]string, 0)
+	var collect func(schema *Schema)
+	collect = func(schema *Schema) {
+		if schema == nil || len(schema.Ref) > 0 {
+			return
+		}
+		for _, name := range sortedKeys(schema.Properties) {
+			fieldNames = append(fieldNames, name)
+			collect(schema.Properties[name])
+		}
+		collect(schema.Items)
+		for _, child := range schema.AllOf {
+			collect(child)
+		}
+	}
+	collect(schema)
+
+	for _, guess := range confidentialityGuesses {
+		matches := make([]string, 0)
+		for _, fieldName := range fieldNames {
+			normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(fieldName))
+			matched := contains(guess.exact, normalized)
+			for _, word := range guess.contains {
+				matched = matched || strings.Contains(normalized, word)
+			}
+			if matched {
+				matches = append(matches, fieldName)
+			}
+		}
+		if len(matches) > 0 {
+			return guess.confidentiality, matches
+		}
+	}
+	return types.Internal, nil
+}
+
+func guessDataFormat(contentType string, mapping importer.Mapping) (types.DataFormat, bool) {
+	if name, ok := mapping.Resolve(importer.MappingDataFormat, contentType, importer.EnumNames(types.DataFormatValues())); ok {
+		dataFormat, err := types.ParseDataFormat(name)
+		return dataFormat, err == nil
+	}
+
+	lowerContentType := strings.ToLower(contentType)
+	for _, guess := range dataFormatsOfContentTypes {
+		if strings.Contains(lowerContentType, guess.contains) {
+			return guess.dataFormat, true
+		}
+	}
+	return types.JSON, false
+}
+
+func appendUnique(values []string, others ...string) []string {
+	for _, other := range others {
+		if !contains(values, other) {
+			values = append(values, other)
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func existingModel() *input.Model {
+	existing := importer.NewModel("Shop")
+	existing.TechnicalAssets["Orders Service"] = importer.NewTechnicalAsset("orders-service", "Orders", types.WebServiceREST)
+	existing.TechnicalAssets["Web Shop"] = importer.NewTechnicalAsset("web-shop", "Shop", types.WebApplication)
+	existing.DataAssets["Customer"] = importer.NewDataAsset("customer-data", "Customers")
+	return existing
+}
+
+func TestImport(t *testing.T) {
+	document, err := LoadFile("testdata/orders.yaml")
+	require.NoError(t, err)
+
+	include, report := Import(document, existingModel(), "Orders Service", "Web Shop", importer.Mapping{})
+
+	// existing data assets are reused, schemas of single values are skipped
+	require.Len(t, include.DataAssets, 1)
+	require.Contains(t, include.DataAssets, "Order")
+	assert.Equal(t, types.StrictlyConfidential.String(), include.DataAssets["Order"].Confidentiality)
+	assert.Equal(t, "Confidentiality guessed from the fields cardNumber", include.DataAssets["Order"].JustificationCiaRating)
+
+	target := include.TechnicalAssets["Orders Service"]
+	assert.Equal(t, []string{"customer-data", "order"}, target.DataAssetsProcessed)
+	assert.Equal(t, []string{types.File.String(), types.JSON.String()}, target.DataFormatsAccepted)
+
+	require.Contains(t, include.TechnicalAssets["Web Shop"].CommunicationLinks, "Orders API")
+	link := include.TechnicalAssets["Web Shop"].CommunicationLinks["Orders API"]
+	assert.Equal(t, "orders-service", link.Target)
+	assert.Equal(t, types.HTTPS.String(), link.Protocol)
+	assert.Equal(t, types.Token.String(), link.Authentication)
+	assert.Equal(t, types.TechnicalUser.String(), link.Authorization)
+	assert.False(t, link.Readonly)
+	assert.Equal(t, []string{"customer-data", "order"}, link.DataAssetsSent)
+	assert.Equal(t, []string{"customer-data", "order"}, link.DataAssetsReceived)
+
+	assert.Equal(t, "token", include.Questions["Which of the security schemes bearer, apiKey is used by 'Web Shop' to access 'Orders Service'?"])
+	assertReported(t, report, "operation PUT /orders/{id}/notes: unknown data format of content type \"text/x-notes\"")
+	assertReported(t, report, "operation PUT /orders/{id}/notes: inline schema of text/x-notes is not imported")
+}
+
+func TestImportWithoutClient(t *testing.T) {
+	document, err := LoadFile("testdata/orders.yaml")
+	require.NoError(t, err)
+
+	include, report := Import(document, existingModel(), "Orders Service", "", importer.Mapping{importer.MappingDataFormat: {"text/x-notes": types.CSV.String()}})
+
+	assert.NotContains(t, include.TechnicalAssets, "Web Shop")
+	assert.Nil(t, include.Questions)
+	assert.Equal(t, []string{types.CSV.String(), types.File.String(), types.JSON.String()}, include.TechnicalAssets["Orders Service"].DataFormatsAccepted)
+	assertReported(t, report, "no client given")
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.yaml")
+	assert.Error(t, err)
+}
+
+type authenticationTest struct {
+	scheme         SecurityScheme
+	mapping        importer.Mapping
+	authentication types.Authentication
+	authorization  types.Authorization
+}
+
+func TestAuthenticationOf(t *testing.T) {
+	testCases := map[string]authenticationTest{
+		"basic": {
+			scheme:         SecurityScheme{Type: "http", Scheme: "basic"},
+			authentication: types.Credentials,
+			authorization:  types.TechnicalUser,
+		},
+		"bearer": {
+			scheme:         SecurityScheme{Type: "http", Scheme: "Bearer"},
+			authentication: types.Token,
+			authorization:  types.TechnicalUser,
+		},
+		"api key in cookie": {
+			scheme:         SecurityScheme{Type: "apiKey", In: "cookie"},
+			authentication: types.SessionId,
+			authorization:  types.TechnicalUser,
+		},
+		"oauth2": {
+			scheme:         SecurityScheme{Type: "oauth2"},
+			authentication: types.Token,
+			authorization:  types.EndUserIdentityPropagation,
+		},
+		"mutual tls": {
+			scheme:         SecurityScheme{Type: "mutualTLS"},
+			authentication: types.ClientCertificate,
+			authorization:  types.TechnicalUser,
+		},
+		"mapped": {
+			scheme:         SecurityScheme{Type: "http", Scheme: "negotiate"},
+			mapping:        importer.Mapping{importer.MappingAuthentication: {"http negotiate": types.Externalized.String()}},
+			authentication: types.Externalized,
+			authorization:  types.TechnicalUser,
+		},
+		"unknown": {
+			scheme:         SecurityScheme{Type: "carrier-pigeon"},
+			authentication: types.NoneAuthentication,
+			authorization:  types.NoneAuthorization,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			scheme := testCase.scheme
+			document := &Document{Components: Components{SecuritySchemes: map[string]*SecurityScheme{"scheme": &scheme}}}
+
+			authentication, authorization := authenticationOf(document, []string{"scheme"}, testCase.mapping, new(importer.Report))
+
+			assert.Equal(t, testCase.authentication, authentication)
+			assert.Equal(t, testCase.authorization, authorization)
+		})
+	}
+}
+
+type guessConfidentialityTest struct {
+	fields   []string
+	expected types.Confidentiality
+}
+
+func TestGuessConfidentiality(t *testing.T) {
+	testCases := map[string]guessConfidentialityTest{
+		"no hints": {
+			fields:   []string{"id", "quantity"},
+			expected: types.Internal,
+		},
+		"personal data": {
+			fields:   []string{"id", "e_mail", "emailAddress"},
+			expected: types.Restricted,
+		},
+		"exact word only": {
+			fields:   []string{"zipper", "shipping"},
+			expected: types.Internal,
+		},
+		"strongest wins": {
+			fields:   []string{"iban", "password"},
+			expected: types.StrictlyConfidential,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			schema := &Schema{Properties: make(map[string]*Schema)}
+			for _, field := range testCase.fields {
+				schema.Properties[field] = &Schema{Type: "string"}
+			}
+
+			actual, _ := guessConfidentiality(schema)
+
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func assertReported(t *testing.T, report *importer.Report, prefix string) {
+	t.Helper()
+	for _, item := range report.Items {
+		if strings.HasPrefix(item, prefix) {
+			return
+		}
+	}
+	t.Errorf("report misses %q in %q", prefix, report.Items)
+}
@@ -0,0 +1,177 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI 3 documents (https://spec.openapis.org/oas/v3.1.0), only the parts needed for the import
+
+type Document struct {
+	OpenAPI    string                `yaml:"openapi"`
+	Info       Info                  `yaml:"info"`
+	Servers    []Server              `yaml:"servers"`
+	Paths      map[string]PathItem   `yaml:"paths"`
+	Components Components            `yaml:"components"`
+	Security   []SecurityRequirement `yaml:"security"`
+}
+
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `yaml:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `yaml:"securitySchemes"`
+	RequestBodies   map[string]*Body           `yaml:"requestBodies"`
+	Responses       map[string]*Body           `yaml:"responses"`
+}
+
+type PathItem struct {
+	Get     *Operation `yaml:"get"`
+	Put     *Operation `yaml:"put"`
+	Post    *Operation `yaml:"post"`
+	Delete  *Operation `yaml:"delete"`
+	Options *Operation `yaml:"options"`
+	Head    *Operation `yaml:"head"`
+	Patch   *Operation `yaml:"patch"`
+	Trace   *Operation `yaml:"trace"`
+}
+
+type Operation struct {
+	OperationId string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	RequestBody *Body                 `yaml:"requestBody"`
+	Responses   map[string]*Body      `yaml:"responses"`
+	Security    []SecurityRequirement `yaml:"security"`
+
+	method string
+	path   string
+}
+
+// Body is a request body or a response
+type Body struct {
+	Ref     string               `yaml:"$ref"`
+	Content map[string]MediaType `yaml:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `yaml:"$ref"`
+	Type        any                `yaml:"type"`
+	Description string             `yaml:"description"`
+	Properties  map[string]*Schema `yaml:"properties"`
+	Items       *Schema            `yaml:"items"`
+	AllOf       []*Schema          `yaml:"allOf"`
+	OneOf       []*Schema          `yaml:"oneOf"`
+	AnyOf       []*Schema          `yaml:"anyOf"`
+}
+
+type SecurityScheme struct {
+	Type        string `yaml:"type"`
+	Scheme      string `yaml:"scheme"`
+	In          string `yaml:"in"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// SecurityRequirement maps names of security schemes to the scopes required
+type SecurityRequirement map[string][]string
+
+// LoadFile reads an OpenAPI 3 document in yaml or json
+func LoadFile(filename string) (*Document, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OpenAPI file %q: %w", filename, err)
+	}
+
+	document := new(Document)
+	err = yaml.Unmarshal(data, document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse OpenAPI file %q: %w", filename, err)
+	}
+	if !strings.HasPrefix(document.OpenAPI, "3.") {
+		return nil, fmt.Errorf("OpenAPI file %q is not an OpenAPI 3 document (openapi: %q)", filename, document.OpenAPI)
+	}
+	return document, nil
+}
+
+// Operations returns all operations of the document
+func (what *Document) Operations() []*Operation {
+	operations := make([]*Operation, 0)
+	for _, path := range sortedKeys(what.Paths) {
+		item := what.Paths[path]
+		for _, method := range []struct {
+			name      string
+			operation *Operation
+		}{{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete}, {"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace}} {
+			if method.operation != nil {
+				method.operation.method, method.operation.path = method.name, path
+				operations = append(operations, method.operation)
+			}
+		}
+	}
+	return operations
+}
+
+// Name identifies an operation by its id, falling back to method and path
+func (what *Operation) Name() string {
+	if len(what.OperationId) > 0 {
+		return what.OperationId
+	}
+	return what.method + " " + what.path
+}
+
+// IsReadOnly tells whether the operation only reads data
+func (what *Operation) IsReadOnly() bool {
+	return what.method == "GET" || what.method == "HEAD" || what.method == "OPTIONS"
+}
+
+// body resolves references to request bodies and responses of the components
+func (what *Document) body(body *Body) *Body {
+	for depth := 0; body != nil && len(body.Ref) > 0 && depth < 10; depth++ {
+		name := body.Ref[strings.LastIndex(body.Ref, "/")+1:]
+		if strings.Contains(body.Ref, "/requestBodies/") {
+			body = what.Components.RequestBodies[name]
+		} else {
+			body = what.Components.Responses[name]
+		}
+	}
+	return body
+}
+
+// SchemaName returns the name of the component a schema reference like "#/components/schemas/Order" refers to
+func SchemaName(ref string) string {
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// IsObject tells whether the schema describes structured data rather than a single value
+func (what *Schema) IsObject() bool {
+	return what != nil && (len(what.Properties) > 0 || len(what.AllOf) > 0 || what.Type == "object")
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
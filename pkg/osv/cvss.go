@@ -0,0 +1,74 @@
+package osv
+
+import (
+	"math"
+	"strings"
+)
+
+// metric values of the CVSS v3 base score (https://www.first.org/cvss/v3.1/specification-document#7-4-Metric-Values)
+var cvss3Metrics = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3BaseScore calculates the base score of a CVSS v3 vector like "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+func cvss3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+
+	values := make(map[string]float64)
+	scopeChanged := false
+	for _, part := range parts[1:] {
+		metric, value, _ := strings.Cut(part, ":")
+		if metric == "S" {
+			scopeChanged = value == "C"
+			continue
+		}
+		if weights, ok := cvss3Metrics[metric]; ok {
+			if weight, ok := weights[value]; ok {
+				values[metric] = weight
+			}
+		}
+	}
+	if len(values) != len(cvss3Metrics) {
+		return 0, false
+	}
+	if scopeChanged { // privileges required weigh more when the scope changes
+		switch values["PR"] {
+		case 0.62:
+			values["PR"] = 0.68
+		case 0.27:
+			values["PR"] = 0.5
+		}
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp rounds up to one decimal as defined by the specification, avoiding floating point artifacts
+func roundUp(value float64) float64 {
+	integer := int64(math.Round(value * 100000))
+	if integer%10000 == 0 {
+		return float64(integer) / 100000
+	}
+	return (math.Floor(float64(integer)/10000) + 1) / 10
+}
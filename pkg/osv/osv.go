@@ -0,0 +1,334 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// OSV advisories (https://ossf.github.io/osv-schema/), only the parts needed for matching components
+
+type Advisory struct {
+	Id               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Withdrawn        string           `json:"withdrawn"`
+	Severity         []Severity       `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package  Package    `json:"package"`
+	Ranges   []Range    `json:"ranges"`
+	Versions []string   `json:"versions"`
+	Severity []Severity `json:"severity"`
+}
+
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+type DatabaseSpecific struct {
+	Severity string   `json:"severity"`
+	CWEIds   []string `json:"cwe_ids"`
+}
+
+// ecosystems of package url types (https://github.com/package-url/purl-spec)
+var ecosystemsOfPurlTypes = map[string]string{
+	"maven":    "Maven",
+	"npm":      "npm",
+	"pypi":     "PyPI",
+	"golang":   "Go",
+	"nuget":    "NuGet",
+	"cargo":    "crates.io",
+	"gem":      "RubyGems",
+	"composer": "Packagist",
+	"hex":      "Hex",
+	"pub":      "Pub",
+}
+
+// scores of textual severities, used when there is no CVSS v3 vector
+var scoresOfSeverities = map[string]float64{
+	"CRITICAL": 9.0,
+	"HIGH":     7.5,
+	"MODERATE": 5.5,
+	"MEDIUM":   5.5,
+	"LOW":      3.0,
+}
+
+type affectedPackage struct {
+	advisory *Advisory
+	affected Affected
+}
+
+// Database is an offline copy of OSV advisories indexed by package
+type Database struct {
+	packages map[string][]affectedPackage
+	count    int
+}
+
+// LoadDatabase reads OSV advisories from a json file, a zip file (like the OSV export of an ecosystem) or a directory of those
+func LoadDatabase(path string) (*Database, error) {
+	database := &Database{packages: make(map[string][]affectedPackage)}
+	err := filepath.WalkDir(path, func(filename string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".json":
+			data, readError := os.ReadFile(filepath.Clean(filename))
+			if readError != nil {
+				return readError
+			}
+			return database.add(filename, data)
+		case ".zip":
+			return database.addZip(filename)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load OSV database %q: %w", path, err)
+	}
+	return database, nil
+}
+
+// Count returns the number of advisories loaded
+func (what *Database) Count() int {
+	return what.count
+}
+
+func (what *Database) addZip(filename string) error {
+	reader, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	for _, file := range reader.File {
+		if !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(content)
+		_ = content.Close()
+		if err != nil {
+			return err
+		}
+		err = what.add(filename+"/"+file.Name, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add indexes a single advisory or a list of advisories
+func (what *Database) add(filename string, data []byte) error {
+	advisories := make([]*Advisory, 0)
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &advisories); err != nil {
+			return fmt.Errorf("unable to parse %q: %w", filename, err)
+		}
+	} else {
+		advisory := new(Advisory)
+		if err := json.Unmarshal(data, advisory); err != nil {
+			return fmt.Errorf("unable to parse %q: %w", filename, err)
+		}
+		advisories = append(advisories, advisory)
+	}
+
+	for _, advisory := range advisories {
+		if len(advisory.Id) == 0 || len(advisory.Withdrawn) > 0 {
+			continue
+		}
+		what.count++
+		for _, affected := range advisory.Affected {
+			key := packageKey(affected.Package.Ecosystem, affected.Package.Name)
+			what.packages[key] = append(what.packages[key], affectedPackage{advisory: advisory, affected: affected})
+		}
+	}
+	return nil
+}
+
+// Vulnerabilities returns the advisories affecting the component, which is identified by its package url
+func (what *Database) Vulnerabilities(component types.SoftwareComponent) []types.Vulnerability {
+	ecosystem, name, version, ok := parsePurl(component.PURL)
+	if !ok {
+		return nil
+	}
+	if len(component.Version) > 0 {
+		version = component.Version
+	}
+
+	vulnerabilities := make([]types.Vulnerability, 0)
+	seen := make(map[string]bool)
+	for _, candidate := range what.packages[packageKey(ecosystem, name)] {
+		fixed, affected := isAffected(candidate.affected, version)
+		if !affected || seen[candidate.advisory.Id] {
+			continue
+		}
+		seen[candidate.advisory.Id] = true
+		vulnerabilities = append(vulnerabilities, types.Vulnerability{
+			Id:      candidate.advisory.Id,
+			Aliases: candidate.advisory.Aliases,
+			Summary: candidate.advisory.Summary,
+			Score:   score(candidate.advisory, candidate.affected),
+			CWE:     cwe(candidate.advisory),
+			Fixed:   fixed,
+		})
+	}
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].Id < vulnerabilities[j].Id
+	})
+	return vulnerabilities
+}
+
+// isAffected evaluates the explicit versions and the ranges of an affected package, returning the fixing version if any
+func isAffected(affected Affected, version string) (string, bool) {
+	for _, versionRange := range affected.Ranges {
+		if versionRange.Type != "SEMVER" && versionRange.Type != "ECOSYSTEM" {
+			continue
+		}
+		// events are sorted: the version is affected if the last event up to it introduces the vulnerability
+		inRange, fixed := false, ""
+	events:
+		for _, event := range versionRange.Events {
+			switch {
+			case len(event.Introduced) > 0:
+				if event.Introduced != "0" && compareVersions(version, event.Introduced) < 0 {
+					break events
+				}
+				inRange = true
+			case len(event.Fixed) > 0:
+				if compareVersions(version, event.Fixed) < 0 {
+					fixed = event.Fixed
+					break events
+				}
+				inRange = false
+			case len(event.LastAffected) > 0:
+				if compareVersions(version, event.LastAffected) <= 0 {
+					break events
+				}
+				inRange = false
+			case len(event.Limit) > 0:
+				if compareVersions(version, event.Limit) < 0 {
+					break events
+				}
+				inRange = false
+			}
+		}
+		if inRange {
+			return fixed, true
+		}
+	}
+
+	for _, affectedVersion := range affected.Versions {
+		if compareVersions(version, affectedVersion) == 0 {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// score prefers a CVSS v3 vector of the affected package or the advisory, falling back to the textual severity
+func score(advisory *Advisory, affected Affected) float64 {
+	for _, severity := range append(affected.Severity, advisory.Severity...) {
+		if severity.Type == "CVSS_V3" {
+			if value, ok := cvss3BaseScore(severity.Score); ok {
+				return value
+			}
+		}
+	}
+	return scoresOfSeverities[strings.ToUpper(advisory.DatabaseSpecific.Severity)]
+}
+
+func cwe(advisory *Advisory) int {
+	for _, id := range advisory.DatabaseSpecific.CWEIds {
+		value, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(id), "CWE-"))
+		if err == nil {
+			return value
+		}
+	}
+	return 0
+}
+
+// parsePurl returns the OSV ecosystem, package name and version of a package url like "pkg:maven/org.example/lib@1.0"
+func parsePurl(purl string) (string, string, string, bool) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return "", "", "", false
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	purlType, rest, ok := strings.Cut(rest, "/")
+	if !ok {
+		return "", "", "", false
+	}
+	ecosystem, ok := ecosystemsOfPurlTypes[strings.ToLower(purlType)]
+	if !ok {
+		return "", "", "", false
+	}
+
+	version := ""
+	if index := strings.LastIndex(rest, "@"); index > strings.LastIndex(rest, "/") && index > 0 {
+		rest, version = rest[:index], rest[index+1:]
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	for i, part := range parts {
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			parts[i] = unescaped
+		}
+	}
+	if unescaped, err := url.PathUnescape(version); err == nil {
+		version = unescaped
+	}
+
+	separator := "/"
+	if ecosystem == "Maven" {
+		separator = ":"
+	}
+	return ecosystem, strings.Join(parts, separator), version, true
+}
+
+func packageKey(ecosystem string, name string) string {
+	ecosystem, _, _ = strings.Cut(ecosystem, ":") // like "Debian:11"
+	name = strings.ToLower(name)
+	if ecosystem == "PyPI" {
+		name = strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	}
+	return strings.ToLower(ecosystem) + "|" + name
+}
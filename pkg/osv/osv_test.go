@@ -0,0 +1,224 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type vulnerabilitiesTest struct {
+	component types.SoftwareComponent
+	expected  []string
+	fixed     string
+}
+
+func TestVulnerabilities(t *testing.T) {
+	database, err := LoadDatabase("testdata/db")
+	require.NoError(t, err)
+	assert.Equal(t, 3, database.Count(), "withdrawn advisories are skipped")
+
+	testCases := map[string]vulnerabilitiesTest{
+		"first range": {
+			component: types.SoftwareComponent{PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8"},
+			expected:  []string{"GHSA-0000-jackson"},
+			fixed:     "2.9.10.8",
+		},
+		"second range": {
+			component: types.SoftwareComponent{PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.10.1"},
+			expected:  []string{"GHSA-0000-jackson"},
+			fixed:     "2.10.5.1",
+		},
+		"between ranges": {
+			component: types.SoftwareComponent{PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.10.8"},
+		},
+		"version of component wins": {
+			component: types.SoftwareComponent{PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8", Version: "2.16.0"},
+		},
+		"ranges and versions": {
+			component: types.SoftwareComponent{PURL: "pkg:npm/lodash@4.17.15"},
+			expected:  []string{"GHSA-0000-lodash", "GHSA-0000-version"},
+			fixed:     "4.17.21",
+		},
+		"fixed": {
+			component: types.SoftwareComponent{PURL: "pkg:npm/lodash@4.17.21"},
+		},
+		"no package url": {
+			component: types.SoftwareComponent{Name: "lodash", Version: "4.17.15"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ids := make([]string, 0)
+			vulnerabilities := database.Vulnerabilities(testCase.component)
+			for _, vulnerability := range vulnerabilities {
+				ids = append(ids, vulnerability.Id)
+			}
+
+			assert.ElementsMatch(t, testCase.expected, ids)
+			if len(vulnerabilities) > 0 {
+				assert.Equal(t, testCase.fixed, vulnerabilities[0].Fixed)
+			}
+		})
+	}
+}
+
+func TestVulnerabilityDetails(t *testing.T) {
+	database, err := LoadDatabase("testdata/db")
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.Vulnerability{{
+		Id:      "GHSA-0000-jackson",
+		Aliases: []string{"CVE-2020-0001"},
+		Summary: "Deserialization of untrusted data in jackson-databind",
+		Score:   9.8,
+		CWE:     502,
+		Fixed:   "2.9.10.8",
+	}}, database.Vulnerabilities(types.SoftwareComponent{PURL: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.8"}))
+
+	lodash := database.Vulnerabilities(types.SoftwareComponent{PURL: "pkg:npm/lodash@4.17.20"})
+	require.Len(t, lodash, 1)
+	assert.Equal(t, 5.5, lodash[0].Score, "textual severity without CVSS vector")
+}
+
+func TestLoadDatabaseFails(t *testing.T) {
+	_, err := LoadDatabase("testdata/missing")
+	assert.Error(t, err)
+}
+
+type parsePurlTest struct {
+	purl      string
+	ecosystem string
+	name      string
+	version   string
+	ok        bool
+}
+
+func TestParsePurl(t *testing.T) {
+	testCases := map[string]parsePurlTest{
+		"maven": {
+			purl:      "pkg:maven/org.example/lib@1.0?type=jar",
+			ecosystem: "Maven",
+			name:      "org.example:lib",
+			version:   "1.0",
+			ok:        true,
+		},
+		"scoped npm": {
+			purl:      "pkg:npm/%40angular/core@17.0.0",
+			ecosystem: "npm",
+			name:      "@angular/core",
+			version:   "17.0.0",
+			ok:        true,
+		},
+		"go": {
+			purl:      "pkg:golang/github.com/gin-gonic/gin@v1.9.1",
+			ecosystem: "Go",
+			name:      "github.com/gin-gonic/gin",
+			version:   "v1.9.1",
+			ok:        true,
+		},
+		"without version": {
+			purl:      "pkg:pypi/django",
+			ecosystem: "PyPI",
+			name:      "django",
+			ok:        true,
+		},
+		"unknown type": {
+			purl: "pkg:deb/debian/openssl@3.0.11",
+		},
+		"no package url": {
+			purl: "org.example:lib:1.0",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ecosystem, packageName, version, ok := parsePurl(testCase.purl)
+
+			assert.Equal(t, testCase.ecosystem, ecosystem)
+			assert.Equal(t, testCase.name, packageName)
+			assert.Equal(t, testCase.version, version)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func TestPackageKey(t *testing.T) {
+	assert.Equal(t, packageKey("PyPI", "Django_REST.framework"), packageKey("PyPI", "django-rest-framework"))
+	assert.Equal(t, packageKey("Debian:11", "openssl"), packageKey("Debian", "openssl"))
+}
+
+type compareVersionsTest struct {
+	first    string
+	second   string
+	expected int
+}
+
+func TestCompareVersions(t *testing.T) {
+	testCases := map[string]compareVersionsTest{
+		"equal":                    {first: "1.2.3", second: "1.2.3", expected: 0},
+		"numeric":                  {first: "1.10.0", second: "1.9.0", expected: 1},
+		"missing parts are zero":   {first: "1.2", second: "1.2.0", expected: 0},
+		"pre-release before":       {first: "1.0.0-rc1", second: "1.0.0", expected: -1},
+		"pre-releases":             {first: "1.0.0-alpha", second: "1.0.0-beta", expected: -1},
+		"release qualifier":        {first: "5.3.0.RELEASE", second: "5.3.0", expected: 0},
+		"prefix and build ignored": {first: "v2.0.0+build5", second: "2.0.0", expected: 0},
+		"more parts":               {first: "2.9.10.8", second: "2.9.10", expected: 1},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, compareVersions(testCase.first, testCase.second))
+			assert.Equal(t, -testCase.expected, compareVersions(testCase.second, testCase.first))
+		})
+	}
+}
+
+type cvss3BaseScoreTest struct {
+	vector   string
+	expected float64
+	ok       bool
+}
+
+func TestCvss3BaseScore(t *testing.T) {
+	testCases := map[string]cvss3BaseScoreTest{
+		"critical": {
+			vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+			expected: 9.8,
+			ok:       true,
+		},
+		"scope changed": {
+			vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H",
+			expected: 10.0,
+			ok:       true,
+		},
+		"medium": {
+			vector:   "CVSS:3.0/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N",
+			expected: 5.4,
+			ok:       true,
+		},
+		"no impact": {
+			vector:   "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N",
+			expected: 0,
+			ok:       true,
+		},
+		"incomplete": {
+			vector: "CVSS:3.1/AV:N/AC:L",
+		},
+		"cvss v2": {
+			vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := cvss3BaseScore(testCase.vector)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package osv
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// compareVersions compares versions of any ecosystem by their numeric and textual parts, good enough for
+// semantic versions and the usual Maven, PyPI or NuGet versions: textual parts after the release (like "-beta1" or "rc2") sort before it
+func compareVersions(first string, second string) int {
+	firstParts, secondParts := versionParts(first), versionParts(second)
+	for i := 0; i < len(firstParts) || i < len(secondParts); i++ {
+		switch {
+		case i >= len(firstParts):
+			return -comparePart(secondParts[i], "")
+		case i >= len(secondParts):
+			return comparePart(firstParts[i], "")
+		}
+		if result := comparePart(firstParts[i], secondParts[i]); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// comparePart compares numbers numerically and texts alphabetically, numbers are bigger than texts and
+// missing parts count as zero, so they are bigger than texts (pre-releases)
+func comparePart(first string, second string) int {
+	if first == second {
+		return 0
+	}
+	if len(first) == 0 {
+		return -comparePart(second, first)
+	}
+
+	firstNumber, firstError := strconv.Atoi(first)
+	secondNumber, secondError := strconv.Atoi(second)
+	switch {
+	case len(second) == 0 && firstError == nil:
+		secondNumber, secondError = 0, nil
+	case len(second) == 0:
+		return -1
+	}
+	switch {
+	case firstError == nil && secondError == nil:
+		if firstNumber < secondNumber {
+			return -1
+		} else if firstNumber > secondNumber {
+			return 1
+		}
+		return 0
+	case firstError == nil:
+		return 1
+	case secondError == nil:
+		return -1
+	}
+	return strings.Compare(first, second)
+}
+
+// versionParts splits a version like "v1.2.0-rc1" into "1", "2", "0", "rc", "1", ignoring build metadata
+func versionParts(version string) []string {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	version, _, _ = strings.Cut(version, "+")
+
+	parts := make([]string, 0)
+	current := ""
+	for _, char := range version {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			if len(current) > 0 {
+				parts = append(parts, current)
+			}
+			current = ""
+			continue
+		}
+		if len(current) > 0 && unicode.IsDigit(char) != unicode.IsDigit(rune(current[len(current)-1])) {
+			parts = append(parts, current)
+			current = ""
+		}
+		current += string(char)
+	}
+	if len(current) > 0 {
+		parts = append(parts, current)
+	}
+
+	// "final", "ga" and "release" are the release itself
+	for len(parts) > 0 {
+		last := parts[len(parts)-1]
+		if last != "final" && last != "ga" && last != "release" {
+			break
+		}
+		parts = parts[:len(parts)-1]
+	}
+	return parts
+}
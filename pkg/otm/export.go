@@ -0,0 +1,237 @@
+package otm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// rough trust ratings of the trust boundary types, as OTM requires one for each trust zone
+var trustRatingOfBoundaryType = map[types.TrustBoundaryType]float64{
+	types.NetworkOnPrem:                   60,
+	types.NetworkDedicatedHoster:          50,
+	types.NetworkVirtualLAN:               60,
+	types.NetworkCloudProvider:            50,
+	types.NetworkCloudSecurityGroup:       70,
+	types.NetworkPolicyNamespaceIsolation: 70,
+	types.ExecutionEnvironment:            80,
+}
+
+func WriteFile(parsedModel *types.ParsedModel, filename string) error {
+	jsonBytes, err := json.MarshalIndent(Export(parsedModel), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal model to OTM: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write OTM file: %w", err)
+	}
+	return nil
+}
+
+// Export converts the parsed model including its generated risks into an OTM model.
+// Each generated risk becomes a threat (identified by its synthetic id) referenced by its most relevant element.
+func Export(parsedModel *types.ParsedModel) *Model {
+	otmModel := &Model{
+		OtmVersion: Version,
+		Project: Project{
+			Name:         parsedModel.Title,
+			Id:           types.MakeID(parsedModel.Title),
+			Description:  parsedModel.AppDescription.Description,
+			Owner:        parsedModel.Author.Name,
+			OwnerContact: parsedModel.Author.Contact,
+		},
+		Representations: []Representation{{Name: "Threagile", Id: "threagile", Type: "threat-model"}},
+		Assets:          make([]Asset, 0),
+		TrustZones:      make([]TrustZone, 0),
+		Components:      make([]Component, 0),
+		Dataflows:       make([]Dataflow, 0),
+		Threats:         make([]Threat, 0),
+	}
+
+	dataAssetIds := make([]string, 0)
+	for id := range parsedModel.DataAssets {
+		dataAssetIds = append(dataAssetIds, id)
+	}
+	sort.Strings(dataAssetIds)
+	for _, id := range dataAssetIds {
+		dataAsset := parsedModel.DataAssets[id]
+		otmModel.Assets = append(otmModel.Assets, Asset{
+			Name:        dataAsset.Title,
+			Id:          dataAsset.Id,
+			Description: dataAsset.Description,
+			Risk: AssetRisk{
+				Confidentiality: scaleValue(int(dataAsset.Confidentiality), len(types.ConfidentialityValues())),
+				Integrity:       scaleValue(int(dataAsset.Integrity), len(types.CriticalityValues())),
+				Availability:    scaleValue(int(dataAsset.Availability), len(types.CriticalityValues())),
+				Comment:         dataAsset.JustificationCiaRating,
+			},
+			Attributes: map[string]any{
+				"usage":    dataAsset.Usage.String(),
+				"quantity": dataAsset.Quantity.String(),
+				"origin":   dataAsset.Origin,
+				"owner":    dataAsset.Owner,
+			},
+		})
+	}
+
+	for _, id := range types.SortedKeysOfTrustBoundaries(parsedModel) {
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		trustZone := TrustZone{
+			Id:          trustBoundary.Id,
+			Name:        trustBoundary.Title,
+			Type:        trustBoundary.Type.String(),
+			Description: trustBoundary.Description,
+			Risk:        TrustZoneRisk{TrustRating: trustRatingOfBoundaryType[trustBoundary.Type]},
+		}
+		if parentId := trustBoundary.ParentTrustBoundaryID(parsedModel); len(parentId) > 0 {
+			trustZone.Parent = &Parent{TrustZone: parentId}
+		}
+		otmModel.TrustZones = append(otmModel.TrustZones, trustZone)
+	}
+
+	threatsByTechnicalAsset := make(map[string][]ThreatInstance)
+	threatsByCommunicationLink := make(map[string][]ThreatInstance)
+	allRisks := types.AllRisks(parsedModel)
+	sort.Slice(allRisks, func(i, j int) bool {
+		return allRisks[i].SyntheticId < allRisks[j].SyntheticId
+	})
+	for _, risk := range allRisks {
+		category := types.GetRiskCategory(parsedModel, risk.CategoryId)
+		threat := Threat{
+			Id:         risk.SyntheticId,
+			Name:       stripTags(risk.Title),
+			Categories: []string{risk.CategoryId},
+			Risk: ThreatRisk{
+				Likelihood:        scaleValue(int(risk.ExploitationLikelihood), len(types.RiskExploitationLikelihoodValues())),
+				LikelihoodComment: risk.ExploitationLikelihood.Title(),
+				Impact:            scaleValue(int(risk.ExploitationImpact), len(types.RiskExploitationImpactValues())),
+				ImpactComment:     risk.ExploitationImpact.Title(),
+			},
+			Attributes: map[string]any{
+				syntheticIdAttribute: risk.SyntheticId,
+				"severity":           risk.Severity.String(),
+			},
+		}
+		if category != nil {
+			threat.Description = category.Description
+			threat.Categories = append(threat.Categories, category.STRIDE.String())
+			if category.CWE > 0 {
+				threat.Cwes = []string{"CWE-" + strconv.Itoa(category.CWE)}
+			}
+		}
+		otmModel.Threats = append(otmModel.Threats, threat)
+
+		instance := ThreatInstance{
+			Threat: risk.SyntheticId,
+			State:  risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String(),
+		}
+		if len(risk.MostRelevantCommunicationLinkId) > 0 {
+			threatsByCommunicationLink[risk.MostRelevantCommunicationLinkId] = append(threatsByCommunicationLink[risk.MostRelevantCommunicationLinkId], instance)
+		} else if len(risk.MostRelevantTechnicalAssetId) > 0 {
+			threatsByTechnicalAsset[risk.MostRelevantTechnicalAssetId] = append(threatsByTechnicalAsset[risk.MostRelevantTechnicalAssetId], instance)
+		}
+	}
+
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		dataFormats := make([]string, 0)
+		for _, dataFormat := range technicalAsset.DataFormatsAccepted {
+			dataFormats = append(dataFormats, dataFormat.String())
+		}
+		component := Component{
+			Id:          technicalAsset.Id,
+			Name:        technicalAsset.Title,
+			Type:        technicalAsset.Technology.String(),
+			Description: technicalAsset.Description,
+			Tags:        technicalAsset.Tags,
+			Assets: &ComponentAssets{
+				Processed: technicalAsset.DataAssetsProcessed,
+				Stored:    technicalAsset.DataAssetsStored,
+			},
+			Threats: threatsByTechnicalAsset[id],
+			Attributes: map[string]any{
+				"type":                    technicalAsset.Type.String(),
+				"size":                    technicalAsset.Size.String(),
+				"machine":                 technicalAsset.Machine.String(),
+				"encryption":              technicalAsset.Encryption.String(),
+				"internet":                technicalAsset.Internet,
+				"out_of_scope":            technicalAsset.OutOfScope,
+				"usage":                   technicalAsset.Usage.String(),
+				"multi_tenant":            technicalAsset.MultiTenant,
+				"redundant":               technicalAsset.Redundant,
+				"custom_developed_parts":  technicalAsset.CustomDevelopedParts,
+				"used_as_client_by_human": technicalAsset.UsedAsClientByHuman,
+				"data_formats_accepted":   dataFormats,
+				"owner":                   technicalAsset.Owner,
+				"confidentiality":         technicalAsset.Confidentiality.String(),
+				"integrity":               technicalAsset.Integrity.String(),
+				"availability":            technicalAsset.Availability.String(),
+				"raa":                     technicalAsset.RAA,
+			},
+		}
+		if trustBoundary, ok := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[id]; ok {
+			component.Parent = &Parent{TrustZone: trustBoundary.Id}
+		}
+		otmModel.Components = append(otmModel.Components, component)
+
+		for _, link := range technicalAsset.CommunicationLinksSorted() {
+			assets := append([]string{}, link.DataAssetsSent...)
+			for _, received := range link.DataAssetsReceived {
+				if !contains(assets, received) {
+					assets = append(assets, received)
+				}
+			}
+			otmModel.Dataflows = append(otmModel.Dataflows, Dataflow{
+				Id:            link.Id,
+				Name:          link.Title,
+				Description:   link.Description,
+				Bidirectional: link.IsBidirectional(),
+				Source:        link.SourceId,
+				Destination:   link.TargetId,
+				Tags:          link.Tags,
+				Assets:        assets,
+				Threats:       threatsByCommunicationLink[link.Id],
+				Attributes: map[string]any{
+					"protocol":             link.Protocol.String(),
+					"authentication":       link.Authentication.String(),
+					"authorization":        link.Authorization.String(),
+					"usage":                link.Usage.String(),
+					"vpn":                  link.VPN,
+					"ip_filtered":          link.IpFiltered,
+					"readonly":             link.Readonly,
+					"data_assets_sent":     link.DataAssetsSent,
+					"data_assets_received": link.DataAssetsReceived,
+				},
+			})
+		}
+	}
+
+	return otmModel
+}
+
+// scaleValue maps an index of a Threagile rating scale onto an OTM value (0-100)
+func scaleValue(index int, scaleSize int) float64 {
+	if scaleSize < 2 {
+		return 0
+	}
+	return float64(index) * 100 / float64(scaleSize-1)
+}
+
+func stripTags(value string) string {
+	return regexp.MustCompile(`<[^>]*>`).ReplaceAllString(value, "")
+}
+
+func contains(a []string, x string) bool {
+	for _, n := range a {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,373 @@
+package otm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const syntheticIdAttribute = "threagile-synthetic-id"
+
+func LoadFile(filename string) (*Model, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OTM file %q: %w", filename, err)
+	}
+
+	otmModel := new(Model)
+	err = json.Unmarshal(data, otmModel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse OTM file %q: %w", filename, err)
+	}
+
+	return otmModel, nil
+}
+
+// Import converts an OTM model into a model input; threats exported by Threagile are mapped into risk tracking entries
+// by their synthetic risk id, others are reported with their risk category (if known).
+func Import(otmModel *Model, mapping importer.Mapping, riskCategoryIds []string) (*input.Model, *importer.Report) {
+	report := new(importer.Report)
+	model := importer.NewModel(otmModel.Project.Name)
+	model.AppDescription.Description = otmModel.Project.Description
+	model.Author.Name = otmModel.Project.Owner
+	model.Author.Contact = otmModel.Project.OwnerContact
+
+	takenIds := make(map[string]bool)
+	idOf := make(map[string]string)
+
+	// Data Assets ===============================================================================
+	for _, asset := range otmModel.Assets {
+		id := importer.UniqueID(withDefault(asset.Id, asset.Name), takenIds)
+		idOf[asset.Id] = id
+
+		dataAsset := importer.NewDataAsset(id, asset.Description)
+		dataAsset.Confidentiality = types.ConfidentialityValues()[scaleIndex(asset.Risk.Confidentiality, len(types.ConfidentialityValues()))].String()
+		dataAsset.Integrity = types.CriticalityValues()[scaleIndex(asset.Risk.Integrity, len(types.CriticalityValues()))].String()
+		dataAsset.Availability = types.CriticalityValues()[scaleIndex(asset.Risk.Availability, len(types.CriticalityValues()))].String()
+		dataAsset.JustificationCiaRating = asset.Risk.Comment
+		dataAsset.Usage = enumAttribute(asset.Attributes, "usage", types.UsageValues(), dataAsset.Usage)
+		dataAsset.Quantity = enumAttribute(asset.Attributes, "quantity", types.QuantityValues(), dataAsset.Quantity)
+		dataAsset.Origin = attribute(asset.Attributes, "origin")
+		dataAsset.Owner = attribute(asset.Attributes, "owner")
+		model.DataAssets[importer.UniqueKey(model.DataAssets, withDefault(asset.Name, id))] = dataAsset
+	}
+
+	// Trust Boundaries ===============================================================================
+	boundaryTitleOf := make(map[string]string)
+	for _, trustZone := range otmModel.TrustZones {
+		where := fmt.Sprintf("trust zone '%v'", trustZone.Name)
+		id := importer.UniqueID(withDefault(trustZone.Id, trustZone.Name), takenIds)
+		idOf[trustZone.Id] = id
+
+		title := importer.UniqueKey(model.TrustBoundaries, withDefault(trustZone.Name, id))
+		boundaryTitleOf[trustZone.Id] = title
+		model.TrustBoundaries[title] = input.TrustBoundary{
+			ID:          id,
+			Description: trustZone.Description,
+			Type: mapping.ResolveOrDefault(importer.MappingTrustBoundaryType, trustZone.Type,
+				importer.EnumNames(types.TrustBoundaryTypeValues()), types.NetworkOnPrem.String(), report, where),
+		}
+	}
+	for _, trustZone := range otmModel.TrustZones {
+		if trustZone.Parent == nil {
+			continue
+		}
+		if len(trustZone.Parent.Component) > 0 {
+			report.Add("trust zone '%v': trust zones inside of components are not supported", trustZone.Name)
+			continue
+		}
+		parentTitle, ok := boundaryTitleOf[trustZone.Parent.TrustZone]
+		if !ok {
+			report.Add("trust zone '%v': unknown parent trust zone %q", trustZone.Name, trustZone.Parent.TrustZone)
+			continue
+		}
+		parent := model.TrustBoundaries[parentTitle]
+		parent.TrustBoundariesNested = append(parent.TrustBoundariesNested, idOf[trustZone.Id])
+		model.TrustBoundaries[parentTitle] = parent
+	}
+
+	// Technical Assets ===============================================================================
+	assetTitleOf := make(map[string]string)
+	for _, component := range otmModel.Components {
+		where := fmt.Sprintf("component '%v'", component.Name)
+		id := importer.UniqueID(withDefault(component.Id, component.Name), takenIds)
+		idOf[component.Id] = id
+
+		technologyName := mapping.ResolveOrDefault(importer.MappingTechnology, component.Type,
+			importer.EnumNames(types.TechnicalAssetTechnologyValues()), types.UnknownTechnology.String(), report, where)
+		technology, _ := types.ParseTechnicalAssetTechnology(technologyName)
+		asset := importer.NewTechnicalAsset(id, component.Description, technology)
+		if assetType, ok := mapping.Resolve(importer.MappingTechnicalAssetType, attribute(component.Attributes, "type"), importer.EnumNames(types.TechnicalAssetTypeValues())); ok {
+			asset.Type = assetType
+		} else if assetType, ok := mapping.Resolve(importer.MappingTechnicalAssetType, component.Type, importer.EnumNames(types.TechnicalAssetTypeValues())); ok {
+			asset.Type = assetType
+		}
+		asset.Internet = attribute(component.Attributes, "internet") == "true"
+		asset.OutOfScope = attribute(component.Attributes, "out_of_scope") == "true"
+		asset.MultiTenant = attribute(component.Attributes, "multi_tenant") == "true"
+		asset.Redundant = attribute(component.Attributes, "redundant") == "true"
+		asset.CustomDevelopedParts = attribute(component.Attributes, "custom_developed_parts") == "true"
+		if usedAsClientByHuman := attribute(component.Attributes, "used_as_client_by_human"); len(usedAsClientByHuman) > 0 {
+			asset.UsedAsClientByHuman = usedAsClientByHuman == "true"
+		}
+		asset.Owner = attribute(component.Attributes, "owner")
+		for _, format := range listAttribute(component.Attributes, "data_formats_accepted") {
+			if dataFormat, ok := mapping.Resolve(importer.MappingDataFormat, format, importer.EnumNames(types.DataFormatValues())); ok {
+				asset.DataFormatsAccepted = append(asset.DataFormatsAccepted, dataFormat)
+			} else {
+				report.Add("%v: unable to map data format %q", where, format)
+			}
+		}
+		asset.Usage = enumAttribute(component.Attributes, "usage", types.UsageValues(), asset.Usage)
+		asset.Size = enumAttribute(component.Attributes, "size", types.TechnicalAssetSizeValues(), asset.Size)
+		asset.Machine = enumAttribute(component.Attributes, "machine", types.TechnicalAssetMachineValues(), asset.Machine)
+		asset.Encryption = enumAttribute(component.Attributes, "encryption", types.EncryptionStyleValues(), asset.Encryption)
+		asset.Confidentiality = enumAttribute(component.Attributes, "confidentiality", types.ConfidentialityValues(), asset.Confidentiality)
+		asset.Integrity = enumAttribute(component.Attributes, "integrity", types.CriticalityValues(), asset.Integrity)
+		asset.Availability = enumAttribute(component.Attributes, "availability", types.CriticalityValues(), asset.Availability)
+
+		for _, tag := range component.Tags {
+			tag = input.NormalizeTag(tag)
+			asset.Tags = append(asset.Tags, tag)
+			model.AddTagToModelInput(tag, false, new([]string))
+		}
+
+		if component.Assets != nil {
+			asset.DataAssetsProcessed = dataAssetIds(component.Assets.Processed, idOf, report, where)
+			asset.DataAssetsStored = dataAssetIds(component.Assets.Stored, idOf, report, where)
+		}
+
+		title := importer.UniqueKey(model.TechnicalAssets, withDefault(component.Name, id))
+		assetTitleOf[component.Id] = title
+		model.TechnicalAssets[title] = asset
+	}
+
+	// place components into trust boundaries and shared runtimes (when nested inside other components)
+	for _, component := range otmModel.Components {
+		if component.Parent == nil {
+			continue
+		}
+		if len(component.Parent.TrustZone) > 0 {
+			boundaryTitle, ok := boundaryTitleOf[component.Parent.TrustZone]
+			if !ok {
+				report.Add("component '%v': unknown parent trust zone %q", component.Name, component.Parent.TrustZone)
+				continue
+			}
+			boundary := model.TrustBoundaries[boundaryTitle]
+			boundary.TechnicalAssetsInside = append(boundary.TechnicalAssetsInside, idOf[component.Id])
+			model.TrustBoundaries[boundaryTitle] = boundary
+		} else if len(component.Parent.Component) > 0 {
+			parentTitle, ok := assetTitleOf[component.Parent.Component]
+			if !ok {
+				report.Add("component '%v': unknown parent component %q", component.Name, component.Parent.Component)
+				continue
+			}
+			runtime, exists := model.SharedRuntimes[parentTitle]
+			if !exists {
+				runtime = input.SharedRuntime{
+					ID:          idOf[component.Parent.Component] + "-runtime",
+					Description: "Components running inside of " + parentTitle,
+				}
+			}
+			runtime.TechnicalAssetsRunning = append(runtime.TechnicalAssetsRunning, idOf[component.Id])
+			model.SharedRuntimes[parentTitle] = runtime
+		}
+	}
+
+	// Communication Links ===============================================================================
+	for _, dataflow := range otmModel.Dataflows {
+		where := fmt.Sprintf("dataflow '%v'", dataflow.Name)
+		sourceTitle, sourceOk := assetTitleOf[dataflow.Source]
+		_, targetOk := assetTitleOf[dataflow.Destination]
+		if !sourceOk || !targetOk {
+			report.Add("%v: source and destination must both be components (%q -> %q)", where, dataflow.Source, dataflow.Destination)
+			continue
+		}
+
+		protocolName := attribute(dataflow.Attributes, "protocol")
+		if len(protocolName) == 0 {
+			report.Add("%v: no protocol given", where)
+		}
+		protocol, _ := types.ParseProtocol(mapping.ResolveOrDefault(importer.MappingProtocol, protocolName,
+			importer.EnumNames(types.ProtocolValues()), types.UnknownProtocol.String(), report, where))
+		link := importer.NewCommunicationLink(idOf[dataflow.Destination], dataflow.Description, protocol)
+		link.Authentication = mapping.ResolveOrDefault(importer.MappingAuthentication, attribute(dataflow.Attributes, "authentication"),
+			importer.EnumNames(types.AuthenticationValues()), types.NoneAuthentication.String(), report, where)
+		if authorization, ok := mapping.Resolve(importer.MappingAuthorization, attribute(dataflow.Attributes, "authorization"), importer.EnumNames(types.AuthorizationValues())); ok {
+			link.Authorization = authorization
+		}
+		link.VPN = attribute(dataflow.Attributes, "vpn") == "true"
+		link.IpFiltered = attribute(dataflow.Attributes, "ip_filtered") == "true"
+		link.Readonly = attribute(dataflow.Attributes, "readonly") == "true"
+		link.Usage = enumAttribute(dataflow.Attributes, "usage", types.UsageValues(), link.Usage)
+		_, sentOk := dataflow.Attributes["data_assets_sent"]
+		_, receivedOk := dataflow.Attributes["data_assets_received"]
+		if sentOk || receivedOk {
+			link.DataAssetsSent = dataAssetIds(listAttribute(dataflow.Attributes, "data_assets_sent"), idOf, report, where)
+			link.DataAssetsReceived = dataAssetIds(listAttribute(dataflow.Attributes, "data_assets_received"), idOf, report, where)
+		} else {
+			link.DataAssetsSent = dataAssetIds(dataflow.Assets, idOf, report, where)
+			if dataflow.Bidirectional {
+				link.DataAssetsReceived = link.DataAssetsSent
+			}
+		}
+		for _, tag := range dataflow.Tags {
+			tag = input.NormalizeTag(tag)
+			link.Tags = append(link.Tags, tag)
+			model.AddTagToModelInput(tag, false, new([]string))
+		}
+
+		source := model.TechnicalAssets[sourceTitle]
+		linkTitle := importer.UniqueKey(source.CommunicationLinks, withDefault(dataflow.Name, dataflow.Id))
+		source.CommunicationLinks[linkTitle] = link
+		model.TechnicalAssets[sourceTitle] = source
+	}
+
+	// Risk Tracking ===============================================================================
+	threats := make(map[string]Threat)
+	for _, threat := range otmModel.Threats {
+		threats[threat.Id] = threat
+	}
+	referencedThreats := make(map[string]bool)
+	for _, component := range otmModel.Components {
+		for _, instance := range component.Threats {
+			referencedThreats[instance.Threat] = true
+			importThreat(model, threats, instance, fmt.Sprintf("component '%v'", component.Name), mapping, riskCategoryIds, report)
+		}
+	}
+	for _, dataflow := range otmModel.Dataflows {
+		for _, instance := range dataflow.Threats {
+			referencedThreats[instance.Threat] = true
+			importThreat(model, threats, instance, fmt.Sprintf("dataflow '%v'", dataflow.Name), mapping, riskCategoryIds, report)
+		}
+	}
+	for _, threat := range otmModel.Threats {
+		if !referencedThreats[threat.Id] {
+			report.Add("threat '%v': not referenced by any component or dataflow", threat.Name)
+		}
+	}
+
+	sort.Strings(model.TagsAvailable)
+	return model, report
+}
+
+func importThreat(model *input.Model, threats map[string]Threat, instance ThreatInstance, where string,
+	mapping importer.Mapping, riskCategoryIds []string, report *importer.Report) {
+	threat, ok := threats[instance.Threat]
+	if !ok {
+		report.Add("%v: unknown threat %q", where, instance.Threat)
+		return
+	}
+	where = fmt.Sprintf("threat '%v' of %v", threat.Name, where)
+
+	status, ok := riskStatusOf(instance, mapping)
+	if !ok {
+		report.Add("%v: unable to map state %q (using %q)", where, instance.State, status.String())
+	}
+
+	syntheticId := attribute(threat.Attributes, syntheticIdAttribute)
+	if len(syntheticId) == 0 {
+		var categoryId string
+		for _, candidate := range append([]string{threat.Id, threat.Name}, threat.Categories...) {
+			if resolved, ok := mapping.Resolve(importer.MappingRiskCategory, candidate, riskCategoryIds); ok {
+				categoryId = resolved
+				break
+			}
+		}
+		if len(categoryId) == 0 {
+			report.Add("%v: no matching risk category (add it to the mapping file to fix this)", where)
+		} else if status != types.Unchecked {
+			report.AddThreat(where, categoryId, status)
+		}
+		return
+	}
+	if status == types.Unchecked {
+		return
+	}
+
+	model.RiskTracking[syntheticId] = input.RiskTracking{
+		Status:        status.String(),
+		Justification: "imported from OTM threat '" + threat.Name + "'",
+	}
+}
+
+func riskStatusOf(instance ThreatInstance, mapping importer.Mapping) (types.RiskStatus, bool) {
+	for _, mitigation := range instance.Mitigations {
+		if strings.EqualFold(mitigation.State, "implemented") {
+			return types.Mitigated, true
+		}
+	}
+
+	return mapping.ResolveRiskStatus(instance.State)
+}
+
+func dataAssetIds(otmIds []string, idOf map[string]string, report *importer.Report, where string) []string {
+	ids := make([]string, 0)
+	for _, otmId := range otmIds {
+		id, ok := idOf[otmId]
+		if !ok {
+			report.Add("%v: unknown asset %q", where, otmId)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// scaleIndex maps an OTM value (0-100) onto an index of a Threagile rating scale
+func scaleIndex(value float64, scaleSize int) int {
+	index := int(value * float64(scaleSize) / 100.1)
+	if index < 0 {
+		return 0
+	}
+	if index >= scaleSize {
+		return scaleSize - 1
+	}
+	return index
+}
+
+func attribute(attributes map[string]any, name string) string {
+	value, ok := attributes[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func listAttribute(attributes map[string]any, name string) []string {
+	values := make([]string, 0)
+	switch value := attributes[name].(type) {
+	case []any:
+		for _, item := range value {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+	case []string:
+		values = append(values, value...)
+	}
+	return values
+}
+
+// enumAttribute returns the attribute if it is a valid value of the enum, otherwise the current value
+func enumAttribute(attributes map[string]any, name string, values []types.TypeEnum, current string) string {
+	value := attribute(attributes, name)
+	for _, valid := range importer.EnumNames(values) {
+		if strings.EqualFold(value, valid) {
+			return valid
+		}
+	}
+	return current
+}
+
+func withDefault(value string, defaultWhenEmpty string) string {
+	if len(strings.TrimSpace(value)) > 0 {
+		return strings.TrimSpace(value)
+	}
+	return defaultWhenEmpty
+}
@@ -0,0 +1,130 @@
+package otm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestImport(t *testing.T) {
+	otmModel, err := LoadFile("testdata/model.otm.json")
+	require.NoError(t, err)
+
+	mapping := importer.Mapping{importer.MappingTrustBoundaryType: {"Private Secured": types.NetworkCloudSecurityGroup.String()}}
+	model, report := Import(otmModel, mapping, []string{"sql-nosql-injection", "unencrypted-communication"})
+
+	assert.Equal(t, "Webshop", model.Title)
+	assert.Equal(t, "Jane Doe", model.Author.Name)
+
+	require.Contains(t, model.DataAssets, "Customer Data")
+	dataAsset := model.DataAssets["Customer Data"]
+	assert.Equal(t, types.Confidential.String(), dataAsset.Confidentiality)
+	assert.Equal(t, types.Important.String(), dataAsset.Integrity)
+	assert.Equal(t, types.Archive.String(), dataAsset.Availability)
+
+	require.Contains(t, model.TrustBoundaries, "DMZ")
+	require.Contains(t, model.TrustBoundaries, "Backend")
+	assert.Equal(t, types.NetworkCloudSecurityGroup.String(), model.TrustBoundaries["Backend"].Type)
+	assert.Equal(t, []string{"backend"}, model.TrustBoundaries["DMZ"].TrustBoundariesNested)
+	assert.Equal(t, []string{"web"}, model.TrustBoundaries["DMZ"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"db"}, model.TrustBoundaries["Backend"].TechnicalAssetsInside)
+
+	require.Contains(t, model.TechnicalAssets, "Web Server")
+	require.Contains(t, model.TechnicalAssets, "Database")
+	web, database := model.TechnicalAssets["Web Server"], model.TechnicalAssets["Database"]
+	assert.True(t, web.Internet)
+	assert.Equal(t, types.WebServer.String(), web.Technology)
+	assert.Equal(t, []string{"customer-data"}, web.DataAssetsProcessed)
+	assert.Equal(t, types.Database.String(), database.Technology)
+	assert.Equal(t, types.Datastore.String(), database.Type)
+	assert.Equal(t, []string{"customer-data"}, database.DataAssetsStored)
+
+	require.Contains(t, web.CommunicationLinks, "Database Access")
+	link := web.CommunicationLinks["Database Access"]
+	assert.Equal(t, "db", link.Target)
+	assert.Equal(t, types.JDBC.String(), link.Protocol)
+	assert.Equal(t, types.Credentials.String(), link.Authentication)
+	assert.Equal(t, []string{"customer-data"}, link.DataAssetsSent)
+	assert.Equal(t, []string{"customer-data"}, link.DataAssetsReceived)
+
+	// only threats exported by threagile know their synthetic risk id
+	require.Len(t, model.RiskTracking, 1)
+	assert.Equal(t, types.Mitigated.String(), model.RiskTracking["sql-nosql-injection@db@web@web>database-access"].Status)
+
+	assertReported(t, report, "component 'Database': unknown asset \"unknown-data\"")
+	assertReported(t, report, "threat 'SQL Injection' of component 'Database': risk category \"sql-nosql-injection\" with status \"in-progress\"")
+	assertReported(t, report, "threat 'Spoofing' of dataflow 'Database Access': no matching risk category")
+	assertReported(t, report, "threat 'Unused': not referenced by any component or dataflow")
+	for _, item := range report.Items {
+		assert.NotContains(t, item, "Sniffing", "unchecked threats need no risk tracking")
+	}
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.otm.json")
+	assert.Error(t, err)
+}
+
+type scaleIndexTest struct {
+	value    float64
+	expected int
+}
+
+func TestScaleIndex(t *testing.T) {
+	testCases := map[string]scaleIndexTest{
+		"lowest":  {value: 0, expected: 0},
+		"middle":  {value: 50, expected: 2},
+		"highest": {value: 100, expected: 4},
+		"below":   {value: -10, expected: 0},
+		"above":   {value: 200, expected: 4},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, scaleIndex(testCase.value, 5))
+		})
+	}
+}
+
+func assertReported(t *testing.T, report *importer.Report, prefix string) {
+	t.Helper()
+	for _, item := range report.Items {
+		if strings.HasPrefix(item, prefix) {
+			return
+		}
+	}
+	t.Errorf("report misses %q in %q", prefix, report.Items)
+}
+
+func TestExportImportKeepsRiskTracking(t *testing.T) {
+	link := types.CommunicationLink{Id: "web>database-access", Title: "Database Access", SourceId: "web", TargetId: "db", Protocol: types.JDBC}
+	risk := types.Risk{
+		CategoryId:                      "sql-nosql-injection",
+		SyntheticId:                     "sql-nosql-injection@db@web@web>database-access",
+		Title:                           "<b>SQL/NoSQL-Injection</b> risk",
+		MostRelevantCommunicationLinkId: link.Id,
+	}
+	parsedModel := &types.ParsedModel{
+		Title: "Webshop",
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web Server", Technology: types.WebServer, CommunicationLinks: []types.CommunicationLink{link}},
+			"db":  {Id: "db", Title: "Database", Technology: types.Database, Type: types.Datastore},
+		},
+		GeneratedRisksByCategory: map[string][]types.Risk{risk.CategoryId: {risk}},
+		RiskTracking:             map[string]types.RiskTracking{risk.SyntheticId: {SyntheticRiskId: risk.SyntheticId, Status: types.Accepted}},
+	}
+
+	otmModel := Export(parsedModel)
+	require.Len(t, otmModel.Threats, 1)
+	assert.Equal(t, "SQL/NoSQL-Injection risk", otmModel.Threats[0].Name)
+	require.Len(t, otmModel.Dataflows, 1)
+	assert.Equal(t, []ThreatInstance{{Threat: risk.SyntheticId, State: types.Accepted.String()}}, otmModel.Dataflows[0].Threats)
+
+	model, _ := Import(otmModel, importer.Mapping{}, []string{risk.CategoryId})
+	assert.Equal(t, types.Accepted.String(), model.RiskTracking[risk.SyntheticId].Status)
+}
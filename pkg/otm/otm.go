@@ -0,0 +1,139 @@
+package otm
+
+// Open Threat Model (OTM) format, see https://github.com/iriusrisk/OpenThreatModel
+
+const Version = "0.2.0"
+
+type Model struct {
+	OtmVersion      string           `json:"otmVersion"`
+	Project         Project          `json:"project"`
+	Representations []Representation `json:"representations,omitempty"`
+	Assets          []Asset          `json:"assets,omitempty"`
+	TrustZones      []TrustZone      `json:"trustZones,omitempty"`
+	Components      []Component      `json:"components,omitempty"`
+	Dataflows       []Dataflow       `json:"dataflows,omitempty"`
+	Threats         []Threat         `json:"threats,omitempty"`
+	Mitigations     []Mitigation     `json:"mitigations,omitempty"`
+}
+
+type Project struct {
+	Name         string         `json:"name"`
+	Id           string         `json:"id"`
+	Description  string         `json:"description,omitempty"`
+	Owner        string         `json:"owner,omitempty"`
+	OwnerContact string         `json:"ownerContact,omitempty"`
+	Tags         []string       `json:"tags,omitempty"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+}
+
+type Representation struct {
+	Name        string         `json:"name"`
+	Id          string         `json:"id"`
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+type Asset struct {
+	Name        string         `json:"name"`
+	Id          string         `json:"id"`
+	Description string         `json:"description,omitempty"`
+	Risk        AssetRisk      `json:"risk"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// AssetRisk values range from 0 (lowest) to 100 (highest)
+type AssetRisk struct {
+	Confidentiality float64 `json:"confidentiality"`
+	Integrity       float64 `json:"integrity"`
+	Availability    float64 `json:"availability"`
+	Comment         string  `json:"comment,omitempty"`
+}
+
+type TrustZone struct {
+	Id          string         `json:"id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Risk        TrustZoneRisk  `json:"risk"`
+	Parent      *Parent        `json:"parent,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// TrustZoneRisk trust rating ranges from 0 (untrusted) to 100 (fully trusted)
+type TrustZoneRisk struct {
+	TrustRating float64 `json:"trustRating"`
+}
+
+type Parent struct {
+	TrustZone string `json:"trustZone,omitempty"`
+	Component string `json:"component,omitempty"`
+}
+
+type Component struct {
+	Id          string           `json:"id"`
+	Name        string           `json:"name"`
+	Type        string           `json:"type"`
+	Description string           `json:"description,omitempty"`
+	Parent      *Parent          `json:"parent,omitempty"`
+	Tags        []string         `json:"tags,omitempty"`
+	Assets      *ComponentAssets `json:"assets,omitempty"`
+	Threats     []ThreatInstance `json:"threats,omitempty"`
+	Attributes  map[string]any   `json:"attributes,omitempty"`
+}
+
+type ComponentAssets struct {
+	Processed []string `json:"processed,omitempty"`
+	Stored    []string `json:"stored,omitempty"`
+}
+
+type Dataflow struct {
+	Id            string           `json:"id"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	Bidirectional bool             `json:"bidirectional,omitempty"`
+	Source        string           `json:"source"`
+	Destination   string           `json:"destination"`
+	Tags          []string         `json:"tags,omitempty"`
+	Assets        []string         `json:"assets,omitempty"`
+	Threats       []ThreatInstance `json:"threats,omitempty"`
+	Attributes    map[string]any   `json:"attributes,omitempty"`
+}
+
+type ThreatInstance struct {
+	Threat      string               `json:"threat"`
+	State       string               `json:"state"`
+	Mitigations []MitigationInstance `json:"mitigations,omitempty"`
+}
+
+type MitigationInstance struct {
+	Mitigation string `json:"mitigation"`
+	State      string `json:"state"`
+}
+
+type Threat struct {
+	Id          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Categories  []string       `json:"categories,omitempty"`
+	Cwes        []string       `json:"cwes,omitempty"`
+	Risk        ThreatRisk     `json:"risk"`
+	Tags        []string       `json:"tags,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+}
+
+// ThreatRisk values range from 0 (lowest) to 100 (highest)
+type ThreatRisk struct {
+	Likelihood        float64 `json:"likelihood"`
+	LikelihoodComment string  `json:"likelihoodComment,omitempty"`
+	Impact            float64 `json:"impact"`
+	ImpactComment     string  `json:"impactComment,omitempty"`
+}
+
+type Mitigation struct {
+	Id            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	RiskReduction float64        `json:"riskReduction"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+}
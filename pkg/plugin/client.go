@@ -0,0 +1,436 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type progressReporter interface {
+	Info(a ...any)
+	Warn(a ...any)
+}
+
+// Client is the host side of a running plugin, calls are serialized
+type Client struct {
+	Filename string
+	Timeout  time.Duration // per call, 0 for none
+	Plugin   InitializeResult
+	Info     InfoResult
+
+	command   *exec.Cmd
+	stdin     io.WriteCloser
+	exited    chan struct{}
+	exitError error
+	nextId    int64
+	calls     sync.Mutex
+	model     *types.ParsedModel       // last one sent to the plugin, nil when it changed since
+	pending   map[int64]chan *Response // of the call waiting for its response
+	killed    bool
+	mutex     sync.Mutex // of model, pending and killed
+}
+
+// Start runs the plugin, negotiates the protocol version and capabilities and gets the info of what it provides
+func Start(filename string, timeout time.Duration, reporter progressReporter) (*Client, error) {
+	client := &Client{
+		Filename: filename,
+		Timeout:  timeout,
+		exited:   make(chan struct{}),
+		pending:  make(map[int64]chan *Response),
+	}
+
+	client.command = exec.Command(filename) // #nosec G204
+	var pipeError error
+	client.stdin, pipeError = client.command.StdinPipe()
+	if pipeError != nil {
+		return nil, pipeError
+	}
+	stdout, pipeError := client.command.StdoutPipe()
+	if pipeError != nil {
+		return nil, pipeError
+	}
+	stderr, pipeError := client.command.StderrPipe()
+	if pipeError != nil {
+		return nil, pipeError
+	}
+
+	startError := client.command.Start()
+	if startError != nil {
+		return nil, startError
+	}
+
+	name := filepath.Base(filename) + ":"
+	go forwardLog(stderr, name, reporter)
+	go client.readResponses(stdout, name, reporter)
+
+	initializeError := client.call(InitializeMethod, InitializeParams{
+		ProtocolVersions: []int{ProtocolVersion},
+		Capabilities:     []string{RiskRulesCapability, RAACapability, MacrosCapability},
+	}, &client.Plugin)
+	if initializeError == nil && client.Plugin.ProtocolVersion != ProtocolVersion {
+		initializeError = fmt.Errorf("unsupported protocol version %d", client.Plugin.ProtocolVersion)
+	}
+	if initializeError != nil {
+		client.kill()
+		return nil, fmt.Errorf("handshake failed: %w", initializeError)
+	}
+
+	infoError := client.call(InfoMethod, nil, &client.Info)
+	if infoError != nil {
+		_ = client.Shutdown()
+		return nil, fmt.Errorf("failed to get info: %w", infoError)
+	}
+
+	return client, nil
+}
+
+func (c *Client) HasCapability(capability string) bool {
+	for _, candidate := range c.Plugin.Capabilities {
+		if candidate == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateRisks runs the risk rule of the plugin on the model (sending the model only when it was not sent before)
+func (c *Client) GenerateRisks(parsedModel *types.ParsedModel, riskRuleId string) ([]types.Risk, error) {
+	setModelError := c.setModel(parsedModel, false)
+	if setModelError != nil {
+		return nil, setModelError
+	}
+
+	generatedRisks := make([]types.Risk, 0)
+	callError := c.call(GenerateRisksMethod, GenerateRisksParams{RiskRuleId: riskRuleId}, &generatedRisks)
+	return generatedRisks, callError
+}
+
+// CalculateRAA sets the RAA of the technical assets calculated by the plugin and returns its intro text
+func (c *Client) CalculateRAA(parsedModel *types.ParsedModel) (string, error) {
+	setModelError := c.setModel(parsedModel, false)
+	if setModelError != nil {
+		return "", setModelError
+	}
+
+	var result CalculateRAAResult
+	callError := c.call(CalculateRAAMethod, nil, &result)
+	if callError != nil {
+		return "", callError
+	}
+
+	for id, raa := range result.RAA {
+		technicalAsset, ok := parsedModel.TechnicalAssets[id]
+		if !ok {
+			return "", fmt.Errorf("RAA calculated for unknown technical asset %q", id)
+		}
+		technicalAsset.RAA = raa
+		parsedModel.TechnicalAssets[id] = technicalAsset
+	}
+
+	c.mutex.Lock()
+	c.model = nil // changed by the RAA, so it is sent again
+	c.mutex.Unlock()
+	return result.IntroText, nil
+}
+
+// RiskRules returns the risk rules of the plugin, running them in the plugin
+func (c *Client) RiskRules() []*RiskRule {
+	rules := make([]*RiskRule, 0)
+	for _, info := range c.Info.RiskRules {
+		rules = append(rules, &RiskRule{client: c, info: info})
+	}
+	return rules
+}
+
+// Macros returns the model macros of the plugin, running them in the plugin
+func (c *Client) Macros() []macros.Macros {
+	pluginMacros := make([]macros.Macros, 0)
+	for _, details := range c.Info.Macros {
+		pluginMacros = append(pluginMacros, &macro{client: c, details: details})
+	}
+	return pluginMacros
+}
+
+// Shutdown asks the plugin to exit and kills it if it does not in time
+func (c *Client) Shutdown() error {
+	c.mutex.Lock()
+	killed := c.killed
+	c.mutex.Unlock()
+	if killed {
+		select {
+		case <-c.exited:
+		case <-time.After(shutdownTimeout):
+		}
+		return nil // after a timeout
+	}
+	select {
+	case <-c.exited:
+		return nil // crashed
+	default:
+	}
+
+	shutdownError := c.callWithTimeout(ShutdownMethod, nil, nil, shutdownTimeout)
+	_ = c.stdin.Close()
+	select {
+	case <-c.exited:
+	case <-time.After(shutdownTimeout):
+		c.kill()
+		return fmt.Errorf("plugin %q did not exit after shutdown", c.Filename)
+	}
+	return shutdownError
+}
+
+// setModel sends the model unless it was sent before, forced when it may have changed since (e.g. risks generated)
+func (c *Client) setModel(parsedModel *types.ParsedModel, force bool) error {
+	c.mutex.Lock()
+	sent := c.model == parsedModel
+	c.mutex.Unlock()
+	if sent && !force {
+		return nil
+	}
+
+	setModelError := c.call(SetModelMethod, SetModelParams{Model: parsedModel}, nil)
+	if setModelError != nil {
+		return setModelError
+	}
+
+	c.mutex.Lock()
+	c.model = parsedModel
+	c.mutex.Unlock()
+	return nil
+}
+
+func (c *Client) call(method string, params any, result any) error {
+	return c.callWithTimeout(method, params, result, c.Timeout)
+}
+
+func (c *Client) callWithTimeout(method string, params any, result any, timeout time.Duration) error {
+	c.calls.Lock()
+	defer c.calls.Unlock()
+
+	c.nextId++
+	request := Request{JSONRPC: jsonRPCVersion, ID: c.nextId, Method: method}
+	if params != nil {
+		data, marshalError := json.Marshal(params)
+		if marshalError != nil {
+			return fmt.Errorf("failed to marshal %v params: %w", method, marshalError)
+		}
+		request.Params = data
+	}
+	data, marshalError := json.Marshal(request)
+	if marshalError != nil {
+		return fmt.Errorf("failed to marshal %v request: %w", method, marshalError)
+	}
+
+	responses := make(chan *Response, 1)
+	c.mutex.Lock()
+	c.pending[request.ID] = responses
+	c.mutex.Unlock()
+	defer func() {
+		c.mutex.Lock()
+		delete(c.pending, request.ID)
+		c.mutex.Unlock()
+	}()
+
+	written := make(chan error, 1)
+	go func() {
+		_, writeError := c.stdin.Write(append(data, '\n'))
+		written <- writeError
+	}()
+
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+	for {
+		select {
+		case writeError := <-written:
+			if writeError != nil {
+				return fmt.Errorf("failed to send %v request: %w", method, writeError)
+			}
+			written = nil
+
+		case response := <-responses:
+			if response.Error != nil {
+				return fmt.Errorf("%v failed: %w", method, response.Error)
+			}
+			if result == nil || len(response.Result) == 0 {
+				return nil
+			}
+			unmarshalError := json.Unmarshal(response.Result, result)
+			if unmarshalError != nil {
+				return fmt.Errorf("invalid %v result: %w", method, unmarshalError)
+			}
+			return nil
+
+		case <-c.exited:
+			if c.exitError != nil {
+				return fmt.Errorf("plugin exited during %v: %w", method, c.exitError)
+			}
+			return fmt.Errorf("plugin exited during %v", method)
+
+		case <-timedOut:
+			c.kill()
+			return fmt.Errorf("%v timed out after %v", method, timeout)
+		}
+	}
+}
+
+func (c *Client) readResponses(stdout io.Reader, name string, reporter progressReporter) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, readError := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			response := new(Response)
+			unmarshalError := json.Unmarshal(line, response)
+			if unmarshalError != nil {
+				reporter.Warn(name, "ignoring invalid response:", unmarshalError)
+			} else {
+				c.deliver(response)
+			}
+		}
+		if readError != nil {
+			break
+		}
+	}
+
+	c.exitError = c.command.Wait()
+	close(c.exited)
+}
+
+// deliver passes the response to the call waiting for it, responses of calls timed out before are dropped
+func (c *Client) deliver(response *Response) {
+	c.mutex.Lock()
+	responses, ok := c.pending[response.ID]
+	delete(c.pending, response.ID)
+	c.mutex.Unlock()
+	if ok {
+		responses <- response // buffered for the one response
+	}
+}
+
+func (c *Client) kill() {
+	c.mutex.Lock()
+	c.killed = true
+	c.mutex.Unlock()
+	if c.command.Process != nil {
+		_ = c.command.Process.Kill()
+	}
+}
+
+func forwardLog(stderr io.Reader, name string, reporter progressReporter) {
+	scanner := bufio.NewScanner(stderr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, errorPrefix) || strings.HasPrefix(line, warningPrefix) {
+			reporter.Warn(name, line) // errors of a plugin are not fatal for threagile
+		} else {
+			reporter.Info(name, strings.TrimPrefix(line, infoPrefix))
+		}
+	}
+}
+
+// RiskRule is a risk rule of a plugin
+type RiskRule struct {
+	client *Client
+	info   RiskRuleInfo
+}
+
+func (r *RiskRule) Category() types.RiskCategory {
+	return r.info.Category
+}
+
+func (r *RiskRule) SupportedTags() []string {
+	return r.info.SupportedTags
+}
+
+func (r *RiskRule) GenerateRisks(parsedModel *types.ParsedModel) []types.Risk {
+	generatedRisks, err := r.client.GenerateRisks(parsedModel, r.info.Category.Id)
+	if err != nil {
+		log.Fatalf("Failed to generate risks for custom risk rule %q of plugin %q: %v\n", r.info.Category.Id, r.client.Filename, err)
+	}
+	return generatedRisks
+}
+
+func (r *RiskRule) TryGenerateRisks(parsedModel *types.ParsedModel) ([]types.Risk, error) {
+	return r.client.GenerateRisks(parsedModel, r.info.Category.Id)
+}
+
+type macro struct {
+	client  *Client
+	details macros.MacroDetails
+}
+
+func (m *macro) GetMacroDetails() macros.MacroDetails {
+	return m.details
+}
+
+func (m *macro) GetNextQuestion(parsedModel *types.ParsedModel) (nextQuestion macros.MacroQuestion, err error) {
+	err = m.client.setModel(parsedModel, true)
+	if err != nil {
+		return nextQuestion, err
+	}
+
+	var result MacroResult
+	err = m.client.call(MacroNextQuestionMethod, MacroParams{MacroId: m.details.ID}, &result)
+	if err != nil {
+		return nextQuestion, err
+	}
+	if result.Question == nil {
+		return macros.NoMoreQuestions(), nil
+	}
+	return *result.Question, nil
+}
+
+func (m *macro) ApplyAnswer(questionID string, answer ...string) (message string, validResult bool, err error) {
+	var result MacroResult
+	err = m.client.call(MacroApplyAnswerMethod, MacroParams{MacroId: m.details.ID, QuestionId: questionID, Answers: answer}, &result)
+	return result.Message, result.Valid, err
+}
+
+func (m *macro) GoBack() (message string, validResult bool, err error) {
+	var result MacroResult
+	err = m.client.call(MacroGoBackMethod, MacroParams{MacroId: m.details.ID}, &result)
+	return result.Message, result.Valid, err
+}
+
+func (m *macro) GetFinalChangeImpact(modelInput *input.Model, parsedModel *types.ParsedModel) (changes []string, message string, validResult bool, err error) {
+	err = m.client.setModel(parsedModel, true)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	var result MacroResult
+	err = m.client.call(MacroFinalChangeImpactMethod, MacroParams{MacroId: m.details.ID, ModelInput: modelInput}, &result)
+	return result.Changes, result.Message, result.Valid, err
+}
+
+func (m *macro) Execute(modelInput *input.Model, parsedModel *types.ParsedModel) (message string, validResult bool, err error) {
+	err = m.client.setModel(parsedModel, true)
+	if err != nil {
+		return "", false, err
+	}
+
+	var result MacroResult
+	err = m.client.call(MacroExecuteMethod, MacroParams{MacroId: m.details.ID, ModelInput: modelInput}, &result)
+	if err == nil && result.ModelInput != nil {
+		*modelInput = *result.ModelInput
+	}
+	return result.Message, result.Valid, err
+}
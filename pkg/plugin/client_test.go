@@ -0,0 +1,270 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// fakePluginEnv makes the test binary act as a plugin (see TestMain) with the protocol version given as value
+const fakePluginEnv = "THREAGILE_FAKE_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if version := os.Getenv(fakePluginEnv); len(version) > 0 {
+		fakePlugin(version)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakePlugin answers the calls of the host with canned results, its risk rules "hang", "crash" or answer an unknown call first
+func fakePlugin(version string) {
+	var model *types.ParsedModel
+	encoder := json.NewEncoder(os.Stdout)
+	reply := func(id int64, result any) {
+		data, _ := json.Marshal(result)
+		_ = encoder.Encode(Response{JSONRPC: jsonRPCVersion, ID: id, Result: data})
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var request Request
+		if json.Unmarshal(scanner.Bytes(), &request) != nil {
+			continue
+		}
+		switch request.Method {
+		case InitializeMethod:
+			var protocolVersion int
+			_, _ = fmt.Sscan(version, &protocolVersion)
+			reply(request.ID, InitializeResult{ProtocolVersion: protocolVersion, Name: "fake", Version: "1.0",
+				Capabilities: []string{RiskRulesCapability, RAACapability, MacrosCapability}})
+
+		case InfoMethod:
+			reply(request.ID, InfoResult{
+				RiskRules: []RiskRuleInfo{{Category: types.RiskCategory{Id: "raa"}, SupportedTags: []string{"fake"}}, {Category: types.RiskCategory{Id: "hang"}}},
+				Macros:    []macros.MacroDetails{{ID: "rename", Title: "Rename"}},
+			})
+
+		case SetModelMethod:
+			var params SetModelParams
+			_ = json.Unmarshal(request.Params, &params)
+			model = params.Model
+			reply(request.ID, nil)
+
+		case CalculateRAAMethod:
+			reply(request.ID, CalculateRAAResult{RAA: map[string]float64{"web": 42}, IntroText: "fake RAA"}) // not applied to its own model
+
+		case GenerateRisksMethod:
+			var params GenerateRisksParams
+			_ = json.Unmarshal(request.Params, &params)
+			switch params.RiskRuleId {
+			case "hang":
+				time.Sleep(time.Hour)
+			case "crash":
+				_, _ = fmt.Fprintln(os.Stderr, "ERROR: crashed")
+				os.Exit(3)
+			case "unknown-call-first":
+				reply(request.ID+1000, []types.Risk{})
+			}
+			reply(request.ID, []types.Risk{{CategoryId: params.RiskRuleId, Title: fmt.Sprint(model.TechnicalAssets["web"].RAA)}})
+
+		case MacroExecuteMethod:
+			var params MacroParams
+			_ = json.Unmarshal(request.Params, &params)
+			params.ModelInput.Title = "renamed"
+			reply(request.ID, MacroResult{Message: "renamed", Valid: true, ModelInput: params.ModelInput})
+
+		case ShutdownMethod:
+			reply(request.ID, nil)
+			return
+
+		default:
+			_ = encoder.Encode(Response{JSONRPC: jsonRPCVersion, ID: request.ID, Error: &Error{Code: MethodNotFoundCode, Message: "unknown method"}})
+		}
+	}
+}
+
+type recordingReporter struct {
+	mutex    sync.Mutex
+	warnings []string
+}
+
+func (r *recordingReporter) Info(...any) {
+}
+
+func (r *recordingReporter) Warn(a ...any) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.warnings = append(r.warnings, fmt.Sprint(a...))
+}
+
+func startFakePlugin(t *testing.T, version string) (*Client, error) {
+	t.Setenv(fakePluginEnv, version)
+	client, err := Start(os.Args[0], 10*time.Second, &recordingReporter{})
+	if client != nil {
+		t.Cleanup(func() { client.kill() })
+	}
+	return client, err
+}
+
+func pluginModel() *types.ParsedModel {
+	return &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{"web": {Id: "web", RAA: 1}}}
+}
+
+func TestStart(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	assert.Equal(t, InitializeResult{ProtocolVersion: 1, Name: "fake", Version: "1.0", Capabilities: []string{RiskRulesCapability, RAACapability, MacrosCapability}}, client.Plugin)
+	assert.True(t, client.HasCapability(RAACapability))
+	assert.False(t, client.HasCapability("unknown"))
+	assert.Equal(t, 10*time.Second, client.Timeout)
+
+	rules := client.RiskRules()
+	require.Len(t, rules, 2)
+	assert.Equal(t, "raa", rules[0].Category().Id)
+	assert.Equal(t, []string{"fake"}, rules[0].SupportedTags())
+	pluginMacros := client.Macros()
+	require.Len(t, pluginMacros, 1)
+	assert.Equal(t, macros.MacroDetails{ID: "rename", Title: "Rename"}, pluginMacros[0].GetMacroDetails())
+
+	assert.NoError(t, client.Shutdown())
+	assert.NoError(t, client.Shutdown(), "shutting down again does nothing")
+}
+
+type startFailsTest struct {
+	filename string
+	version  string
+	expected string
+}
+
+func TestStartFails(t *testing.T) {
+	notExecutable := filepath.Join(t.TempDir(), "plugin")
+	require.NoError(t, os.WriteFile(notExecutable, []byte("#!/bin/sh"), 0600))
+
+	testCases := map[string]startFailsTest{
+		"unsupported protocol version": {
+			filename: os.Args[0],
+			version:  "2",
+			expected: "handshake failed: unsupported protocol version 2",
+		},
+		"not executable": {
+			filename: notExecutable,
+			version:  "1",
+			expected: "permission denied",
+		},
+		"missing": {
+			filename: filepath.Join(t.TempDir(), "missing"),
+			version:  "1",
+			expected: "no such file or directory",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(fakePluginEnv, testCase.version)
+			_, err := Start(testCase.filename, 10*time.Second, &recordingReporter{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expected)
+		})
+	}
+}
+
+func TestModelSentAgainAfterRAA(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+	parsedModel := pluginModel()
+
+	generatedRisks, err := client.GenerateRisks(parsedModel, "raa")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Risk{{CategoryId: "raa", Title: "1"}}, generatedRisks)
+
+	introText, err := client.CalculateRAA(parsedModel)
+	require.NoError(t, err)
+	assert.Equal(t, "fake RAA", introText)
+	assert.Equal(t, 42.0, parsedModel.TechnicalAssets["web"].RAA)
+
+	generatedRisks, err = client.RiskRules()[0].TryGenerateRisks(parsedModel)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Risk{{CategoryId: "raa", Title: "42"}}, generatedRisks, "the plugin gets the RAA of the host")
+	assert.NoError(t, client.Shutdown())
+}
+
+func TestCalculateRAAOfUnknownTechnicalAsset(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	_, err = client.CalculateRAA(&types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{}})
+	require.Error(t, err)
+	assert.Equal(t, `RAA calculated for unknown technical asset "web"`, err.Error())
+}
+
+func TestCallTimeout(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+	client.Timeout = 100 * time.Millisecond
+
+	_, err = client.GenerateRisks(pluginModel(), "hang")
+	require.Error(t, err)
+	assert.Equal(t, "generateRisks timed out after 100ms", err.Error())
+
+	_, err = client.GenerateRisks(pluginModel(), "raa")
+	assert.Error(t, err, "the plugin is killed after a timeout")
+	assert.NoError(t, client.Shutdown())
+}
+
+func TestCrash(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	_, err = client.GenerateRisks(pluginModel(), "crash")
+	require.Error(t, err)
+	assert.Equal(t, "plugin exited during generateRisks: exit status 3", err.Error())
+	assert.NoError(t, client.Shutdown())
+}
+
+func TestResponseOfUnknownCall(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		generatedRisks, err := client.GenerateRisks(pluginModel(), "unknown-call-first")
+		require.NoError(t, err)
+		assert.Len(t, generatedRisks, 1, "the response of the unknown call is dropped without blocking")
+	}
+	assert.NoError(t, client.Shutdown())
+}
+
+func TestUnknownMethod(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	_, _, err = client.Macros()[0].GoBack()
+	require.Error(t, err)
+	assert.Equal(t, "macroGoBack failed: unknown method (code -32601)", err.Error())
+}
+
+func TestMacroExecute(t *testing.T) {
+	client, err := startFakePlugin(t, "1")
+	require.NoError(t, err)
+
+	modelInput := &input.Model{Title: "original"}
+	message, valid, err := client.Macros()[0].Execute(modelInput, pluginModel())
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "renamed", message)
+	assert.Equal(t, "renamed", modelInput.Title)
+}
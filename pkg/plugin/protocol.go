@@ -0,0 +1,127 @@
+/*
+Package plugin implements the protocol of persistent plugins providing custom risk rules, RAA calculation and model macros,
+both for the host (Client) and for plugin authors (Serve).
+
+A plugin is started once and stays alive while it is used. The host and the plugin exchange JSON-RPC 2.0 messages,
+one per line, over stdin and stdout of the plugin. Anything the plugin writes to stderr is forwarded to the host's
+progress reporter (lines starting with "ERROR: " or "WARNING: " as warnings). The host starts with
+"initialize" to agree on the protocol version and the capabilities, sends the parsed model with "setModel"
+before the calls needing it (again whenever it changed, e.g. after the RAA calculation) and ends with "shutdown", after which the plugin exits.
+*/
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const ProtocolVersion = 1
+
+const (
+	RiskRulesCapability = "risk-rules"
+	RAACapability       = "raa"
+	MacrosCapability    = "macros"
+)
+
+const (
+	InitializeMethod             = "initialize"
+	InfoMethod                   = "info"
+	SetModelMethod               = "setModel"
+	GenerateRisksMethod          = "generateRisks"
+	CalculateRAAMethod           = "calculateRAA"
+	MacroNextQuestionMethod      = "macroNextQuestion"
+	MacroApplyAnswerMethod       = "macroApplyAnswer"
+	MacroGoBackMethod            = "macroGoBack"
+	MacroFinalChangeImpactMethod = "macroFinalChangeImpact"
+	MacroExecuteMethod           = "macroExecute"
+	ShutdownMethod               = "shutdown"
+)
+
+// error codes of JSON-RPC 2.0 and of the protocol
+const (
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+	ModelMissingCode   = -32001 // setModel has to be called first
+)
+
+const jsonRPCVersion = "2.0"
+
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (what *Error) Error() string {
+	return fmt.Sprintf("%v (code %d)", what.Message, what.Code)
+}
+
+type InitializeParams struct {
+	ProtocolVersions []int    `json:"protocol_versions"` // supported by the host
+	Capabilities     []string `json:"capabilities"`      // supported by the host
+}
+
+type InitializeResult struct {
+	ProtocolVersion int      `json:"protocol_version"` // chosen by the plugin out of the ones of the host
+	Name            string   `json:"name"`
+	Version         string   `json:"version,omitempty"`
+	Capabilities    []string `json:"capabilities"` // provided by the plugin out of the ones of the host
+}
+
+type RiskRuleInfo struct {
+	Category      types.RiskCategory `json:"category"`
+	SupportedTags []string           `json:"supported_tags,omitempty"`
+}
+
+type InfoResult struct {
+	RiskRules []RiskRuleInfo        `json:"risk_rules,omitempty"`
+	Macros    []macros.MacroDetails `json:"macros,omitempty"`
+}
+
+type SetModelParams struct {
+	Model *types.ParsedModel `json:"model"`
+}
+
+type GenerateRisksParams struct {
+	RiskRuleId string `json:"risk_rule_id"`
+}
+
+type CalculateRAAResult struct {
+	RAA       map[string]float64 `json:"raa"` // by technical asset id
+	IntroText string             `json:"intro_text,omitempty"`
+}
+
+type MacroParams struct {
+	MacroId    string       `json:"macro_id"`
+	QuestionId string       `json:"question_id,omitempty"`
+	Answers    []string     `json:"answers,omitempty"`
+	ModelInput *input.Model `json:"model_input,omitempty"`
+}
+
+type MacroResult struct {
+	Question   *macros.MacroQuestion `json:"question,omitempty"`
+	Changes    []string              `json:"changes,omitempty"`
+	Message    string                `json:"message,omitempty"`
+	Valid      bool                  `json:"valid"`
+	ModelInput *input.Model          `json:"model_input,omitempty"` // as changed by executing the macro
+}
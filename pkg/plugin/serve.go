@@ -0,0 +1,290 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// prefixes of stderr lines forwarded to the progress reporter
+const (
+	infoPrefix    = "INFO: "
+	warningPrefix = "WARNING: "
+	errorPrefix   = "ERROR: "
+)
+
+// Plugin is what a plugin provides, the capabilities follow from the fields set
+type Plugin struct {
+	Name      string
+	Version   string
+	RiskRules []risks.RiskRule
+	RAA       func(parsedModel *types.ParsedModel) string // sets the RAA of the technical assets and returns the intro text
+	Macros    []macros.Macros
+}
+
+// Serve answers the calls of threagile on stdin and stdout until shutdown, to be called by the main function of a plugin
+func Serve(p Plugin) {
+	err := p.ServeIO(os.Stdin, os.Stdout)
+	if err != nil {
+		Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+func (p Plugin) ServeIO(in io.Reader, out io.Writer) error {
+	server := &pluginServer{plugin: p, encoder: json.NewEncoder(out)}
+	reader := bufio.NewReader(in)
+	for !server.shutdown {
+		line, readError := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			writeError := server.handle(line)
+			if writeError != nil {
+				return writeError
+			}
+		}
+		if readError == io.EOF {
+			return nil
+		}
+		if readError != nil {
+			return readError
+		}
+	}
+	return nil
+}
+
+func Infof(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, infoPrefix+format+"\n", a...)
+}
+
+func Warnf(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, warningPrefix+format+"\n", a...)
+}
+
+func Errorf(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, errorPrefix+format+"\n", a...)
+}
+
+type pluginServer struct {
+	plugin      Plugin
+	encoder     *json.Encoder
+	initialized bool
+	shutdown    bool
+	model       *types.ParsedModel
+}
+
+func (s *pluginServer) capabilities() []string {
+	capabilities := make([]string, 0)
+	if len(s.plugin.RiskRules) > 0 {
+		capabilities = append(capabilities, RiskRulesCapability)
+	}
+	if s.plugin.RAA != nil {
+		capabilities = append(capabilities, RAACapability)
+	}
+	if len(s.plugin.Macros) > 0 {
+		capabilities = append(capabilities, MacrosCapability)
+	}
+	return capabilities
+}
+
+func (s *pluginServer) handle(line []byte) error {
+	var request Request
+	unmarshalError := json.Unmarshal(line, &request)
+	if unmarshalError != nil {
+		return s.encoder.Encode(Response{JSONRPC: jsonRPCVersion, Error: &Error{Code: ParseErrorCode, Message: unmarshalError.Error()}})
+	}
+
+	result, callError := s.call(request)
+	response := Response{JSONRPC: jsonRPCVersion, ID: request.ID}
+	if callError != nil {
+		response.Error = callError
+	} else if result != nil {
+		data, marshalError := json.Marshal(result)
+		if marshalError != nil {
+			response.Error = &Error{Code: InternalErrorCode, Message: marshalError.Error()}
+		} else {
+			response.Result = data
+		}
+	}
+	return s.encoder.Encode(response)
+}
+
+func (s *pluginServer) call(request Request) (result any, err *Error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = nil, &Error{Code: InternalErrorCode, Message: fmt.Sprintf("panic: %v", recovered)}
+		}
+	}()
+
+	return s.dispatch(request)
+}
+
+func (s *pluginServer) dispatch(request Request) (any, *Error) {
+	if request.JSONRPC != jsonRPCVersion {
+		return nil, &Error{Code: InvalidRequestCode, Message: fmt.Sprintf("unsupported jsonrpc version %q", request.JSONRPC)}
+	}
+	if !s.initialized && request.Method != InitializeMethod {
+		return nil, &Error{Code: InvalidRequestCode, Message: "not initialized"}
+	}
+
+	switch request.Method {
+	case InitializeMethod:
+		var params InitializeParams
+		if err := unmarshalParams(request, &params); err != nil {
+			return nil, err
+		}
+		if !containsVersion(params.ProtocolVersions, ProtocolVersion) {
+			return nil, &Error{Code: InvalidParamsCode, Message: fmt.Sprintf("protocol version %d not supported by the host", ProtocolVersion)}
+		}
+		s.initialized = true
+		return InitializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Name:            s.plugin.Name,
+			Version:         s.plugin.Version,
+			Capabilities:    intersect(s.capabilities(), params.Capabilities),
+		}, nil
+
+	case InfoMethod:
+		info := InfoResult{RiskRules: make([]RiskRuleInfo, 0), Macros: make([]macros.MacroDetails, 0)}
+		for _, rule := range s.plugin.RiskRules {
+			info.RiskRules = append(info.RiskRules, RiskRuleInfo{Category: rule.Category(), SupportedTags: rule.SupportedTags()})
+		}
+		for _, macro := range s.plugin.Macros {
+			info.Macros = append(info.Macros, macro.GetMacroDetails())
+		}
+		return info, nil
+
+	case SetModelMethod:
+		var params SetModelParams
+		if err := unmarshalParams(request, &params); err != nil {
+			return nil, err
+		}
+		s.model = params.Model
+		return nil, nil
+
+	case GenerateRisksMethod:
+		var params GenerateRisksParams
+		if err := unmarshalParams(request, &params); err != nil {
+			return nil, err
+		}
+		if s.model == nil {
+			return nil, modelMissing()
+		}
+		for _, rule := range s.plugin.RiskRules {
+			if rule.Category().Id == params.RiskRuleId {
+				return rule.GenerateRisks(s.model), nil
+			}
+		}
+		return nil, &Error{Code: InvalidParamsCode, Message: fmt.Sprintf("unknown risk rule %q", params.RiskRuleId)}
+
+	case CalculateRAAMethod:
+		if s.plugin.RAA == nil {
+			return nil, &Error{Code: MethodNotFoundCode, Message: "no RAA calculation"}
+		}
+		if s.model == nil {
+			return nil, modelMissing()
+		}
+		result := CalculateRAAResult{RAA: make(map[string]float64), IntroText: s.plugin.RAA(s.model)}
+		for id, technicalAsset := range s.model.TechnicalAssets {
+			result.RAA[id] = technicalAsset.RAA
+		}
+		return result, nil
+
+	case MacroNextQuestionMethod, MacroApplyAnswerMethod, MacroGoBackMethod, MacroFinalChangeImpactMethod, MacroExecuteMethod:
+		var params MacroParams
+		if err := unmarshalParams(request, &params); err != nil {
+			return nil, err
+		}
+		return s.callMacro(request.Method, params)
+
+	case ShutdownMethod:
+		s.shutdown = true
+		return nil, nil
+	}
+
+	return nil, &Error{Code: MethodNotFoundCode, Message: fmt.Sprintf("unknown method %q", request.Method)}
+}
+
+func (s *pluginServer) callMacro(method string, params MacroParams) (any, *Error) {
+	var macro macros.Macros
+	for _, candidate := range s.plugin.Macros {
+		if candidate.GetMacroDetails().ID == params.MacroId {
+			macro = candidate
+		}
+	}
+	if macro == nil {
+		return nil, &Error{Code: InvalidParamsCode, Message: fmt.Sprintf("unknown macro %q", params.MacroId)}
+	}
+	if s.model == nil && method != MacroApplyAnswerMethod && method != MacroGoBackMethod {
+		return nil, modelMissing()
+	}
+
+	var result MacroResult
+	var err error
+	switch method {
+	case MacroNextQuestionMethod:
+		var question macros.MacroQuestion
+		question, err = macro.GetNextQuestion(s.model)
+		if !question.NoMoreQuestions() {
+			result.Question = &question
+		}
+
+	case MacroApplyAnswerMethod:
+		result.Message, result.Valid, err = macro.ApplyAnswer(params.QuestionId, params.Answers...)
+
+	case MacroGoBackMethod:
+		result.Message, result.Valid, err = macro.GoBack()
+
+	case MacroFinalChangeImpactMethod:
+		result.Changes, result.Message, result.Valid, err = macro.GetFinalChangeImpact(params.ModelInput, s.model)
+
+	case MacroExecuteMethod:
+		result.Message, result.Valid, err = macro.Execute(params.ModelInput, s.model)
+		result.ModelInput = params.ModelInput
+	}
+	if err != nil {
+		return nil, &Error{Code: InternalErrorCode, Message: err.Error()}
+	}
+	return result, nil
+}
+
+func unmarshalParams(request Request, params any) *Error {
+	if len(request.Params) == 0 {
+		return &Error{Code: InvalidParamsCode, Message: "missing params"}
+	}
+	err := json.Unmarshal(request.Params, params)
+	if err != nil {
+		return &Error{Code: InvalidParamsCode, Message: err.Error()}
+	}
+	return nil
+}
+
+func modelMissing() *Error {
+	return &Error{Code: ModelMissingCode, Message: "no model set"}
+}
+
+func containsVersion(versions []int, version int) bool {
+	for _, candidate := range versions {
+		if candidate == version {
+			return true
+		}
+	}
+	return false
+}
+
+func intersect(values []string, allowed []string) []string {
+	result := make([]string, 0)
+	for _, value := range values {
+		for _, candidate := range allowed {
+			if value == candidate {
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
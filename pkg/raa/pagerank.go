@@ -0,0 +1,158 @@
+package raa
+
+import (
+	"math"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const pageRankIntroText = "For each technical asset the <b>\"Relative Attacker Attractiveness\"</b> (RAA) value was calculated " +
+	"in percent. The higher the RAA, the more interesting it is for an attacker to compromise the asset. The calculation algorithm " +
+	"ranks the technical assets by their centrality in the communication graph (weighted PageRank): An attacker is assumed to start " +
+	"at technical assets in proportion to their own attractiveness (sensitivity ratings and quantities of stored and processed data) " +
+	"and to move along the communication links, preferring links transferring sensitive data and links crossing trust boundaries." +
+	"<br><br>The following lists all technical assets sorted by their RAA value from highest (most attacker attractive) to lowest. " +
+	"This list can be used to prioritize on efforts relevant for the most attacker-attractive technical assets:"
+
+const (
+	pageRankDamping       = 0.85
+	pageRankMaxIterations = 100
+	pageRankTolerance     = 1e-10
+	trustBoundaryBoost    = 2 // of the weight of communication links crossing a trust boundary
+)
+
+// calculatePageRank sets the RAA by a weighted PageRank over the communication links, personalized by the attacker attractiveness
+func (what *Calculator) calculatePageRank(parsedModel *types.ParsedModel) string {
+	ids := make([]string, 0, len(parsedModel.TechnicalAssets))
+	for id := range parsedModel.TechnicalAssets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	breakdowns := make([]*types.RAABreakdown, len(ids))
+	personalization := make([]float64, len(ids))
+	total := 0.0
+	for i, id := range ids {
+		breakdowns[i] = what.attackerAttractiveness(parsedModel, parsedModel.TechnicalAssets[id])
+		if breakdowns[i] != nil {
+			personalization[i] = breakdowns[i].Score
+			total += breakdowns[i].Score
+		}
+	}
+	for i := range personalization {
+		if total > 0 {
+			personalization[i] /= total
+		} else {
+			personalization[i] = 1 / float64(len(ids))
+		}
+	}
+
+	links := make([][]link, len(ids))
+	outgoing := make([]float64, len(ids))
+	for i, id := range ids {
+		weights := make(map[int]float64)
+		for _, commLink := range parsedModel.TechnicalAssets[id].CommunicationLinks {
+			target, ok := index[commLink.TargetId]
+			if !ok || target == i {
+				continue
+			}
+			weights[target] += what.communicationLinkWeight(parsedModel, commLink)
+		}
+		// summed in the order of the targets, so the ranks do not depend on the map order
+		for target := range weights {
+			links[i] = append(links[i], link{target: target, weight: weights[target]})
+		}
+		sort.Slice(links[i], func(a, b int) bool { return links[i][a].target < links[i][b].target })
+		for _, targetLink := range links[i] {
+			outgoing[i] += targetLink.weight
+		}
+	}
+
+	rank := pageRank(personalization, links, outgoing)
+
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+	for i := range ids {
+		if breakdowns[i] == nil {
+			continue
+		}
+		minimum = math.Min(minimum, rank[i])
+		maximum = math.Max(maximum, rank[i])
+	}
+	span := maximum - minimum
+	for i, id := range ids {
+		techAsset := parsedModel.TechnicalAssets[id]
+		techAsset.RAA = 1 // since 0 suggests no attacks at all
+		techAsset.RAABreakdown = breakdowns[i]
+		if breakdowns[i] != nil {
+			if span > 0 {
+				techAsset.RAA = math.Max((rank[i]-minimum)/span*100, 1)
+			}
+			breakdowns[i].Centrality = rank[i]
+			breakdowns[i].Minimum, breakdowns[i].Maximum = minimum, maximum
+		}
+		parsedModel.TechnicalAssets[id] = techAsset
+	}
+
+	return pageRankIntroText
+}
+
+// communicationLinkWeight is the sensitivity of the transferred data (at least 1), boosted when crossing a trust boundary
+func (what *Calculator) communicationLinkWeight(parsedModel *types.ParsedModel, commLink types.CommunicationLink) float64 {
+	weight := 0.0
+	for _, dataAssetId := range commLink.DataAssetsSent {
+		weight += what.dataFactor(parsedModel, types.RAASentData, dataAssetId, commLink.Id).Sum()
+	}
+	for _, dataAssetId := range commLink.DataAssetsReceived {
+		weight += what.dataFactor(parsedModel, types.RAAReceivedData, dataAssetId, commLink.Id).Sum()
+	}
+	weight = math.Max(weight, 1)
+	if commLink.IsAcrossTrustBoundary(parsedModel) {
+		weight *= trustBoundaryBoost
+	}
+	return weight
+}
+
+// link is the summed weight of the communication links from a technical asset to the one at the target index
+type link struct {
+	target int
+	weight float64
+}
+
+// pageRank iterates the ranks until they converge, teleporting and leaving dangling nodes by the personalization
+func pageRank(personalization []float64, links [][]link, outgoing []float64) []float64 {
+	rank := make([]float64, len(personalization))
+	copy(rank, personalization)
+	for iteration := 0; iteration < pageRankMaxIterations; iteration++ {
+		dangling := 0.0
+		for i := range rank {
+			if outgoing[i] == 0 {
+				dangling += rank[i]
+			}
+		}
+
+		next := make([]float64, len(rank))
+		for i := range next {
+			next[i] = (1 - pageRankDamping + pageRankDamping*dangling) * personalization[i]
+		}
+		for i, targetLinks := range links {
+			for _, targetLink := range targetLinks {
+				next[targetLink.target] += pageRankDamping * rank[i] * targetLink.weight / outgoing[i]
+			}
+		}
+
+		change := 0.0
+		for i := range rank {
+			change += math.Abs(next[i] - rank[i])
+		}
+		rank = next
+		if change < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
@@ -0,0 +1,106 @@
+package raa_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/raa"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// graphModel links the technical assets as given by source and target ids, sending the data asset named by the link
+func graphModel(links map[string][]string, dataSent map[string]string) *types.ParsedModel {
+	parsedModel := &types.ParsedModel{
+		DataAssets: map[string]types.DataAsset{
+			"secret": {Id: "secret", Confidentiality: types.StrictlyConfidential, Integrity: types.MissionCritical, Quantity: types.VeryMany},
+			"public": {Id: "public"},
+		},
+		TechnicalAssets: make(map[string]types.TechnicalAsset),
+		DirectContainingTrustBoundaryMappedByTechnicalAssetId: make(map[string]types.TrustBoundary),
+	}
+	for source, targets := range links {
+		techAsset := parsedModel.TechnicalAssets[source]
+		techAsset.Id = source
+		for _, target := range targets {
+			commLink := types.CommunicationLink{Id: source + ">" + target, SourceId: source, TargetId: target}
+			if dataAssetId, ok := dataSent[commLink.Id]; ok {
+				commLink.DataAssetsSent = []string{dataAssetId}
+			}
+			techAsset.CommunicationLinks = append(techAsset.CommunicationLinks, commLink)
+			parsedModel.TechnicalAssets[target] = types.TechnicalAsset{Id: target, CommunicationLinks: parsedModel.TechnicalAssets[target].CommunicationLinks}
+		}
+		parsedModel.TechnicalAssets[source] = techAsset
+	}
+	return parsedModel
+}
+
+type pageRankTest struct {
+	links      map[string][]string
+	dataSent   map[string]string
+	boundaries map[string]string // trust boundary id by technical asset id
+	ranking    []string          // technical asset ids from highest to lowest RAA
+}
+
+func TestPageRank(t *testing.T) {
+	testCases := map[string]pageRankTest{
+		"chain": {
+			links:   map[string][]string{"client": {"web"}, "web": {"db"}},
+			ranking: []string{"db", "web", "client"},
+		},
+		"fan-in": {
+			links:   map[string][]string{"a": {"hub"}, "b": {"hub"}, "c": {"hub"}, "hub": {"d"}},
+			ranking: []string{"d", "hub", "a"},
+		},
+		"sensitive data": {
+			links:    map[string][]string{"client": {"x", "y"}},
+			dataSent: map[string]string{"client>x": "public", "client>y": "secret"},
+			ranking:  []string{"client", "y", "x"}, // the client processes the secret data itself
+		},
+		"trust boundary crossing": {
+			links:      map[string][]string{"client": {"x", "y"}},
+			boundaries: map[string]string{"client": "internal", "x": "internal", "y": "dmz"},
+			ranking:    []string{"y", "x", "client"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsedModel := graphModel(testCase.links, testCase.dataSent)
+			for techAssetId, trustBoundaryId := range testCase.boundaries {
+				parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[techAssetId] = types.TrustBoundary{Id: trustBoundaryId}
+			}
+			calculate(t, parsedModel, raa.PageRankAlgorithm)
+
+			for i := 1; i < len(testCase.ranking); i++ {
+				higher, lower := parsedModel.TechnicalAssets[testCase.ranking[i-1]], parsedModel.TechnicalAssets[testCase.ranking[i]]
+				assert.Greater(t, higher.RAA, lower.RAA, "%v ranks higher than %v", higher.Id, lower.Id)
+			}
+			assert.Equal(t, 100.0, parsedModel.TechnicalAssets[testCase.ranking[0]].RAA)
+			assert.Equal(t, 1.0, parsedModel.TechnicalAssets[testCase.ranking[len(testCase.ranking)-1]].RAA)
+			for _, techAsset := range parsedModel.TechnicalAssets {
+				require.NotNil(t, techAsset.RAABreakdown)
+				assert.Greater(t, techAsset.RAABreakdown.Centrality, 0.0)
+			}
+		})
+	}
+}
+
+func TestPageRankOfSingleTechnicalAsset(t *testing.T) {
+	parsedModel := &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{"alone": {Id: "alone"}}}
+	calculate(t, parsedModel, raa.PageRankAlgorithm)
+	assert.Equal(t, 1.0, parsedModel.TechnicalAssets["alone"].RAA, "0 suggests no attacks at all")
+}
+
+func TestPageRankIsDeterministic(t *testing.T) {
+	parsedModel := exampleModel(t)
+	calculate(t, parsedModel, raa.PageRankAlgorithm)
+	for i := 0; i < 10; i++ {
+		again := exampleModel(t)
+		calculate(t, again, raa.PageRankAlgorithm)
+		for id, techAsset := range parsedModel.TechnicalAssets {
+			require.Equal(t, techAsset.RAA, again.TechnicalAssets[id].RAA, "exactly the same RAA of %v", id)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Package raa calculates the "Relative Attacker Attractiveness" (RAA) of the technical assets of a model.
+*/
+package raa
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const introText = "For each technical asset the <b>\"Relative Attacker Attractiveness\"</b> (RAA) value was calculated " +
+	"in percent. The higher the RAA, the more interesting it is for an attacker to compromise the asset. The calculation algorithm takes " +
+	"the sensitivity ratings and quantities of stored and processed data into account as well as the communication links of the " +
+	"technical asset. Neighbouring assets to high-value RAA targets might receive an increase in their RAA value when they have " +
+	"a communication link towards that target (\"Pivoting-Factor\").<br><br>The following lists all technical assets sorted by their " +
+	"RAA value from highest (most attacker attractive) to lowest. This list can be used to prioritize on efforts relevant for the most " +
+	"attacker-attractive technical assets:"
+
+// algorithms of the RAA calculation
+const (
+	AttractivenessAlgorithm = "attractiveness" // by the own attractiveness and the one of direct neighbours (default)
+	PageRankAlgorithm       = "pagerank"       // by the centrality of the technical asset in the communication graph
+)
+
+// Calculator keeps no state between calculations, so it may be used concurrently for different models
+type Calculator struct {
+	attractiveness common.Attractiveness
+	algorithm      string
+}
+
+func NewCalculator(attractiveness common.Attractiveness, algorithm string) (*Calculator, error) {
+	switch algorithm {
+	case "":
+		algorithm = AttractivenessAlgorithm
+	case AttractivenessAlgorithm, PageRankAlgorithm:
+	default:
+		return nil, fmt.Errorf("unknown RAA algorithm %q (supported: %v, %v)", algorithm, AttractivenessAlgorithm, PageRankAlgorithm)
+	}
+	return &Calculator{attractiveness: attractiveness, algorithm: algorithm}, nil
+}
+
+// Calculate sets the RAA and its breakdown of the technical assets and returns the intro text (for reporting etc., can be short summary-like)
+func (what *Calculator) Calculate(parsedModel *types.ParsedModel) string {
+	if what.algorithm == PageRankAlgorithm {
+		return what.calculatePageRank(parsedModel)
+	}
+
+	calculation := &calculation{
+		Calculator:  what,
+		parsedModel: parsedModel,
+		breakdowns:  make(map[string]*types.RAABreakdown),
+	}
+	calculation.determineRange()
+
+	relativeAttractiveness := make(map[string]float64)
+	breakdowns := make(map[string]*types.RAABreakdown)
+	for techAssetID, techAsset := range parsedModel.TechnicalAssets {
+		aa := calculation.attackerAttractiveness(techAsset)
+		adjustment, neighbours := calculation.pivotingNeighbourEffectAdjustment(techAsset)
+		aa += adjustment
+		relativeAttractiveness[techAssetID] = calculation.relativeAttackerAttractiveness(aa)
+
+		if breakdown, ok := calculation.breakdowns[techAssetID]; ok {
+			explained := *breakdown
+			explained.PivotingBonus = adjustment
+			explained.PivotingNeighbours = neighbours
+			explained.Minimum, explained.Maximum = calculation.minimum, calculation.maximum
+			breakdowns[techAssetID] = &explained
+		}
+	}
+	for techAssetID, raa := range relativeAttractiveness {
+		techAsset := parsedModel.TechnicalAssets[techAssetID]
+		techAsset.RAA = raa
+		techAsset.RAABreakdown = breakdowns[techAssetID]
+		parsedModel.TechnicalAssets[techAssetID] = techAsset
+	}
+
+	return introText
+}
+
+// calculation is the state of calculating the RAA of one model
+type calculation struct {
+	*Calculator
+	parsedModel        *types.ParsedModel
+	breakdowns         map[string]*types.RAABreakdown // of the attacker attractiveness by technical asset id (if in scope)
+	minimum, maximum   float64
+	attractivenessSpan float64
+}
+
+// determine the min/max of all
+func (what *calculation) determineRange() {
+	what.minimum, what.maximum = 9223372036854775807, -9223372036854775808
+	keys := make([]string, 0)
+	for k := range what.parsedModel.TechnicalAssets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		score := what.attackerAttractiveness(what.parsedModel.TechnicalAssets[key])
+		if score > what.maximum {
+			what.maximum = score
+		}
+		if score < what.minimum {
+			what.minimum = score
+		}
+	}
+	if !(what.minimum < what.maximum) {
+		what.maximum = what.minimum + 1
+	}
+	what.attractivenessSpan = what.maximum - what.minimum
+}
+
+// set the concrete value in relation to the minimum and maximum of all
+func (what *calculation) relativeAttackerAttractiveness(attractiveness float64) float64 {
+	// calculate the percent value of the value within the defined min/max range
+	value := attractiveness - what.minimum
+	percent := value / what.attractivenessSpan * 100
+	if percent <= 0 {
+		percent = 1 // since 0 suggests no attacks at all
+	}
+	return percent
+}
+
+// increase the RAA (relative attacker attractiveness) by one third (1/3) of the delta to the highest outgoing neighbour (if positive delta),
+// returns the increase and the neighbours causing it (sorted)
+func (what *calculation) pivotingNeighbourEffectAdjustment(techAsset types.TechnicalAsset) (float64, []string) {
+	if techAsset.OutOfScope {
+		return 0, nil
+	}
+	adjustment := 0.0
+	var neighbours []string
+	for _, commLink := range techAsset.CommunicationLinks {
+		outgoingNeighbour := what.parsedModel.TechnicalAssets[commLink.TargetId]
+		delta := what.relativeAttackerAttractiveness(what.attackerAttractiveness(outgoingNeighbour)) - what.relativeAttackerAttractiveness(what.attackerAttractiveness(techAsset))
+		if delta > 0 {
+			potentialIncrease := delta / 3
+			if potentialIncrease > adjustment {
+				adjustment = potentialIncrease
+				neighbours = []string{commLink.TargetId}
+			} else if potentialIncrease == adjustment && !contains(neighbours, commLink.TargetId) {
+				neighbours = append(neighbours, commLink.TargetId)
+			}
+		}
+	}
+	sort.Strings(neighbours)
+	return adjustment, neighbours
+}
+
+func (what *calculation) attackerAttractiveness(techAsset types.TechnicalAsset) float64 {
+	if breakdown, ok := what.breakdowns[techAsset.Id]; ok {
+		return breakdown.Score
+	}
+	breakdown := what.Calculator.attackerAttractiveness(what.parsedModel, techAsset)
+	if breakdown == nil {
+		return 0
+	}
+	if len(techAsset.Id) > 0 {
+		what.breakdowns[techAsset.Id] = breakdown
+	}
+	return breakdown.Score
+}
+
+// The sum of all CIAs of the asset itself (fibonacci scale) plus the sum of the comm-links' transferred CIAs
+// Multiplied by the quantity values of the data asset for C and I (not A)
+func (what *Calculator) attackerAttractiveness(parsedModel *types.ParsedModel, techAsset types.TechnicalAsset) *types.RAABreakdown {
+	if techAsset.OutOfScope {
+		return nil
+	}
+	confidentiality, integrity, availability := what.attractiveness.Confidentiality, what.attractiveness.Integrity, what.attractiveness.Availability
+	breakdown := &types.RAABreakdown{Multiplier: 1}
+	breakdown.Factors = append(breakdown.Factors, types.RAAFactor{
+		Source:          types.RAAOwnRating,
+		Confidentiality: fibonacci(confidentiality.Asset + int(techAsset.Confidentiality)),
+		Integrity:       fibonacci(integrity.Asset + int(techAsset.Integrity)),
+		Availability:    fibonacci(availability.Asset + int(techAsset.Availability)),
+	})
+	for _, dataAssetId := range techAsset.DataAssetsProcessed {
+		breakdown.Factors = append(breakdown.Factors, what.dataFactor(parsedModel, types.RAAProcessedData, dataAssetId, ""))
+	}
+	// NOTE: Assuming all stored data is also processed, this effectively scores stored data twice
+	for _, dataAssetId := range techAsset.DataAssetsStored {
+		breakdown.Factors = append(breakdown.Factors, what.dataFactor(parsedModel, types.RAAStoredData, dataAssetId, ""))
+	}
+	// NOTE: To send or receive data effectively is processing that data and it's questionable if the attractiveness increases further
+	for _, dataFlow := range techAsset.CommunicationLinks {
+		for _, dataAssetId := range dataFlow.DataAssetsSent {
+			breakdown.Factors = append(breakdown.Factors, what.dataFactor(parsedModel, types.RAASentData, dataAssetId, dataFlow.Id))
+		}
+		for _, dataAssetId := range dataFlow.DataAssetsReceived {
+			breakdown.Factors = append(breakdown.Factors, what.dataFactor(parsedModel, types.RAAReceivedData, dataAssetId, dataFlow.Id))
+		}
+	}
+
+	var score = 0.0
+	for _, factor := range breakdown.Factors {
+		score += factor.Confidentiality
+		score += factor.Integrity
+		score += factor.Availability
+	}
+	multiply := func(factor float64) {
+		score = score * factor
+		breakdown.Multiplier = breakdown.Multiplier * factor
+	}
+	divide := func(divisor float64) {
+		score = score / divisor
+		breakdown.Multiplier = breakdown.Multiplier / divisor
+	}
+	if techAsset.Technology == types.LoadBalancer || techAsset.Technology == types.ReverseProxy {
+		divide(5.5)
+	}
+	if techAsset.Technology == types.Monitoring {
+		divide(5)
+	}
+	if techAsset.Technology == types.ContainerPlatform {
+		multiply(5)
+	}
+	if techAsset.Technology == types.Vault {
+		multiply(2)
+	}
+	if techAsset.Technology == types.BuildPipeline || techAsset.Technology == types.SourcecodeRepository || techAsset.Technology == types.ArtifactRegistry {
+		multiply(2)
+	}
+	if techAsset.Technology == types.IdentityProvider || techAsset.Technology == types.IdentityStoreDatabase || techAsset.Technology == types.IdentityStoreLDAP {
+		multiply(2.5)
+	} else if techAsset.Type == types.Datastore {
+		multiply(2)
+	}
+	if techAsset.MultiTenant {
+		multiply(1.5)
+	}
+	breakdown.Score = score
+	return breakdown
+}
+
+// dataFactor weighs a data asset by the attacker focus on processed or stored data or on transferred data (with a communication link)
+func (what *Calculator) dataFactor(parsedModel *types.ParsedModel, source string, dataAssetId string, commLinkId string) types.RAAFactor {
+	focus := func(attackerFocus common.AttackerFocus) int { return attackerFocus.ProcessedOrStoredData }
+	if len(commLinkId) > 0 {
+		focus = func(attackerFocus common.AttackerFocus) int { return attackerFocus.TransferredData }
+	}
+	dataAsset := parsedModel.DataAssets[dataAssetId]
+	quantityFactor := what.quantityFactor(dataAsset)
+	return types.RAAFactor{
+		Source:              source,
+		DataAssetId:         dataAssetId,
+		CommunicationLinkId: commLinkId,
+		QuantityFactor:      quantityFactor,
+		Confidentiality:     fibonacci(focus(what.attractiveness.Confidentiality)+int(dataAsset.Confidentiality)) * quantityFactor,
+		Integrity:           fibonacci(focus(what.attractiveness.Integrity)+int(dataAsset.Integrity)) * quantityFactor,
+		Availability:        fibonacci(focus(what.attractiveness.Availability) + int(dataAsset.Availability)),
+	}
+}
+
+func (what *Calculator) quantityFactor(dataAsset types.DataAsset) float64 {
+	return fibonacci(what.attractiveness.Quantity + int(dataAsset.Quantity))
+}
+
+// fibonacci returns the value at the index of the sequence 1, 2, 3, 5, 8, 13, ...
+func fibonacci(index int) float64 {
+	current, next := 1.0, 2.0
+	for i := 0; i < index; i++ {
+		current, next = next, current+next
+	}
+	return current
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package raa_test
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/raa"
+	"github.com/threagile/threagile/pkg/security/risks"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// raaCalcOfExample is the RAA the former raa_calc plugin calculated for the example model
+var raaCalcOfExample = map[string]float64{
+	"apache-webserver":     60.6120919375654,
+	"backend-admin-client": 1,
+	"backoffice-client":    1,
+	"contract-fileserver":  33.2657200811359,
+	"customer-client":      1,
+	"erp-system":           62.062039616154216,
+	"external-dev-client":  1,
+	"git-repo":             31.49087221095335,
+	"identity-provider":    40.999362954246536,
+	"jenkins-buildserver":  60.099849550227866,
+	"ldap-auth-server":     51.34381338742393,
+	"load-balancer":        9.952491809545325,
+	"marketing-cms":        22.55383688062901,
+	"sql-database":         100,
+}
+
+func defaultAttractiveness() common.Attractiveness {
+	return new(common.Config).Defaults("").Attractiveness
+}
+
+func exampleModel(t *testing.T) *types.ParsedModel {
+	modelInput := new(input.Model).Defaults()
+	require.NoError(t, modelInput.Load("../../demo/example/threagile.yaml"))
+	parsedModel, err := model.ParseModel(modelInput, make(map[string]risks.RiskRule), make(map[string]*model.CustomRisk))
+	require.NoError(t, err)
+	return parsedModel
+}
+
+func calculate(t *testing.T, parsedModel *types.ParsedModel, algorithm string) {
+	calculator, err := raa.NewCalculator(defaultAttractiveness(), algorithm)
+	require.NoError(t, err)
+	assert.NotEmpty(t, calculator.Calculate(parsedModel))
+}
+
+func TestCalculateAsRAACalc(t *testing.T) {
+	parsedModel := exampleModel(t)
+	calculate(t, parsedModel, raa.AttractivenessAlgorithm)
+
+	require.Len(t, parsedModel.TechnicalAssets, len(raaCalcOfExample))
+	for id, expected := range raaCalcOfExample {
+		assert.InDelta(t, expected, parsedModel.TechnicalAssets[id].RAA, 1e-9, id)
+	}
+
+	breakdown := parsedModel.TechnicalAssets["sql-database"].RAABreakdown
+	require.NotNil(t, breakdown)
+	assert.Equal(t, types.RAAOwnRating, breakdown.Factors[0].Source)
+	assert.Equal(t, 2.0, breakdown.Multiplier, "datastores are twice as attractive")
+	assert.Equal(t, breakdown.Score, breakdown.Maximum)
+}
+
+func TestCalculateConcurrently(t *testing.T) {
+	calculator, err := raa.NewCalculator(defaultAttractiveness(), "")
+	require.NoError(t, err)
+
+	parsedModels := []*types.ParsedModel{exampleModel(t), exampleModel(t), exampleModel(t), exampleModel(t)}
+	var wait sync.WaitGroup
+	for _, parsedModel := range parsedModels {
+		wait.Add(1)
+		go func(parsedModel *types.ParsedModel) {
+			defer wait.Done()
+			calculator.Calculate(parsedModel)
+		}(parsedModel)
+	}
+	wait.Wait()
+
+	for _, parsedModel := range parsedModels {
+		for id, expected := range raaCalcOfExample {
+			assert.InDelta(t, expected, parsedModel.TechnicalAssets[id].RAA, 1e-9, id)
+		}
+	}
+}
+
+type newCalculatorTest struct {
+	algorithm string
+	expected  string
+}
+
+func TestNewCalculator(t *testing.T) {
+	testCases := map[string]newCalculatorTest{
+		"default":        {algorithm: ""},
+		"attractiveness": {algorithm: raa.AttractivenessAlgorithm},
+		"pagerank":       {algorithm: raa.PageRankAlgorithm},
+		"unknown":        {algorithm: "betweenness", expected: `unknown RAA algorithm "betweenness" (supported: attractiveness, pagerank)`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calculator, err := raa.NewCalculator(defaultAttractiveness(), testCase.algorithm)
+			if len(testCase.expected) > 0 {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expected, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, calculator)
+		})
+	}
+}
+
+// pivotingModel has a client of two equally attractive servers and an unrelated low value asset
+func pivotingModel(linkOrder ...string) *types.ParsedModel {
+	parsedModel := &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{
+		"b":   {Id: "b", Confidentiality: types.StrictlyConfidential, Integrity: types.MissionCritical, Availability: types.MissionCritical},
+		"c":   {Id: "c", Confidentiality: types.StrictlyConfidential, Integrity: types.MissionCritical, Availability: types.MissionCritical},
+		"low": {Id: "low"},
+	}}
+	client := types.TechnicalAsset{Id: "a"}
+	for _, target := range linkOrder {
+		client.CommunicationLinks = append(client.CommunicationLinks, types.CommunicationLink{Id: "a>" + target, SourceId: "a", TargetId: target})
+	}
+	parsedModel.TechnicalAssets["a"] = client
+	return parsedModel
+}
+
+func TestPivotingNeighbourEffect(t *testing.T) {
+	for _, linkOrder := range [][]string{{"b", "c", "low"}, {"c", "low", "b"}, {"low", "c", "b", "c"}} {
+		t.Run(fmt.Sprint(linkOrder), func(t *testing.T) {
+			parsedModel := pivotingModel(linkOrder...)
+			calculate(t, parsedModel, raa.AttractivenessAlgorithm)
+
+			// scores of 8+5+5 (a and low) and 55+34+34 (b and c) by the fibonacci values of the default attacker focus plus the ratings
+			breakdown := parsedModel.TechnicalAssets["a"].RAABreakdown
+			require.NotNil(t, breakdown)
+			assert.Equal(t, 18.0, breakdown.Score)
+			assert.Equal(t, 33.0, breakdown.PivotingBonus, "a third of the RAA delta of 99 to the most attractive neighbour")
+			assert.Equal(t, []string{"b", "c"}, breakdown.PivotingNeighbours, "sorted regardless of the link order")
+			assert.InDelta(t, 33.0/105*100, parsedModel.TechnicalAssets["a"].RAA, 1e-9)
+
+			assert.Equal(t, 100.0, parsedModel.TechnicalAssets["b"].RAA)
+			assert.Equal(t, 1.0, parsedModel.TechnicalAssets["low"].RAA, "0 suggests no attacks at all")
+		})
+	}
+}
+
+func TestCalculateBreakdown(t *testing.T) {
+	customers := types.DataAsset{Id: "customers", Confidentiality: types.Confidential, Integrity: types.Important, Availability: types.Operational, Quantity: types.Many}
+	datastore := types.TechnicalAsset{Type: types.Datastore, Confidentiality: types.StrictlyConfidential, Integrity: types.Critical, Availability: types.Critical,
+		DataAssetsStored: []string{"customers"}}
+	db, backup := datastore, datastore
+	db.Id, backup.Id = "db", "backup"
+	parsedModel := &types.ParsedModel{
+		DataAssets: map[string]types.DataAsset{"customers": customers},
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Confidentiality: types.Internal, Integrity: types.Operational, Availability: types.Operational,
+				DataAssetsProcessed: []string{"customers"},
+				CommunicationLinks: []types.CommunicationLink{
+					{Id: "web>db", SourceId: "web", TargetId: "db", DataAssetsSent: []string{"customers"}},
+					{Id: "web>backup", SourceId: "web", TargetId: "backup"},
+				}},
+			"db":     db,
+			"backup": backup,
+		},
+	}
+	calculate(t, parsedModel, raa.AttractivenessAlgorithm)
+
+	// fibonacci values of the default attacker focus plus the ratings, data weighed by the quantity factor fibonacci(0+2)
+	web := parsedModel.TechnicalAssets["web"]
+	require.NotNil(t, web.RAABreakdown)
+	assert.Equal(t, []types.RAAFactor{
+		{Source: types.RAAOwnRating, Confidentiality: 13, Integrity: 8, Availability: 8},
+		{Source: types.RAAProcessedData, DataAssetId: "customers", QuantityFactor: 3, Confidentiality: 21 * 3, Integrity: 8 * 3, Availability: 5},
+		{Source: types.RAASentData, DataAssetId: "customers", CommunicationLinkId: "web>db", QuantityFactor: 3, Confidentiality: 8 * 3, Integrity: 5 * 3, Availability: 3},
+	}, web.RAABreakdown.Factors)
+	assert.Equal(t, 1.0, web.RAABreakdown.Multiplier)
+	assert.Equal(t, 29.0+92+42, web.RAABreakdown.Score)
+	assert.Equal(t, 33.0, web.RAABreakdown.PivotingBonus, "a third of the RAA delta of 99 to the most attractive neighbours")
+	assert.Equal(t, []string{"backup", "db"}, web.RAABreakdown.PivotingNeighbours)
+
+	stored := parsedModel.TechnicalAssets["db"].RAABreakdown
+	require.NotNil(t, stored)
+	assert.Equal(t, []types.RAAFactor{
+		{Source: types.RAAOwnRating, Confidentiality: 55, Integrity: 21, Availability: 21},
+		{Source: types.RAAStoredData, DataAssetId: "customers", QuantityFactor: 3, Confidentiality: 21 * 3, Integrity: 8 * 3, Availability: 5},
+	}, stored.Factors)
+	assert.Equal(t, 2.0, stored.Multiplier, "datastore")
+	assert.Equal(t, (97.0+92)*2, stored.Score)
+	assert.Zero(t, stored.PivotingBonus)
+	assert.Empty(t, stored.PivotingNeighbours)
+
+	for id, techAsset := range parsedModel.TechnicalAssets {
+		breakdown := techAsset.RAABreakdown
+		require.NotNil(t, breakdown, id)
+		assert.Equal(t, 163.0, breakdown.Minimum, id)
+		assert.Equal(t, 378.0, breakdown.Maximum, id)
+
+		sum := 0.0
+		for _, factor := range breakdown.Factors {
+			sum += factor.Confidentiality + factor.Integrity + factor.Availability
+		}
+		assert.InDelta(t, breakdown.Score, sum*breakdown.Multiplier, 1e-9, id)
+		raa := (breakdown.Score + breakdown.PivotingBonus - breakdown.Minimum) / (breakdown.Maximum - breakdown.Minimum) * 100
+		assert.InDelta(t, techAsset.RAA, math.Max(raa, 1), 1e-9, id, "the breakdown adds up to the RAA")
+	}
+}
+
+func TestCalculateWithAttractiveness(t *testing.T) {
+	attractiveness := defaultAttractiveness()
+	attractiveness.Confidentiality.Asset = 10 // makes the confidential asset the most attractive
+	parsedModel := &types.ParsedModel{TechnicalAssets: map[string]types.TechnicalAsset{
+		"confidential": {Id: "confidential", Confidentiality: types.StrictlyConfidential},
+		"critical":     {Id: "critical", Integrity: types.MissionCritical, Availability: types.MissionCritical},
+	}}
+
+	calculator, err := raa.NewCalculator(defaultAttractiveness(), "")
+	require.NoError(t, err)
+	calculator.Calculate(parsedModel)
+	assert.Equal(t, 100.0, parsedModel.TechnicalAssets["critical"].RAA)
+	assert.Equal(t, 1.0, parsedModel.TechnicalAssets["confidential"].RAA)
+
+	calculator, err = raa.NewCalculator(attractiveness, "")
+	require.NoError(t, err)
+	calculator.Calculate(parsedModel)
+	assert.Equal(t, 100.0, parsedModel.TechnicalAssets["confidential"].RAA)
+	assert.Equal(t, 1.0, parsedModel.TechnicalAssets["critical"].RAA)
+}
+
+func TestCalculateOutOfScope(t *testing.T) {
+	for _, algorithm := range []string{raa.AttractivenessAlgorithm, raa.PageRankAlgorithm} {
+		t.Run(algorithm, func(t *testing.T) {
+			parsedModel := pivotingModel("b")
+			parsedModel.TechnicalAssets["out"] = types.TechnicalAsset{Id: "out", OutOfScope: true, Confidentiality: types.StrictlyConfidential}
+			calculate(t, parsedModel, algorithm)
+
+			assert.Equal(t, 1.0, parsedModel.TechnicalAssets["out"].RAA)
+			assert.Nil(t, parsedModel.TechnicalAssets["out"].RAABreakdown)
+			assert.NotNil(t, parsedModel.TechnicalAssets["a"].RAABreakdown)
+		})
+	}
+}
@@ -357,7 +357,11 @@ func WriteRisksExcelToFile(parsedModel *types.ParsedModel, filename string) erro
 			err = excel.SetCellValue(sheetName, "C"+strconv.Itoa(excelRow), risk.ExploitationImpact.Title())
 			err = excel.SetCellValue(sheetName, "D"+strconv.Itoa(excelRow), category.STRIDE.Title())
 			err = excel.SetCellValue(sheetName, "E"+strconv.Itoa(excelRow), category.Function.Title())
-			err = excel.SetCellValue(sheetName, "F"+strconv.Itoa(excelRow), "CWE-"+strconv.Itoa(category.CWE))
+			cwe := category.CWE
+			if risk.CWE > 0 {
+				cwe = risk.CWE
+			}
+			err = excel.SetCellValue(sheetName, "F"+strconv.Itoa(excelRow), "CWE-"+strconv.Itoa(cwe))
 			err = excel.SetCellValue(sheetName, "G"+strconv.Itoa(excelRow), category.Title)
 			err = excel.SetCellValue(sheetName, "H"+strconv.Itoa(excelRow), techAsset.Title)
 			err = excel.SetCellValue(sheetName, "I"+strconv.Itoa(excelRow), commLink.Title)
@@ -705,3 +709,94 @@ func removeFormattingTags(content string) interface{} {
 	result = strings.ReplaceAll(strings.ReplaceAll(result, "<u>", ""), "</u>", "")
 	return result
 }
+
+func WriteComponentsExcelToFile(parsedModel *types.ParsedModel, filename string) error {
+	excel := excelize.NewFile()
+	sheetName := parsedModel.Title
+	err := excel.SetDocProps(&excelize.DocProperties{
+		Category:       "Component Inventory",
+		ContentStatus:  "Final",
+		Creator:        parsedModel.Author.Name,
+		Description:    sheetName + " via Threagile",
+		Identifier:     "xlsx",
+		Keywords:       "Component Inventory",
+		LastModifiedBy: parsedModel.Author.Name,
+		Revision:       "0",
+		Subject:        sheetName,
+		Title:          sheetName,
+		Language:       "en-US",
+		Version:        "1.0.0",
+	})
+	if err != nil {
+		return err
+	}
+
+	sheetIndex, _ := excel.NewSheet(sheetName)
+	_ = excel.DeleteSheet("Sheet1")
+	orientation := "landscape"
+	size := 9
+	err = excel.SetPageLayout(sheetName, &excelize.PageLayoutOptions{Orientation: &orientation, Size: &size}) // A4
+	if err != nil {
+		return err
+	}
+
+	columns := []struct {
+		title string
+		width float64
+	}{{"Component", 50}, {"Version", 20}, {"Type", 15}, {"Licenses", 30}, {"Package URL", 60}, {"Technical Assets", 60}}
+	for i, column := range columns {
+		axis := alphabet[i]
+		err = excel.SetCellValue(sheetName, axis+"1", column.title)
+		if err == nil {
+			err = excel.SetColWidth(sheetName, axis, axis, column.width)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write header: %w", err)
+		}
+	}
+
+	styleHeadLeftBold, err := excel.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal:  "left",
+			ShrinkToFit: true,
+			WrapText:    false,
+		},
+		Font: &excelize.Font{
+			Color: "#000000",
+			Size:  14,
+			Bold:  true,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#eeeeee"},
+			Pattern: 1,
+		},
+	})
+	if err == nil {
+		err = excel.SetCellStyle(sheetName, "A1", alphabet[len(columns)-1]+"1", styleHeadLeftBold)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to set cell style: %w", err)
+	}
+
+	for i, usage := range types.ComponentInventory(parsedModel) {
+		technicalAssetTitles := make([]string, 0)
+		for _, id := range usage.TechnicalAssets {
+			technicalAssetTitles = append(technicalAssetTitles, parsedModel.TechnicalAssets[id].Title)
+		}
+		row := strconv.Itoa(i + 2)
+		for j, value := range []string{usage.Title(), usage.Version, usage.Type, strings.Join(usage.Licenses, ", "), usage.PURL, strings.Join(technicalAssetTitles, ", ")} {
+			err = excel.SetCellValue(sheetName, alphabet[j]+row, value)
+			if err != nil {
+				return fmt.Errorf("unable to write row: %w", err)
+			}
+		}
+	}
+
+	excel.SetActiveSheet(sheetIndex)
+	err = excel.SaveAs(filename)
+	if err != nil {
+		return fmt.Errorf("unable to save excel file: %w", err)
+	}
+	return nil
+}
@@ -9,30 +9,60 @@ import (
 	"path/filepath"
 
 	"github.com/threagile/threagile/pkg/common"
+	"github.com/threagile/threagile/pkg/graph"
+	"github.com/threagile/threagile/pkg/kubernetes"
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/otm"
+	"github.com/threagile/threagile/pkg/tickets"
 )
 
 type GenerateCommands struct {
-	DataFlowDiagram     bool
-	DataAssetDiagram    bool
-	RisksJSON           bool
-	TechnicalAssetsJSON bool
-	StatsJSON           bool
-	RisksExcel          bool
-	TagsExcel           bool
-	ReportPDF           bool
+	DataFlowDiagram         bool
+	DataAssetDiagram        bool
+	DataFlowDiagramMermaid  bool
+	DataFlowDiagramPlantUML bool
+	OTM                     bool
+	NetworkPolicies         bool
+	GraphML                 bool
+	Cypher                  bool
+	SQLite                  bool
+	JiraCSV                 bool
+	GitHubIssues            bool
+	RisksJSON               bool
+	TechnicalAssetsJSON     bool
+	StatsJSON               bool
+	ComponentsJSON          bool
+	AttackPathsJSON         bool
+	DataLineageJSON         bool
+	RisksExcel              bool
+	TagsExcel               bool
+	ComponentsExcel         bool
+	ReportPDF               bool
 }
 
 func (c *GenerateCommands) Defaults() *GenerateCommands {
 	*c = GenerateCommands{
-		DataFlowDiagram:     true,
-		DataAssetDiagram:    true,
-		RisksJSON:           true,
-		TechnicalAssetsJSON: true,
-		StatsJSON:           true,
-		RisksExcel:          true,
-		TagsExcel:           true,
-		ReportPDF:           true,
+		DataFlowDiagram:         true,
+		DataAssetDiagram:        true,
+		DataFlowDiagramMermaid:  false,
+		DataFlowDiagramPlantUML: false,
+		OTM:                     false,
+		NetworkPolicies:         false,
+		GraphML:                 false,
+		Cypher:                  false,
+		SQLite:                  false,
+		JiraCSV:                 false,
+		GitHubIssues:            false,
+		RisksJSON:               true,
+		TechnicalAssetsJSON:     true,
+		StatsJSON:               true,
+		ComponentsJSON:          false,
+		AttackPathsJSON:         false,
+		DataLineageJSON:         false,
+		RisksExcel:              true,
+		TagsExcel:               true,
+		ComponentsExcel:         false,
+		ReportPDF:               true,
 	}
 	return c
 }
@@ -95,6 +125,101 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	// Data-flow Diagram as text based formats
+	if commands.DataFlowDiagramMermaid {
+		progressReporter.Info("Writing data flow diagram mermaid")
+		err := WriteDataFlowDiagramMermaid(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenameMermaid), config.AddModelTitle)
+		if err != nil {
+			return fmt.Errorf("error while writing data flow diagram mermaid: %s", err)
+		}
+	}
+	if commands.DataFlowDiagramPlantUML {
+		progressReporter.Info("Writing data flow diagram plantuml")
+		err := WriteDataFlowDiagramPlantUML(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePlantUML), config.AddModelTitle)
+		if err != nil {
+			return fmt.Errorf("error while writing data flow diagram plantuml: %s", err)
+		}
+	}
+
+	// open threat model json
+	if commands.OTM {
+		progressReporter.Info("Writing open threat model json")
+		err := otm.WriteFile(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.OtmFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing open threat model json: %s", err)
+		}
+	}
+
+	// kubernetes network policies
+	if commands.NetworkPolicies {
+		progressReporter.Info("Writing network policies")
+		mapping, err := kubernetes.LoadPodMapping(config.NetworkPolicyMapping)
+		if err != nil {
+			return err
+		}
+		err = kubernetes.WriteNetworkPolicies(readResult.ParsedModel, mapping, filepath.Join(config.OutputFolder, config.NetworkPoliciesFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing network policies: %s", err)
+		}
+	}
+
+	// model graph as graphml
+	if commands.GraphML {
+		progressReporter.Info("Writing graphml")
+		err := graph.WriteGraphML(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.GraphMLFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing graphml: %s", err)
+		}
+	}
+
+	// model graph as cypher script
+	if commands.Cypher {
+		progressReporter.Info("Writing cypher script")
+		err := graph.WriteCypher(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.CypherFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing cypher script: %s", err)
+		}
+	}
+
+	// model and risks as sqlite database
+	if commands.SQLite {
+		progressReporter.Info("Writing sqlite database")
+		modelHash, err := hashModelFile(config.InputFile)
+		if err != nil {
+			return err
+		}
+		err = WriteSQLite(readResult.ParsedModel, modelHash, filepath.Join(config.OutputFolder, config.SQLiteFilename), config.SQLiteAppend)
+		if err != nil {
+			return fmt.Errorf("error while writing sqlite database: %s", err)
+		}
+	}
+
+	// unchecked risks as tickets
+	if commands.JiraCSV || commands.GitHubIssues {
+		templates, err := tickets.LoadTemplates(config.TicketTemplate)
+		if err != nil {
+			return err
+		}
+		riskTickets, err := tickets.Tickets(readResult.ParsedModel, templates)
+		if err != nil {
+			return err
+		}
+		if commands.JiraCSV {
+			progressReporter.Info("Writing jira csv")
+			err = tickets.WriteJiraCSV(riskTickets, filepath.Join(config.OutputFolder, config.JiraCSVFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing jira csv: %s", err)
+			}
+		}
+		if commands.GitHubIssues {
+			progressReporter.Info("Writing github issues json")
+			err = tickets.WriteGitHubIssues(riskTickets, filepath.Join(config.OutputFolder, config.GitHubIssuesFilename))
+			if err != nil {
+				return fmt.Errorf("error while writing github issues json: %s", err)
+			}
+		}
+	}
+
 	// risks as risks json
 	if commands.RisksJSON {
 		progressReporter.Info("Writing risks json")
@@ -122,6 +247,33 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
+	// component inventory json
+	if commands.ComponentsJSON {
+		progressReporter.Info("Writing components json")
+		err := WriteComponentsJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonComponentsFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing components json: %s", err)
+		}
+	}
+
+	// attack paths json
+	if commands.AttackPathsJSON {
+		progressReporter.Info("Writing attack paths json")
+		err := WriteAttackPathsJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonAttackPathsFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing attack paths json: %s", err)
+		}
+	}
+
+	// data lineage json
+	if commands.DataLineageJSON {
+		progressReporter.Info("Writing data lineage json")
+		err := WriteDataLineageJSON(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.JsonDataLineageFilename))
+		if err != nil {
+			return fmt.Errorf("error while writing data lineage json: %s", err)
+		}
+	}
+
 	// risks Excel
 	if commands.RisksExcel {
 		progressReporter.Info("Writing risks excel")
@@ -140,18 +292,20 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 		}
 	}
 
-	if commands.ReportPDF {
-		// hash the YAML input file
-		f, err := os.Open(config.InputFile)
+	// component inventory Excel
+	if commands.ComponentsExcel {
+		progressReporter.Info("Writing components excel")
+		err := WriteComponentsExcelToFile(readResult.ParsedModel, filepath.Join(config.OutputFolder, config.ExcelComponentsFilename))
 		if err != nil {
 			return err
 		}
-		defer func() { _ = f.Close() }()
-		hasher := sha256.New()
-		if _, err := io.Copy(hasher, f); err != nil {
+	}
+
+	if commands.ReportPDF {
+		modelHash, err := hashModelFile(config.InputFile)
+		if err != nil {
 			return err
 		}
-		modelHash := hex.EncodeToString(hasher.Sum(nil))
 		// report PDF
 		progressReporter.Info("Writing report pdf")
 
@@ -161,7 +315,7 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 			filepath.Join(config.OutputFolder, config.DataFlowDiagramFilenamePNG),
 			filepath.Join(config.OutputFolder, config.DataAssetDiagramFilenamePNG),
 			config.InputFile,
-			config.SkipRiskRules,
+			model.SkippedRiskRules(*config),
 			config.BuildTimestamp,
 			modelHash,
 			readResult.IntroTextRAA,
@@ -176,6 +330,20 @@ func Generate(config *common.Config, readResult *model.ReadResult, commands *Gen
 	return nil
 }
 
+// hashModelFile returns the sha256 of the YAML input file
+func hashModelFile(filename string) (string, error) {
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 type progressReporter interface {
 	Info(a ...any)
 	Warn(a ...any)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/threagile/threagile/pkg/attackpath"
 	"github.com/threagile/threagile/pkg/security/types"
 )
 
@@ -43,6 +44,18 @@ func WriteTechnicalAssetsJSON(parsedModel *types.ParsedModel, filename string) e
 	return nil
 }
 
+func WriteComponentsJSON(parsedModel *types.ParsedModel, filename string) error {
+	jsonBytes, err := json.Marshal(types.ComponentInventory(parsedModel))
+	if err != nil {
+		return fmt.Errorf("failed to marshal components to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write components to JSON file: %w", err)
+	}
+	return nil
+}
+
 func WriteStatsJSON(parsedModel *types.ParsedModel, filename string) error {
 	jsonBytes, err := json.Marshal(types.OverallRiskStatistics(parsedModel))
 	if err != nil {
@@ -54,3 +67,27 @@ func WriteStatsJSON(parsedModel *types.ParsedModel, filename string) error {
 	}
 	return nil
 }
+
+func WriteAttackPathsJSON(parsedModel *types.ParsedModel, filename string) error {
+	jsonBytes, err := json.Marshal(attackpath.FindPaths(parsedModel))
+	if err != nil {
+		return fmt.Errorf("failed to marshal attack paths to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write attack paths to JSON file: %w", err)
+	}
+	return nil
+}
+
+func WriteDataLineageJSON(parsedModel *types.ParsedModel, filename string) error {
+	jsonBytes, err := json.Marshal(types.DataLineages(parsedModel))
+	if err != nil {
+		return fmt.Errorf("failed to marshal data lineage to JSON: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write data lineage to JSON file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func WriteDataFlowDiagramMermaid(parsedModel *types.ParsedModel, filename string, addModelTitle bool) error {
+	var content strings.Builder
+	if addModelTitle {
+		content.WriteString("---\ntitle: " + mermaidText(parsedModel.Title) + "\n---\n")
+	}
+	direction := "TB"
+	if parsedModel.DiagramTweakLayoutLeftToRight {
+		direction = "LR"
+	}
+	content.WriteString("flowchart " + direction + "\n")
+
+	layout := newDiagramLayout(parsedModel)
+	var styles strings.Builder
+	writeMermaidContainer(&content, &styles, parsedModel, layout, "", "  ")
+
+	// Data Flows (Technical Communication Links) ===============================================================================
+	linkIndex := 0
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, dataFlow := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+			arrow := "-->"
+			if determineArrowLineStyle(dataFlow) != "solid" {
+				arrow = "-.->"
+			}
+			label := ""
+			if !parsedModel.DiagramTweakSuppressEdgeLabels {
+				label = `|"` + mermaidText(diagramLinkLabel(dataFlow)) + `"|`
+			}
+			content.WriteString("  " + mermaidId("a", dataFlow.SourceId) + " " + arrow + label + " " + mermaidId("a", dataFlow.TargetId) + "\n")
+			styles.WriteString("  linkStyle " + strconv.Itoa(linkIndex) + " stroke:" + determineArrowColor(dataFlow, parsedModel) +
+				",stroke-width:" + diagramPixels(determineArrowPenWidth(dataFlow, parsedModel)) + "\n")
+			linkIndex++
+		}
+	}
+
+	content.WriteString(styles.String())
+
+	err := os.WriteFile(filename, []byte(content.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write data flow diagram to mermaid file: %w", err)
+	}
+	return nil
+}
+
+func writeMermaidContainer(content *strings.Builder, styles *strings.Builder, parsedModel *types.ParsedModel, layout *diagramLayout, boundaryId string, indent string) {
+	for _, nestedId := range layout.nestedBoundaries[boundaryId] {
+		trustBoundary := parsedModel.TrustBoundaries[nestedId]
+		nodeId := mermaidId("tb", trustBoundary.Id)
+		content.WriteString(indent + "subgraph " + nodeId + `["` + mermaidText(trustBoundary.Title) + " (" + trustBoundary.Type.String() + `)"]` + "\n")
+		writeMermaidContainer(content, styles, parsedModel, layout, nestedId, indent+"  ")
+		content.WriteString(indent + "end\n")
+
+		fillColor, borderStyle := diagramTrustBoundaryColors(parsedModel, trustBoundary)
+		dashArray := "5 5"
+		if borderStyle == "dotted" {
+			dashArray = "2 2"
+		}
+		styles.WriteString("  style " + nodeId + " fill:" + fillColor + ",stroke:" + RgbHexColorTwilight() + ",stroke-width:3px,stroke-dasharray:" + dashArray + "\n")
+	}
+
+	for _, runtimeId := range layout.runtimesInBoundary[boundaryId] {
+		sharedRuntime := parsedModel.SharedRuntimes[runtimeId]
+		nodeId := mermaidId("sr", sharedRuntime.Id)
+		content.WriteString(indent + "subgraph " + nodeId + `["` + mermaidText(sharedRuntime.Title) + ` (shared runtime)"]` + "\n")
+		for _, assetId := range layout.assetsInRuntime[runtimeId] {
+			writeMermaidTechAssetNode(content, styles, parsedModel, parsedModel.TechnicalAssets[assetId], indent+"  ")
+		}
+		content.WriteString(indent + "end\n")
+		styles.WriteString("  style " + nodeId + " fill:#FFFFFF,stroke:" + Gray + ",stroke-width:2px\n")
+	}
+
+	for _, assetId := range layout.assetsInBoundary[boundaryId] {
+		writeMermaidTechAssetNode(content, styles, parsedModel, parsedModel.TechnicalAssets[assetId], indent)
+	}
+}
+
+func writeMermaidTechAssetNode(content *strings.Builder, styles *strings.Builder, parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, indent string) {
+	nodeId := mermaidId("a", technicalAsset.Id)
+	label := `"<small>` + mermaidText(technicalAsset.Technology.String()) + `</small><br/><b>` + mermaidText(technicalAsset.Title) + `</b><br/><small>` + diagramRAALabel(technicalAsset) + `</small>"`
+
+	var shape string
+	switch technicalAsset.Type {
+	case types.ExternalEntity:
+		shape = "[" + label + "]"
+	case types.Process:
+		shape = "([" + label + "])"
+	case types.Datastore:
+		shape = "[(" + label + ")]"
+	}
+	if technicalAsset.UsedAsClientByHuman {
+		shape = "{{" + label + "}}"
+	}
+	content.WriteString(indent + nodeId + shape + "\n")
+
+	style := "fill:" + determineShapeFillColor(technicalAsset, parsedModel) +
+		",stroke:" + determineShapeBorderColor(technicalAsset, parsedModel) +
+		",stroke-width:" + diagramPixels(determineShapeBorderPenWidth(technicalAsset, parsedModel)) +
+		",color:" + determineTechnicalAssetLabelColor(technicalAsset, parsedModel)
+	if determineShapeBorderLineStyle(technicalAsset) == "dotted" {
+		style += ",stroke-dasharray:2 2"
+	}
+	styles.WriteString("  style " + nodeId + " " + style + "\n")
+}
+
+// mermaidId makes a node id of the prefix and the id of a model element, letters and digits are kept and
+// all other characters (like "-" and ".", which mermaid reads as part of an edge) are escaped by their code
+func mermaidId(prefix string, id string) string {
+	var result strings.Builder
+	result.WriteString(prefix + "_")
+	for _, character := range id {
+		if character < unicode.MaxASCII && (unicode.IsLetter(character) || unicode.IsDigit(character)) {
+			result.WriteRune(character)
+		} else {
+			result.WriteString("_" + strconv.FormatInt(int64(character), 16) + "_")
+		}
+	}
+	return result.String()
+}
+
+func mermaidText(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, `"`, "#quot;")
+}
+
+// diagramLayout groups the technical assets of a model into (nested) trust boundaries and shared runtimes,
+// as required by text based diagram formats where each node can only be placed into one group.
+// A shared runtime is placed into the trust boundary directly containing all of its assets (if any),
+// assets of a shared runtime spanning multiple trust boundaries stay within their trust boundaries.
+type diagramLayout struct {
+	nestedBoundaries   map[string][]string // trust boundary id ("" for top level) -> nested trust boundary ids
+	runtimesInBoundary map[string][]string // trust boundary id ("" for top level) -> shared runtime ids
+	assetsInBoundary   map[string][]string // trust boundary id ("" for top level) -> technical asset ids
+	assetsInRuntime    map[string][]string // shared runtime id -> technical asset ids
+}
+
+func newDiagramLayout(parsedModel *types.ParsedModel) *diagramLayout {
+	layout := &diagramLayout{
+		nestedBoundaries:   make(map[string][]string),
+		runtimesInBoundary: make(map[string][]string),
+		assetsInBoundary:   make(map[string][]string),
+		assetsInRuntime:    make(map[string][]string),
+	}
+
+	for _, id := range types.SortedKeysOfTrustBoundaries(parsedModel) {
+		trustBoundary := parsedModel.TrustBoundaries[id]
+		if len(trustBoundary.TechnicalAssetsInside) == 0 && len(trustBoundary.TrustBoundariesNested) == 0 {
+			continue
+		}
+		parentId := trustBoundary.ParentTrustBoundaryID(parsedModel)
+		layout.nestedBoundaries[parentId] = append(layout.nestedBoundaries[parentId], id)
+	}
+
+	runtimeIds := types.SortedKeysOfSharedRuntime(parsedModel)
+	homeBoundaryOfRuntime := make(map[string]string)
+	for _, id := range runtimeIds {
+		homeBoundary := ""
+		for i, assetId := range parsedModel.SharedRuntimes[id].TechnicalAssetsRunning {
+			boundaryId := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[assetId].Id
+			if i == 0 {
+				homeBoundary = boundaryId
+			} else if homeBoundary != boundaryId {
+				homeBoundary = ""
+				break
+			}
+		}
+		homeBoundaryOfRuntime[id] = homeBoundary
+	}
+
+	for _, assetId := range parsedModel.SortedTechnicalAssetIDs() {
+		boundaryId := parsedModel.DirectContainingTrustBoundaryMappedByTechnicalAssetId[assetId].Id
+		placed := false
+		for _, runtimeId := range runtimeIds {
+			if homeBoundaryOfRuntime[runtimeId] == boundaryId && contains(parsedModel.SharedRuntimes[runtimeId].TechnicalAssetsRunning, assetId) {
+				layout.assetsInRuntime[runtimeId] = append(layout.assetsInRuntime[runtimeId], assetId)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			layout.assetsInBoundary[boundaryId] = append(layout.assetsInBoundary[boundaryId], assetId)
+		}
+	}
+
+	for _, runtimeId := range runtimeIds {
+		if len(layout.assetsInRuntime[runtimeId]) > 0 {
+			homeBoundary := homeBoundaryOfRuntime[runtimeId]
+			layout.runtimesInBoundary[homeBoundary] = append(layout.runtimesInBoundary[homeBoundary], runtimeId)
+		}
+	}
+
+	for _, ids := range layout.assetsInBoundary {
+		sort.Strings(ids)
+	}
+	return layout
+}
+
+// same colors as used for the graphviz clusters
+func diagramTrustBoundaryColors(parsedModel *types.ParsedModel, trustBoundary types.TrustBoundary) (fillColor string, borderStyle string) {
+	fillColor, borderStyle = "#FAFAFA", "dashed"
+	if len(trustBoundary.ParentTrustBoundaryID(parsedModel)) > 0 {
+		fillColor = "#F1F1F1"
+	}
+	if trustBoundary.Type == types.NetworkPolicyNamespaceIsolation {
+		fillColor = "#DFF4FF"
+	}
+	if trustBoundary.Type == types.ExecutionEnvironment {
+		fillColor, borderStyle = "#FFFFF0", "dotted"
+	}
+	return fillColor, borderStyle
+}
+
+func diagramLinkLabel(dataFlow types.CommunicationLink) string {
+	return dataFlow.Protocol.String() + " / " + dataFlow.Authentication.String()
+}
+
+func diagramRAALabel(technicalAsset types.TechnicalAsset) string {
+	if technicalAsset.OutOfScope {
+		return "RAA: out of scope"
+	}
+	return "RAA: " + fmt.Sprintf("%.0f", technicalAsset.RAA) + " %"
+}
+
+func diagramPixels(penWidth string) string {
+	width, err := strconv.ParseFloat(penWidth, 64)
+	if err != nil {
+		return "2px"
+	}
+	return fmt.Sprintf("%.1fpx", width)
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteDataFlowDiagramMermaid(t *testing.T) {
+	parsedModel := createDiagramModel(t)
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.mmd")
+
+	err := WriteDataFlowDiagramMermaid(parsedModel, filename, true)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(content)
+
+	assert.Contains(t, diagram, "title: Diagram Test\n")
+	assert.Contains(t, diagram, "flowchart TB\n")
+
+	// nested trust boundaries
+	assert.Contains(t, diagram, "  subgraph tb_cloud_2d_network[\"Cloud Network (network-cloud-provider)\"]\n"+
+		"    subgraph tb_app_2d_namespace[\"App Namespace (execution-environment)\"]\n")
+	assert.Contains(t, diagram, "  style tb_cloud_2d_network fill:")
+	assert.Contains(t, diagram, "  style tb_app_2d_namespace fill:")
+
+	// shared runtime grouping its assets inside the nested trust boundary
+	assert.Contains(t, diagram, "      subgraph sr_app_2d_server[\"App Server (shared runtime)\"]\n"+
+		"        a_backend_2d_app([")
+	assert.Contains(t, diagram, "        a_web_2d_app_2d_v1([")
+	assert.Contains(t, diagram, "  style sr_app_2d_server fill:#FFFFFF")
+
+	// assets outside of any trust boundary
+	assert.Contains(t, diagram, "\n  a_user_2d_browser{{")
+	assert.Contains(t, diagram, "    a_db[(")
+
+	// data flows with protocol and authentication labels
+	assert.Contains(t, diagram, "  a_user_2d_browser -->|\"https / credentials\"| a_web_2d_app_2d_v1\n")
+	assert.Contains(t, diagram, "  a_web_2d_app_2d_v1 -->|\"jdbc-encrypted / client-certificate\"| a_db\n")
+	assert.Contains(t, diagram, "  linkStyle 0 stroke:")
+	assert.Contains(t, diagram, "  linkStyle 1 stroke:")
+	assert.NotContains(t, diagram, "linkStyle 2 ")
+}
+
+func TestWriteDataFlowDiagramMermaidSuppressEdgeLabels(t *testing.T) {
+	parsedModel := createDiagramModel(t)
+	parsedModel.DiagramTweakSuppressEdgeLabels = true
+	parsedModel.DiagramTweakLayoutLeftToRight = true
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.mmd")
+
+	err := WriteDataFlowDiagramMermaid(parsedModel, filename, false)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(content)
+
+	assert.NotContains(t, diagram, "title:")
+	assert.Contains(t, diagram, "flowchart LR\n")
+	assert.Contains(t, diagram, "  a_user_2d_browser --> a_web_2d_app_2d_v1\n")
+	assert.NotContains(t, diagram, "https / credentials")
+}
+
+type mermaidIdTest struct {
+	prefix   string
+	id       string
+	expected string
+}
+
+func TestMermaidId(t *testing.T) {
+	testCases := map[string]mermaidIdTest{
+		"plain": {
+			prefix:   "a",
+			id:       "webserver1",
+			expected: "a_webserver1",
+		},
+		"dash": {
+			prefix:   "a",
+			id:       "web-server",
+			expected: "a_web_2d_server",
+		},
+		"dot": {
+			prefix:   "tb",
+			id:       "app.namespace",
+			expected: "tb_app_2e_namespace",
+		},
+		"underscore is escaped to keep ids unique": {
+			prefix:   "a",
+			id:       "web_2d_server",
+			expected: "a_web_5f_2d_5f_server",
+		},
+		"non ascii": {
+			prefix:   "sr",
+			id:       "laufzeitumgebung-ä",
+			expected: "sr_laufzeitumgebung_2d__e4_",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, mermaidId(testCase.prefix, testCase.id))
+		})
+	}
+}
+
+// createDiagramModel creates a model with a trust boundary nested into another one, a shared runtime
+// within the nested trust boundary and ids containing hyphens
+func createDiagramModel(t *testing.T) *types.ParsedModel {
+	modelInput := &input.Model{
+		Title:               "Diagram Test",
+		BusinessCriticality: "important",
+		DataAssets: map[string]input.DataAsset{
+			"Customer Data": {
+				ID:              "customer-data",
+				Usage:           "business",
+				Quantity:        "many",
+				Confidentiality: "confidential",
+				Integrity:       "critical",
+				Availability:    "operational",
+			},
+		},
+		TechnicalAssets: map[string]input.TechnicalAsset{
+			"User Browser": createDiagramTechnicalAsset("user-browser", "external-entity", "browser", true, map[string]input.CommunicationLink{
+				"Web Traffic": {
+					Target:         "web-app-v1",
+					Protocol:       "https",
+					Authentication: "credentials",
+					Authorization:  "enduser-identity-propagation",
+					Usage:          "business",
+					DataAssetsSent: []string{"customer-data"},
+				},
+			}),
+			"Web App": createDiagramTechnicalAsset("web-app-v1", "process", "web-server", false, map[string]input.CommunicationLink{
+				"Database Traffic": {
+					Target:             "db",
+					Protocol:           "jdbc-encrypted",
+					Authentication:     "client-certificate",
+					Authorization:      "technical-user",
+					Usage:              "business",
+					DataAssetsSent:     []string{"customer-data"},
+					DataAssetsReceived: []string{"customer-data"},
+				},
+			}),
+			"Backend App": createDiagramTechnicalAsset("backend-app", "process", "application-server", false, nil),
+			"Database":    createDiagramTechnicalAsset("db", "datastore", "database", false, nil),
+		},
+		TrustBoundaries: map[string]input.TrustBoundary{
+			"Cloud Network": {
+				ID:                    "cloud-network",
+				Type:                  "network-cloud-provider",
+				TechnicalAssetsInside: []string{"db"},
+				TrustBoundariesNested: []string{"app-namespace"},
+			},
+			"App Namespace": {
+				ID:                    "app-namespace",
+				Type:                  "execution-environment",
+				TechnicalAssetsInside: []string{"web-app-v1", "backend-app"},
+			},
+		},
+		SharedRuntimes: map[string]input.SharedRuntime{
+			"App Server": {
+				ID:                     "app-server",
+				TechnicalAssetsRunning: []string{"web-app-v1", "backend-app"},
+			},
+		},
+	}
+
+	parsedModel, err := model.ParseModel(modelInput, nil, nil)
+	require.NoError(t, err)
+	return parsedModel
+}
+
+func createDiagramTechnicalAsset(id string, assetType string, technology string, usedAsClientByHuman bool, communicationLinks map[string]input.CommunicationLink) input.TechnicalAsset {
+	return input.TechnicalAsset{
+		ID:                  id,
+		Usage:               "business",
+		Type:                assetType,
+		Size:                "application",
+		Technology:          technology,
+		Machine:             "virtual",
+		Encryption:          "none",
+		Confidentiality:     "confidential",
+		Integrity:           "critical",
+		Availability:        "operational",
+		UsedAsClientByHuman: usedAsClientByHuman,
+		DataAssetsProcessed: []string{"customer-data"},
+		CommunicationLinks:  communicationLinks,
+	}
+}
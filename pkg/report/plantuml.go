@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func WriteDataFlowDiagramPlantUML(parsedModel *types.ParsedModel, filename string, addModelTitle bool) error {
+	var content strings.Builder
+	content.WriteString("@startuml\n")
+	if addModelTitle {
+		content.WriteString("title " + plantUMLText(parsedModel.Title) + "\n")
+	}
+	if parsedModel.DiagramTweakLayoutLeftToRight {
+		content.WriteString("left to right direction\n")
+	}
+	content.WriteString("skinparam defaultFontName Verdana\n")
+	content.WriteString("skinparam shadowing false\n")
+	content.WriteString("skinparam ArrowFontColor " + Gray + "\n\n")
+
+	layout := newDiagramLayout(parsedModel)
+	writePlantUMLContainer(&content, parsedModel, layout, "", "")
+	content.WriteString("\n")
+
+	// Data Flows (Technical Communication Links) ===============================================================================
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, dataFlow := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+			arrowStyle := determineArrowColor(dataFlow, parsedModel)
+			if lineStyle := determineArrowLineStyle(dataFlow); lineStyle != "solid" {
+				arrowStyle += "," + lineStyle
+			}
+			content.WriteString("a" + hash(dataFlow.SourceId) + " -[" + arrowStyle + "]-> a" + hash(dataFlow.TargetId))
+			if !parsedModel.DiagramTweakSuppressEdgeLabels {
+				content.WriteString(" : " + plantUMLText(diagramLinkLabel(dataFlow)))
+			}
+			content.WriteString("\n")
+		}
+	}
+	content.WriteString("@enduml\n")
+
+	err := os.WriteFile(filename, []byte(content.String()), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write data flow diagram to plantuml file: %w", err)
+	}
+	return nil
+}
+
+func writePlantUMLContainer(content *strings.Builder, parsedModel *types.ParsedModel, layout *diagramLayout, boundaryId string, indent string) {
+	for _, nestedId := range layout.nestedBoundaries[boundaryId] {
+		trustBoundary := parsedModel.TrustBoundaries[nestedId]
+		element := "package"
+		switch trustBoundary.Type {
+		case types.NetworkCloudProvider:
+			element = "cloud"
+		case types.ExecutionEnvironment:
+			element = "frame"
+		}
+		fillColor, borderStyle := diagramTrustBoundaryColors(parsedModel, trustBoundary)
+		content.WriteString(indent + element + ` "` + plantUMLText(trustBoundary.Title) + `\n(` + trustBoundary.Type.String() + `)" as tb` + hash(trustBoundary.Id) +
+			" " + fillColor + ";line:" + RgbHexColorTwilight() + ";line." + borderStyle + " {\n")
+		writePlantUMLContainer(content, parsedModel, layout, nestedId, indent+"  ")
+		content.WriteString(indent + "}\n")
+	}
+
+	for _, runtimeId := range layout.runtimesInBoundary[boundaryId] {
+		sharedRuntime := parsedModel.SharedRuntimes[runtimeId]
+		content.WriteString(indent + `node "` + plantUMLText(sharedRuntime.Title) + `\n(shared runtime)" as sr` + hash(sharedRuntime.Id) + " #FFFFFF;line:" + Gray + " {\n")
+		for _, assetId := range layout.assetsInRuntime[runtimeId] {
+			writePlantUMLTechAssetNode(content, parsedModel, parsedModel.TechnicalAssets[assetId], indent+"  ")
+		}
+		content.WriteString(indent + "}\n")
+	}
+
+	for _, assetId := range layout.assetsInBoundary[boundaryId] {
+		writePlantUMLTechAssetNode(content, parsedModel, parsedModel.TechnicalAssets[assetId], indent)
+	}
+}
+
+func writePlantUMLTechAssetNode(content *strings.Builder, parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, indent string) {
+	var element string
+	switch technicalAsset.Type {
+	case types.ExternalEntity:
+		element = "rectangle"
+	case types.Process:
+		element = "usecase"
+	case types.Datastore:
+		element = "database"
+	}
+	if technicalAsset.UsedAsClientByHuman {
+		element = "hexagon"
+	}
+
+	label := "<size:11>" + plantUMLText(technicalAsset.Technology.String()) + `</size>\n` +
+		"<color:" + determineTechnicalAssetLabelColor(technicalAsset, parsedModel) + ">**" + plantUMLText(technicalAsset.Title) + `**</color>\n` +
+		"<size:11>" + diagramRAALabel(technicalAsset) + "</size>"
+	style := determineShapeFillColor(technicalAsset, parsedModel) + ";line:" + determineShapeBorderColor(technicalAsset, parsedModel)
+	if lineStyle := determineShapeBorderLineStyle(technicalAsset); lineStyle != "solid" {
+		style += ";line." + lineStyle
+	}
+	content.WriteString(indent + element + ` "` + label + `" as a` + hash(technicalAsset.Id) + " " + style + "\n")
+}
+
+func plantUMLText(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, `"`, "'")
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDataFlowDiagramPlantUML(t *testing.T) {
+	parsedModel := createDiagramModel(t)
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.puml")
+
+	err := WriteDataFlowDiagramPlantUML(parsedModel, filename, true)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(content)
+
+	assert.Contains(t, diagram, "@startuml\ntitle Diagram Test\n")
+	assert.Contains(t, diagram, "@enduml\n")
+	assert.NotContains(t, diagram, "left to right direction")
+
+	// nested trust boundaries
+	assert.Contains(t, diagram, `cloud "Cloud Network\n(network-cloud-provider)" as tb`+hash("cloud-network")+" ")
+	assert.Contains(t, diagram, "\n  "+`frame "App Namespace\n(execution-environment)" as tb`+hash("app-namespace")+" ")
+
+	// shared runtime grouping its assets inside the nested trust boundary
+	assert.Contains(t, diagram, "\n    "+`node "App Server\n(shared runtime)" as sr`+hash("app-server")+" #FFFFFF")
+	assert.Contains(t, diagram, `**Web App**</color>\n<size:11>`)
+	assert.Contains(t, diagram, "\n      usecase \"")
+	assert.Contains(t, diagram, "\" as a"+hash("web-app-v1")+" ")
+	assert.Contains(t, diagram, "\" as a"+hash("backend-app")+" ")
+
+	// assets outside of any trust boundary and within the outer trust boundary
+	assert.Contains(t, diagram, "\nhexagon \"")
+	assert.Contains(t, diagram, "\n  database \"")
+
+	// data flows with protocol and authentication labels
+	assert.Contains(t, diagram, "a"+hash("user-browser")+" -[")
+	assert.Contains(t, diagram, "]-> a"+hash("web-app-v1")+" : https / credentials\n")
+	assert.Contains(t, diagram, "]-> a"+hash("db")+" : jdbc-encrypted / client-certificate\n")
+}
+
+func TestWriteDataFlowDiagramPlantUMLSuppressEdgeLabels(t *testing.T) {
+	parsedModel := createDiagramModel(t)
+	parsedModel.DiagramTweakSuppressEdgeLabels = true
+	parsedModel.DiagramTweakLayoutLeftToRight = true
+	filename := filepath.Join(t.TempDir(), "data-flow-diagram.puml")
+
+	err := WriteDataFlowDiagramPlantUML(parsedModel, filename, false)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	diagram := string(content)
+
+	assert.NotContains(t, diagram, "title ")
+	assert.Contains(t, diagram, "left to right direction\n")
+	assert.Contains(t, diagram, "]-> a"+hash("web-app-v1")+"\n")
+	assert.NotContains(t, diagram, "https / credentials")
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+	"github.com/threagile/threagile/pkg/attackpath"
 	"github.com/threagile/threagile/pkg/docs"
 	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/security/risks"
@@ -95,6 +96,7 @@ func (r *pdfReporter) WriteReportPDF(reportFilename string,
 	r.createAssignmentByFunction(model)
 	r.createRAA(model, introTextRAA)
 	r.embedDataRiskMapping(dataAssetDiagramFilenamePNG, tempFolder)
+	r.createAttackPaths(model)
 	//createDataRiskQuickWins()
 	r.createOutOfScopeAssets(model)
 	r.createModelFailures(model)
@@ -314,6 +316,12 @@ func (r *pdfReporter) createTableOfContents(parsedModel *types.ParsedModel) {
 	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
 	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
 
+	y += 6
+	r.pdf.Text(11, y, "    "+"Attack Paths")
+	r.pdf.Text(175, y, "{attack-paths}")
+	r.pdf.Line(15.6, y+1.3, 11+171.5, y+1.3)
+	r.pdf.Link(10, y-5, 172.5, 6.5, r.pdf.AddLink())
+
 	/*
 		y += 6
 		assets := "assets"
@@ -1405,6 +1413,160 @@ func (r *pdfReporter) createOutOfScopeAssets(parsedModel *types.ParsedModel) {
 	r.pdf.SetDashPattern([]float64{}, 0)
 }
 
+func (r *pdfReporter) createAttackPaths(parsedModel *types.ParsedModel) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.SetTextColor(0, 0, 0)
+	chapTitle := "Attack Paths"
+	r.addHeadline(chapTitle, false)
+	r.defineLinkTarget("{attack-paths}")
+	r.currentChapterTitleBreadcrumb = chapTitle
+
+	result := attackpath.FindPaths(parsedModel)
+	html := r.pdf.HTMLBasicNew()
+	html.Write(5, "This chapter lists the most likely attack paths from the entry points (internet-facing technical assets, "+
+		"assets used as client by humans and assets tagged as <b>"+attackpath.EntryPointTag+"</b>) along the communication links "+
+		"to the technical assets processing or storing strictly-confidential or mission-critical data assets. "+
+		"Each hop is weighted by the authentication and encryption of the link, crossed network trust boundaries and "+
+		"the risks still open on the target. The likelihood of a path is the product of the likelihoods of its hops, "+
+		"it is meant to rank the paths and not as absolute probability. For each data asset the most likely paths of up to "+
+		strconv.Itoa(attackpath.MaxPathsPerDataAsset)+" entry points are shown.<br>")
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdfColorGray()
+	html.Write(5, "Data asset paragraphs are clickable and link to the corresponding chapter.")
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdfColorBlack()
+
+	if len(result.DataAssetIds) == 0 {
+		r.pdfColorGray()
+		html.Write(5, "<br><br>No data assets are strictly-confidential or mission-critical.")
+		r.pdfColorBlack()
+		return
+	}
+
+	for _, dataAssetId := range result.DataAssetIds {
+		dataAsset := parsedModel.DataAssets[dataAssetId]
+		if r.pdf.GetY() > 250 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		} else {
+			html.Write(5, "<br><br>")
+		}
+		posY := r.pdf.GetY()
+		r.pdfColorDataAssets()
+		html.Write(5, "<b>"+uni(dataAsset.Title)+"</b>")
+		r.pdf.Link(9, posY, 190, r.pdf.GetY()-posY+4, r.tocLinkIdByAssetId[dataAsset.Id])
+		r.pdfColorBlack()
+
+		paths := result.PathsTo(dataAssetId)
+		if len(paths) == 0 {
+			r.pdfColorGray()
+			html.Write(5, "<br>No path from any entry point.")
+			r.pdfColorBlack()
+			continue
+		}
+		for _, path := range paths {
+			titles := make([]string, 0)
+			for _, id := range path.TechnicalAssetIds {
+				titles = append(titles, uni(parsedModel.TechnicalAssets[id].Title))
+			}
+			html.Write(5, "<br><b>"+fmt.Sprintf("%.1f", path.Likelihood*100)+" %</b>: "+strings.Join(titles, " > "))
+			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdfColorGray()
+			html.Write(5, "<br>        entry point: "+fmt.Sprintf("%.0f", path.EntryLikelihood*100)+" %")
+			for _, hop := range path.Hops {
+				details := []string{hop.Protocol, hop.Authentication}
+				if hop.Encrypted {
+					details = append(details, "encrypted")
+				}
+				if hop.CrossesTrustBoundary {
+					details = append(details, "crossing trust boundary")
+				}
+				if hop.OpenRisks == 1 {
+					details = append(details, "1 open risk on target")
+				} else if hop.OpenRisks > 1 {
+					details = append(details, strconv.Itoa(hop.OpenRisks)+" open risks on target")
+				}
+				html.Write(5, "<br>        "+uni(hop.LinkTitle)+" ("+strings.Join(details, ", ")+"): "+fmt.Sprintf("%.0f", hop.Likelihood*100)+" %")
+			}
+			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdfColorBlack()
+		}
+	}
+}
+
+// createRAABreakdown writes the table of the factors contributing to the RAA of a technical asset
+func (r *pdfReporter) createRAABreakdown(parsedModel *types.ParsedModel, breakdown *types.RAABreakdown) {
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	r.pdf.Ln(-1)
+	r.pdf.Ln(4)
+	if r.pdf.GetY() > 260 { // 260 only for major titles (to avoid "Schusterjungen"), for the rest attributes 270
+		r.pageBreak()
+		r.pdf.SetY(36)
+	}
+	html := r.pdf.HTMLBasicNew()
+	r.pdfColorBlack()
+	r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+	r.pdf.CellFormat(190, 6, "RAA Breakdown", "0", 0, "", false, 0, "")
+	r.pdf.Ln(-1)
+	r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+	r.pdfColorGray()
+	explanation := fmt.Sprintf("The attacker attractiveness score of %.1f is the sum of the factors below multiplied by %.2f "+
+		"(by technology, type and multi-tenancy).", breakdown.Score, breakdown.Multiplier)
+	if breakdown.PivotingBonus > 0 {
+		neighbours := make([]string, 0)
+		for _, id := range breakdown.PivotingNeighbours {
+			neighbours = append(neighbours, parsedModel.TechnicalAssets[id].Title)
+		}
+		explanation += fmt.Sprintf(" A pivoting bonus of %.1f is added for the communication links to the more attractive %v.",
+			breakdown.PivotingBonus, strings.Join(neighbours, ", "))
+	}
+	if breakdown.Centrality > 0 {
+		explanation += fmt.Sprintf(" Starting from the scores, the weighted PageRank over the communication links is %.4f. "+
+			"The RAA is this centrality relative to the range of %.4f to %.4f of all technical assets.",
+			breakdown.Centrality, breakdown.Minimum, breakdown.Maximum)
+	} else {
+		explanation += fmt.Sprintf(" The RAA is the resulting score relative to the range of %.1f to %.1f of all technical assets.",
+			breakdown.Minimum, breakdown.Maximum)
+	}
+	html.Write(5, uni(explanation))
+	r.pdf.Ln(-1)
+	r.pdf.Ln(-1)
+
+	r.pdf.SetFont("Helvetica", "", fontSizeBody)
+	r.pdfColorGray()
+	r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+	r.pdf.CellFormat(105, 6, "Factor", "0", 0, "", false, 0, "")
+	r.pdf.CellFormat(20, 6, "Quantity", "0", 0, "R", false, 0, "")
+	r.pdf.CellFormat(20, 6, "C", "0", 0, "R", false, 0, "")
+	r.pdf.CellFormat(20, 6, "I", "0", 0, "R", false, 0, "")
+	r.pdf.CellFormat(20, 6, "A", "0", 0, "R", false, 0, "")
+	r.pdf.Ln(-1)
+	for _, factor := range breakdown.Factors {
+		if r.pdf.GetY() > 270 {
+			r.pageBreak()
+			r.pdf.SetY(36)
+		}
+		title := factor.Source
+		if len(factor.DataAssetId) > 0 {
+			title += ": " + parsedModel.DataAssets[factor.DataAssetId].Title
+		}
+		quantity := ""
+		if factor.QuantityFactor > 0 {
+			quantity = fmt.Sprintf("x %.0f", factor.QuantityFactor)
+		}
+		r.pdfColorBlack()
+		r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+		r.pdf.CellFormat(105, 6, uni(title), "0", 0, "", false, 0, "")
+		r.pdfColorGray()
+		r.pdf.CellFormat(20, 6, quantity, "0", 0, "R", false, 0, "")
+		r.pdfColorBlack()
+		r.pdf.CellFormat(20, 6, fmt.Sprintf("%.0f", factor.Confidentiality), "0", 0, "R", false, 0, "")
+		r.pdf.CellFormat(20, 6, fmt.Sprintf("%.0f", factor.Integrity), "0", 0, "R", false, 0, "")
+		r.pdf.CellFormat(20, 6, fmt.Sprintf("%.0f", factor.Availability), "0", 0, "R", false, 0, "")
+		r.pdf.Ln(-1)
+	}
+}
+
 func sortedTechnicalAssetsByRAAAndTitle(parsedModel *types.ParsedModel) []types.TechnicalAsset {
 	assets := make([]types.TechnicalAsset, 0)
 	for _, asset := range parsedModel.TechnicalAssets {
@@ -2495,6 +2657,7 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.ParsedModel) {
 			r.pdfColorGray()
 			r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
 			r.pdf.MultiCell(215, 5, uni(risk.SyntheticId), "0", "0", false)
+			r.writeRiskRatingAdjustment(risk)
 			r.pdf.SetFont("Helvetica", "", fontSizeBody)
 			if len(risk.MostRelevantSharedRuntimeId) > 0 {
 				r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[risk.MostRelevantSharedRuntimeId])
@@ -2512,6 +2675,18 @@ func (r *pdfReporter) createRiskCategories(parsedModel *types.ParsedModel) {
 	}
 }
 
+func (r *pdfReporter) writeRiskRatingAdjustment(risk types.Risk) {
+	if risk.RatingAdjustment == nil {
+		return
+	}
+	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
+	adjustment := risk.RatingAdjustment
+	r.pdfColorGray()
+	r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
+	r.pdf.MultiCell(160, 4, uni("Rating adjusted from "+adjustment.OriginalSeverity.Title()+" severity ("+adjustment.OriginalExploitationLikelihood.Title()+
+		" likelihood with "+adjustment.OriginalExploitationImpact.Title()+" impact): "+strings.Join(adjustment.Reasons, "; ")), "0", "0", false)
+}
+
 func (r *pdfReporter) writeRiskTrackingStatus(parsedModel *types.ParsedModel, risk types.Risk) {
 	uni := r.pdf.UnicodeTranslatorFromDescriptor("")
 	tracking := risk.GetRiskTracking(parsedModel)
@@ -2720,6 +2895,7 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.ParsedModel) {
 				r.pdf.SetFont("Helvetica", "", fontSizeVerySmall)
 				r.pdfColorGray()
 				r.pdf.MultiCell(215, 5, uni(risk.SyntheticId), "0", "0", false)
+				r.writeRiskRatingAdjustment(risk)
 				r.pdf.Link(20, posY, 180, r.pdf.GetY()-posY, r.tocLinkIdByAssetId[risk.CategoryId])
 				r.pdf.SetFont("Helvetica", "", fontSizeBody)
 				r.writeRiskTrackingStatus(parsedModel, risk)
@@ -3026,6 +3202,41 @@ func (r *pdfReporter) createTechnicalAssets(parsedModel *types.ParsedModel) {
 			r.pdf.MultiCell(190, 6, uni(technicalAsset.JustificationOutOfScope), "0", "0", false)
 			r.pdf.Ln(-1)
 		}
+
+		if len(technicalAsset.Components) > 0 {
+			r.pdf.Ln(-1)
+			r.pdf.Ln(4)
+			if r.pdf.GetY() > 260 { // 260 only for major titles (to avoid "Schusterjungen"), for the rest attributes 270
+				r.pageBreak()
+				r.pdf.SetY(36)
+			}
+			r.pdfColorBlack()
+			r.pdf.SetFont("Helvetica", "B", fontSizeBody)
+			r.pdf.CellFormat(190, 6, "Components: "+strconv.Itoa(len(technicalAsset.Components)), "0", 0, "", false, 0, "")
+			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdfColorGray()
+			html.Write(5, "Taken from the SBOM "+uni(technicalAsset.SBOM)+".")
+			r.pdf.Ln(-1)
+			r.pdf.Ln(-1)
+			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			for _, component := range technicalAsset.Components {
+				if r.pdf.GetY() > 270 {
+					r.pageBreak()
+					r.pdf.SetY(36)
+				}
+				r.pdfColorBlack()
+				r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+				r.pdf.CellFormat(110, 6, uni(component.Title()), "0", 0, "", false, 0, "")
+				r.pdfColorGray()
+				r.pdf.CellFormat(35, 6, uni(component.Version), "0", 0, "", false, 0, "")
+				r.pdf.CellFormat(40, 6, uni(strings.Join(component.Licenses, ", ")), "0", 0, "", false, 0, "")
+				r.pdf.Ln(-1)
+			}
+		}
+
+		if technicalAsset.RAABreakdown != nil {
+			r.createRAABreakdown(parsedModel, technicalAsset.RAABreakdown)
+		}
 		r.pdf.Ln(-1)
 
 		if len(technicalAsset.CommunicationLinks) > 0 {
@@ -3652,6 +3863,32 @@ func (r *pdfReporter) createDataAssets(parsedModel *types.ParsedModel) {
 		}
 		r.pdf.MultiCell(145, 6, uni(receivedViaText), "0", "0", false)
 
+		lineage := dataAsset.Lineage(parsedModel)
+		for _, row := range []struct {
+			label string
+			ids   []string
+		}{{"Origin:", lineage.OriginIds}, {"Reaches:", lineage.TechnicalAssetIds}} {
+			if r.pdf.GetY() > 265 {
+				r.pageBreak()
+				r.pdf.SetY(36)
+			}
+			r.pdfColorGray()
+			r.pdf.CellFormat(5, 6, "", "0", 0, "", false, 0, "")
+			r.pdf.CellFormat(40, 6, row.label, "0", 0, "", false, 0, "")
+			r.pdfColorBlack()
+			titles := make([]string, 0)
+			for _, id := range row.ids {
+				titles = append(titles, parsedModel.TechnicalAssets[id].Title)
+			}
+			sort.Strings(titles)
+			text := strings.Join(titles, ", ")
+			if len(titles) == 0 {
+				r.pdfColorGray()
+				text = "none"
+			}
+			r.pdf.MultiCell(145, 6, uni(text), "0", "0", false)
+		}
+
 		/*
 			// where is this data asset at risk (i.e. why)
 			risksByTechAssetId := dataAsset.IdentifiedRisksByResponsibleTechnicalAssetId()
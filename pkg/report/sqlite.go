@@ -0,0 +1,432 @@
+package report
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/threagile/threagile/pkg/security/types"
+
+	_ "modernc.org/sqlite" // pure go sqlite driver
+)
+
+// tables of the sqlite output, all rows belong to an analysis run (several runs of models can be appended to one database)
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		model_hash TEXT NOT NULL,
+		analysis_date TEXT NOT NULL,
+		analysis_time TEXT NOT NULL,
+		model_title TEXT,
+		model_date TEXT,
+		threagile_version TEXT,
+		business_criticality TEXT,
+		UNIQUE (model_hash, analysis_date)
+	)`,
+	`CREATE TABLE IF NOT EXISTS technical_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		title TEXT,
+		description TEXT,
+		usage TEXT,
+		type TEXT,
+		size TEXT,
+		technology TEXT,
+		machine TEXT,
+		internet INTEGER,
+		multi_tenant INTEGER,
+		redundant INTEGER,
+		custom_developed_parts INTEGER,
+		out_of_scope INTEGER,
+		used_as_client_by_human INTEGER,
+		encryption TEXT,
+		owner TEXT,
+		confidentiality TEXT,
+		integrity TEXT,
+		availability TEXT,
+		highest_confidentiality TEXT,
+		highest_integrity TEXT,
+		highest_availability TEXT,
+		raa REAL,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS technical_asset_data_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		technical_asset_id TEXT NOT NULL,
+		data_asset_id TEXT NOT NULL,
+		relation TEXT NOT NULL, -- processed or stored
+		PRIMARY KEY (run_id, technical_asset_id, data_asset_id, relation)
+	)`,
+	`CREATE TABLE IF NOT EXISTS data_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		title TEXT,
+		description TEXT,
+		usage TEXT,
+		quantity TEXT,
+		confidentiality TEXT,
+		integrity TEXT,
+		availability TEXT,
+		origin TEXT,
+		owner TEXT,
+		justification_cia_rating TEXT,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS communication_links (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		source_id TEXT NOT NULL,
+		target_id TEXT NOT NULL,
+		title TEXT,
+		description TEXT,
+		protocol TEXT,
+		authentication TEXT,
+		authorization TEXT,
+		usage TEXT,
+		vpn INTEGER,
+		ip_filtered INTEGER,
+		readonly INTEGER,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS communication_link_data_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		communication_link_id TEXT NOT NULL,
+		data_asset_id TEXT NOT NULL,
+		direction TEXT NOT NULL, -- sent or received
+		PRIMARY KEY (run_id, communication_link_id, data_asset_id, direction)
+	)`,
+	`CREATE TABLE IF NOT EXISTS trust_boundaries (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		parent_id TEXT, -- trust boundary this one is nested in
+		title TEXT,
+		description TEXT,
+		type TEXT,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS trust_boundary_technical_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		trust_boundary_id TEXT NOT NULL,
+		technical_asset_id TEXT NOT NULL,
+		PRIMARY KEY (run_id, trust_boundary_id, technical_asset_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS shared_runtimes (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		title TEXT,
+		description TEXT,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS shared_runtime_technical_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		shared_runtime_id TEXT NOT NULL,
+		technical_asset_id TEXT NOT NULL,
+		PRIMARY KEY (run_id, shared_runtime_id, technical_asset_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS tags (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		element_type TEXT NOT NULL, -- technical_asset, data_asset, communication_link, trust_boundary or shared_runtime
+		element_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (run_id, element_type, element_id, tag)
+	)`,
+	`CREATE TABLE IF NOT EXISTS risk_categories (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		id TEXT NOT NULL,
+		title TEXT,
+		description TEXT,
+		impact TEXT,
+		asvs TEXT,
+		cheat_sheet TEXT,
+		action TEXT,
+		mitigation TEXT,
+		"check" TEXT,
+		detection_logic TEXT,
+		risk_assessment TEXT,
+		false_positives TEXT,
+		function TEXT,
+		stride TEXT,
+		model_failure_possible_reason INTEGER,
+		cwe INTEGER,
+		PRIMARY KEY (run_id, id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS risks (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		synthetic_id TEXT NOT NULL,
+		category_id TEXT NOT NULL,
+		title TEXT,
+		severity TEXT,
+		exploitation_likelihood TEXT,
+		exploitation_impact TEXT,
+		data_breach_probability TEXT,
+		risk_status TEXT, -- status of the risk tracking, unchecked if not tracked
+		cwe INTEGER,
+		most_relevant_technical_asset_id TEXT,
+		most_relevant_data_asset_id TEXT,
+		most_relevant_communication_link_id TEXT,
+		most_relevant_trust_boundary_id TEXT,
+		most_relevant_shared_runtime_id TEXT,
+		PRIMARY KEY (run_id, synthetic_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS risk_data_breach_technical_assets (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		synthetic_risk_id TEXT NOT NULL,
+		technical_asset_id TEXT NOT NULL,
+		PRIMARY KEY (run_id, synthetic_risk_id, technical_asset_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS risk_tracking (
+		run_id INTEGER NOT NULL REFERENCES runs(id),
+		synthetic_risk_id TEXT NOT NULL, -- may contain wildcards
+		status TEXT,
+		justification TEXT,
+		ticket TEXT,
+		date TEXT,
+		checked_by TEXT,
+		PRIMARY KEY (run_id, synthetic_risk_id)
+	)`,
+	`CREATE VIEW IF NOT EXISTS risk_counts AS
+		SELECT runs.id AS run_id, runs.model_title, runs.model_hash, runs.analysis_date, risks.severity, risks.risk_status, count(*) AS count
+		FROM runs JOIN risks ON risks.run_id = runs.id
+		GROUP BY runs.id, risks.severity, risks.risk_status`,
+}
+
+var sqliteRunTables = []string{"technical_assets", "technical_asset_data_assets", "data_assets", "communication_links",
+	"communication_link_data_assets", "trust_boundaries", "trust_boundary_technical_assets", "shared_runtimes",
+	"shared_runtime_technical_assets", "tags", "risk_categories", "risks", "risk_data_breach_technical_assets", "risk_tracking"}
+
+// WriteSQLite stores the model and its risks in a sqlite database. Without appendRun an existing database is replaced,
+// otherwise the analysis is added as a new run (replacing a run of the same model hash on the same day).
+func WriteSQLite(parsedModel *types.ParsedModel, modelHash string, filename string, appendRun bool) error {
+	if !appendRun {
+		if err := os.Remove(filepath.Clean(filename)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to replace sqlite database %q: %w", filename, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", filepath.Clean(filename))
+	if err != nil {
+		return fmt.Errorf("unable to open sqlite database %q: %w", filename, err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to write sqlite database %q: %w", filename, err)
+	}
+	err = writeSQLiteRun(tx, parsedModel, modelHash, time.Now())
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("unable to write sqlite database %q: %w", filename, err)
+	}
+	return tx.Commit()
+}
+
+func writeSQLiteRun(tx *sql.Tx, parsedModel *types.ParsedModel, modelHash string, now time.Time) error {
+	for _, statement := range sqliteSchema {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	analysisDate := now.Format("2006-01-02")
+	var previousRunId int64
+	err := tx.QueryRow(`SELECT id FROM runs WHERE model_hash = ? AND analysis_date = ?`, modelHash, analysisDate).Scan(&previousRunId)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return err
+	default:
+		for _, table := range append(sqliteRunTables, "") {
+			statement := "DELETE FROM " + table + " WHERE run_id = ?"
+			if len(table) == 0 {
+				statement = "DELETE FROM runs WHERE id = ?"
+			}
+			if _, err := tx.Exec(statement, previousRunId); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := tx.Exec(`INSERT INTO runs (model_hash, analysis_date, analysis_time, model_title, model_date, threagile_version, business_criticality)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, modelHash, analysisDate, now.Format(time.RFC3339), parsedModel.Title,
+		parsedModel.Date.Format("2006-01-02"), parsedModel.ThreagileVersion, parsedModel.BusinessCriticality.String())
+	if err != nil {
+		return err
+	}
+	runId, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	insert := func(table string, values ...any) error {
+		placeholders := "?"
+		for range values {
+			placeholders += ", ?"
+		}
+		_, err := tx.Exec("INSERT INTO "+table+" VALUES ("+placeholders+")", append([]any{runId}, values...)...)
+		return err
+	}
+	insertTags := func(elementType string, elementId string, tags []string) error {
+		seen := make(map[string]bool)
+		for _, tag := range tags {
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			if err := insert("tags", elementType, elementId, tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, dataAsset := range parsedModel.DataAssets {
+		err = insert("data_assets", dataAsset.Id, dataAsset.Title, dataAsset.Description, dataAsset.Usage.String(), dataAsset.Quantity.String(),
+			dataAsset.Confidentiality.String(), dataAsset.Integrity.String(), dataAsset.Availability.String(), dataAsset.Origin, dataAsset.Owner,
+			dataAsset.JustificationCiaRating)
+		if err != nil {
+			return err
+		}
+		if err = insertTags("data_asset", dataAsset.Id, dataAsset.Tags); err != nil {
+			return err
+		}
+	}
+
+	for _, technicalAsset := range parsedModel.TechnicalAssets {
+		err = insert("technical_assets", technicalAsset.Id, technicalAsset.Title, technicalAsset.Description, technicalAsset.Usage.String(),
+			technicalAsset.Type.String(), technicalAsset.Size.String(), technicalAsset.Technology.String(), technicalAsset.Machine.String(),
+			technicalAsset.Internet, technicalAsset.MultiTenant, technicalAsset.Redundant, technicalAsset.CustomDevelopedParts,
+			technicalAsset.OutOfScope, technicalAsset.UsedAsClientByHuman, technicalAsset.Encryption.String(), technicalAsset.Owner,
+			technicalAsset.Confidentiality.String(), technicalAsset.Integrity.String(), technicalAsset.Availability.String(),
+			technicalAsset.HighestConfidentiality(parsedModel).String(), technicalAsset.HighestIntegrity(parsedModel).String(),
+			technicalAsset.HighestAvailability(parsedModel).String(), technicalAsset.RAA)
+		if err != nil {
+			return err
+		}
+		if err = insertTags("technical_asset", technicalAsset.Id, technicalAsset.Tags); err != nil {
+			return err
+		}
+		for relation, dataAssetIds := range map[string][]string{"processed": technicalAsset.DataAssetsProcessed, "stored": technicalAsset.DataAssetsStored} {
+			for _, dataAssetId := range uniqueValues(dataAssetIds) {
+				if err = insert("technical_asset_data_assets", technicalAsset.Id, dataAssetId, relation); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, link := range parsedModel.CommunicationLinks {
+		err = insert("communication_links", link.Id, link.SourceId, link.TargetId, link.Title, link.Description, link.Protocol.String(),
+			link.Authentication.String(), link.Authorization.String(), link.Usage.String(), link.VPN, link.IpFiltered, link.Readonly)
+		if err != nil {
+			return err
+		}
+		if err = insertTags("communication_link", link.Id, link.Tags); err != nil {
+			return err
+		}
+		for direction, dataAssetIds := range map[string][]string{"sent": link.DataAssetsSent, "received": link.DataAssetsReceived} {
+			for _, dataAssetId := range uniqueValues(dataAssetIds) {
+				if err = insert("communication_link_data_assets", link.Id, dataAssetId, direction); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	parentTrustBoundaries := make(map[string]string)
+	for _, trustBoundary := range parsedModel.TrustBoundaries {
+		for _, nestedId := range trustBoundary.TrustBoundariesNested {
+			parentTrustBoundaries[nestedId] = trustBoundary.Id
+		}
+	}
+	for _, trustBoundary := range parsedModel.TrustBoundaries {
+		var parentId any
+		if id, ok := parentTrustBoundaries[trustBoundary.Id]; ok {
+			parentId = id
+		}
+		err = insert("trust_boundaries", trustBoundary.Id, parentId, trustBoundary.Title, trustBoundary.Description, trustBoundary.Type.String())
+		if err != nil {
+			return err
+		}
+		if err = insertTags("trust_boundary", trustBoundary.Id, trustBoundary.Tags); err != nil {
+			return err
+		}
+		for _, technicalAssetId := range uniqueValues(trustBoundary.TechnicalAssetsInside) {
+			if err = insert("trust_boundary_technical_assets", trustBoundary.Id, technicalAssetId); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, sharedRuntime := range parsedModel.SharedRuntimes {
+		if err = insert("shared_runtimes", sharedRuntime.Id, sharedRuntime.Title, sharedRuntime.Description); err != nil {
+			return err
+		}
+		if err = insertTags("shared_runtime", sharedRuntime.Id, sharedRuntime.Tags); err != nil {
+			return err
+		}
+		for _, technicalAssetId := range uniqueValues(sharedRuntime.TechnicalAssetsRunning) {
+			if err = insert("shared_runtime_technical_assets", sharedRuntime.Id, technicalAssetId); err != nil {
+				return err
+			}
+		}
+	}
+
+	writtenRisks := make(map[string]bool) // rules may create the same risk several times, they are tracked as one
+	for _, category := range types.SortedRiskCategories(parsedModel) {
+		err = insert("risk_categories", category.Id, category.Title, category.Description, category.Impact, category.ASVS, category.CheatSheet,
+			category.Action, category.Mitigation, category.Check, category.DetectionLogic, category.RiskAssessment, category.FalsePositives,
+			category.Function.String(), category.STRIDE.String(), category.ModelFailurePossibleReason, category.CWE)
+		if err != nil {
+			return err
+		}
+		for _, risk := range parsedModel.GeneratedRisksByCategory[category.Id] {
+			if writtenRisks[risk.SyntheticId] {
+				continue
+			}
+			writtenRisks[risk.SyntheticId] = true
+			cwe := category.CWE
+			if risk.CWE > 0 {
+				cwe = risk.CWE
+			}
+			err = insert("risks", risk.SyntheticId, category.Id, removeFormattingTags(risk.Title), risk.Severity.String(),
+				risk.ExploitationLikelihood.String(), risk.ExploitationImpact.String(), risk.DataBreachProbability.String(),
+				risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String(), cwe, risk.MostRelevantTechnicalAssetId,
+				risk.MostRelevantDataAssetId, risk.MostRelevantCommunicationLinkId, risk.MostRelevantTrustBoundaryId, risk.MostRelevantSharedRuntimeId)
+			if err != nil {
+				return err
+			}
+			for _, technicalAssetId := range uniqueValues(risk.DataBreachTechnicalAssetIDs) {
+				if err = insert("risk_data_breach_technical_assets", risk.SyntheticId, technicalAssetId); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for id, tracking := range parsedModel.RiskTracking {
+		var date any
+		if !tracking.Date.IsZero() {
+			date = tracking.Date.Format("2006-01-02")
+		}
+		err = insert("risk_tracking", id, tracking.Status.String(), tracking.Justification, tracking.Ticket, date, tracking.CheckedBy)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uniqueValues(values []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
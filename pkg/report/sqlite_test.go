@@ -0,0 +1,157 @@
+package report
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestWriteSQLite(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "threagile.db")
+
+	err := WriteSQLite(createSQLiteModel(t), "hash-1", filename, false)
+	require.NoError(t, err)
+
+	db := openSQLite(t, filename)
+	expectedCounts := map[string]int{
+		"runs":                              1,
+		"technical_assets":                  4,
+		"technical_asset_data_assets":       4,
+		"data_assets":                       1,
+		"communication_links":               2,
+		"communication_link_data_assets":    3,
+		"trust_boundaries":                  2,
+		"trust_boundary_technical_assets":   3,
+		"shared_runtimes":                   1,
+		"shared_runtime_technical_assets":   2,
+		"tags":                              1,
+		"risk_categories":                   1,
+		"risks":                             2,
+		"risk_data_breach_technical_assets": 2,
+		"risk_tracking":                     1,
+	}
+	for table, expected := range expectedCounts {
+		assert.Equal(t, expected, countSQLiteRows(t, db, table, ""), table)
+	}
+
+	assert.Equal(t, "cloud-network", querySQLiteString(t, db, `SELECT parent_id FROM trust_boundaries WHERE id = 'app-namespace'`))
+	var parentId sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT parent_id FROM trust_boundaries WHERE id = 'cloud-network'`).Scan(&parentId))
+	assert.False(t, parentId.Valid, "top level trust boundary has no parent")
+
+	assert.Equal(t, "mitigated", querySQLiteString(t, db, `SELECT risk_status FROM risks WHERE synthetic_id = 'test-risk@web-app-v1'`))
+	assert.Equal(t, "unchecked", querySQLiteString(t, db, `SELECT risk_status FROM risks WHERE synthetic_id = 'test-risk@db'`))
+	assert.Equal(t, 1, countSQLiteRows(t, db, "risk_counts", "WHERE risk_status = 'unchecked'"))
+}
+
+func TestWriteSQLiteReplacesDatabase(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "threagile.db")
+	require.NoError(t, os.WriteFile(filename, []byte("not a database"), 0600))
+
+	require.NoError(t, WriteSQLite(createSQLiteModel(t), "hash-1", filename, false))
+	require.NoError(t, WriteSQLite(createSQLiteModel(t), "hash-2", filename, false))
+
+	db := openSQLite(t, filename)
+	assert.Equal(t, 1, countSQLiteRows(t, db, "runs", ""))
+	assert.Equal(t, "hash-2", querySQLiteString(t, db, `SELECT model_hash FROM runs`))
+	assert.Equal(t, 2, countSQLiteRows(t, db, "risks", ""))
+}
+
+func TestWriteSQLiteAppendRun(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "threagile.db")
+	day := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	writeSQLiteRunAt(t, filename, createSQLiteModel(t), "hash-1", day)
+	writeSQLiteRunAt(t, filename, createSQLiteModel(t), "hash-2", day)
+	writeSQLiteRunAt(t, filename, createSQLiteModel(t), "hash-1", day.AddDate(0, 0, 1))
+
+	db := openSQLite(t, filename)
+	assert.Equal(t, 3, countSQLiteRows(t, db, "runs", ""), "a different hash or day adds a run")
+	assert.Equal(t, 6, countSQLiteRows(t, db, "risks", ""))
+
+	firstRunId := querySQLiteString(t, db, `SELECT id FROM runs WHERE model_hash = 'hash-1' AND analysis_date = '2024-03-01'`)
+	parsedModel := createSQLiteModel(t)
+	parsedModel.Title = "Changed Title"
+	delete(parsedModel.GeneratedRisksByCategory, "test-risk")
+	writeSQLiteRunAt(t, filename, parsedModel, "hash-1", day.Add(time.Hour))
+
+	assert.Equal(t, 3, countSQLiteRows(t, db, "runs", ""), "a run of the same hash on the same day is replaced")
+	assert.Equal(t, 0, countSQLiteRows(t, db, "technical_assets", "WHERE run_id = "+firstRunId))
+	assert.Equal(t, "Changed Title", querySQLiteString(t, db, `SELECT model_title FROM runs WHERE model_hash = 'hash-1' AND analysis_date = '2024-03-01'`))
+	assert.Equal(t, "2024-03-01T11:00:00Z", querySQLiteString(t, db, `SELECT analysis_time FROM runs WHERE model_hash = 'hash-1' AND analysis_date = '2024-03-01'`))
+	assert.Equal(t, 4, countSQLiteRows(t, db, "risks", ""))
+	assert.Equal(t, 12, countSQLiteRows(t, db, "technical_assets", ""))
+}
+
+// createSQLiteModel adds a risk category with two risks (one of them tracked) to the diagram test model
+func createSQLiteModel(t *testing.T) *types.ParsedModel {
+	parsedModel := createDiagramModel(t)
+	parsedModel.TagsAvailable = []string{"web"}
+	webApp := parsedModel.TechnicalAssets["web-app-v1"]
+	webApp.Tags = []string{"web"}
+	parsedModel.TechnicalAssets["web-app-v1"] = webApp
+
+	parsedModel.IndividualRiskCategories = map[string]types.RiskCategory{
+		"test-risk": {Id: "test-risk", Title: "Test Risk", CWE: 20},
+	}
+	parsedModel.GeneratedRisksByCategory = map[string][]types.Risk{
+		"test-risk": {
+			{
+				CategoryId:                   "test-risk",
+				SyntheticId:                  "test-risk@web-app-v1",
+				Title:                        "<b>Test Risk</b> at <b>Web App</b>",
+				Severity:                     types.ElevatedSeverity,
+				MostRelevantTechnicalAssetId: "web-app-v1",
+				DataBreachTechnicalAssetIDs:  []string{"web-app-v1", "db", "db"},
+			},
+			{
+				CategoryId:                   "test-risk",
+				SyntheticId:                  "test-risk@db",
+				Title:                        "<b>Test Risk</b> at <b>Database</b>",
+				Severity:                     types.MediumSeverity,
+				MostRelevantTechnicalAssetId: "db",
+			},
+		},
+	}
+	parsedModel.RiskTracking = map[string]types.RiskTracking{
+		"test-risk@web-app-v1": {SyntheticRiskId: "test-risk@web-app-v1", Status: types.Mitigated, Ticket: "JIRA-1"},
+	}
+	return parsedModel
+}
+
+func writeSQLiteRunAt(t *testing.T, filename string, parsedModel *types.ParsedModel, modelHash string, now time.Time) {
+	db, err := sql.Open("sqlite", filename)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, writeSQLiteRun(tx, parsedModel, modelHash, now))
+	require.NoError(t, tx.Commit())
+}
+
+func openSQLite(t *testing.T, filename string) *sql.DB {
+	db, err := sql.Open("sqlite", filename)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func countSQLiteRows(t *testing.T, db *sql.DB, table string, where string) int {
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM "+table+" "+where).Scan(&count))
+	return count
+}
+
+func querySQLiteString(t *testing.T, db *sql.DB, query string) string {
+	var value string
+	require.NoError(t, db.QueryRow(query).Scan(&value))
+	return value
+}
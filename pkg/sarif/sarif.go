@@ -0,0 +1,357 @@
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// SARIF logs (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html), only the parts needed for attaching findings to technical assets
+
+type Log struct {
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool                     Tool                        `json:"tool"`
+	Results                  []Result                    `json:"results"`
+	VersionControlProvenance []VersionControlDetails     `json:"versionControlProvenance"`
+	OriginalUriBaseIds       map[string]ArtifactLocation `json:"originalUriBaseIds"`
+}
+
+type Tool struct {
+	Driver ToolComponent `json:"driver"`
+}
+
+type ToolComponent struct {
+	Name  string                `json:"name"`
+	Rules []ReportingDescriptor `json:"rules"`
+}
+
+type ReportingDescriptor struct {
+	Id                   string                  `json:"id"`
+	Name                 string                  `json:"name"`
+	ShortDescription     Message                 `json:"shortDescription"`
+	DefaultConfiguration ReportingConfiguration  `json:"defaultConfiguration"`
+	Relationships        []ReportingRelationship `json:"relationships"`
+	Properties           Properties              `json:"properties"`
+}
+
+type ReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type ReportingRelationship struct {
+	Target ReportingDescriptorReference `json:"target"`
+}
+
+type ReportingDescriptorReference struct {
+	Id            string                 `json:"id"`
+	ToolComponent ToolComponentReference `json:"toolComponent"`
+}
+
+type ToolComponentReference struct {
+	Name string `json:"name"`
+}
+
+type Properties struct {
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity"`
+	CWE              any      `json:"cwe"`
+}
+
+type Result struct {
+	RuleId        string        `json:"ruleId"`
+	RuleIndex     *int          `json:"ruleIndex"`
+	Level         string        `json:"level"`
+	Message       Message       `json:"message"`
+	Locations     []Location    `json:"locations"`
+	Suppressions  []Suppression `json:"suppressions"`
+	BaselineState string        `json:"baselineState"`
+	Properties    Properties    `json:"properties"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           Region           `json:"region"`
+}
+
+type ArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseId string `json:"uriBaseId"`
+}
+
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+type Suppression struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+}
+
+type VersionControlDetails struct {
+	RepositoryURI string `json:"repositoryUri"`
+}
+
+var cwePattern = regexp.MustCompile(`(?i)\bcwe[-/:_ ]?(\d+)\b`)
+
+// LoadFiles reads a SARIF file or all SARIF files (*.sarif, *.sarif.json) of a directory
+func LoadFiles(path string) (map[string]*Log, error) {
+	logs := make(map[string]*Log)
+	err := filepath.WalkDir(path, func(filename string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		lowerFilename := strings.ToLower(filename)
+		if filename != path && !strings.HasSuffix(lowerFilename, ".sarif") && !strings.HasSuffix(lowerFilename, ".sarif.json") {
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		log, loadError := LoadFile(filename)
+		if loadError != nil {
+			return loadError
+		}
+		logs[filename] = log
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func LoadFile(filename string) (*Log, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SARIF file %q: %w", filename, err)
+	}
+
+	log := new(Log)
+	err = json.Unmarshal(data, log)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SARIF file %q: %w", filename, err)
+	}
+	return log, nil
+}
+
+// Findings returns the findings of all runs with the technical asset they belong to, unmapped findings are counted only
+func (what *Log) Findings(mapping Mapping) (map[string][]types.ExternalFinding, int) {
+	findings := make(map[string][]types.ExternalFinding)
+	unmapped := 0
+	for _, run := range what.Runs {
+		repositories := make([]string, 0)
+		for _, provenance := range run.VersionControlProvenance {
+			repositories = append(repositories, normalize(provenance.RepositoryURI))
+		}
+
+		for _, result := range run.Results {
+			if len(result.Suppressions) > 0 || result.BaselineState == "absent" {
+				continue
+			}
+			rule := run.rule(result)
+
+			finding := types.ExternalFinding{
+				Tool:    run.Tool.Driver.Name,
+				RuleId:  result.RuleId,
+				Title:   firstOf(rule.ShortDescription.Text, rule.Name, result.RuleId, rule.Id),
+				Message: result.Message.Text,
+				Level:   firstOf(result.Level, rule.DefaultConfiguration.Level, "warning"),
+				Score:   score(result.Properties, rule.Properties),
+				CWE:     cwe(rule, result.Properties),
+			}
+			if len(finding.RuleId) == 0 {
+				finding.RuleId = rule.Id
+			}
+
+			candidates := make([]string, 0)
+			if len(result.Locations) > 0 {
+				location := result.Locations[0].PhysicalLocation
+				finding.Location = run.resolve(location.ArtifactLocation)
+				finding.Line = location.Region.StartLine
+				for _, repository := range repositories {
+					candidates = append(candidates, repository+"/"+finding.Location)
+				}
+				candidates = append(candidates, finding.Location)
+			}
+			candidates = append(candidates, repositories...)
+
+			technicalAssetId, ok := mapping.Resolve(candidates...)
+			if !ok {
+				unmapped++
+				continue
+			}
+			findings[technicalAssetId] = append(findings[technicalAssetId], finding)
+		}
+	}
+	return findings, unmapped
+}
+
+func (what Run) rule(result Result) ReportingDescriptor {
+	if result.RuleIndex != nil && *result.RuleIndex >= 0 && *result.RuleIndex < len(what.Tool.Driver.Rules) {
+		return what.Tool.Driver.Rules[*result.RuleIndex]
+	}
+	for _, rule := range what.Tool.Driver.Rules {
+		if rule.Id == result.RuleId {
+			return rule
+		}
+	}
+	return ReportingDescriptor{}
+}
+
+// resolve prefixes relative artifact locations with the uri of their base id, when it is a remote one
+func (what Run) resolve(location ArtifactLocation) string {
+	base, ok := what.OriginalUriBaseIds[location.URIBaseId]
+	if !ok || len(base.URI) == 0 || strings.HasPrefix(base.URI, "file:") || strings.Contains(location.URI, "://") {
+		return location.URI
+	}
+	return strings.TrimSuffix(base.URI, "/") + "/" + strings.TrimPrefix(location.URI, "/")
+}
+
+// score returns the security severity (as used by GitHub code scanning and others) of the result or its rule
+func score(properties ...Properties) float64 {
+	for _, property := range properties {
+		value, err := strconv.ParseFloat(strings.TrimSpace(property.SecuritySeverity), 64)
+		if err == nil {
+			return value
+		}
+	}
+	return 0
+}
+
+// cwe looks for the CWE in rule relationships, the cwe property and tags like "CWE-89" or "external/cwe/cwe-89"
+func cwe(rule ReportingDescriptor, properties Properties) int {
+	for _, relationship := range rule.Relationships {
+		if strings.EqualFold(relationship.Target.ToolComponent.Name, "CWE") {
+			if value, ok := parseCWE("CWE-" + strings.TrimPrefix(strings.ToUpper(relationship.Target.Id), "CWE-")); ok {
+				return value
+			}
+		}
+	}
+	for _, candidate := range []Properties{properties, rule.Properties} {
+		texts := append([]string{}, candidate.Tags...)
+		switch value := candidate.CWE.(type) {
+		case string:
+			texts = append(texts, value)
+		case []any:
+			for _, item := range value {
+				texts = append(texts, fmt.Sprint(item))
+			}
+		}
+		for _, text := range texts {
+			if value, ok := parseCWE(text); ok {
+				return value
+			}
+		}
+	}
+	return 0
+}
+
+func parseCWE(text string) (int, bool) {
+	match := cwePattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	value, err := strconv.Atoi(match[1])
+	return value, err == nil && value > 0
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if len(strings.TrimSpace(value)) > 0 {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// Mapping assigns findings to technical assets by (prefixes of) repository paths or URLs, e.g.
+//
+//	github.com/acme/webshop: apache-webserver
+//	github.com/acme/monorepo/services/erp: erp-system
+//	https://shop.example.com/: apache-webserver
+type Mapping map[string]string
+
+func LoadMapping(filename string) (Mapping, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SARIF mapping file %q: %w", filename, err)
+	}
+
+	mapping := make(Mapping)
+	err = yaml.Unmarshal(data, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse SARIF mapping file %q: %w", filename, err)
+	}
+	return mapping, nil
+}
+
+// TechnicalAssetIds returns the technical asset ids the mapping refers to
+func (what Mapping) TechnicalAssetIds() []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0)
+	for _, id := range what {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Resolve returns the technical asset of the longest mapped prefix matching any of the candidates
+func (what Mapping) Resolve(candidates ...string) (string, bool) {
+	technicalAssetId, longest := "", -1
+	for prefix, id := range what {
+		normalizedPrefix := normalize(prefix)
+		for _, candidate := range candidates {
+			normalizedCandidate := normalize(candidate)
+			if len(normalizedPrefix) == 0 || len(normalizedPrefix) <= longest {
+				continue
+			}
+			if normalizedCandidate == normalizedPrefix || strings.HasPrefix(normalizedCandidate, normalizedPrefix+"/") {
+				technicalAssetId, longest = id, len(normalizedPrefix)
+			}
+		}
+	}
+	return technicalAssetId, longest >= 0
+}
+
+// normalize makes repository paths and URLs comparable: "git@github.com:acme/shop.git" and "https://github.com/acme/shop/" both become "github.com/acme/shop"
+func normalize(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "git+")
+	if _, rest, ok := strings.Cut(value, "://"); ok {
+		value = rest
+		if credentials, host, ok := strings.Cut(value, "@"); ok && !strings.Contains(credentials, "/") {
+			value = host
+		}
+	} else if user, rest, ok := strings.Cut(value, "@"); ok && !strings.Contains(user, "/") { // scp-like syntax of git
+		value = strings.Replace(rest, ":", "/", 1)
+	}
+	value = strings.TrimPrefix(value, "./")
+	value = strings.Trim(value, "/")
+	return strings.TrimSuffix(value, ".git")
+}
@@ -0,0 +1,146 @@
+package sarif
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestFindings(t *testing.T) {
+	logs, err := LoadFiles("testdata/scans")
+	require.NoError(t, err)
+	require.Len(t, logs, 2, "only SARIF files are read")
+	mapping, err := LoadMapping("testdata/mapping.yaml")
+	require.NoError(t, err)
+
+	findings, unmapped := logs[filepath.Join("testdata", "scans", "codeql.sarif")].Findings(mapping)
+
+	assert.Equal(t, 0, unmapped)
+	assert.Equal(t, map[string][]types.ExternalFinding{
+		"erp-system": {{
+			Tool:     "CodeQL",
+			RuleId:   "java/sql-injection",
+			Title:    "Query built from user-controlled sources",
+			Message:  "This query depends on a user-provided value.",
+			Level:    "error",
+			Score:    8.8,
+			CWE:      89,
+			Location: "services/erp/src/Orders.java",
+			Line:     42,
+		}},
+		"monorepo": {{
+			Tool:     "CodeQL",
+			RuleId:   "java/weak-cryptographic-algorithm",
+			Title:    "WeakCrypto",
+			Message:  "MD5 is weak.",
+			Level:    "note",
+			CWE:      327,
+			Location: "libs/crypto/Hash.java",
+		}},
+	}, findings)
+
+	findings, unmapped = logs[filepath.Join("testdata", "scans", "zap.sarif.json")].Findings(mapping)
+
+	assert.Equal(t, 1, unmapped)
+	require.Len(t, findings["apache-webserver"], 1)
+	finding := findings["apache-webserver"][0]
+	assert.Equal(t, "https://shop.example.com/checkout", finding.Location)
+	assert.Equal(t, 693, finding.CWE)
+	assert.Equal(t, 5.0, finding.Score)
+	assert.Equal(t, "10038", finding.Title)
+}
+
+func TestLoadFilesFails(t *testing.T) {
+	_, err := LoadFiles("testdata/missing")
+	assert.Error(t, err)
+}
+
+type resolveTest struct {
+	candidates []string
+	expected   string
+	ok         bool
+}
+
+func TestMappingResolve(t *testing.T) {
+	mapping := Mapping{
+		"github.com/acme/monorepo":              "monorepo",
+		"github.com/acme/monorepo/services/erp": "erp-system",
+		"https://shop.example.com/":             "apache-webserver",
+	}
+	testCases := map[string]resolveTest{
+		"longest prefix": {
+			candidates: []string{"github.com/acme/monorepo/services/erp/main.go"},
+			expected:   "erp-system",
+			ok:         true,
+		},
+		"scp-like git url": {
+			candidates: []string{"git@github.com:acme/monorepo.git"},
+			expected:   "monorepo",
+			ok:         true,
+		},
+		"url with credentials": {
+			candidates: []string{"https://token@github.com/acme/monorepo/README.md"},
+			expected:   "monorepo",
+			ok:         true,
+		},
+		"url": {
+			candidates: []string{"HTTPS://shop.example.com/cart"},
+			expected:   "apache-webserver",
+			ok:         true,
+		},
+		"prefix of a path segment only": {
+			candidates: []string{"github.com/acme/monorepo-tools/main.go"},
+		},
+		"any candidate": {
+			candidates: []string{"src/main.go", "github.com/acme/monorepo"},
+			expected:   "monorepo",
+			ok:         true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := mapping.Resolve(testCase.candidates...)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func TestMappingTechnicalAssetIds(t *testing.T) {
+	mapping, err := LoadMapping("testdata/mapping.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"apache-webserver", "erp-system", "monorepo"}, mapping.TechnicalAssetIds())
+}
+
+type parseCWETest struct {
+	text     string
+	expected int
+	ok       bool
+}
+
+func TestParseCWE(t *testing.T) {
+	testCases := map[string]parseCWETest{
+		"plain":       {text: "CWE-79", expected: 79, ok: true},
+		"codeql tag":  {text: "external/cwe/cwe-089", expected: 89, ok: true},
+		"lower case":  {text: "cwe:22", expected: 22, ok: true},
+		"no cwe":      {text: "security", expected: 0},
+		"zero":        {text: "CWE-0", expected: 0},
+		"inside word": {text: "xcwe-79", expected: 0},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := parseCWE(testCase.text)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package builtin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type ExternalFindingsRule struct {
+	raaLimit int
+}
+
+func NewExternalFindingsRule() *ExternalFindingsRule {
+	return &ExternalFindingsRule{raaLimit: 75}
+}
+
+func (r *ExternalFindingsRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:    "external-findings",
+		Title: "External Findings",
+		Description: "Scanners (SAST, DAST, ...) reported findings for a technical asset, which are not covered by a more specific risk category " +
+			"of the model.",
+		Impact:     "If this risk is unmitigated, attackers might be able to exploit the weaknesses actually found in the technical asset.",
+		ASVS:       "V1 - Architecture, Design and Threat Modeling Requirements",
+		CheatSheet: "https://cheatsheetseries.owasp.org/IndexTopTen.html",
+		Action:     "Scanner Findings",
+		Mitigation: "Fix the findings as described by the scanner or mark them as false positives within the scanner.",
+		Check:      "Are the findings of the scanners triaged and fixed?",
+		Function:   types.Development,
+		STRIDE:     types.Tampering,
+		DetectionLogic: "In-scope technical assets with findings of SARIF files (given as --sarif and mapped to technical assets by " +
+			"--sarif-mapping). Findings with the CWE of a risk generated for the technical asset raise the exploitation likelihood of " +
+			"that risk instead (like for SQL/NoSQL-Injection or Cross-Site Scripting).",
+		RiskAssessment: "The exploitation likelihood depends on the security severity or level of the findings, the exploitation impact on the " +
+			"sensitivity of the technical asset itself and of the data assets processed, raised for RAA values of " + strconv.Itoa(r.raaLimit) + " % or higher.",
+		FalsePositives: "Findings should be triaged within the scanner, suppressed findings are not imported " +
+			"(the risk id contains the tool and rule of the findings, so each one can be tracked on its own).",
+		ModelFailurePossibleReason: false,
+		CWE:                        0,
+	}
+}
+
+func (*ExternalFindingsRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (r *ExternalFindingsRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
+	risks := make([]types.Risk, 0)
+	for _, id := range input.SortedTechnicalAssetIDs() {
+		technicalAsset := input.TechnicalAssets[id]
+		if technicalAsset.OutOfScope {
+			continue
+		}
+		findingsByRule := make(map[string][]types.ExternalFinding)
+		for _, finding := range technicalAsset.Findings {
+			key := strings.ReplaceAll(strings.ToLower(finding.Tool+"@"+finding.RuleId), " ", "-")
+			findingsByRule[key] = append(findingsByRule[key], finding)
+		}
+		keys := make([]string, 0)
+		for key := range findingsByRule {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			risks = append(risks, r.createRisk(input, technicalAsset, key, findingsByRule[key]))
+		}
+	}
+	return risks
+}
+
+func (r *ExternalFindingsRule) createRisk(input *types.ParsedModel, technicalAsset types.TechnicalAsset, key string, findings []types.ExternalFinding) types.Risk {
+	title := "<b>External Findings</b> risk at <b>" + technicalAsset.Title + "</b>: <b>" + findings[0].Title + "</b> reported by <b>" +
+		findings[0].Tool + "</b>"
+	if len(findings) > 1 {
+		title += " (" + strconv.Itoa(len(findings)) + " findings)"
+	}
+
+	likelihood := types.Unlikely
+	for _, finding := range findings {
+		findingLikelihood := types.Unlikely
+		switch {
+		case finding.Score >= 9:
+			findingLikelihood = types.Frequent
+		case finding.Score >= 7:
+			findingLikelihood = types.VeryLikely
+		case finding.Score >= 4 || (finding.Score == 0 && finding.Level == "error"):
+			findingLikelihood = types.Likely
+		}
+		if findingLikelihood > likelihood {
+			likelihood = findingLikelihood
+		}
+	}
+
+	impact := types.LowImpact
+	if technicalAsset.HighestConfidentiality(input) == types.StrictlyConfidential ||
+		technicalAsset.HighestIntegrity(input) == types.MissionCritical ||
+		technicalAsset.HighestAvailability(input) == types.MissionCritical {
+		impact = types.HighImpact
+	} else if technicalAsset.HighestConfidentiality(input) >= types.Confidential ||
+		technicalAsset.HighestIntegrity(input) >= types.Critical ||
+		technicalAsset.HighestAvailability(input) >= types.Critical {
+		impact = types.MediumImpact
+	}
+	if technicalAsset.RAA >= float64(r.raaLimit) {
+		impact++
+	}
+
+	risk := types.Risk{
+		CategoryId:                   r.Category().Id,
+		Severity:                     types.CalculateSeverity(likelihood, impact),
+		ExploitationLikelihood:       likelihood,
+		ExploitationImpact:           impact,
+		Title:                        title,
+		MostRelevantTechnicalAssetId: technicalAsset.Id,
+		DataBreachProbability:        types.Possible,
+		DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+		CWE:                          findings[0].CWE,
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + technicalAsset.Id + "@" + key
+	return risk
+}
@@ -0,0 +1,108 @@
+package builtin
+
+import (
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type InconsistentDataLineageRule struct{}
+
+func NewInconsistentDataLineageRule() *InconsistentDataLineageRule {
+	return &InconsistentDataLineageRule{}
+}
+
+func (*InconsistentDataLineageRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:    "inconsistent-data-lineage",
+		Title: "Inconsistent Data Lineage",
+		Description: "When the data assets processed, stored, sent and received by the technical assets do not add up to a " +
+			"consistent flow of data, e.g. data stored in a datastore never arrives there or data is sent by a technical asset " +
+			"which never gets it, this is an indicator for an incomplete or wrong model.",
+		Impact: "If this risk is unmitigated, the data flows of the model do not match reality, so risks of technical assets " +
+			"and communication links actually handling the data might be missed.",
+		ASVS:       "V1 - Architecture, Design and Threat Modeling Requirements",
+		CheatSheet: "https://cheatsheetseries.owasp.org/cheatsheets/Threat_Modeling_Cheat_Sheet.html",
+		Action:     "Model Completeness",
+		Mitigation: "Complete the model so that each data asset is processed or stored where it is sent from and where it is " +
+			"sent to, and so that data in datastores arrives there via communication links.",
+		Check:    "Are all data flows of the data assets modelled?",
+		Function: types.Architecture,
+		STRIDE:   types.InformationDisclosure,
+		DetectionLogic: "The lineage of each data asset is followed along the communication links from its origins, " +
+			"the technical assets processing or storing it without receiving it from elsewhere. Flagged are datastores storing " +
+			"data assets which are not sent to them, technical assets sending data assets they neither process nor receive, " +
+			"technical assets receiving data assets they neither process nor forward, and data assets transferred but not " +
+			"processed or stored where the transfers start.",
+		RiskAssessment: "The risk assessment is depending on the confidentiality and integrity rating of the data asset " +
+			"either " + types.LowSeverity.String() + " or " + types.MediumSeverity.String() + ".",
+		FalsePositives: "Data assets created by a datastore itself (e.g. logs) or entering the system by other means than " +
+			"communication links can be considered as false positives after individual review.",
+		ModelFailurePossibleReason: true,
+		CWE:                        1008,
+	}
+}
+
+func (*InconsistentDataLineageRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (r *InconsistentDataLineageRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
+	risks := make([]types.Risk, 0)
+	for _, lineage := range types.DataLineages(input) {
+		dataAsset := input.DataAssets[lineage.DataAssetId]
+		for _, inconsistency := range lineage.Inconsistencies {
+			if inconsistency.Kind == types.ReceivedButNotUsed && (dataAsset.Confidentiality >= types.Confidential || dataAsset.Integrity >= types.Critical) {
+				continue // already flagged as unnecessary data transfer
+			}
+			if len(inconsistency.TechnicalAssetId) > 0 && input.TechnicalAssets[inconsistency.TechnicalAssetId].OutOfScope {
+				continue
+			}
+			risks = append(risks, r.createRisk(input, dataAsset, lineage, inconsistency))
+		}
+	}
+	return risks
+}
+
+func (r *InconsistentDataLineageRule) createRisk(input *types.ParsedModel, dataAsset types.DataAsset, lineage types.DataLineage,
+	inconsistency types.DataLineageInconsistency) types.Risk {
+	impact := types.LowImpact
+	if dataAsset.Confidentiality == types.StrictlyConfidential || dataAsset.Integrity == types.MissionCritical {
+		impact = types.MediumImpact
+	}
+
+	technicalAsset := input.TechnicalAssets[inconsistency.TechnicalAssetId]
+	title := "<b>Inconsistent Data Lineage</b> of <b>" + dataAsset.Title + "</b> data"
+	dataBreachTechnicalAssetIDs := []string{technicalAsset.Id}
+	switch inconsistency.Kind {
+	case types.StoredButNeverReceived:
+		title += " stored at <b>" + technicalAsset.Title + "</b> but never sent there"
+	case types.SentButNotAvailable:
+		title += " sent by <b>" + technicalAsset.Title + "</b> via <b>" + input.CommunicationLinks[inconsistency.CommunicationLinkId].Title +
+			"</b> but neither processed nor received there"
+	case types.ReceivedButNotUsed:
+		title += " received by <b>" + technicalAsset.Title + "</b> but neither processed nor forwarded there"
+	case types.WithoutOrigin:
+		title += " transferred without any origin processing or storing it"
+		dataBreachTechnicalAssetIDs = lineage.TechnicalAssetIds
+	}
+
+	risk := types.Risk{
+		CategoryId:                      r.Category().Id,
+		Severity:                        types.CalculateSeverity(types.Unlikely, impact),
+		ExploitationLikelihood:          types.Unlikely,
+		ExploitationImpact:              impact,
+		Title:                           title,
+		MostRelevantDataAssetId:         dataAsset.Id,
+		MostRelevantTechnicalAssetId:    technicalAsset.Id,
+		MostRelevantCommunicationLinkId: inconsistency.CommunicationLinkId,
+		DataBreachProbability:           types.Improbable,
+		DataBreachTechnicalAssetIDs:     dataBreachTechnicalAssetIDs,
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + string(inconsistency.Kind) + "@" + dataAsset.Id
+	if len(technicalAsset.Id) > 0 {
+		risk.SyntheticId += "@" + technicalAsset.Id
+	}
+	if len(inconsistency.CommunicationLinkId) > 0 {
+		risk.SyntheticId += "@" + inconsistency.CommunicationLinkId
+	}
+	return risk
+}
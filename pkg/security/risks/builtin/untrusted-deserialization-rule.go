@@ -29,8 +29,9 @@ func (*UntrustedDeserializationRule) Category() types.RiskCategory {
 		Check:          "Are recommendations from the linked cheat sheet and referenced ASVS chapter applied?",
 		Function:       types.Architecture,
 		STRIDE:         types.Tampering,
-		DetectionLogic: "In-scope technical assets accepting serialization data formats (including EJB and RMI protocols).",
-		RiskAssessment: "The risk rating depends on the sensitivity of the technical asset itself and of the data assets processed.",
+		DetectionLogic: "In-scope technical assets accepting serialization data formats (including EJB and RMI protocols) or containing a library known for deserialization issues according to their SBOM.",
+		RiskAssessment: "The risk rating depends on the sensitivity of the technical asset itself and of the data assets processed. " +
+			"A library known for deserialization issues in the SBOM raises the likelihood, assets with such a library but without accepting serialized data are rated unlikely.",
 		FalsePositives: "Fully trusted (i.e. cryptographically signed or similar) data deserialized can be considered " +
 			"as false positives after individual review.",
 		ModelFailurePossibleReason: false,
@@ -70,14 +71,15 @@ func (r *UntrustedDeserializationRule) GenerateRisks(input *types.ParsedModel) [
 				}
 			}
 		}
-		if hasOne {
-			risks = append(risks, r.createRisk(input, technicalAsset, acrossTrustBoundary, commLinkTitle))
+		libraries := technicalAsset.ComponentsWithHint(types.DeserializationHint)
+		if hasOne || len(libraries) > 0 {
+			risks = append(risks, r.createRisk(input, technicalAsset, hasOne, acrossTrustBoundary, commLinkTitle, libraries))
 		}
 	}
 	return risks
 }
 
-func (r *UntrustedDeserializationRule) createRisk(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, acrossTrustBoundary bool, commLinkTitle string) types.Risk {
+func (r *UntrustedDeserializationRule) createRisk(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, hasOne bool, acrossTrustBoundary bool, commLinkTitle string, libraries []types.SoftwareComponent) types.Risk {
 	title := "<b>Untrusted Deserialization</b> risk at <b>" + technicalAsset.Title + "</b>"
 	impact := types.HighImpact
 	likelihood := types.Likely
@@ -85,6 +87,14 @@ func (r *UntrustedDeserializationRule) createRisk(parsedModel *types.ParsedModel
 		likelihood = types.VeryLikely
 		title += " across a trust boundary (at least via communication link <b>" + commLinkTitle + "</b>)"
 	}
+	if len(libraries) > 0 {
+		title += " (library <b>" + libraries[0].Title() + "</b>)"
+		if !hasOne {
+			likelihood = types.Unlikely
+		} else if likelihood < types.Frequent {
+			likelihood++
+		}
+	}
 	if technicalAsset.HighestConfidentiality(parsedModel) == types.StrictlyConfidential ||
 		technicalAsset.HighestIntegrity(parsedModel) == types.MissionCritical ||
 		technicalAsset.HighestAvailability(parsedModel) == types.MissionCritical {
@@ -0,0 +1,112 @@
+package builtin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type VulnerableComponentRule struct {
+	raaLimit int
+}
+
+func NewVulnerableComponentRule() *VulnerableComponentRule {
+	return &VulnerableComponentRule{raaLimit: 75}
+}
+
+func (r *VulnerableComponentRule) Category() types.RiskCategory {
+	return types.RiskCategory{
+		Id:    "vulnerable-component",
+		Title: "Vulnerable Component",
+		Description: "When a component listed in the SBOM of a technical asset is affected by a known vulnerability, " +
+			"the vulnerability might be exploitable through the technical asset.",
+		Impact:     "If this risk is unmitigated, attackers might be able to exploit the known vulnerability with publicly available information or exploits.",
+		ASVS:       "V14 - Configuration Verification Requirements",
+		CheatSheet: "https://cheatsheetseries.owasp.org/cheatsheets/Vulnerable_Dependency_Management_Cheat_Sheet.html",
+		Action:     "Vulnerable Dependency Management",
+		Mitigation: "Update the component to a version fixing the vulnerability. When no fixed version is available, " +
+			"check whether the vulnerable functionality is used and apply the workarounds of the advisory.",
+		Check:    "Are recommendations from the linked cheat sheet and referenced ASVS chapter applied?",
+		Function: types.Development,
+		STRIDE:   types.ElevationOfPrivilege,
+		DetectionLogic: "In-scope technical assets with SBOM components affected by a vulnerability of the offline OSV database " +
+			"(given as --osv-database).",
+		RiskAssessment: "The exploitation likelihood depends on the CVSS score of the vulnerability, the exploitation impact on the " +
+			"sensitivity of the technical asset itself and of the data assets processed, raised for RAA values of " + strconv.Itoa(r.raaLimit) + " % or higher.",
+		FalsePositives: "Vulnerabilities in functionality of the component not used by the technical asset can be considered " +
+			"as false positives after individual review (the risk id contains the vulnerability id, so each one can be tracked on its own).",
+		ModelFailurePossibleReason: false,
+		CWE:                        1395,
+	}
+}
+
+func (*VulnerableComponentRule) SupportedTags() []string {
+	return []string{}
+}
+
+func (r *VulnerableComponentRule) GenerateRisks(input *types.ParsedModel) []types.Risk {
+	risks := make([]types.Risk, 0)
+	for _, id := range input.SortedTechnicalAssetIDs() {
+		technicalAsset := input.TechnicalAssets[id]
+		if technicalAsset.OutOfScope {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, component := range technicalAsset.Components {
+			for _, vulnerability := range component.Vulnerabilities {
+				if !seen[vulnerability.Id] {
+					seen[vulnerability.Id] = true
+					risks = append(risks, r.createRisk(input, technicalAsset, component, vulnerability))
+				}
+			}
+		}
+	}
+	return risks
+}
+
+func (r *VulnerableComponentRule) createRisk(input *types.ParsedModel, technicalAsset types.TechnicalAsset, component types.SoftwareComponent, vulnerability types.Vulnerability) types.Risk {
+	title := "<b>Vulnerable Component</b> risk at <b>" + technicalAsset.Title + "</b>: <b>" + vulnerability.Id + "</b> in <b>" +
+		component.Title() + " " + component.Version + "</b>"
+	if len(vulnerability.Fixed) > 0 {
+		title += " (fixed in " + vulnerability.Fixed + ")"
+	}
+
+	likelihood := types.Likely // unknown score
+	switch {
+	case vulnerability.Score >= 9:
+		likelihood = types.Frequent
+	case vulnerability.Score >= 7:
+		likelihood = types.VeryLikely
+	case vulnerability.Score > 0 && vulnerability.Score < 4:
+		likelihood = types.Unlikely
+	}
+
+	impact := types.LowImpact
+	if technicalAsset.HighestConfidentiality(input) == types.StrictlyConfidential ||
+		technicalAsset.HighestIntegrity(input) == types.MissionCritical ||
+		technicalAsset.HighestAvailability(input) == types.MissionCritical {
+		impact = types.HighImpact
+	} else if technicalAsset.HighestConfidentiality(input) >= types.Confidential ||
+		technicalAsset.HighestIntegrity(input) >= types.Critical ||
+		technicalAsset.HighestAvailability(input) >= types.Critical {
+		impact = types.MediumImpact
+	}
+	if technicalAsset.RAA >= float64(r.raaLimit) {
+		impact++
+	}
+
+	risk := types.Risk{
+		CategoryId:                   r.Category().Id,
+		Severity:                     types.CalculateSeverity(likelihood, impact),
+		ExploitationLikelihood:       likelihood,
+		ExploitationImpact:           impact,
+		Title:                        title,
+		MostRelevantTechnicalAssetId: technicalAsset.Id,
+		DataBreachProbability:        types.Possible,
+		DataBreachTechnicalAssetIDs:  []string{technicalAsset.Id},
+		CWE:                          vulnerability.CWE,
+	}
+	risk.SyntheticId = risk.CategoryId + "@" + technicalAsset.Id + "@" + strings.ToLower(vulnerability.Id)
+	return risk
+}
@@ -26,8 +26,9 @@ func (*XmlExternalEntityRule) Category() types.RiskCategory {
 		Check:          "Are recommendations from the linked cheat sheet and referenced ASVS chapter applied?",
 		Function:       types.Development,
 		STRIDE:         types.InformationDisclosure,
-		DetectionLogic: "In-scope technical assets accepting XML data formats.",
+		DetectionLogic: "In-scope technical assets accepting XML data formats or containing an XML parser library according to their SBOM.",
 		RiskAssessment: "The risk rating depends on the sensitivity of the technical asset itself and of the data assets processed. " +
+			"An XML parser library in the SBOM raises the likelihood of assets accepting XML, assets with such a library but without accepting XML are rated unlikely. " +
 			"Also for cloud-based environments the exploitation impact is at least medium, as cloud backend services can be attacked via SSRF (and XXE vulnerabilities are often also SSRF vulnerabilities).",
 		FalsePositives: "Fully trusted (i.e. cryptographically signed or similar) XML data can be considered " +
 			"as false positives after individual review.",
@@ -47,17 +48,31 @@ func (r *XmlExternalEntityRule) GenerateRisks(input *types.ParsedModel) []types.
 		if technicalAsset.OutOfScope {
 			continue
 		}
+		acceptsXml := false
 		for _, format := range technicalAsset.DataFormatsAccepted {
 			if format == types.XML {
-				risks = append(risks, r.createRisk(input, technicalAsset))
+				acceptsXml = true
 			}
 		}
+		xmlParsers := technicalAsset.ComponentsWithHint(types.XmlParserHint)
+		if acceptsXml || len(xmlParsers) > 0 {
+			risks = append(risks, r.createRisk(input, technicalAsset, acceptsXml, xmlParsers))
+		}
 	}
 	return risks
 }
 
-func (r *XmlExternalEntityRule) createRisk(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset) types.Risk {
+func (r *XmlExternalEntityRule) createRisk(parsedModel *types.ParsedModel, technicalAsset types.TechnicalAsset, acceptsXml bool, xmlParsers []types.SoftwareComponent) types.Risk {
 	title := "<b>XML External Entity (XXE)</b> risk at <b>" + technicalAsset.Title + "</b>"
+	likelihood := types.VeryLikely
+	if len(xmlParsers) > 0 {
+		title += " (XML parser <b>" + xmlParsers[0].Title() + "</b>)"
+		if acceptsXml {
+			likelihood = types.Frequent
+		} else {
+			likelihood = types.Unlikely
+		}
+	}
 	impact := types.MediumImpact
 	if technicalAsset.HighestConfidentiality(parsedModel) == types.StrictlyConfidential ||
 		technicalAsset.HighestIntegrity(parsedModel) == types.MissionCritical ||
@@ -66,8 +81,8 @@ func (r *XmlExternalEntityRule) createRisk(parsedModel *types.ParsedModel, techn
 	}
 	risk := types.Risk{
 		CategoryId:                   r.Category().Id,
-		Severity:                     types.CalculateSeverity(types.VeryLikely, impact),
-		ExploitationLikelihood:       types.VeryLikely,
+		Severity:                     types.CalculateSeverity(likelihood, impact),
+		ExploitationLikelihood:       likelihood,
 		ExploitationImpact:           impact,
 		Title:                        title,
 		MostRelevantTechnicalAssetId: technicalAsset.Id,
@@ -11,6 +11,13 @@ type RiskRule interface {
 	GenerateRisks(*types.ParsedModel) []types.Risk
 }
 
+// GetOptInRiskRules returns the IDs of the built-in risk rules which only run when enabled explicitly
+func GetOptInRiskRules() []string {
+	return []string{
+		builtin.NewInconsistentDataLineageRule().Category().Id,
+	}
+}
+
 func GetBuiltInRiskRules() []RiskRule {
 	return []RiskRule{
 		builtin.NewAccidentalSecretLeakRule(),
@@ -20,7 +27,9 @@ func GetBuiltInRiskRules() []RiskRule {
 		builtin.NewCrossSiteRequestForgeryRule(),
 		builtin.NewCrossSiteScriptingRule(),
 		builtin.NewDosRiskyAccessAcrossTrustBoundaryRule(),
+		builtin.NewExternalFindingsRule(),
 		builtin.NewIncompleteModelRule(),
+		builtin.NewInconsistentDataLineageRule(),
 		builtin.NewLdapInjectionRule(),
 		builtin.NewMissingAuthenticationRule(),
 		builtin.NewMissingAuthenticationSecondFactorRule(builtin.NewMissingAuthenticationRule()),
@@ -52,6 +61,7 @@ func GetBuiltInRiskRules() []RiskRule {
 		builtin.NewUnnecessaryDataTransferRule(),
 		builtin.NewUnnecessaryTechnicalAssetRule(),
 		builtin.NewUntrustedDeserializationRule(),
+		builtin.NewVulnerableComponentRule(),
 		builtin.NewWrongCommunicationLinkContentRule(),
 		builtin.NewWrongTrustBoundaryContentRule(),
 		builtin.NewXmlExternalEntityRule(),
@@ -0,0 +1,217 @@
+package types
+
+import (
+	"sort"
+)
+
+type DataLineageInconsistencyKind string
+
+const (
+	StoredButNeverReceived DataLineageInconsistencyKind = "stored-but-never-received" // stored without any communication link bringing the data there
+	SentButNotAvailable    DataLineageInconsistencyKind = "sent-but-not-available"    // sent by a technical asset which neither processes nor receives the data
+	ReceivedButNotUsed     DataLineageInconsistencyKind = "received-but-not-used"     // received by a technical asset which neither processes nor forwards the data
+	WithoutOrigin          DataLineageInconsistencyKind = "without-origin"            // transferred, but not processed or stored where the transfers start
+)
+
+// DataFlow is the transfer of a data asset along a communication link, either sent (source to target) or received (target to source)
+type DataFlow struct {
+	CommunicationLinkId string `json:"communication_link_id"`
+	FromId              string `json:"from_id"`
+	ToId                string `json:"to_id"`
+	Received            bool   `json:"received,omitempty"` // as response of the communication link
+}
+
+type DataLineageInconsistency struct {
+	Kind                DataLineageInconsistencyKind `json:"kind"`
+	TechnicalAssetId    string                       `json:"technical_asset_id,omitempty"`
+	CommunicationLinkId string                       `json:"communication_link_id,omitempty"`
+}
+
+// DataLineage is where a data asset originates, which technical assets it reaches transitively and which links it travels
+type DataLineage struct {
+	DataAssetId          string                     `json:"data_asset_id"`
+	OriginIds            []string                   `json:"origin_ids"`             // processing or storing the data asset without receiving it from outside their own data flow cycle
+	TechnicalAssetIds    []string                   `json:"technical_asset_ids"`    // reached from the origins, including them
+	CommunicationLinkIds []string                   `json:"communication_link_ids"` // travelled from the origins
+	Flows                []DataFlow                 `json:"flows"`
+	Inconsistencies      []DataLineageInconsistency `json:"inconsistencies"`
+}
+
+// DataLineages returns the lineage of all data assets sorted by data asset id
+func DataLineages(parsedModel *ParsedModel) []DataLineage {
+	lineages := make([]DataLineage, 0)
+	ids := make([]string, 0)
+	for id := range parsedModel.DataAssets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		lineages = append(lineages, parsedModel.DataAssets[id].Lineage(parsedModel))
+	}
+	return lineages
+}
+
+func (what DataAsset) Lineage(parsedModel *ParsedModel) DataLineage {
+	lineage := DataLineage{
+		DataAssetId:          what.Id,
+		OriginIds:            make([]string, 0),
+		TechnicalAssetIds:    make([]string, 0),
+		CommunicationLinkIds: make([]string, 0),
+		Flows:                make([]DataFlow, 0),
+		Inconsistencies:      make([]DataLineageInconsistency, 0),
+	}
+
+	incoming := make(map[string][]DataFlow)
+	outgoing := make(map[string][]DataFlow)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		for _, link := range parsedModel.TechnicalAssets[id].CommunicationLinksSorted() {
+			flows := make([]DataFlow, 0)
+			if contains(link.DataAssetsSent, what.Id) {
+				flows = append(flows, DataFlow{CommunicationLinkId: link.Id, FromId: link.SourceId, ToId: link.TargetId})
+			}
+			if contains(link.DataAssetsReceived, what.Id) {
+				flows = append(flows, DataFlow{CommunicationLinkId: link.Id, FromId: link.TargetId, ToId: link.SourceId, Received: true})
+			}
+			for _, flow := range flows {
+				lineage.Flows = append(lineage.Flows, flow)
+				incoming[flow.ToId] = append(incoming[flow.ToId], flow)
+				outgoing[flow.FromId] = append(outgoing[flow.FromId], flow)
+			}
+		}
+	}
+
+	holders := make([]string, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		if !technicalAsset.ProcessesOrStoresDataAsset(what.Id) && !contains(technicalAsset.DataAssetsStored, what.Id) {
+			continue
+		}
+		holders = append(holders, id)
+		// processes might create the data they store, datastores don't
+		if technicalAsset.Type == Datastore && len(incoming[id]) == 0 && len(lineage.Flows) > 0 {
+			lineage.Inconsistencies = append(lineage.Inconsistencies, DataLineageInconsistency{Kind: StoredButNeverReceived, TechnicalAssetId: id})
+		}
+	}
+
+	// as data is mostly sent and received back, the origins are the holders within the cycles of data flows no data flows into
+	components := dataFlowComponents(parsedModel, holders, incoming, outgoing)
+	isSource := make(map[int]bool)
+	for _, component := range components {
+		isSource[component] = true
+	}
+	for _, flow := range lineage.Flows {
+		if components[flow.FromId] != components[flow.ToId] {
+			isSource[components[flow.ToId]] = false
+		}
+	}
+	for _, id := range holders {
+		if isSource[components[id]] {
+			lineage.OriginIds = append(lineage.OriginIds, id)
+		}
+	}
+
+	for _, flow := range lineage.Flows {
+		if !contains(holders, flow.FromId) && len(incoming[flow.FromId]) == 0 && !lineage.hasInconsistency(SentButNotAvailable, flow.FromId, flow.CommunicationLinkId) {
+			lineage.Inconsistencies = append(lineage.Inconsistencies, DataLineageInconsistency{Kind: SentButNotAvailable, TechnicalAssetId: flow.FromId, CommunicationLinkId: flow.CommunicationLinkId})
+		}
+	}
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if len(incoming[id]) > 0 && !contains(holders, id) && len(outgoing[id]) == 0 {
+			lineage.Inconsistencies = append(lineage.Inconsistencies, DataLineageInconsistency{Kind: ReceivedButNotUsed, TechnicalAssetId: id})
+		}
+	}
+	if len(lineage.OriginIds) == 0 && len(lineage.Flows) > 0 {
+		lineage.Inconsistencies = append(lineage.Inconsistencies, DataLineageInconsistency{Kind: WithoutOrigin})
+	}
+
+	// follow the flows from the origins, or from wherever the data is sent from when there are none
+	queue := append(make([]string, 0), lineage.OriginIds...)
+	if len(queue) == 0 {
+		for _, flow := range lineage.Flows {
+			if !contains(queue, flow.FromId) {
+				queue = append(queue, flow.FromId)
+			}
+		}
+	}
+	reached := make(map[string]bool)
+	travelled := make(map[string]bool)
+	for _, id := range queue {
+		reached[id] = true
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, flow := range outgoing[id] {
+			travelled[flow.CommunicationLinkId] = true
+			if !reached[flow.ToId] {
+				reached[flow.ToId] = true
+				queue = append(queue, flow.ToId)
+			}
+		}
+	}
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if reached[id] {
+			lineage.TechnicalAssetIds = append(lineage.TechnicalAssetIds, id)
+		}
+	}
+	for _, flow := range lineage.Flows {
+		if travelled[flow.CommunicationLinkId] && !contains(lineage.CommunicationLinkIds, flow.CommunicationLinkId) {
+			lineage.CommunicationLinkIds = append(lineage.CommunicationLinkIds, flow.CommunicationLinkId)
+		}
+	}
+	return lineage
+}
+
+func (what DataLineage) hasInconsistency(kind DataLineageInconsistencyKind, technicalAssetId string, communicationLinkId string) bool {
+	for _, inconsistency := range what.Inconsistencies {
+		if inconsistency.Kind == kind && inconsistency.TechnicalAssetId == technicalAssetId && inconsistency.CommunicationLinkId == communicationLinkId {
+			return true
+		}
+	}
+	return false
+}
+
+// dataFlowComponents returns the strongly connected component (Tarjan) of each technical asset holding or transferring the data asset
+func dataFlowComponents(parsedModel *ParsedModel, holders []string, incoming map[string][]DataFlow, outgoing map[string][]DataFlow) map[string]int {
+	components := make(map[string]int)
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := make([]string, 0)
+	counter := 0
+	var connect func(id string)
+	connect = func(id string) {
+		index[id] = counter
+		lowLink[id] = counter
+		counter++
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, flow := range outgoing[id] {
+			if _, visited := index[flow.ToId]; !visited {
+				connect(flow.ToId)
+				if lowLink[flow.ToId] < lowLink[id] {
+					lowLink[id] = lowLink[flow.ToId]
+				}
+			} else if onStack[flow.ToId] && index[flow.ToId] < lowLink[id] {
+				lowLink[id] = index[flow.ToId]
+			}
+		}
+		if lowLink[id] == index[id] {
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				components[member] = index[id]
+				if member == id {
+					break
+				}
+			}
+		}
+	}
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		if _, visited := index[id]; !visited && (contains(holders, id) || len(incoming[id]) > 0 || len(outgoing[id]) > 0) {
+			connect(id)
+		}
+	}
+	return components
+}
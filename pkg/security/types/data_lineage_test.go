@@ -0,0 +1,142 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lineageTest struct {
+	technicalAssets   []TechnicalAsset
+	origins           []string
+	technicalAssetIds []string
+	links             []string
+	inconsistencies   []DataLineageInconsistency
+}
+
+func lineageModel(technicalAssets []TechnicalAsset) *ParsedModel {
+	parsedModel := &ParsedModel{
+		DataAssets:         map[string]DataAsset{"orders": {Id: "orders"}},
+		TechnicalAssets:    make(map[string]TechnicalAsset),
+		CommunicationLinks: make(map[string]CommunicationLink),
+	}
+	for _, technicalAsset := range technicalAssets {
+		parsedModel.TechnicalAssets[technicalAsset.Id] = technicalAsset
+		for _, link := range technicalAsset.CommunicationLinks {
+			parsedModel.CommunicationLinks[link.Id] = link
+		}
+	}
+	return parsedModel
+}
+
+func sent(source string, target string) CommunicationLink {
+	return CommunicationLink{Id: source + ">" + target, Title: target, SourceId: source, TargetId: target, DataAssetsSent: []string{"orders"}}
+}
+
+func TestDataAssetLineage(t *testing.T) {
+	testCases := map[string]lineageTest{
+		"consistent": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "app", Type: Process, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []CommunicationLink{sent("app", "db")}},
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}},
+			},
+			origins:           []string{"app"},
+			technicalAssetIds: []string{"app", "db"},
+			links:             []string{"app>db"},
+		},
+		"only read from datastore": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "app", Type: Process, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []CommunicationLink{
+					{Id: "app>db", Title: "db", SourceId: "app", TargetId: "db", DataAssetsReceived: []string{"orders"}},
+				}},
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}},
+			},
+			origins:           []string{"db"},
+			technicalAssetIds: []string{"app", "db"},
+			links:             []string{"app>db"},
+			inconsistencies:   []DataLineageInconsistency{{Kind: StoredButNeverReceived, TechnicalAssetId: "db"}},
+		},
+		"cycle": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "app", Type: Process, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []CommunicationLink{sent("app", "db")}},
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}, CommunicationLinks: []CommunicationLink{sent("db", "app")}},
+			},
+			origins:           []string{"app", "db"},
+			technicalAssetIds: []string{"app", "db"},
+			links:             []string{"app>db", "db>app"},
+		},
+		"stored but never received": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "app", Type: Process, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []CommunicationLink{sent("app", "web")}},
+				{Id: "web", Type: Process, DataAssetsProcessed: []string{"orders"}},
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}},
+			},
+			origins:           []string{"app", "db"},
+			technicalAssetIds: []string{"app", "db", "web"},
+			links:             []string{"app>web"},
+			inconsistencies:   []DataLineageInconsistency{{Kind: StoredButNeverReceived, TechnicalAssetId: "db"}},
+		},
+		"sent but not available": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "proxy", Type: Process, CommunicationLinks: []CommunicationLink{sent("proxy", "db")}},
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}},
+			},
+			technicalAssetIds: []string{"db", "proxy"},
+			links:             []string{"proxy>db"},
+			inconsistencies: []DataLineageInconsistency{
+				{Kind: SentButNotAvailable, TechnicalAssetId: "proxy", CommunicationLinkId: "proxy>db"},
+				{Kind: WithoutOrigin},
+			},
+		},
+		"received but not used": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "app", Type: Process, DataAssetsProcessed: []string{"orders"}, CommunicationLinks: []CommunicationLink{sent("app", "sink")}},
+				{Id: "sink", Type: Process},
+			},
+			origins:           []string{"app"},
+			technicalAssetIds: []string{"app", "sink"},
+			links:             []string{"app>sink"},
+			inconsistencies:   []DataLineageInconsistency{{Kind: ReceivedButNotUsed, TechnicalAssetId: "sink"}},
+		},
+		"not transferred": {
+			technicalAssets: []TechnicalAsset{
+				{Id: "db", Type: Datastore, DataAssetsStored: []string{"orders"}},
+			},
+			origins:           []string{"db"},
+			technicalAssetIds: []string{"db"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsedModel := lineageModel(testCase.technicalAssets)
+			lineage := parsedModel.DataAssets["orders"].Lineage(parsedModel)
+
+			assert.Equal(t, "orders", lineage.DataAssetId)
+			assert.Equal(t, nonNil(testCase.origins), lineage.OriginIds)
+			assert.Equal(t, nonNil(testCase.technicalAssetIds), lineage.TechnicalAssetIds)
+			assert.Equal(t, nonNil(testCase.links), lineage.CommunicationLinkIds)
+			if testCase.inconsistencies == nil {
+				testCase.inconsistencies = make([]DataLineageInconsistency, 0)
+			}
+			assert.Equal(t, testCase.inconsistencies, lineage.Inconsistencies)
+		})
+	}
+}
+
+func TestDataLineages(t *testing.T) {
+	parsedModel := lineageModel(nil)
+	parsedModel.DataAssets["customers"] = DataAsset{Id: "customers"}
+
+	lineages := DataLineages(parsedModel)
+	assert.Len(t, lineages, 2)
+	assert.Equal(t, "customers", lineages[0].DataAssetId)
+	assert.Equal(t, "orders", lineages[1].DataAssetId)
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return make([]string, 0)
+	}
+	return values
+}
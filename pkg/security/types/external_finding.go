@@ -0,0 +1,14 @@
+package types
+
+// ExternalFinding is a finding of an external scanner (SAST, DAST, ...) attached to a technical asset
+type ExternalFinding struct {
+	Tool     string  `json:"tool,omitempty" yaml:"tool,omitempty"`
+	RuleId   string  `json:"rule_id,omitempty" yaml:"rule_id,omitempty"`
+	Title    string  `json:"title,omitempty" yaml:"title,omitempty"`
+	Message  string  `json:"message,omitempty" yaml:"message,omitempty"`
+	Level    string  `json:"level,omitempty" yaml:"level,omitempty"` // error, warning, note or none
+	Score    float64 `json:"score,omitempty" yaml:"score,omitempty"` // security severity (0-10), if given by the scanner
+	CWE      int     `json:"cwe,omitempty" yaml:"cwe,omitempty"`
+	Location string  `json:"location,omitempty" yaml:"location,omitempty"`
+	Line     int     `json:"line,omitempty" yaml:"line,omitempty"`
+}
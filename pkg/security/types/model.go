@@ -40,6 +40,7 @@ type ParsedModel struct {
 	IndividualRiskCategories                      map[string]RiskCategory      `json:"individual_risk_categories,omitempty" yaml:"individual_risk_categories,omitempty"`
 	BuiltInRiskCategories                         map[string]RiskCategory      `json:"built_in_risk_categories,omitempty" yaml:"built_in_risk_categories,omitempty"`
 	RiskTracking                                  map[string]RiskTracking      `json:"risk_tracking,omitempty" yaml:"risk_tracking,omitempty"`
+	RiskRatingOverrides                           []RiskRatingOverride         `json:"risk_rating_overrides,omitempty" yaml:"risk_rating_overrides,omitempty"`
 	CommunicationLinks                            map[string]CommunicationLink `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	AllSupportedTags                              map[string]bool              `json:"all_supported_tags,omitempty" yaml:"all_supported_tags,omitempty"`
 	DiagramTweakNodesep                           int                          `json:"diagram_tweak_nodesep,omitempty" yaml:"diagram_tweak_nodesep,omitempty"`
@@ -55,6 +56,7 @@ type ParsedModel struct {
 	DirectContainingTrustBoundaryMappedByTechnicalAssetId map[string]TrustBoundary       `json:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty" yaml:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty"`
 	GeneratedRisksByCategory                              map[string][]Risk              `json:"generated_risks_by_category,omitempty" yaml:"generated_risks_by_category,omitempty"`
 	GeneratedRisksBySyntheticId                           map[string]Risk                `json:"generated_risks_by_synthetic_id,omitempty" yaml:"generated_risks_by_synthetic_id,omitempty"`
+	RiskRuleProfiles                                      []RiskRuleProfile              `json:"risk_rule_profiles,omitempty" yaml:"risk_rule_profiles,omitempty"`
 }
 
 func (parsedModel *ParsedModel) AddToListOfSupportedTags(tags []string) {
@@ -0,0 +1,37 @@
+package types
+
+// sources of RAA factors
+const (
+	RAAOwnRating     = "own rating"
+	RAAProcessedData = "processed data"
+	RAAStoredData    = "stored data"
+	RAASentData      = "sent data"
+	RAAReceivedData  = "received data"
+)
+
+// RAABreakdown explains the RAA of a technical asset by what contributes to its attacker attractiveness score
+type RAABreakdown struct {
+	Factors            []RAAFactor `json:"factors,omitempty" yaml:"factors,omitempty"`
+	Multiplier         float64     `json:"multiplier" yaml:"multiplier"` // by technology, type and multi-tenancy
+	Score              float64     `json:"score" yaml:"score"`           // sum of the factors times the multiplier
+	PivotingBonus      float64     `json:"pivoting_bonus,omitempty" yaml:"pivoting_bonus,omitempty"`
+	PivotingNeighbours []string    `json:"pivoting_neighbours,omitempty" yaml:"pivoting_neighbours,omitempty"` // technical asset ids causing the bonus
+	Centrality         float64     `json:"centrality,omitempty" yaml:"centrality,omitempty"`                   // weighted PageRank the RAA is relative to instead of the score (pagerank algorithm)
+	Minimum            float64     `json:"minimum" yaml:"minimum"`                                             // score (or centrality) of all technical assets, the RAA is relative to
+	Maximum            float64     `json:"maximum" yaml:"maximum"`
+}
+
+// RAAFactor is the contribution of the own CIA rating or of a data asset to the attacker attractiveness score
+type RAAFactor struct {
+	Source              string  `json:"source" yaml:"source"`
+	DataAssetId         string  `json:"data_asset_id,omitempty" yaml:"data_asset_id,omitempty"`
+	CommunicationLinkId string  `json:"communication_link_id,omitempty" yaml:"communication_link_id,omitempty"`
+	QuantityFactor      float64 `json:"quantity_factor,omitempty" yaml:"quantity_factor,omitempty"` // applied to confidentiality and integrity
+	Confidentiality     float64 `json:"confidentiality" yaml:"confidentiality"`
+	Integrity           float64 `json:"integrity" yaml:"integrity"`
+	Availability        float64 `json:"availability" yaml:"availability"`
+}
+
+func (what RAAFactor) Sum() float64 {
+	return what.Confidentiality + what.Integrity + what.Availability
+}
@@ -15,6 +15,8 @@ type Risk struct {
 	MostRelevantCommunicationLinkId string                     `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty"`
 	DataBreachProbability           DataBreachProbability      `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
 	DataBreachTechnicalAssetIDs     []string                   `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty"`
+	CWE                             int                        `yaml:"cwe,omitempty" json:"cwe,omitempty"` // only when more specific than the CWE of the category
+	RatingAdjustment                *RiskRatingAdjustment      `yaml:"rating_adjustment,omitempty" json:"rating_adjustment,omitempty"`
 	// TODO: refactor all "Id" here to "ID"?
 }
 
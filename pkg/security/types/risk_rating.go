@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+)
+
+// SeverityMatrix maps the exploitation likelihood and impact of risks to their severity, combinations missing in the matrix are rated by CalculateSeverity
+type SeverityMatrix map[RiskExploitationLikelihood]map[RiskExploitationImpact]RiskSeverity
+
+// ParseSeverityMatrix parses a matrix of likelihood to impact to severity values, e.g. {"likely": {"high": "critical"}}
+func ParseSeverityMatrix(values map[string]map[string]string) (SeverityMatrix, error) {
+	matrix := make(SeverityMatrix)
+	for likelihoodValue, impacts := range values {
+		likelihood, err := ParseRiskExploitationLikelihood(likelihoodValue)
+		if err != nil {
+			return nil, fmt.Errorf("unknown likelihood %q in severity matrix", likelihoodValue)
+		}
+		if _, ok := matrix[likelihood]; !ok {
+			matrix[likelihood] = make(map[RiskExploitationImpact]RiskSeverity)
+		}
+		for impactValue, severityValue := range impacts {
+			impact, err := ParseRiskExploitationImpact(impactValue)
+			if err != nil {
+				return nil, fmt.Errorf("unknown impact %q of likelihood %q in severity matrix", impactValue, likelihoodValue)
+			}
+			severity, err := ParseRiskSeverity(severityValue)
+			if err != nil {
+				return nil, fmt.Errorf("unknown severity %q of likelihood %q and impact %q in severity matrix", severityValue, likelihoodValue, impactValue)
+			}
+			matrix[likelihood][impact] = severity
+		}
+	}
+	return matrix, nil
+}
+
+func (what SeverityMatrix) Severity(likelihood RiskExploitationLikelihood, impact RiskExploitationImpact) RiskSeverity {
+	if severity, ok := what[likelihood][impact]; ok {
+		return severity
+	}
+	return CalculateSeverity(likelihood, impact)
+}
+
+// RiskRatingOverride sets the exploitation likelihood and/or impact of the risks of a category, optionally only of risks
+// at technical assets, communication links, trust boundaries or shared runtimes tagged with any of the tags
+type RiskRatingOverride struct {
+	Title                  string                      `json:"title,omitempty" yaml:"title,omitempty"`
+	CategoryId             string                      `json:"category,omitempty" yaml:"category,omitempty"`
+	Tags                   []string                    `json:"tags,omitempty" yaml:"tags,omitempty"`
+	ExploitationLikelihood *RiskExploitationLikelihood `json:"exploitation_likelihood,omitempty" yaml:"exploitation_likelihood,omitempty"`
+	ExploitationImpact     *RiskExploitationImpact     `json:"exploitation_impact,omitempty" yaml:"exploitation_impact,omitempty"`
+	Justification          string                      `json:"justification,omitempty" yaml:"justification,omitempty"`
+}
+
+func (what RiskRatingOverride) Matches(parsedModel *ParsedModel, risk Risk) bool {
+	if risk.CategoryId != what.CategoryId {
+		return false
+	}
+	if len(what.Tags) == 0 {
+		return true
+	}
+
+	technicalAssetIds := make([]string, 0)
+	if len(risk.MostRelevantTechnicalAssetId) > 0 {
+		technicalAssetIds = append(technicalAssetIds, risk.MostRelevantTechnicalAssetId)
+	}
+	if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok {
+		if link.IsTaggedWithAny(what.Tags...) {
+			return true
+		}
+		technicalAssetIds = append(technicalAssetIds, link.SourceId, link.TargetId)
+	}
+	for _, id := range technicalAssetIds {
+		if technicalAsset, ok := parsedModel.TechnicalAssets[id]; ok && technicalAsset.IsTaggedWithAnyTraversingUp(parsedModel, what.Tags...) {
+			return true
+		}
+	}
+	if trustBoundary, ok := parsedModel.TrustBoundaries[risk.MostRelevantTrustBoundaryId]; ok && trustBoundary.IsTaggedWithAnyTraversingUp(parsedModel, what.Tags...) {
+		return true
+	}
+	if sharedRuntime, ok := parsedModel.SharedRuntimes[risk.MostRelevantSharedRuntimeId]; ok && sharedRuntime.IsTaggedWithAny(what.Tags...) {
+		return true
+	}
+	return false
+}
+
+// RiskRatingAdjustment is the rating of the risk rule (or individual risk) before the severity matrix, rating overrides
+// or external findings of the same CWE changed it
+type RiskRatingAdjustment struct {
+	OriginalSeverity               RiskSeverity               `json:"original_severity" yaml:"original_severity"`
+	OriginalExploitationLikelihood RiskExploitationLikelihood `json:"original_exploitation_likelihood" yaml:"original_exploitation_likelihood"`
+	OriginalExploitationImpact     RiskExploitationImpact     `json:"original_exploitation_impact" yaml:"original_exploitation_impact"`
+	Reasons                        []string                   `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+}
@@ -0,0 +1,185 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type parseSeverityMatrixTest struct {
+	input         map[string]map[string]string
+	expected      SeverityMatrix
+	expectedError string
+}
+
+func TestParseSeverityMatrix(t *testing.T) {
+	testCases := map[string]parseSeverityMatrixTest{
+		"empty": {
+			input:    nil,
+			expected: SeverityMatrix{},
+		},
+		"valid": {
+			input: map[string]map[string]string{
+				"likely":   {"high": "critical", "low": "medium"},
+				"unlikely": {"very-high": "high"},
+			},
+			expected: SeverityMatrix{
+				Likely:   {HighImpact: CriticalSeverity, LowImpact: MediumSeverity},
+				Unlikely: {VeryHighImpact: HighSeverity},
+			},
+		},
+		"unknown likelihood": {
+			input:         map[string]map[string]string{"sometimes": {"high": "critical"}},
+			expectedError: `unknown likelihood "sometimes" in severity matrix`,
+		},
+		"unknown impact": {
+			input:         map[string]map[string]string{"likely": {"huge": "critical"}},
+			expectedError: `unknown impact "huge" of likelihood "likely" in severity matrix`,
+		},
+		"unknown severity": {
+			input:         map[string]map[string]string{"likely": {"high": "severe"}},
+			expectedError: `unknown severity "severe" of likelihood "likely" and impact "high" in severity matrix`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			matrix, err := ParseSeverityMatrix(testCase.input)
+			if len(testCase.expectedError) > 0 {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, matrix)
+		})
+	}
+}
+
+func TestSeverityMatrixSeverity(t *testing.T) {
+	matrix := SeverityMatrix{Likely: {HighImpact: LowSeverity}}
+
+	assert.Equal(t, LowSeverity, matrix.Severity(Likely, HighImpact))
+	assert.Equal(t, CalculateSeverity(Likely, MediumImpact), matrix.Severity(Likely, MediumImpact), "missing impact of likelihood")
+	assert.Equal(t, CalculateSeverity(Frequent, HighImpact), matrix.Severity(Frequent, HighImpact), "missing likelihood")
+	assert.Equal(t, CalculateSeverity(Likely, HighImpact), SeverityMatrix(nil).Severity(Likely, HighImpact), "no matrix")
+}
+
+type riskRatingOverrideMatchesTest struct {
+	override RiskRatingOverride
+	risk     Risk
+	expected bool
+}
+
+func TestRiskRatingOverrideMatches(t *testing.T) {
+	parsedModel := &ParsedModel{
+		TechnicalAssets: map[string]TechnicalAsset{
+			"web":     {Id: "web", Tags: []string{"web"}},
+			"db":      {Id: "db"},
+			"worker":  {Id: "worker"},
+			"runtime": {Id: "runtime"},
+		},
+		CommunicationLinks: map[string]CommunicationLink{
+			"web>db":     {Id: "web>db", SourceId: "web", TargetId: "db"},
+			"worker>db":  {Id: "worker>db", SourceId: "worker", TargetId: "db", Tags: []string{"vpn"}},
+			"db>runtime": {Id: "db>runtime", SourceId: "db", TargetId: "runtime"},
+		},
+		TrustBoundaries: map[string]TrustBoundary{
+			"dmz":      {Id: "dmz", TechnicalAssetsInside: []string{"worker"}, TrustBoundariesNested: []string{"internal"}, Tags: []string{"dmz"}},
+			"internal": {Id: "internal", TechnicalAssetsInside: []string{"db"}, Tags: []string{"internal"}},
+		},
+		SharedRuntimes: map[string]SharedRuntime{
+			"kubernetes": {Id: "kubernetes", TechnicalAssetsRunning: []string{"runtime"}, Tags: []string{"k8s"}},
+		},
+	}
+
+	testCases := map[string]riskRatingOverrideMatchesTest{
+		"other category": {
+			override: RiskRatingOverride{CategoryId: "other"},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "web"},
+			expected: false,
+		},
+		"category without tags": {
+			override: RiskRatingOverride{CategoryId: "test"},
+			risk:     Risk{CategoryId: "test"},
+			expected: true,
+		},
+		"tagged technical asset": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"other", "web"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "web"},
+			expected: true,
+		},
+		"untagged technical asset": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"web"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "worker"},
+			expected: false,
+		},
+		"technical asset in tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"dmz"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "worker"},
+			expected: true,
+		},
+		"technical asset in nested trust boundary of tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"dmz"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "db"},
+			expected: true,
+		},
+		"technical asset in tagged shared runtime": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"k8s"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTechnicalAssetId: "runtime"},
+			expected: true,
+		},
+		"tagged communication link": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"vpn"}},
+			risk:     Risk{CategoryId: "test", MostRelevantCommunicationLinkId: "worker>db"},
+			expected: true,
+		},
+		"communication link from tagged technical asset": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"web"}},
+			risk:     Risk{CategoryId: "test", MostRelevantCommunicationLinkId: "web>db"},
+			expected: true,
+		},
+		"communication link to technical asset in tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"internal"}},
+			risk:     Risk{CategoryId: "test", MostRelevantCommunicationLinkId: "web>db"},
+			expected: true,
+		},
+		"untagged communication link": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"vpn"}},
+			risk:     Risk{CategoryId: "test", MostRelevantCommunicationLinkId: "web>db"},
+			expected: false,
+		},
+		"tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"internal"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTrustBoundaryId: "internal"},
+			expected: true,
+		},
+		"trust boundary nested in tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"dmz"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTrustBoundaryId: "internal"},
+			expected: true,
+		},
+		"trust boundary containing tagged trust boundary": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"internal"}},
+			risk:     Risk{CategoryId: "test", MostRelevantTrustBoundaryId: "dmz"},
+			expected: false,
+		},
+		"tagged shared runtime": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"k8s"}},
+			risk:     Risk{CategoryId: "test", MostRelevantSharedRuntimeId: "kubernetes"},
+			expected: true,
+		},
+		"tags without any element": {
+			override: RiskRatingOverride{CategoryId: "test", Tags: []string{"web"}},
+			risk:     Risk{CategoryId: "test"},
+			expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.override.Matches(parsedModel, testCase.risk))
+		})
+	}
+}
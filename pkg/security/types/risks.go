@@ -124,7 +124,16 @@ func SortByRiskCategoryHighestContainingRiskSeveritySortStillAtRisk(parsedModel
 
 type RiskStatistics struct {
 	// TODO add also some more like before / after (i.e. with mitigation applied)
-	Risks map[string]map[string]int `yaml:"risks" json:"risks"`
+	Risks     map[string]map[string]int `yaml:"risks" json:"risks"`
+	RiskRules []RiskRuleProfile         `yaml:"risk_rules,omitempty" json:"risk_rules,omitempty"`
+}
+
+// RiskRuleProfile is how a risk rule ran during risk generation
+type RiskRuleProfile struct {
+	RiskRuleId string  `yaml:"risk_rule_id" json:"risk_rule_id"`
+	Duration   float64 `yaml:"duration_seconds" json:"duration_seconds"`
+	Risks      int     `yaml:"risks" json:"risks"`
+	Failure    string  `yaml:"failure,omitempty" json:"failure,omitempty"`
 }
 
 func SortByRiskSeverity(risks []Risk, parsedModel *ParsedModel) {
@@ -824,5 +833,6 @@ func OverallRiskStatistics(parsedModel *ParsedModel) RiskStatistics {
 			result.Risks[risk.Severity.String()][risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel).String()]++
 		}
 	}
+	result.RiskRules = parsedModel.RiskRuleProfiles
 	return result
 }
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// SoftwareComponent is a third-party component (library, framework, ...) of a technical asset, as listed in its SBOM
+type SoftwareComponent struct {
+	Type     string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Group    string   `json:"group,omitempty" yaml:"group,omitempty"`
+	Name     string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Version  string   `json:"version,omitempty" yaml:"version,omitempty"`
+	PURL     string   `json:"purl,omitempty" yaml:"purl,omitempty"`
+	Licenses []string `json:"licenses,omitempty" yaml:"licenses,omitempty"`
+	// will be set by matching against a vulnerability database:
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty" yaml:"vulnerabilities,omitempty"`
+}
+
+// Vulnerability is a known vulnerability (advisory) of a software component
+type Vulnerability struct {
+	Id      string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Score   float64  `json:"score,omitempty" yaml:"score,omitempty"` // CVSS base score (0-10)
+	CWE     int      `json:"cwe,omitempty" yaml:"cwe,omitempty"`
+	Fixed   string   `json:"fixed,omitempty" yaml:"fixed,omitempty"` // first version fixing the vulnerability, if known
+}
+
+// ComponentHint is a technology hint derived from the components of a technical asset
+type ComponentHint string
+
+const (
+	XmlParserHint       ComponentHint = "xml-parser"
+	DeserializationHint ComponentHint = "deserialization"
+)
+
+// parts of component groups, names or package urls (lower case) hinting at a technology
+var componentHints = map[ComponentHint][]string{
+	XmlParserHint: {"xerces", "woodstox", "aalto-xml", "dom4j", "jdom", "xstream", "jackson-dataformat-xml", "jaxb", "xmlbeans",
+		"saxon", "spring-oxm", "lxml", "libxml", "nokogiri", "xml2js", "fast-xml-parser", "xmldom", "simplexml", "system.xml"},
+	DeserializationHint: {"springframework", "commons-collections", "commons-beanutils", "xstream", "jackson-databind", "snakeyaml",
+		"fastjson", "kryo", "hessian", "pyyaml", "jsonpickle", "node-serialize", "newtonsoft.json"},
+}
+
+func (what SoftwareComponent) Title() string {
+	if len(what.Group) > 0 {
+		return what.Group + ":" + what.Name
+	}
+	return what.Name
+}
+
+// HasHint tells whether the component is known for the given technology
+func (what SoftwareComponent) HasHint(hint ComponentHint) bool {
+	text := strings.ToLower(what.Group + "/" + what.Name + " " + what.PURL)
+	for _, part := range componentHints[hint] {
+		if strings.Contains(text, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// ComponentUsage is an entry of the component inventory of a model
+type ComponentUsage struct {
+	SoftwareComponent
+	TechnicalAssets []string `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"`
+}
+
+// ComponentInventory aggregates the components of all technical assets, sorted by title and version
+func ComponentInventory(parsedModel *ParsedModel) []ComponentUsage {
+	usages := make(map[string]*ComponentUsage)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		technicalAsset := parsedModel.TechnicalAssets[id]
+		for _, component := range technicalAsset.Components {
+			key := component.Title() + "@" + component.Version
+			usage, ok := usages[key]
+			if !ok {
+				usage = &ComponentUsage{SoftwareComponent: component}
+				usages[key] = usage
+			}
+			if !contains(usage.TechnicalAssets, technicalAsset.Id) {
+				usage.TechnicalAssets = append(usage.TechnicalAssets, technicalAsset.Id)
+			}
+		}
+	}
+
+	inventory := make([]ComponentUsage, 0, len(usages))
+	for _, usage := range usages {
+		inventory = append(inventory, *usage)
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		if inventory[i].Title() != inventory[j].Title() {
+			return inventory[i].Title() < inventory[j].Title()
+		}
+		return inventory[i].Version < inventory[j].Version
+	})
+	return inventory
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hasHintTest struct {
+	component SoftwareComponent
+	hint      ComponentHint
+	expected  bool
+}
+
+func TestSoftwareComponentHasHint(t *testing.T) {
+	testCases := map[string]hasHintTest{
+		"xml parser by name": {
+			component: SoftwareComponent{Group: "xerces", Name: "xercesImpl"},
+			hint:      XmlParserHint,
+			expected:  true,
+		},
+		"xml parser by package url": {
+			component: SoftwareComponent{Name: "parser", PURL: "pkg:npm/fast-xml-parser@4.3.2"},
+			hint:      XmlParserHint,
+			expected:  true,
+		},
+		"deserialization": {
+			component: SoftwareComponent{Group: "org.yaml", Name: "snakeyaml"},
+			hint:      DeserializationHint,
+			expected:  true,
+		},
+		"other hint": {
+			component: SoftwareComponent{Group: "org.yaml", Name: "snakeyaml"},
+			hint:      XmlParserHint,
+		},
+		"no hint": {
+			component: SoftwareComponent{Name: "lodash"},
+			hint:      DeserializationHint,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.component.HasHint(testCase.hint))
+		})
+	}
+}
+
+func TestComponentInventory(t *testing.T) {
+	lodash := SoftwareComponent{Name: "lodash", Version: "4.17.21"}
+	oldLodash := SoftwareComponent{Name: "lodash", Version: "4.17.15"}
+	jackson := SoftwareComponent{Group: "com.fasterxml.jackson.core", Name: "jackson-databind", Version: "2.16.0"}
+	parsedModel := &ParsedModel{TechnicalAssets: map[string]TechnicalAsset{
+		"web": {Id: "web", Components: []SoftwareComponent{lodash, oldLodash}},
+		"api": {Id: "api", Components: []SoftwareComponent{jackson, lodash, lodash}},
+	}}
+
+	assert.Equal(t, []ComponentUsage{
+		{SoftwareComponent: jackson, TechnicalAssets: []string{"api"}},
+		{SoftwareComponent: oldLodash, TechnicalAssets: []string{"web"}},
+		{SoftwareComponent: lodash, TechnicalAssets: []string{"api", "web"}},
+	}, ComponentInventory(parsedModel))
+}
@@ -37,8 +37,14 @@ type TechnicalAsset struct {
 	DataFormatsAccepted     []DataFormat             `json:"data_formats_accepted,omitempty" yaml:"data_formats_accepted,omitempty"`
 	CommunicationLinks      []CommunicationLink      `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	DiagramTweakOrder       int                      `json:"diagram_tweak_order,omitempty" yaml:"diagram_tweak_order,omitempty"`
+	SBOM                    string                   `json:"sbom,omitempty" yaml:"sbom,omitempty"`
+	// will be set by loading the SBOM:
+	Components []SoftwareComponent `json:"components,omitempty" yaml:"components,omitempty"`
+	// will be set by loading scanner results:
+	Findings []ExternalFinding `json:"findings,omitempty" yaml:"findings,omitempty"`
 	// will be set by separate calculation step:
-	RAA float64 `json:"raa,omitempty" yaml:"raa,omitempty"`
+	RAA          float64       `json:"raa,omitempty" yaml:"raa,omitempty"`
+	RAABreakdown *RAABreakdown `json:"raa_breakdown,omitempty" yaml:"raa_breakdown,omitempty"` // if explained by the calculation
 }
 
 func (what TechnicalAsset) IsTaggedWithAny(tags ...string) bool {
@@ -140,6 +146,28 @@ func (what TechnicalAsset) DataFormatsAcceptedSorted() []DataFormat {
 	return result
 }
 
+// ComponentsWithHint returns the components of the asset known for the given technology
+func (what TechnicalAsset) ComponentsWithHint(hint ComponentHint) []SoftwareComponent {
+	result := make([]SoftwareComponent, 0)
+	for _, component := range what.Components {
+		if component.HasHint(hint) {
+			result = append(result, component)
+		}
+	}
+	return result
+}
+
+// FindingsOfCWE returns the external findings of the asset with the given CWE
+func (what TechnicalAsset) FindingsOfCWE(cwe int) []ExternalFinding {
+	result := make([]ExternalFinding, 0)
+	for _, finding := range what.Findings {
+		if finding.CWE == cwe {
+			result = append(result, finding)
+		}
+	}
+	return result
+}
+
 func (what TechnicalAsset) CommunicationLinksSorted() []CommunicationLink {
 	result := make([]CommunicationLink, 0)
 	for _, format := range what.CommunicationLinks {
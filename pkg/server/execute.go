@@ -179,7 +179,14 @@ func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
 	dpi int) {
 	// Remember to also add the same args to the exec based sub-process calls!
 	var cmd *exec.Cmd
-	args := []string{"-model", modelFile, "-output", outputDir, "-execute-model-macro", s.config.ExecuteModelMacro, "-raa-run", s.config.RAAPlugin, "-custom-risk-rules-plugins", strings.Join(s.config.RiskRulesPlugins, ","), "-skip-risk-rules", s.config.SkipRiskRules, "-diagram-dpi", strconv.Itoa(dpi)}
+	args := []string{"-model", modelFile, "-output", outputDir, "-execute-model-macro", s.config.ExecuteModelMacro, "-raa-run", s.config.RAAPlugin, "-raa-algorithm", s.config.RAAAlgorithm, "-custom-risk-rules-plugins", strings.Join(s.config.RiskRulesPlugins, ","), "-skip-risk-rules", s.config.SkipRiskRules, "-diagram-dpi", strconv.Itoa(dpi)}
+	if len(s.config.RiskRulesFolder) > 0 {
+		args = append(args, "-custom-risk-rules-dir", s.config.RiskRulesFolder)
+	}
+	if len(s.config.Plugins) > 0 {
+		args = append(args, "-plugins", strings.Join(s.config.Plugins, ","))
+	}
+	args = append(args, "-risk-rules-parallelism", strconv.Itoa(s.config.RiskRulesParallelism), "-risk-rule-timeout", strconv.Itoa(s.config.RiskRuleTimeout))
 	if s.config.Verbose {
 		args = append(args, "-verbose")
 	}
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/threagile/threagile/pkg/common"
 	"github.com/threagile/threagile/pkg/model"
@@ -176,7 +177,14 @@ func RunServer(config *common.Config) {
 	router.DELETE("/models/:model-id/shared-runtimes/:shared-runtime-id", s.deleteSharedRuntime)
 
 	reporter := common.DefaultProgressReporter{Verbose: s.config.Verbose}
-	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, reporter)
+	s.customRiskRules = model.LoadCustomRiskRules(s.config.RiskRulesPlugins, model.NewWasmLimits(*s.config), reporter)
+	defer model.CloseCustomRiskRules(s.customRiskRules, reporter)
+	rulesError := model.LoadRiskRulesFolder(s.customRiskRules, s.config.RiskRulesFolder, reporter)
+	if rulesError != nil {
+		log.Fatalf("unable to load risk rules: %v", rulesError)
+	}
+	plugins := model.StartPlugins(s.config.Plugins, time.Duration(s.config.PluginTimeout)*time.Second, s.customRiskRules, reporter)
+	defer model.ShutdownPlugins(plugins, reporter)
 
 	fmt.Println("Threagile s running...")
 	_ = router.Run(":" + strconv.Itoa(s.config.ServerPort)) // listen and serve on 0.0.0.0:8080 or whatever port was specified
@@ -0,0 +1,518 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type asset struct {
+	resource *Resource
+	kind     assetKind
+	title    string
+	groups   []*Resource
+	networks []*Resource
+}
+
+type converter struct {
+	model          *input.Model
+	mapping        importer.Mapping
+	report         *importer.Report
+	takenIds       map[string]bool
+	byAddress      map[string][]*Resource
+	byValue        map[string][]*Resource
+	assets         []*asset
+	assetOf        map[*Resource]*asset
+	groupsOf       map[*Resource][]*Resource
+	boundaryTitles map[*Resource]string
+}
+
+// Import turns the resources of a terraform state or plan into a model: networks, subnets and security groups become
+// trust boundaries, compute, database, storage, queue and gateway resources technical assets (tagged for the
+// missing-cloud-hardening rule) and security group rules as well as integrations communication links
+func Import(show *Show, title string, mapping importer.Mapping) (*input.Model, *importer.Report) {
+	what := &converter{
+		model:          importer.NewModel(title),
+		mapping:        mapping,
+		report:         new(importer.Report),
+		takenIds:       make(map[string]bool),
+		byAddress:      make(map[string][]*Resource),
+		byValue:        make(map[string][]*Resource),
+		assetOf:        make(map[*Resource]*asset),
+		groupsOf:       make(map[*Resource][]*Resource),
+		boundaryTitles: make(map[*Resource]string),
+	}
+	what.model.AppDescription.Description = "Imported from terraform " + title
+
+	resources := show.Resources()
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Address < resources[j].Address
+	})
+
+	ignored := make(map[string]int)
+	for _, resource := range resources {
+		what.byAddress[baseAddress(resource.Address)] = append(what.byAddress[baseAddress(resource.Address)], resource)
+		for _, attribute := range identifyingAttributes {
+			if value := resource.String(attribute); len(value) > 0 {
+				what.byValue[value] = append(what.byValue[value], resource)
+			}
+		}
+
+		if !what.isKnown(resource.Type) {
+			ignored[resource.Type]++
+		}
+	}
+	for _, resourceType := range sortedKeys(ignored) {
+		what.report.Add("ignored %d resource(s) of type %v", ignored[resourceType], resourceType)
+	}
+
+	for _, resource := range resources {
+		if association, ok := associations[resource.Type]; ok {
+			for _, member := range what.resolve(resource, association[0]) {
+				what.groupsOf[member] = append(what.groupsOf[member], what.resolveTypes(resource, association[1], boundaryKinds)...)
+			}
+		}
+	}
+
+	what.addAssets(resources)
+	what.addBoundaries(resources)
+	what.addSecurityGroupRules(resources)
+	what.addLinks(resources)
+
+	importer.AddQuestion(what.model, importer.DataAssetsQuestion, "")
+	sort.Strings(what.model.TagsAvailable)
+	return what.model, what.report
+}
+
+func (what *converter) isKnown(resourceType string) bool {
+	_, isAsset := assetKinds[resourceType]
+	_, isBoundary := boundaryKinds[resourceType]
+	_, isIndirection := indirections[resourceType]
+	_, isAssociation := associations[resourceType]
+	_, isEncryption := encryptionResources[resourceType]
+	_, isLink := linkKinds[resourceType]
+	_, isRule := ruleTypes[resourceType]
+	return isAsset || isBoundary || isIndirection || isAssociation || isEncryption || isLink || isRule || resourceType == "aws_lb_listener" || resourceType == "aws_lb_target_group"
+}
+
+// Technical Assets ===============================================================================
+
+func (what *converter) addAssets(resources []*Resource) {
+	nameCount := make(map[string]int)
+	for _, resource := range resources {
+		if _, ok := assetKinds[resource.Type]; ok {
+			nameCount[resource.DisplayName()]++
+		}
+	}
+
+	for _, resource := range resources {
+		kind, ok := assetKinds[resource.Type]
+		if !ok {
+			continue
+		}
+
+		title := resource.DisplayName()
+		if nameCount[title] > 1 {
+			title = fmt.Sprintf("%v (%v)", title, resource.Address)
+		}
+		title = importer.UniqueKey(what.model.TechnicalAssets, title)
+
+		technology := kind.technology
+		for _, candidate := range []string{resource.Address, resource.DisplayName(), resource.Type} {
+			if technologyName, ok := what.mapping.Resolve(importer.MappingTechnology, candidate, importer.EnumNames(types.TechnicalAssetTechnologyValues())); ok {
+				technology, _ = types.ParseTechnicalAssetTechnology(technologyName)
+				break
+			}
+		}
+
+		technicalAsset := importer.NewTechnicalAsset(importer.UniqueID(resource.Address, what.takenIds), "Terraform resource "+resource.Address, technology)
+		technicalAsset.Machine = kind.machine.String()
+		technicalAsset.Tags = []string{kind.tag}
+		what.model.AddTagToModelInput(kind.tag, false, new([]string))
+		if kind.encryption != nil {
+			technicalAsset.Encryption = kind.encryption(resource).String()
+		}
+		if technology == types.UnknownTechnology {
+			importer.MarkForReview(what.model, &technicalAsset, fmt.Sprintf("Which technology is used by '%v' (%v)?", title, resource.Type), "")
+			what.report.Add("%v: unknown technology (add it to the mapping file to fix this)", resource.Address)
+		}
+		what.model.TechnicalAssets[title] = technicalAsset
+
+		current := &asset{resource: resource, kind: kind, title: title}
+		current.groups, current.networks = what.placement(resource, kind.placement)
+		what.assets = append(what.assets, current)
+		what.assetOf[resource] = current
+
+		if kind.internet != nil && kind.internet(resource) {
+			what.exposeToInternet(current, fmt.Sprintf("%v is reachable from the internet", resource.Address), kind.protocol)
+		}
+	}
+
+	for _, resource := range resources {
+		if path, ok := encryptionResources[resource.Type]; ok {
+			for _, encrypted := range what.resolveAssets(resource, path) {
+				what.update(encrypted, func(technicalAsset *input.TechnicalAsset) {
+					technicalAsset.Encryption = types.Transparent.String()
+				})
+			}
+		}
+	}
+}
+
+// placement resolves the security groups and networks (like subnets) an asset resides in, also through indirections like
+// network interfaces and through security groups associated with those
+func (what *converter) placement(resource *Resource, paths [][]string) ([]*Resource, []*Resource) {
+	groups, networks := make([]*Resource, 0), make([]*Resource, 0)
+	visited := []*Resource{resource}
+
+	var walk func(current *Resource, paths [][]string, depth int)
+	walk = func(current *Resource, paths [][]string, depth int) {
+		for _, path := range paths {
+			for _, referenced := range what.resolve(current, path) {
+				if kind, ok := boundaryKinds[referenced.Type]; ok {
+					if kind.securityGroup {
+						groups = appendUnique(groups, referenced)
+					} else {
+						networks = appendUnique(networks, referenced)
+						visited = append(visited, referenced)
+					}
+				} else if indirection, ok := indirections[referenced.Type]; ok && depth < 3 {
+					visited = append(visited, referenced)
+					walk(referenced, indirection, depth+1)
+				}
+			}
+		}
+	}
+	walk(resource, paths, 0)
+
+	for _, current := range visited {
+		for _, group := range what.groupsOf[current] {
+			groups = appendUnique(groups, group)
+		}
+	}
+	return groups, networks
+}
+
+// subnet returns the innermost network of an asset
+func (what *asset) subnet() *Resource {
+	for _, network := range what.networks {
+		if len(boundaryKinds[network.Type].parents) > 0 {
+			return network
+		}
+	}
+	if len(what.networks) > 0 {
+		return what.networks[0]
+	}
+	return nil
+}
+
+// Trust Boundaries ===============================================================================
+
+func (what *converter) addBoundaries(resources []*Resource) {
+	boundaries := make([]*Resource, 0)
+	nameCount := make(map[string]int)
+	for _, resource := range resources {
+		if kind, ok := boundaryKinds[resource.Type]; ok {
+			boundaries = append(boundaries, resource)
+			nameCount[kind.label+" "+resource.DisplayName()]++
+		}
+	}
+
+	inside := make(map[*Resource][]string)
+	nested := make(map[*Resource][]*Resource)
+	members := make(map[*Resource][]*asset)
+	for _, current := range what.assets {
+		for _, group := range current.groups {
+			members[group] = append(members[group], current)
+		}
+
+		switch {
+		case len(current.groups) > 0:
+			inside[current.groups[0]] = append(inside[current.groups[0]], what.model.TechnicalAssets[current.title].ID)
+			if len(current.groups) > 1 {
+				names := make([]string, 0)
+				for _, group := range current.groups {
+					names = append(names, group.Address)
+				}
+				what.update(current, func(technicalAsset *input.TechnicalAsset) {
+					importer.MarkForReview(what.model, technicalAsset, fmt.Sprintf("'%v' is member of the security groups %v, which trust boundary does it belong to?", current.title, strings.Join(names, ", ")), names[0])
+				})
+			}
+		case current.subnet() != nil:
+			inside[current.subnet()] = append(inside[current.subnet()], what.model.TechnicalAssets[current.title].ID)
+		}
+	}
+
+	for _, boundary := range boundaries {
+		kind := boundaryKinds[boundary.Type]
+		var parent *Resource
+		if kind.securityGroup {
+			parent = what.groupParent(boundary, members[boundary])
+		} else {
+			parent = first(what.resolveTypes(boundary, firstOrNil(kind.parents), boundaryKinds))
+		}
+		if parent != nil && parent != boundary {
+			nested[parent] = append(nested[parent], boundary)
+		}
+	}
+
+	// only boundaries containing assets are kept
+	var isUsed func(boundary *Resource) bool
+	isUsed = func(boundary *Resource) bool {
+		if len(inside[boundary]) > 0 {
+			return true
+		}
+		for _, child := range nested[boundary] {
+			if isUsed(child) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, boundary := range boundaries {
+		if !isUsed(boundary) {
+			continue
+		}
+		kind := boundaryKinds[boundary.Type]
+		title := kind.label + " " + boundary.DisplayName()
+		if nameCount[title] > 1 {
+			title = fmt.Sprintf("%v (%v)", title, boundary.Address)
+		}
+		title = importer.UniqueKey(what.model.TrustBoundaries, title)
+		what.boundaryTitles[boundary] = title
+
+		what.model.AddTagToModelInput(kind.tag, false, new([]string))
+		what.model.TrustBoundaries[title] = input.TrustBoundary{
+			ID:                    importer.UniqueID(boundary.Address, what.takenIds),
+			Description:           "Terraform resource " + boundary.Address,
+			Type:                  kind.boundaryType.String(),
+			Tags:                  []string{kind.tag},
+			TechnicalAssetsInside: inside[boundary],
+		}
+	}
+
+	for _, boundary := range boundaries {
+		title, ok := what.boundaryTitles[boundary]
+		if !ok {
+			continue
+		}
+		trustBoundary := what.model.TrustBoundaries[title]
+		for _, child := range nested[boundary] {
+			if childTitle, ok := what.boundaryTitles[child]; ok {
+				trustBoundary.TrustBoundariesNested = append(trustBoundary.TrustBoundariesNested, what.model.TrustBoundaries[childTitle].ID)
+			}
+		}
+		what.model.TrustBoundaries[title] = trustBoundary
+	}
+}
+
+// groupParent returns the subnet shared by all members of a security group, otherwise the network of the security group
+func (what *converter) groupParent(group *Resource, members []*asset) *Resource {
+	var subnet *Resource
+	for i, member := range members {
+		if i == 0 {
+			subnet = member.subnet()
+		} else if member.subnet() != subnet {
+			subnet = nil
+			break
+		}
+	}
+	if subnet != nil {
+		return subnet
+	}
+
+	if network := first(what.resolveTypes(group, firstOrNil(boundaryKinds[group.Type].parents), boundaryKinds)); network != nil {
+		return network
+	}
+	for _, member := range members {
+		if memberSubnet := member.subnet(); memberSubnet != nil {
+			return first(what.resolveTypes(memberSubnet, firstOrNil(boundaryKinds[memberSubnet.Type].parents), boundaryKinds))
+		}
+	}
+	return nil
+}
+
+// Communication Links ===============================================================================
+
+func (what *converter) addLinks(resources []*Resource) {
+	loadBalancersOf := make(map[*Resource][]*asset)
+	for _, resource := range resources {
+		if resource.Type != "aws_lb_listener" {
+			continue
+		}
+		for _, loadBalancer := range what.resolveAssets(resource, []string{"load_balancer_arn"}) {
+			for _, targetGroup := range what.resolve(resource, []string{"default_action", "target_group_arn"}) {
+				loadBalancersOf[targetGroup] = append(loadBalancersOf[targetGroup], loadBalancer)
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		kind, ok := linkKinds[resource.Type]
+		if !ok {
+			continue
+		}
+
+		sources := what.resolveAssets(resource, kind.source)
+		for _, targetGroup := range what.resolve(resource, kind.source) {
+			sources = append(sources, loadBalancersOf[targetGroup]...)
+		}
+		targets := what.resolveAssets(resource, kind.target)
+		if len(sources) == 0 || len(targets) == 0 {
+			what.report.Add("%v: unable to resolve the connected resources", resource.Address)
+			continue
+		}
+
+		for _, source := range sources {
+			for _, target := range targets {
+				what.link(source, target, fmt.Sprintf("%v %v", kind.description, resource.Address), target.kind.protocol)
+			}
+		}
+	}
+}
+
+func (what *converter) exposeToInternet(target *asset, description string, protocol types.Protocol) {
+	what.update(target, func(technicalAsset *input.TechnicalAsset) {
+		technicalAsset.Internet = true
+	})
+
+	clientTitle := importer.ExternalClient(what.model, what.takenIds)
+	what.linkFrom(clientTitle, target, description, protocol)
+}
+
+func (what *converter) link(source *asset, target *asset, description string, protocol types.Protocol) {
+	if source != target {
+		what.linkFrom(source.title, target, description, protocol)
+	}
+}
+
+// linkFrom adds a communication link unless the source already has one to the target
+func (what *converter) linkFrom(sourceTitle string, target *asset, description string, protocol types.Protocol) {
+	source := what.model.TechnicalAssets[sourceTitle]
+	targetId := what.model.TechnicalAssets[target.title].ID
+	for _, existing := range source.CommunicationLinks {
+		if existing.Target == targetId {
+			return
+		}
+	}
+
+	source.CommunicationLinks[importer.UniqueKey(source.CommunicationLinks, target.title)] = importer.NewCommunicationLink(targetId, description, protocol)
+	if protocol == types.UnknownProtocol {
+		importer.MarkForReview(what.model, &source, fmt.Sprintf("Which protocol is used by '%v' to access '%v'?", sourceTitle, target.title), "")
+	}
+	what.model.TechnicalAssets[sourceTitle] = source
+}
+
+func (what *converter) update(target *asset, change func(technicalAsset *input.TechnicalAsset)) {
+	technicalAsset := what.model.TechnicalAssets[target.title]
+	change(&technicalAsset)
+	what.model.TechnicalAssets[target.title] = technicalAsset
+}
+
+func (what *converter) ask(question string, answer string) {
+	if _, ok := what.model.Questions[question]; !ok {
+		importer.AddQuestion(what.model, question, answer)
+	}
+}
+
+// References ===============================================================================
+
+// resolve returns the resources referenced by the attribute at the path, either by value (like ids in states) or by
+// expression (plans, where ids are not known yet)
+func (what *converter) resolve(resource *Resource, path []string) []*Resource {
+	result := make([]*Resource, 0)
+	if len(path) == 0 {
+		return result
+	}
+	for _, value := range resource.Strings(path...) {
+		for _, candidate := range what.byValue[value] {
+			if candidate != resource {
+				result = appendUnique(result, candidate)
+			}
+		}
+	}
+	for _, address := range resource.references(path...) {
+		for _, candidate := range what.byAddress[address] {
+			if candidate != resource {
+				result = appendUnique(result, candidate)
+			}
+		}
+	}
+	return result
+}
+
+func (what *converter) resolveTypes(resource *Resource, path []string, kinds map[string]boundaryKind) []*Resource {
+	result := make([]*Resource, 0)
+	for _, referenced := range what.resolve(resource, path) {
+		if _, ok := kinds[referenced.Type]; ok {
+			result = append(result, referenced)
+		}
+	}
+	return result
+}
+
+func (what *converter) resolveAssets(resource *Resource, path []string) []*asset {
+	result := make([]*asset, 0)
+	for _, referenced := range what.resolve(resource, path) {
+		if current, ok := what.assetOf[referenced]; ok {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+func appendUnique(resources []*Resource, resource *Resource) []*Resource {
+	for _, existing := range resources {
+		if existing == resource {
+			return resources
+		}
+	}
+	return append(resources, resource)
+}
+
+func first(resources []*Resource) *Resource {
+	if len(resources) == 0 {
+		return nil
+	}
+	return resources[0]
+}
+
+func firstOrNil(paths [][]string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	return paths[0]
+}
+
+func singlePort(from int, to int) []int {
+	if from > 0 && from == to {
+		return []int{from}
+	}
+	return nil
+}
+
+// parsePorts reads single ports like "443" of port lists, ranges and wildcards are ignored
+func parsePorts(values []string) []int {
+	ports := make([]int, 0)
+	for _, value := range values {
+		if port, err := strconv.Atoi(value); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
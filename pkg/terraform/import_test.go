@@ -0,0 +1,145 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestImportState(t *testing.T) {
+	show, err := LoadFile("testdata/state.json")
+	require.NoError(t, err)
+
+	model, report := Import(show, "shop", importer.Mapping{})
+
+	require.Len(t, model.TechnicalAssets, 4)
+	web, orders, assets := model.TechnicalAssets["web"], model.TechnicalAssets["orders"], model.TechnicalAssets["shop-assets"]
+	assert.Equal(t, types.UnknownTechnology.String(), web.Technology)
+	assert.Equal(t, []string{"aws:ec2", importer.ReviewTag}, web.Tags)
+	assert.True(t, web.Internet)
+	assert.Equal(t, types.Transparent.String(), web.Encryption)
+	assert.Equal(t, types.Database.String(), orders.Technology)
+	assert.False(t, orders.Internet)
+	assert.Equal(t, types.NoneEncryption.String(), orders.Encryption)
+	assert.Equal(t, types.Transparent.String(), assets.Encryption, "encrypted by a separate resource")
+
+	require.Contains(t, web.CommunicationLinks, "orders")
+	assert.Equal(t, "aws-db-instance-db", web.CommunicationLinks["orders"].Target)
+	assert.Equal(t, types.SqlAccessProtocol.String(), web.CommunicationLinks["orders"].Protocol)
+	client := model.TechnicalAssets["External Client"]
+	require.Contains(t, client.CommunicationLinks, "web")
+	assert.Equal(t, types.HTTPS.String(), client.CommunicationLinks["web"].Protocol)
+
+	require.Len(t, model.TrustBoundaries, 4)
+	assert.Equal(t, []string{"aws-instance-web"}, model.TrustBoundaries["Security Group web"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"aws-db-instance-db"}, model.TrustBoundaries["Security Group db"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"aws-security-group-db", "aws-security-group-web"}, model.TrustBoundaries["Subnet private"].TrustBoundariesNested)
+	assert.Equal(t, []string{"aws-subnet-private"}, model.TrustBoundaries["VPC shop"].TrustBoundariesNested)
+	assert.Equal(t, types.NetworkCloudSecurityGroup.String(), model.TrustBoundaries["Security Group web"].Type)
+
+	assert.Contains(t, model.Questions, "Which systems in 10.1.0.0/16 are allowed to communicate by aws_security_group.db?")
+	assertReported(t, report, "ignored 1 resource(s) of type aws_iam_role")
+	assertReported(t, report, "aws_instance.web: unknown technology")
+}
+
+func TestImportStateWithMapping(t *testing.T) {
+	show, err := LoadFile("testdata/state.json")
+	require.NoError(t, err)
+
+	model, report := Import(show, "shop", importer.Mapping{importer.MappingTechnology: {"aws_instance.web": types.WebServer.String()}})
+
+	assert.Equal(t, types.WebServer.String(), model.TechnicalAssets["web"].Technology)
+	assert.NotContains(t, model.TechnicalAssets["web"].Tags, importer.ReviewTag)
+	for _, item := range report.Items {
+		assert.NotContains(t, item, "unknown technology")
+	}
+}
+
+func TestImportPlan(t *testing.T) {
+	show, err := LoadFile("testdata/plan.json")
+	require.NoError(t, err)
+
+	model, _ := Import(show, "api", importer.Mapping{})
+
+	require.Contains(t, model.TechnicalAssets, "orders-api")
+	require.Contains(t, model.TechnicalAssets, "orders")
+	api := model.TechnicalAssets["orders-api"]
+	assert.True(t, api.Internet)
+	assert.Equal(t, types.Gateway.String(), api.Technology)
+
+	// ids are unknown in plans, references of the configuration are resolved within the module
+	require.Contains(t, api.CommunicationLinks, "orders")
+	assert.Equal(t, "module-api-aws-lambda-function-orders-0", api.CommunicationLinks["orders"].Target)
+	assert.Equal(t, types.HTTPS.String(), api.CommunicationLinks["orders"].Protocol)
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.json")
+	assert.Error(t, err)
+
+	filename := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"format_version": "1.0"}`), 0600))
+	_, err = LoadFile(filename)
+	assert.ErrorContains(t, err, "neither values nor planned values")
+}
+
+type referencedAddressTest struct {
+	reference string
+	expected  string
+	ok        bool
+}
+
+func TestReferencedAddress(t *testing.T) {
+	testCases := map[string]referencedAddressTest{
+		"attribute": {
+			reference: "aws_vpc.main.id",
+			expected:  "aws_vpc.main",
+			ok:        true,
+		},
+		"resource": {
+			reference: "aws_vpc.main",
+			expected:  "aws_vpc.main",
+			ok:        true,
+		},
+		"indexed": {
+			reference: `aws_subnet.private["a"].id`,
+			expected:  "aws_subnet.private",
+			ok:        true,
+		},
+		"variable": {
+			reference: "var.vpc_id",
+		},
+		"data source": {
+			reference: "data.aws_ami.ubuntu.id",
+		},
+		"too short": {
+			reference: "aws_vpc",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := referencedAddress(testCase.reference)
+
+			assert.Equal(t, testCase.expected, actual)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
+
+func assertReported(t *testing.T, report *importer.Report, prefix string) {
+	t.Helper()
+	for _, item := range report.Items {
+		if strings.HasPrefix(item, prefix) {
+			return
+		}
+	}
+	t.Errorf("report misses %q in %q", prefix, report.Items)
+}
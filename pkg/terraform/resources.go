@@ -0,0 +1,228 @@
+package terraform
+
+import (
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+type assetKind struct {
+	technology types.TechnicalAssetTechnology
+	machine    types.TechnicalAssetMachine
+	protocol   types.Protocol
+	tag        string
+	placement  [][]string // attributes referencing subnets, security groups, networks or indirections to them
+	internet   func(resource *Resource) bool
+	encryption func(resource *Resource) types.EncryptionStyle
+}
+
+type boundaryKind struct {
+	label         string
+	boundaryType  types.TrustBoundaryType
+	tag           string
+	parents       [][]string // attributes referencing the enclosing network
+	securityGroup bool       // assets are placed into security groups, which are nested into the subnet of their assets
+}
+
+// cloud tags as understood by the missing-cloud-hardening rule
+const (
+	tagAWS   = "aws"
+	tagAzure = "azure"
+	tagGCP   = "gcp"
+)
+
+var assetKinds = map[string]assetKind{
+	// AWS
+	"aws_instance": {types.UnknownTechnology, types.Virtual, types.UnknownProtocol, "aws:ec2",
+		[][]string{{"subnet_id"}, {"vpc_security_group_ids"}, {"security_groups"}}, nil,
+		func(resource *Resource) types.EncryptionStyle {
+			return encryptedIf(resource.Bool("root_block_device", "encrypted") && !hasFalse(resource, "ebs_block_device", "encrypted"))
+		}},
+	"aws_lambda_function": {types.Function, types.Serverless, types.HTTPS, "aws:lambda",
+		[][]string{{"vpc_config", "subnet_ids"}, {"vpc_config", "security_group_ids"}}, nil, nil},
+	"aws_db_instance": {types.Database, types.Virtual, types.SqlAccessProtocol, "aws:rds",
+		[][]string{{"db_subnet_group_name"}, {"vpc_security_group_ids"}}, isTrue("publicly_accessible"), encryptedWhen("storage_encrypted")},
+	"aws_rds_cluster": {types.Database, types.Virtual, types.SqlAccessProtocol, "aws:rds",
+		[][]string{{"db_subnet_group_name"}, {"vpc_security_group_ids"}}, nil, encryptedWhen("storage_encrypted")},
+	"aws_dynamodb_table": {types.Database, types.Serverless, types.HTTPS, "aws:dynamodb",
+		nil, nil, alwaysEncrypted},
+	"aws_s3_bucket": {types.FileServer, types.Serverless, types.HTTPS, "aws:s3",
+		nil, nil, encryptedWhen("server_side_encryption_configuration")},
+	"aws_ebs_volume": {types.BlockStorage, types.Virtual, types.UnknownProtocol, "aws:ebs",
+		nil, nil, encryptedWhen("encrypted")},
+	"aws_sqs_queue": {types.MessageQueue, types.Serverless, types.HTTPS, "aws:sqs",
+		nil, nil, encryptedWhen("sqs_managed_sse_enabled", "kms_master_key_id")},
+	"aws_api_gateway_rest_api": {types.Gateway, types.Serverless, types.HTTPS, "aws:apigateway",
+		nil, func(resource *Resource) bool {
+			return !contains(resource.Strings("endpoint_configuration", "types"), "PRIVATE")
+		}, nil},
+	"aws_apigatewayv2_api": {types.Gateway, types.Serverless, types.HTTPS, "aws:apigateway",
+		nil, always, nil},
+	"aws_lb": {types.LoadBalancer, types.Virtual, types.HTTPS, tagAWS,
+		[][]string{{"subnets"}, {"security_groups"}}, isFalse("internal"), nil},
+	"aws_alb": {types.LoadBalancer, types.Virtual, types.HTTPS, tagAWS,
+		[][]string{{"subnets"}, {"security_groups"}}, isFalse("internal"), nil},
+	"aws_elasticache_replication_group": {types.Database, types.Virtual, types.NosqlAccessProtocol, tagAWS,
+		[][]string{{"subnet_group_name"}, {"security_group_ids"}}, nil, encryptedWhen("at_rest_encryption_enabled")},
+	"aws_elasticache_cluster": {types.Database, types.Virtual, types.NosqlAccessProtocol, tagAWS,
+		[][]string{{"subnet_group_name"}, {"security_group_ids"}}, nil, nil},
+
+	// Azure
+	"azurerm_linux_virtual_machine": {types.UnknownTechnology, types.Virtual, types.UnknownProtocol, "azure:vm",
+		[][]string{{"network_interface_ids"}}, nil, alwaysEncrypted},
+	"azurerm_windows_virtual_machine": {types.UnknownTechnology, types.Virtual, types.UnknownProtocol, "azure:vm",
+		[][]string{{"network_interface_ids"}}, nil, alwaysEncrypted},
+	"azurerm_linux_function_app": {types.Function, types.Serverless, types.HTTPS, "azure:functions",
+		[][]string{{"virtual_network_subnet_id"}}, isNotFalse("public_network_access_enabled"), nil},
+	"azurerm_windows_function_app": {types.Function, types.Serverless, types.HTTPS, "azure:functions",
+		[][]string{{"virtual_network_subnet_id"}}, isNotFalse("public_network_access_enabled"), nil},
+	"azurerm_mssql_server": {types.Database, types.Serverless, types.SqlAccessProtocol, "azure:sql",
+		nil, isTrue("public_network_access_enabled"), alwaysEncrypted},
+	"azurerm_postgresql_flexible_server": {types.Database, types.Serverless, types.SqlAccessProtocol, "azure:postgresql",
+		[][]string{{"delegated_subnet_id"}}, isTrue("public_network_access_enabled"), alwaysEncrypted},
+	"azurerm_mysql_flexible_server": {types.Database, types.Serverless, types.SqlAccessProtocol, "azure:mysql",
+		[][]string{{"delegated_subnet_id"}}, nil, alwaysEncrypted},
+	"azurerm_cosmosdb_account": {types.Database, types.Serverless, types.NosqlAccessProtocol, "azure:cosmosdb",
+		nil, isTrue("public_network_access_enabled"), alwaysEncrypted},
+	"azurerm_storage_account": {types.FileServer, types.Serverless, types.HTTPS, "azure:storage",
+		nil, isTrue("public_network_access_enabled"), alwaysEncrypted},
+	"azurerm_servicebus_namespace": {types.MessageQueue, types.Serverless, types.HTTPS, "azure:servicebus",
+		nil, isTrue("public_network_access_enabled"), alwaysEncrypted},
+	"azurerm_api_management": {types.Gateway, types.Serverless, types.HTTPS, "azure:apim",
+		[][]string{{"virtual_network_configuration", "subnet_id"}}, isNotFalse("public_network_access_enabled"), nil},
+
+	// GCP
+	"google_compute_instance": {types.UnknownTechnology, types.Virtual, types.UnknownProtocol, "gcp:compute",
+		[][]string{{"network_interface", "subnetwork"}, {"network_interface", "network"}}, nil, alwaysEncrypted},
+	"google_cloudfunctions_function": {types.Function, types.Serverless, types.HTTPS, "gcp:functions",
+		nil, func(resource *Resource) bool {
+			settings := resource.String("ingress_settings")
+			return len(settings) == 0 || settings == "ALLOW_ALL"
+		}, nil},
+	"google_cloudfunctions2_function": {types.Function, types.Serverless, types.HTTPS, "gcp:functions",
+		nil, func(resource *Resource) bool {
+			settings := resource.String("service_config", "ingress_settings")
+			return len(settings) == 0 || settings == "ALLOW_ALL"
+		}, nil},
+	"google_sql_database_instance": {types.Database, types.Serverless, types.SqlAccessProtocol, "gcp:sql",
+		[][]string{{"settings", "ip_configuration", "private_network"}}, isTrue("settings", "ip_configuration", "ipv4_enabled"), alwaysEncrypted},
+	"google_storage_bucket": {types.FileServer, types.Serverless, types.HTTPS, "gcp:storage",
+		nil, nil, alwaysEncrypted},
+	"google_pubsub_topic": {types.MessageQueue, types.Serverless, types.HTTPS, "gcp:pubsub",
+		nil, nil, alwaysEncrypted},
+	"google_api_gateway_gateway": {types.Gateway, types.Serverless, types.HTTPS, "gcp:apigateway",
+		nil, always, nil},
+}
+
+var boundaryKinds = map[string]boundaryKind{
+	// AWS
+	"aws_vpc":            {"VPC", types.NetworkCloudProvider, "aws:vpc", nil, false},
+	"aws_subnet":         {"Subnet", types.NetworkCloudProvider, "aws:vpc", [][]string{{"vpc_id"}}, false},
+	"aws_security_group": {"Security Group", types.NetworkCloudSecurityGroup, tagAWS, [][]string{{"vpc_id"}}, true},
+
+	// Azure
+	"azurerm_virtual_network":        {"Virtual Network", types.NetworkCloudProvider, "azure:vnet", nil, false},
+	"azurerm_subnet":                 {"Subnet", types.NetworkCloudProvider, "azure:vnet", [][]string{{"virtual_network_name"}}, false},
+	"azurerm_network_security_group": {"Network Security Group", types.NetworkCloudSecurityGroup, tagAzure, nil, true},
+
+	// GCP
+	"google_compute_network":    {"VPC", types.NetworkCloudProvider, "gcp:vpc", nil, false},
+	"google_compute_subnetwork": {"Subnet", types.NetworkCloudProvider, "gcp:vpc", [][]string{{"network"}}, false},
+}
+
+// resources in between assets and their subnets, like network interfaces of virtual machines
+var indirections = map[string][][]string{
+	"aws_db_subnet_group":          {{"subnet_ids"}},
+	"aws_elasticache_subnet_group": {{"subnet_ids"}},
+	"azurerm_network_interface":    {{"ip_configuration", "subnet_id"}},
+}
+
+// resources associating security groups with subnets or network interfaces: member and security group attributes
+var associations = map[string][2][]string{
+	"azurerm_subnet_network_security_group_association":    {{"subnet_id"}, {"network_security_group_id"}},
+	"azurerm_network_interface_security_group_association": {{"network_interface_id"}, {"network_security_group_id"}},
+}
+
+// attributes other resources use to refer to a resource
+var identifyingAttributes = []string{"id", "arn", "invoke_arn", "self_link", "name", "function_name"}
+
+func always(*Resource) bool {
+	return true
+}
+
+func isTrue(path ...string) func(resource *Resource) bool {
+	return func(resource *Resource) bool {
+		return resource.Bool(path...)
+	}
+}
+
+func isFalse(path ...string) func(resource *Resource) bool {
+	return func(resource *Resource) bool {
+		return hasFalse(resource, path...)
+	}
+}
+
+// isNotFalse is true unless the attribute is explicitly set to false, for attributes defaulting to true
+func isNotFalse(path ...string) func(resource *Resource) bool {
+	return func(resource *Resource) bool {
+		return !hasFalse(resource, path...)
+	}
+}
+
+func hasFalse(resource *Resource, path ...string) bool {
+	for _, value := range lookup(resource.Values, path) {
+		if flag, ok := value.(bool); ok && !flag {
+			return true
+		}
+	}
+	return false
+}
+
+func alwaysEncrypted(*Resource) types.EncryptionStyle {
+	return types.Transparent
+}
+
+// encryptedWhen returns transparent encryption when any of the attributes is set
+func encryptedWhen(attributes ...string) func(resource *Resource) types.EncryptionStyle {
+	return func(resource *Resource) types.EncryptionStyle {
+		for _, attribute := range attributes {
+			if resource.Has(attribute) {
+				return types.Transparent
+			}
+		}
+		return types.NoneEncryption
+	}
+}
+
+func encryptedIf(encrypted bool) types.EncryptionStyle {
+	if encrypted {
+		return types.Transparent
+	}
+	return types.NoneEncryption
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// resources configuring the encryption of an asset separately, like the encryption of s3 buckets since AWS provider 4
+var encryptionResources = map[string][]string{
+	"aws_s3_bucket_server_side_encryption_configuration": {"bucket"},
+}
+
+type linkKind struct {
+	description string
+	source      []string
+	target      []string
+}
+
+// resources connecting assets, like API gateway integrations invoking functions
+var linkKinds = map[string]linkKind{
+	"aws_api_gateway_integration":     {"API gateway integration", []string{"rest_api_id"}, []string{"uri"}},
+	"aws_apigatewayv2_integration":    {"API gateway integration", []string{"api_id"}, []string{"integration_uri"}},
+	"aws_lambda_event_source_mapping": {"Event source mapping", []string{"function_name"}, []string{"event_source_arn"}},
+	"aws_lb_target_group_attachment":  {"Load balancer target", []string{"target_group_arn"}, []string{"target_id"}},
+}
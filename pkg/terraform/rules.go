@@ -0,0 +1,233 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// resources holding security group or firewall rules
+var ruleTypes = map[string]bool{
+	"aws_security_group":                  true,
+	"aws_security_group_rule":             true,
+	"aws_vpc_security_group_ingress_rule": true,
+	"aws_vpc_security_group_egress_rule":  true,
+	"azurerm_network_security_group":      true,
+	"azurerm_network_security_rule":       true,
+	"google_compute_firewall":             true,
+}
+
+// address ranges meaning "everywhere"
+var internetRanges = []string{"0.0.0.0/0", "::/0", "*", "Internet", "any"}
+
+// rule allows traffic between the targets (members of a security group) and peers or address ranges
+type rule struct {
+	source  string
+	ingress bool
+	targets []*asset
+	peers   []*asset
+	ranges  []string
+	ports   []int
+}
+
+func (what *converter) addSecurityGroupRules(resources []*Resource) {
+	members := make(map[*Resource][]*asset)
+	for _, current := range what.assets {
+		for _, group := range current.groups {
+			members[group] = append(members[group], current)
+		}
+	}
+	membersOf := func(groups []*Resource) []*asset {
+		result := make([]*asset, 0)
+		for _, group := range groups {
+			result = append(result, members[group]...)
+		}
+		return result
+	}
+
+	rules := make([]rule, 0)
+	for _, resource := range resources {
+		switch resource.Type {
+		case "aws_security_group":
+			for _, direction := range []string{"ingress", "egress"} {
+				for _, element := range elements(resource, direction) {
+					peers := membersOf(what.resolveTypes(element, []string{"security_groups"}, boundaryKinds))
+					if element.Bool("self") {
+						peers = append(peers, members[resource]...)
+					}
+					rules = append(rules, rule{resource.Address, direction == "ingress", members[resource], peers,
+						append(element.Strings("cidr_blocks"), element.Strings("ipv6_cidr_blocks")...), singlePort(firstInt(element, "from_port"), firstInt(element, "to_port"))})
+				}
+			}
+
+		case "aws_security_group_rule":
+			groups := what.resolveTypes(resource, []string{"security_group_id"}, boundaryKinds)
+			peers := membersOf(what.resolveTypes(resource, []string{"source_security_group_id"}, boundaryKinds))
+			if resource.Bool("self") {
+				peers = append(peers, membersOf(groups)...)
+			}
+			rules = append(rules, rule{resource.Address, resource.String("type") == "ingress", membersOf(groups), peers,
+				append(resource.Strings("cidr_blocks"), resource.Strings("ipv6_cidr_blocks")...), singlePort(firstInt(resource, "from_port"), firstInt(resource, "to_port"))})
+
+		case "aws_vpc_security_group_ingress_rule", "aws_vpc_security_group_egress_rule":
+			groups := what.resolveTypes(resource, []string{"security_group_id"}, boundaryKinds)
+			peers := membersOf(what.resolveTypes(resource, []string{"referenced_security_group_id"}, boundaryKinds))
+			rules = append(rules, rule{resource.Address, resource.Type == "aws_vpc_security_group_ingress_rule", membersOf(groups), peers,
+				append(resource.Strings("cidr_ipv4"), resource.Strings("cidr_ipv6")...), singlePort(firstInt(resource, "from_port"), firstInt(resource, "to_port"))})
+
+		case "azurerm_network_security_group":
+			for _, element := range elements(resource, "security_rule") {
+				rules = append(rules, what.azureRule(resource.Address, members[resource], element)...)
+			}
+
+		case "azurerm_network_security_rule":
+			groups := what.resolveTypes(resource, []string{"network_security_group_name"}, boundaryKinds)
+			rules = append(rules, what.azureRule(resource.Address, membersOf(groups), resource)...)
+
+		case "google_compute_firewall":
+			rules = append(rules, what.gcpRule(resource)...)
+		}
+	}
+
+	for _, current := range rules {
+		what.applyRule(current)
+	}
+}
+
+func (what *converter) azureRule(source string, targets []*asset, element *Resource) []rule {
+	if !strings.EqualFold(element.String("access"), "Allow") {
+		return nil
+	}
+	if element.Has("source_application_security_group_ids") || element.Has("destination_application_security_group_ids") {
+		what.ask(fmt.Sprintf("Which assets belong to the application security groups referenced by %v?", source), "")
+	}
+
+	ingress := strings.EqualFold(element.String("direction"), "Inbound")
+	prefix := "destination"
+	if ingress {
+		prefix = "source"
+	}
+	ranges := append(element.Strings(prefix+"_address_prefix"), element.Strings(prefix+"_address_prefixes")...)
+	ports := parsePorts(append(element.Strings("destination_port_range"), element.Strings("destination_port_ranges")...))
+	return []rule{{source, ingress, targets, nil, ranges, ports}}
+}
+
+func (what *converter) gcpRule(resource *Resource) []rule {
+	if !resource.Has("allow") {
+		return nil
+	}
+
+	networks := what.resolveTypes(resource, []string{"network"}, boundaryKinds)
+	tagged := func(tags []string) []*asset {
+		result := make([]*asset, 0)
+		for _, current := range what.assets {
+			inNetwork := false
+			for _, network := range networks {
+				inNetwork = inNetwork || containsResource(current.networks, network)
+			}
+			if inNetwork && (tags == nil || intersects(current.resource.Strings("tags"), tags)) {
+				result = append(result, current)
+			}
+		}
+		return result
+	}
+
+	targetTags := resource.Strings("target_tags")
+	if len(targetTags) == 0 {
+		targetTags = nil
+	}
+	var peers []*asset
+	if sourceTags := resource.Strings("source_tags"); len(sourceTags) > 0 {
+		peers = tagged(sourceTags)
+	}
+
+	ingress := resource.String("direction") != "EGRESS"
+	ranges := resource.Strings("source_ranges")
+	if !ingress {
+		ranges = resource.Strings("destination_ranges")
+	}
+	return []rule{{resource.Address, ingress, tagged(targetTags), peers, ranges, parsePorts(resource.Strings("allow", "ports"))}}
+}
+
+func (what *converter) applyRule(current rule) {
+	description := "Allowed by " + current.source
+	for _, target := range current.targets {
+		for _, peer := range current.peers {
+			if current.ingress {
+				what.link(peer, target, description, protocolOf(target, current.ports))
+			} else {
+				what.link(target, peer, description, protocolOf(peer, current.ports))
+			}
+		}
+
+		// egress is usually open to everywhere, which doesn't tell anything about communication
+		if !current.ingress {
+			continue
+		}
+		for _, addressRange := range current.ranges {
+			if contains(internetRanges, addressRange) {
+				what.exposeToInternet(target, description, protocolOf(target, current.ports))
+			} else if strings.Contains(addressRange, "/") {
+				what.ask(fmt.Sprintf("Which systems in %v are allowed to communicate by %v?", addressRange, current.source), "")
+			}
+		}
+	}
+}
+
+// protocolOf guesses the protocol by the port of a rule, falling back to the protocol of the target asset
+func protocolOf(target *asset, ports []int) types.Protocol {
+	for _, port := range ports {
+		if protocol, ok := importer.GuessProtocolFromPort(port); ok {
+			return protocol
+		}
+	}
+	return target.kind.protocol
+}
+
+// elements returns the nested blocks of a resource (like inline rules) as resources of their own
+func elements(resource *Resource, attribute string) []*Resource {
+	values := lookup(resource.Values, []string{attribute})
+	expressions := lookup(resource.expressions, []string{attribute})
+
+	result := make([]*Resource, 0)
+	for i, value := range values {
+		element, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		current := &Resource{Address: resource.Address, Type: resource.Type, Values: element, module: resource.module}
+		if len(expressions) == len(values) {
+			current.expressions, _ = expressions[i].(map[string]any)
+		}
+		result = append(result, current)
+	}
+	return result
+}
+
+func firstInt(resource *Resource, attribute string) int {
+	values := resource.Ints(attribute)
+	if len(values) == 0 {
+		return 0
+	}
+	return values[0]
+}
+
+func containsResource(resources []*Resource, resource *Resource) bool {
+	for _, candidate := range resources {
+		if candidate == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(values []string, others []string) bool {
+	for _, value := range values {
+		if contains(others, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Output of "terraform show -json" (https://developer.hashicorp.com/terraform/internals/json-format) for states as well as plans,
+// only the parts needed for the import
+
+type Show struct {
+	FormatVersion string         `json:"format_version"`
+	Values        *Values        `json:"values"`
+	PlannedValues *Values        `json:"planned_values"`
+	Configuration *Configuration `json:"configuration"`
+}
+
+type Values struct {
+	RootModule Module `json:"root_module"`
+}
+
+type Module struct {
+	Address      string     `json:"address"`
+	Resources    []Resource `json:"resources"`
+	ChildModules []Module   `json:"child_modules"`
+}
+
+type Resource struct {
+	Address      string         `json:"address"`
+	Mode         string         `json:"mode"`
+	Type         string         `json:"type"`
+	Name         string         `json:"name"`
+	ProviderName string         `json:"provider_name"`
+	Values       map[string]any `json:"values"`
+
+	module      string
+	expressions map[string]any
+}
+
+type Configuration struct {
+	RootModule ConfigurationModule `json:"root_module"`
+}
+
+type ConfigurationModule struct {
+	Resources   []ConfigurationResource `json:"resources"`
+	ModuleCalls map[string]struct {
+		Module ConfigurationModule `json:"module"`
+	} `json:"module_calls"`
+}
+
+type ConfigurationResource struct {
+	Address     string         `json:"address"`
+	Mode        string         `json:"mode"`
+	Expressions map[string]any `json:"expressions"`
+}
+
+// LoadFile reads the json output of "terraform show -json" for a state or a plan
+func LoadFile(filename string) (*Show, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read terraform file %q: %w", filename, err)
+	}
+
+	show := new(Show)
+	err = json.Unmarshal(data, show)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse terraform file %q: %w", filename, err)
+	}
+	if show.Values == nil && show.PlannedValues == nil {
+		return nil, fmt.Errorf("terraform file %q contains neither values nor planned values, is it the output of 'terraform show -json'?", filename)
+	}
+	return show, nil
+}
+
+// Resources returns all managed resources of the state or plan, including those of child modules; the expressions of the
+// configuration (only part of plans) are attached to resolve references to resources whose ids are not known yet
+func (what *Show) Resources() []*Resource {
+	values := what.Values
+	if values == nil {
+		values = what.PlannedValues
+	}
+
+	expressions := make(map[string]map[string]any)
+	if what.Configuration != nil {
+		collectExpressions(what.Configuration.RootModule, "", expressions)
+	}
+
+	resources := make([]*Resource, 0)
+	var collect func(module Module)
+	collect = func(module Module) {
+		for i := range module.Resources {
+			resource := &module.Resources[i]
+			if resource.Mode != "managed" {
+				continue
+			}
+			if len(module.Address) > 0 {
+				resource.module = module.Address + "."
+			}
+			resource.expressions = expressions[baseAddress(resource.Address)]
+			resources = append(resources, resource)
+		}
+		for _, child := range module.ChildModules {
+			collect(child)
+		}
+	}
+	collect(values.RootModule)
+	return resources
+}
+
+func collectExpressions(module ConfigurationModule, prefix string, expressions map[string]map[string]any) {
+	for _, resource := range module.Resources {
+		if resource.Mode == "managed" {
+			expressions[prefix+resource.Address] = resource.Expressions
+		}
+	}
+	for name, call := range module.ModuleCalls {
+		collectExpressions(call.Module, prefix+"module."+name+".", expressions)
+	}
+}
+
+// index suffixes of resources created with count or for_each, like [0] or ["a"]
+var indexPattern = regexp.MustCompile(`\[[^]]*]`)
+
+// baseAddress strips the index suffixes of resources created with count or for_each
+func baseAddress(address string) string {
+	return indexPattern.ReplaceAllString(address, "")
+}
+
+// referencedAddress turns a reference of an expression (like "aws_vpc.main.id") into a resource address (like "aws_vpc.main"),
+// references to variables, locals, data sources and module outputs are ignored
+func referencedAddress(reference string) (string, bool) {
+	parts := strings.Split(baseAddress(reference), ".")
+	if len(parts) < 2 {
+		return "", false
+	}
+	switch parts[0] {
+	case "var", "local", "data", "module", "each", "count", "path", "self", "terraform":
+		return "", false
+	}
+	return parts[0] + "." + parts[1], true
+}
+
+// lookup returns all values at the path, lists on the way are traversed
+func lookup(value any, path []string) []any {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		result := make([]any, 0)
+		for _, element := range typed {
+			result = append(result, lookup(element, path)...)
+		}
+		return result
+	case map[string]any:
+		if len(path) == 0 {
+			return []any{typed}
+		}
+		return lookup(typed[path[0]], path[1:])
+	default:
+		if len(path) == 0 {
+			return []any{typed}
+		}
+		return nil
+	}
+}
+
+// Strings returns all non-empty strings at the path of the resource values
+func (what *Resource) Strings(path ...string) []string {
+	result := make([]string, 0)
+	for _, value := range lookup(what.Values, path) {
+		if text, ok := value.(string); ok && len(text) > 0 {
+			result = append(result, text)
+		}
+	}
+	return result
+}
+
+// String returns the first non-empty string at the path of the resource values
+func (what *Resource) String(path ...string) string {
+	values := what.Strings(path...)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Bool tells whether any value at the path of the resource values is true
+func (what *Resource) Bool(path ...string) bool {
+	for _, value := range lookup(what.Values, path) {
+		if flag, ok := value.(bool); ok && flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Has tells whether there is any non-empty value at the path of the resource values
+func (what *Resource) Has(path ...string) bool {
+	for _, value := range lookup(what.Values, path) {
+		switch typed := value.(type) {
+		case string:
+			if len(typed) > 0 {
+				return true
+			}
+		case bool:
+			if typed {
+				return true
+			}
+		case map[string]any:
+			if len(typed) > 0 {
+				return true
+			}
+		case nil:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Ints returns all numbers at the path of the resource values
+func (what *Resource) Ints(path ...string) []int {
+	result := make([]int, 0)
+	for _, value := range lookup(what.Values, path) {
+		if number, ok := value.(float64); ok {
+			result = append(result, int(number))
+		}
+	}
+	return result
+}
+
+// references returns the addresses of the resources referenced by the expression at the path of the configuration
+func (what *Resource) references(path ...string) []string {
+	addresses := make([]string, 0)
+	for _, value := range lookup(what.expressions, append(path, "references")) {
+		if reference, ok := value.(string); ok {
+			if address, ok := referencedAddress(reference); ok {
+				addresses = append(addresses, what.module+address)
+			}
+		}
+	}
+	return addresses
+}
+
+// DisplayName returns the name of the resource within the cloud, falling back to its name within terraform
+func (what *Resource) DisplayName() string {
+	for _, path := range [][]string{{"tags", "Name"}, {"function_name"}, {"bucket"}, {"identifier"}, {"cluster_identifier"}, {"replication_group_id"}, {"cluster_id"}, {"name"}} {
+		if name := what.String(path...); len(name) > 0 {
+			return name
+		}
+	}
+	return what.Name
+}
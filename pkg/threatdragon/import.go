@@ -0,0 +1,227 @@
+package threatdragon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// diagrams are placed next to each other, so shapes of different diagrams never enclose each other
+const diagramOffset = 1e6
+
+func LoadFile(filename string) (*Model, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Threat Dragon file %q: %w", filename, err)
+	}
+
+	model := new(Model)
+	err = json.Unmarshal(data, model)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Threat Dragon file %q: %w", filename, err)
+	}
+
+	return model, nil
+}
+
+// Convert turns all diagrams of a Threat Dragon model into one diagram ready to be imported.
+func Convert(model *Model) *importer.Diagram {
+	diagram := &importer.Diagram{
+		Title:       model.Summary.Title,
+		Description: model.Summary.Description,
+		Owner:       model.Summary.Owner,
+	}
+
+	contributors := make([]string, 0)
+	for _, contributor := range model.Detail.Contributors {
+		contributors = append(contributors, contributor.Name)
+	}
+	diagram.Properties = append(diagram.Properties,
+		importer.Property{Name: "Contributors", Value: strings.Join(contributors, ", ")},
+		importer.Property{Name: "Reviewer", Value: model.Detail.Reviewer})
+
+	kindOf := make(map[string]importer.ElementKind)
+	for diagramIndex, tdDiagram := range model.Detail.Diagrams {
+		cells := tdDiagram.Cells
+		if tdDiagram.DiagramJson != nil {
+			cells = append(cells, tdDiagram.DiagramJson.Cells...)
+		}
+
+		flows := make([]Cell, 0)
+		for _, cell := range cells {
+			data := cell.data()
+			switch data.Type {
+			case "tm.Actor":
+				element := newElement(cell, data, importer.ExternalEntityElement, float64(diagramIndex)*diagramOffset)
+				element.Technology = types.ClientSystem
+				if data.ProvidesAuthentication {
+					element.Technology = types.IdentityProvider
+				}
+				diagram.Elements = append(diagram.Elements, element)
+				kindOf[cell.Id] = element.Kind
+			case "tm.Process":
+				element := newElement(cell, data, importer.ProcessElement, float64(diagramIndex)*diagramOffset)
+				if data.IsWebApplication {
+					element.Technology = types.WebApplication
+				}
+				element.Properties = append(element.Properties,
+					booleanProperty("Handles card payment", data.HandlesCardPayment),
+					booleanProperty("Handles goods or services", data.HandlesGoodsOrServices),
+					importer.Property{Name: "Privilege level", Value: data.PrivilegeLevel})
+				diagram.Elements = append(diagram.Elements, element)
+				kindOf[cell.Id] = element.Kind
+			case "tm.Store":
+				element := newElement(cell, data, importer.DataStoreElement, float64(diagramIndex)*diagramOffset)
+				element.Technology = types.Database
+				if data.IsEncrypted {
+					element.Encryption = types.Transparent
+				}
+				element.Properties = append(element.Properties,
+					booleanProperty("Is a log", data.IsALog),
+					booleanProperty("Stores credentials", data.StoresCredentials),
+					booleanProperty("Is signed", data.IsSigned),
+					booleanProperty("Stores inventory", data.StoresInventory))
+				diagram.Elements = append(diagram.Elements, element)
+				kindOf[cell.Id] = element.Kind
+			case "tm.Flow":
+				flows = append(flows, cell)
+			case "tm.Boundary", "tm.BoundaryBox":
+				boundary := importer.Boundary{
+					Id:          cell.Id,
+					Name:        cell.name(),
+					Description: data.Description,
+				}
+				if cell.Shape == "trust-boundary-box" || data.Type == "tm.BoundaryBox" {
+					boundary.Bounds = cell.bounds(float64(diagramIndex) * diagramOffset)
+				}
+				diagram.Boundaries = append(diagram.Boundaries, boundary)
+			default:
+				continue
+			}
+
+			for _, threat := range data.Threats {
+				diagram.Threats = append(diagram.Threats, importer.Threat{
+					ElementId:   cell.Id,
+					Title:       threat.Title,
+					Category:    threat.Type,
+					State:       threat.Status,
+					Description: strings.TrimSpace(threat.Description + "\n" + threat.Mitigation),
+				})
+			}
+		}
+
+		// flows last, as they need to know what they are connected to
+		for _, cell := range flows {
+			data := cell.data()
+			flow := importer.Flow{
+				Id:            cell.Id,
+				Name:          cell.name(),
+				Description:   data.Description,
+				SourceId:      cell.Source.id(),
+				TargetId:      cell.Target.id(),
+				Type:          data.Protocol,
+				Bidirectional: data.IsBidirectional,
+				OutOfScope:    data.OutOfScope,
+				Properties: []importer.Property{
+					booleanProperty("Encrypted", data.IsEncrypted),
+					{Name: "Reason out of scope", Value: data.ReasonOutOfScope},
+				},
+			}
+
+			if data.IsPublicNetwork {
+				if !markInternet(diagram, kindOf, flow.SourceId) && !markInternet(diagram, kindOf, flow.TargetId) {
+					flow.Properties = append(flow.Properties, booleanProperty("Public network", true))
+				}
+			}
+
+			diagram.Flows = append(diagram.Flows, flow)
+		}
+	}
+
+	return diagram
+}
+
+func newElement(cell Cell, data CellData, kind importer.ElementKind, offset float64) importer.Element {
+	return importer.Element{
+		Id:               cell.Id,
+		Name:             cell.name(),
+		Description:      data.Description,
+		Kind:             kind,
+		Type:             cell.name(),
+		Bounds:           cell.bounds(offset),
+		OutOfScope:       data.OutOfScope,
+		OutOfScopeReason: data.ReasonOutOfScope,
+	}
+}
+
+// markInternet flags external entities reached via a public network as internet facing
+func markInternet(diagram *importer.Diagram, kindOf map[string]importer.ElementKind, id string) bool {
+	if kind, ok := kindOf[id]; !ok || kind != importer.ExternalEntityElement {
+		return false
+	}
+	for i := range diagram.Elements {
+		if diagram.Elements[i].Id == id {
+			diagram.Elements[i].Internet = true
+		}
+	}
+	return true
+}
+
+func booleanProperty(name string, value bool) importer.Property {
+	if !value {
+		return importer.Property{Name: name}
+	}
+	return importer.Property{Name: name, Value: strconv.FormatBool(value)}
+}
+
+func (what Cell) data() CellData {
+	if what.Data != nil {
+		return *what.Data
+	}
+	return what.CellData
+}
+
+func (what Cell) name() string {
+	if len(what.data().Name) > 0 {
+		return what.data().Name
+	}
+	if what.Attrs != nil && what.Attrs.Text != nil && len(what.Attrs.Text.Text) > 0 {
+		return what.Attrs.Text.Text
+	}
+	for _, raw := range what.Labels {
+		var text string
+		if json.Unmarshal(raw, &text) == nil && len(text) > 0 {
+			return text
+		}
+		var label Label
+		if json.Unmarshal(raw, &label) == nil && label.Attrs != nil {
+			if label.Attrs.Text != nil && len(label.Attrs.Text.Text) > 0 {
+				return label.Attrs.Text.Text
+			}
+			if label.Attrs.Label != nil && len(label.Attrs.Label.Text) > 0 {
+				return label.Attrs.Label.Text
+			}
+		}
+	}
+	return ""
+}
+
+func (what Cell) bounds(offset float64) *importer.Bounds {
+	if what.Position == nil || what.Size == nil {
+		return nil
+	}
+	return &importer.Bounds{X: what.Position.X + offset, Y: what.Position.Y, Width: what.Size.Width, Height: what.Size.Height}
+}
+
+func (what Endpoint) id() string {
+	if len(what.Cell) > 0 {
+		return what.Cell
+	}
+	return what.Id
+}
@@ -0,0 +1,71 @@
+package threatdragon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestConvert(t *testing.T) {
+	model, err := LoadFile("testdata/model.json")
+	require.NoError(t, err)
+
+	diagram := Convert(model)
+
+	assert.Equal(t, "Webshop", diagram.Title)
+	assert.Equal(t, "Jane Doe", diagram.Owner)
+	assert.Contains(t, diagram.Properties, importer.Property{Name: "Contributors", Value: "John Doe"})
+
+	require.Len(t, diagram.Elements, 4)
+	elements := make(map[string]importer.Element)
+	for _, element := range diagram.Elements {
+		elements[element.Name] = element
+	}
+	assert.Equal(t, importer.ExternalEntityElement, elements["Customer"].Kind)
+	assert.Equal(t, types.ClientSystem, elements["Customer"].Technology)
+	assert.True(t, elements["Customer"].Internet, "reached via a public network")
+	assert.Equal(t, types.WebApplication, elements["Shop"].Technology)
+	assert.Contains(t, elements["Shop"].Properties, importer.Property{Name: "Handles card payment", Value: "true"})
+	assert.Equal(t, importer.DataStoreElement, elements["Orders"].Kind)
+	assert.Equal(t, types.Transparent, elements["Orders"].Encryption)
+	assert.Equal(t, importer.ProcessElement, elements["Batch Job"].Kind, "version 1.x diagram")
+	assert.Greater(t, elements["Batch Job"].Bounds.X, elements["Orders"].Bounds.X, "diagrams are placed next to each other")
+
+	require.Len(t, diagram.Flows, 2)
+	assert.Equal(t, "Order", diagram.Flows[0].Name)
+	assert.Equal(t, "customer", diagram.Flows[0].SourceId)
+	assert.Equal(t, "shop", diagram.Flows[0].TargetId)
+	assert.Equal(t, "Store Order", diagram.Flows[1].Name)
+	assert.True(t, diagram.Flows[1].Bidirectional)
+
+	require.Len(t, diagram.Boundaries, 2)
+	assert.NotNil(t, diagram.Boundaries[0].Bounds)
+	assert.Nil(t, diagram.Boundaries[1].Bounds, "curves don't enclose anything")
+
+	require.Len(t, diagram.Threats, 2)
+	assert.Equal(t, importer.Threat{ElementId: "shop", Title: "Spoofed customer", Category: "Spoofing", State: "Mitigated", Description: "Login\nMFA"}, diagram.Threats[0])
+}
+
+func TestImport(t *testing.T) {
+	model, err := LoadFile("testdata/model.json")
+	require.NoError(t, err)
+
+	mapping := importer.Mapping{importer.MappingProtocol: {"SQL": types.SqlAccessProtocol.String()}, importer.MappingRiskCategory: {"Spoofing": "missing-authentication"}}
+	modelInput, report := importer.ImportDiagram(Convert(model), mapping, []string{"missing-authentication"})
+
+	require.Contains(t, modelInput.TechnicalAssets, "Shop")
+	require.Contains(t, modelInput.TechnicalAssets["Shop"].CommunicationLinks, "Store Order")
+	assert.Equal(t, types.SqlAccessProtocol.String(), modelInput.TechnicalAssets["Shop"].CommunicationLinks["Store Order"].Protocol)
+	assert.Equal(t, []string{"shop", "orders"}, modelInput.TrustBoundaries["Data Center"].TechnicalAssetsInside)
+	assert.Empty(t, modelInput.RiskTracking)
+	assert.Contains(t, report.Items, "threat 'Spoofed customer' of 'shop': risk category \"missing-authentication\" with status \"mitigated\", add the risk tracking once the risk id is known from the analysis")
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.json")
+	assert.Error(t, err)
+}
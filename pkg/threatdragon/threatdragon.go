@@ -0,0 +1,125 @@
+package threatdragon
+
+import "encoding/json"
+
+// OWASP Threat Dragon model format (version 2.x, version 1.x diagrams are read as well), see https://owasp.org/www-project-threat-dragon/
+
+type Model struct {
+	Version string  `json:"version"`
+	Summary Summary `json:"summary"`
+	Detail  Detail  `json:"detail"`
+}
+
+type Summary struct {
+	Title       string `json:"title"`
+	Owner       string `json:"owner"`
+	Description string `json:"description"`
+}
+
+type Detail struct {
+	Contributors []Contributor `json:"contributors"`
+	Diagrams     []Diagram     `json:"diagrams"`
+	Reviewer     string        `json:"reviewer"`
+}
+
+type Contributor struct {
+	Name string `json:"name"`
+}
+
+type Diagram struct {
+	Title       string       `json:"title"`
+	DiagramType string       `json:"diagramType"`
+	Cells       []Cell       `json:"cells"`
+	DiagramJson *DiagramJson `json:"diagramJson,omitempty"` // version 1.x
+}
+
+type DiagramJson struct {
+	Cells []Cell `json:"cells"`
+}
+
+// Cell is a shape or edge of a diagram; version 2.x keeps the threat model properties in data,
+// version 1.x directly in the cell
+type Cell struct {
+	Id       string            `json:"id"`
+	Shape    string            `json:"shape"`
+	Position *Position         `json:"position,omitempty"`
+	Size     *Size             `json:"size,omitempty"`
+	Source   Endpoint          `json:"source"`
+	Target   Endpoint          `json:"target"`
+	Data     *CellData         `json:"data,omitempty"`
+	Labels   []json.RawMessage `json:"labels,omitempty"` // flow names, either plain text or label objects
+	Attrs    *Attrs            `json:"attrs,omitempty"`  // version 1.x element names
+	CellData
+}
+
+type CellData struct {
+	Type             string   `json:"type"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	OutOfScope       bool     `json:"outOfScope"`
+	ReasonOutOfScope string   `json:"reasonOutOfScope"`
+	Threats          []Threat `json:"threats"`
+
+	// actor
+	ProvidesAuthentication bool `json:"providesAuthentication"`
+
+	// process
+	HandlesCardPayment     bool   `json:"handlesCardPayment"`
+	HandlesGoodsOrServices bool   `json:"handlesGoodsOrServices"`
+	IsWebApplication       bool   `json:"isWebApplication"`
+	PrivilegeLevel         string `json:"privilegeLevel"`
+
+	// store
+	IsALog            bool `json:"isALog"`
+	StoresCredentials bool `json:"storesCredentials"`
+	IsEncrypted       bool `json:"isEncrypted"`
+	IsSigned          bool `json:"isSigned"`
+	StoresInventory   bool `json:"storesInventory"`
+
+	// flow
+	IsBidirectional bool   `json:"isBidirectional"`
+	IsPublicNetwork bool   `json:"isPublicNetwork"`
+	Protocol        string `json:"protocol"`
+}
+
+type Threat struct {
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Mitigation  string `json:"mitigation"`
+}
+
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Size struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Endpoint references the connected cell (version 2.x uses cell, version 1.x uses id)
+type Endpoint struct {
+	Cell string `json:"cell"`
+	Id   string `json:"id"`
+}
+
+type Label struct {
+	Attrs *LabelAttrs `json:"attrs,omitempty"`
+}
+
+type LabelAttrs struct {
+	Text  *Text `json:"text,omitempty"`
+	Label *Text `json:"label,omitempty"`
+}
+
+type Attrs struct {
+	Text *Text `json:"text,omitempty"`
+}
+
+type Text struct {
+	Text string `json:"text"`
+}
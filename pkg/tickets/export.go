@@ -0,0 +1,100 @@
+package tickets
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// JiraRiskIdField is the column of the risk id in the Jira csv, map it to a custom field when importing so it is
+// part of Jira's csv exports which can be read back as ticket mapping
+const JiraRiskIdField = "Threagile Risk ID"
+
+var jiraPriorities = map[types.RiskSeverity]string{
+	types.CriticalSeverity: "Highest",
+	types.HighSeverity:     "High",
+	types.ElevatedSeverity: "Medium",
+	types.MediumSeverity:   "Low",
+	types.LowSeverity:      "Lowest",
+}
+
+// WriteJiraCSV writes the tickets as csv for the Jira csv import, Jira takes several labels as several columns of the same name
+func WriteJiraCSV(tickets []Ticket, filename string) error {
+	labelColumns := 0
+	for _, ticket := range tickets {
+		if len(ticket.Labels) > labelColumns {
+			labelColumns = len(ticket.Labels)
+		}
+	}
+
+	header := []string{"Summary", "Description", "Issue Type", "Priority", "Assignee", JiraRiskIdField}
+	for i := 0; i < labelColumns; i++ {
+		header = append(header, "Labels")
+	}
+	records := [][]string{header}
+	for _, ticket := range tickets {
+		assignee := ""
+		if len(ticket.Assignees) > 0 {
+			assignee = ticket.Assignees[0]
+		}
+		record := []string{ticket.Title, ticket.Body, "Task", jiraPriorities[ticket.Severity], assignee, ticket.RiskId}
+		for i := 0; i < labelColumns; i++ {
+			label := ""
+			if i < len(ticket.Labels) {
+				label = ticket.Labels[i]
+			}
+			record = append(record, label)
+		}
+		records = append(records, record)
+	}
+
+	file, err := os.Create(filepath.Clean(filename))
+	if err != nil {
+		return fmt.Errorf("unable to create jira csv %q: %w", filename, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	writer := csv.NewWriter(file)
+	err = writer.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("unable to write jira csv %q: %w", filename, err)
+	}
+	return nil
+}
+
+// gitHubIssue is the payload of the GitHub REST API to create an issue (POST /repos/{owner}/{repo}/issues)
+type gitHubIssue struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// WriteGitHubIssues writes the tickets as json array of GitHub issue payloads, the risk id is part of the body, e.g. to file them with
+//
+//	jq -c '.[]' risks-github-issues.json | while read -r issue; do echo "$issue" | gh api repos/{owner}/{repo}/issues --input -; done
+func WriteGitHubIssues(tickets []Ticket, filename string) error {
+	issues := make([]gitHubIssue, 0)
+	for _, ticket := range tickets {
+		issues = append(issues, gitHubIssue{
+			Title:     ticket.Title,
+			Body:      ticket.Body,
+			Labels:    ticket.Labels,
+			Assignees: ticket.Assignees,
+		})
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to create github issues: %w", err)
+	}
+	err = os.WriteFile(filepath.Clean(filename), data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write github issues %q: %w", filename, err)
+	}
+	return nil
+}
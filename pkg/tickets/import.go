@@ -0,0 +1,257 @@
+package tickets
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// Mapping assigns tickets to synthetic risk ids
+type Mapping map[string]string
+
+var riskIdInBody = regexp.MustCompile(`(?m)^Risk ID: (\S+)\s*$`)
+
+// LoadMapping reads the tickets of risks from
+//   - yaml (or json object) of risk ids to tickets,
+//   - Jira csv exports (columns "Issue key" and the custom field "Threagile Risk ID"), or any csv of risk id and ticket,
+//   - GitHub issue lists as json array (gh issue list --json number,url,body), the risk id is taken from the "Risk ID: " line of the body.
+func LoadMapping(filename string) (Mapping, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ticket mapping %q: %w", filename, err)
+	}
+
+	var mapping Mapping
+	switch trimmed := bytes.TrimSpace(data); {
+	case strings.EqualFold(filepath.Ext(filename), ".csv"):
+		mapping, err = parseCSVMapping(data)
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		mapping, err = parseIssueList(data)
+	default:
+		mapping = make(Mapping)
+		err = yaml.Unmarshal(data, &mapping)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ticket mapping %q: %w", filename, err)
+	}
+	return mapping, nil
+}
+
+func parseCSVMapping(data []byte) (Mapping, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	riskColumn, ticketColumn := 0, 1
+	if len(records) > 0 {
+		header := records[0]
+		riskHeader, ticketHeader := -1, -1
+		for i, name := range header {
+			name = strings.ToLower(strings.TrimSpace(name))
+			switch {
+			case strings.Contains(name, strings.ToLower(JiraRiskIdField)), name == "risk id", name == "synthetic_risk_id", name == "risk":
+				riskHeader = i
+			case name == "issue key", name == "key", name == "ticket", name == "issue", name == "url":
+				if ticketHeader < 0 {
+					ticketHeader = i
+				}
+			}
+		}
+		if riskHeader >= 0 || ticketHeader >= 0 {
+			if riskHeader < 0 || ticketHeader < 0 {
+				return nil, fmt.Errorf("csv needs a column %q (or \"risk id\") and a column \"issue key\" (or \"ticket\")", JiraRiskIdField)
+			}
+			riskColumn, ticketColumn = riskHeader, ticketHeader
+			records = records[1:]
+		}
+	}
+
+	mapping := make(Mapping)
+	for _, record := range records {
+		if riskColumn >= len(record) || ticketColumn >= len(record) {
+			continue
+		}
+		riskId, ticket := strings.TrimSpace(record[riskColumn]), strings.TrimSpace(record[ticketColumn])
+		if len(riskId) > 0 && len(ticket) > 0 {
+			mapping[riskId] = ticket
+		}
+	}
+	return mapping, nil
+}
+
+type issue struct {
+	Number  int    `json:"number"`
+	URL     string `json:"url"`
+	HtmlURL string `json:"html_url"`
+	Body    string `json:"body"`
+}
+
+func parseIssueList(data []byte) (Mapping, error) {
+	issues := make([]issue, 0)
+	err := json.Unmarshal(data, &issues)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := make(Mapping)
+	for _, issue := range issues {
+		match := riskIdInBody.FindStringSubmatch(issue.Body)
+		if match == nil {
+			continue
+		}
+		switch {
+		case len(issue.HtmlURL) > 0:
+			mapping[match[1]] = issue.HtmlURL
+		case len(issue.URL) > 0:
+			mapping[match[1]] = issue.URL
+		case issue.Number > 0:
+			mapping[match[1]] = "#" + strconv.Itoa(issue.Number)
+		}
+	}
+	return mapping, nil
+}
+
+// UpdateModelFile sets the tickets in the risk tracking of the model file (creating unchecked risk tracking entries where needed).
+// The file is edited line by line to keep its formatting and comments, the previous model file is kept as backup.
+// It returns the number of risk tracking entries changed.
+func UpdateModelFile(filename string, mapping Mapping) (int, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return 0, fmt.Errorf("unable to read model file %q: %w", filename, err)
+	}
+	var document yaml.Node
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse model file %q: %w", filename, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return 0, fmt.Errorf("model file %q is not a yaml mapping", filename)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	replacements := make(map[int][]string) // lines (0-based) replaced by other lines
+	insertAfter := func(line int, newLines ...string) {
+		if _, ok := replacements[line]; !ok {
+			replacements[line] = []string{lines[line]}
+		}
+		replacements[line] = append(replacements[line], newLines...)
+	}
+
+	riskTrackingKey, riskTracking := lookup(document.Content[0], "risk_tracking")
+	entryIndent, fieldIndent := "  ", "  "
+	if riskTracking != nil && riskTracking.Kind == yaml.MappingNode && riskTracking.Style&yaml.FlowStyle == 0 && len(riskTracking.Content) > 0 {
+		entryIndent = strings.Repeat(" ", riskTracking.Content[0].Column-1)
+		if entry := riskTracking.Content[1]; entry.Kind == yaml.MappingNode && entry.Style&yaml.FlowStyle == 0 && len(entry.Content) > 0 {
+			fieldIndent = strings.Repeat(" ", entry.Content[0].Column-riskTracking.Content[0].Column)
+		}
+	}
+
+	riskIds := make([]string, 0, len(mapping))
+	for riskId := range mapping {
+		riskIds = append(riskIds, riskId)
+	}
+	sort.Strings(riskIds)
+
+	changed := 0
+	newEntries := make([]string, 0)
+	for _, riskId := range riskIds {
+		ticket := mapping[riskId]
+		var entryKey, entry *yaml.Node
+		if riskTracking != nil && riskTracking.Kind == yaml.MappingNode {
+			entryKey, entry = lookup(riskTracking, riskId)
+		}
+		if entry == nil {
+			newEntries = append(newEntries, entryIndent+scalar(riskId)+":",
+				entryIndent+fieldIndent+"status: "+types.Unchecked.String(),
+				entryIndent+fieldIndent+"ticket: "+scalar(ticket))
+			changed++
+			continue
+		}
+		if entry.Kind != yaml.MappingNode || entry.Style&yaml.FlowStyle != 0 || len(entry.Content) == 0 {
+			return 0, fmt.Errorf("risk tracking of %q in model file %q is not a block mapping", riskId, filename)
+		}
+
+		ticketKey, ticketValue := lookup(entry, "ticket")
+		switch {
+		case ticketValue == nil:
+			insertAfter(entryKey.Line-1, strings.Repeat(" ", entry.Content[0].Column-1)+"ticket: "+scalar(ticket))
+		case ticketValue.Value == ticket:
+			continue
+		case ticketValue.Kind != yaml.ScalarNode || ticketValue.Line != ticketKey.Line || ticketValue.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+			return 0, fmt.Errorf("ticket of %q in model file %q is not a single line", riskId, filename)
+		default:
+			replacements[ticketKey.Line-1] = []string{strings.Repeat(" ", ticketKey.Column-1) + "ticket: " + scalar(ticket)}
+		}
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	if len(newEntries) > 0 {
+		switch {
+		case riskTrackingKey == nil:
+			lines = append(lines, "", "risk_tracking:")
+			lines = append(lines, newEntries...)
+		case riskTracking.Kind == yaml.MappingNode && riskTracking.Style&yaml.FlowStyle == 0 && len(riskTracking.Content) > 0:
+			insertAfter(riskTrackingKey.Line-1, newEntries...)
+		case riskTracking.Kind == yaml.MappingNode && len(riskTracking.Content) > 0, riskTracking.Line != riskTrackingKey.Line:
+			return 0, fmt.Errorf("risk tracking in model file %q is not a block mapping", filename)
+		default: // empty
+			replacements[riskTrackingKey.Line-1] = append([]string{strings.Repeat(" ", riskTrackingKey.Column-1) + "risk_tracking:"}, newEntries...)
+		}
+	}
+
+	updated := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if replacement, ok := replacements[i]; ok {
+			updated = append(updated, replacement...)
+			continue
+		}
+		updated = append(updated, line)
+	}
+
+	err = os.WriteFile(filepath.Clean(filename)+".backup", data, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("unable to write backup of model file %q: %w", filename, err)
+	}
+	err = os.WriteFile(filepath.Clean(filename), []byte(strings.Join(updated, "\n")+"\n"), 0600)
+	if err != nil {
+		return 0, fmt.Errorf("unable to write model file %q: %w", filename, err)
+	}
+	return changed, nil
+}
+
+// lookup returns the key and value node of a mapping
+func lookup(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// scalar returns the value as yaml scalar, quoted where needed
+func scalar(value string) string {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return strconv.Quote(value)
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,248 @@
+package tickets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type updateModelFileTest struct {
+	model         string
+	mapping       Mapping
+	expectedModel string
+	expectedCount int
+	expectedError string
+}
+
+func TestUpdateModelFile(t *testing.T) {
+	testCases := map[string]updateModelFileTest{
+		"entry with ticket": {
+			model: `title: Shop
+risk_tracking:
+  missing-waf@web:
+    status: in-progress
+    ticket: JIRA-0 # old ticket
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2"},
+			expectedModel: `title: Shop
+risk_tracking:
+  missing-waf@web:
+    status: in-progress
+    ticket: JIRA-2
+`,
+			expectedCount: 1,
+		},
+		"entry with the same ticket": {
+			model: `risk_tracking:
+  missing-waf@web:
+    status: in-progress
+    ticket: JIRA-2
+`,
+			mapping:       Mapping{"missing-waf@web": "JIRA-2"},
+			expectedCount: 0,
+		},
+		"entry without ticket": {
+			model: `risk_tracking:
+  missing-waf@web:
+    status: accepted
+    justification: behind a proxy
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2"},
+			expectedModel: `risk_tracking:
+  missing-waf@web:
+    ticket: JIRA-2
+    status: accepted
+    justification: behind a proxy
+`,
+			expectedCount: 1,
+		},
+		"no entry": {
+			model: `risk_tracking:
+  missing-waf@web:
+    status: accepted
+
+# comment
+`,
+			mapping: Mapping{"unencrypted-communication@web>db": "https://github.com/acme/shop/issues/7"},
+			expectedModel: `risk_tracking:
+  unencrypted-communication@web>db:
+    status: unchecked
+    ticket: https://github.com/acme/shop/issues/7
+  missing-waf@web:
+    status: accepted
+
+# comment
+`,
+			expectedCount: 1,
+		},
+		"empty risk tracking": {
+			model: `title: Shop
+risk_tracking:
+tags_available: []
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2", "ssrf@web": "JIRA-3"},
+			expectedModel: `title: Shop
+risk_tracking:
+  missing-waf@web:
+    status: unchecked
+    ticket: JIRA-2
+  ssrf@web:
+    status: unchecked
+    ticket: JIRA-3
+tags_available: []
+`,
+			expectedCount: 2,
+		},
+		"empty flow style risk tracking": {
+			model: `title: Shop
+risk_tracking: {}
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2"},
+			expectedModel: `title: Shop
+risk_tracking:
+  missing-waf@web:
+    status: unchecked
+    ticket: JIRA-2
+`,
+			expectedCount: 1,
+		},
+		"flow style risk tracking": {
+			model: `risk_tracking: {missing-waf@web: {status: accepted}}
+`,
+			mapping:       Mapping{"ssrf@web": "JIRA-3"},
+			expectedError: "is not a block mapping",
+		},
+		"flow style entry": {
+			model: `risk_tracking:
+  missing-waf@web: {status: accepted}
+`,
+			mapping:       Mapping{"missing-waf@web": "JIRA-2"},
+			expectedError: `risk tracking of "missing-waf@web"`,
+		},
+		"missing risk tracking": {
+			model: `title: Shop
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2"},
+			expectedModel: `title: Shop
+
+risk_tracking:
+  missing-waf@web:
+    status: unchecked
+    ticket: JIRA-2
+`,
+			expectedCount: 1,
+		},
+		"indentation preserved": {
+			model: `risk_tracking:
+    missing-waf@web:
+        status: accepted
+    ssrf@web:
+        status: in-progress
+        ticket: JIRA-0
+`,
+			mapping: Mapping{"missing-waf@web": "JIRA-2", "ssrf@web": "JIRA-3", "dos@web": "JIRA-4"},
+			expectedModel: `risk_tracking:
+    dos@web:
+        status: unchecked
+        ticket: JIRA-4
+    missing-waf@web:
+        ticket: JIRA-2
+        status: accepted
+    ssrf@web:
+        status: in-progress
+        ticket: JIRA-3
+`,
+			expectedCount: 3,
+		},
+		"quoted values": {
+			model: `risk_tracking:
+`,
+			mapping: Mapping{"missing-waf@web": "#12"},
+			expectedModel: `risk_tracking:
+  missing-waf@web:
+    status: unchecked
+    ticket: '#12'
+`,
+			expectedCount: 1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "threagile.yaml")
+			require.NoError(t, os.WriteFile(filename, []byte(testCase.model), 0600))
+
+			count, err := UpdateModelFile(filename, testCase.mapping)
+			if len(testCase.expectedError) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedCount, count)
+
+			data, err := os.ReadFile(filename)
+			require.NoError(t, err)
+			backup, backupErr := os.ReadFile(filename + ".backup")
+			if testCase.expectedCount == 0 {
+				assert.Equal(t, testCase.model, string(data))
+				assert.True(t, os.IsNotExist(backupErr), "unchanged model file needs no backup")
+				return
+			}
+			assert.Equal(t, testCase.expectedModel, string(data))
+			require.NoError(t, backupErr)
+			assert.Equal(t, testCase.model, string(backup))
+		})
+	}
+}
+
+type loadMappingTest struct {
+	filename string
+	expected Mapping
+}
+
+func TestLoadMapping(t *testing.T) {
+	testCases := map[string]loadMappingTest{
+		"yaml": {
+			filename: "mapping.yaml",
+			expected: Mapping{
+				"unencrypted-communication@web>db": "JIRA-1",
+				"missing-waf@web":                  "https://github.com/acme/shop/issues/7",
+			},
+		},
+		"jira csv export": {
+			filename: "jira-export.csv",
+			expected: Mapping{
+				"unencrypted-communication@web>db": "JIRA-1",
+				"missing-waf@web":                  "JIRA-2",
+			},
+		},
+		"gh issue list": {
+			filename: "gh-issue-list.json",
+			expected: Mapping{
+				"unencrypted-communication@web>db": "#8",
+				"missing-waf@web":                  "https://github.com/acme/shop/issues/7",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mapping, err := LoadMapping(filepath.Join("testdata", testCase.filename))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, mapping)
+		})
+	}
+}
+
+func TestLoadMappingCSVWithoutRiskIdColumn(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, os.WriteFile(filename, []byte("Summary,Issue key\nMissing WAF,JIRA-2\n"), 0600))
+
+	_, err := LoadMapping(filename)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), JiraRiskIdField)
+}
@@ -0,0 +1,227 @@
+package tickets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+const Label = "threagile"
+
+// Ticket is an issue to file for a risk
+type Ticket struct {
+	RiskId    string
+	Title     string
+	Body      string
+	Severity  types.RiskSeverity
+	Labels    []string
+	Assignees []string // owners of the affected technical assets
+}
+
+// Templates are text/template templates of the title and body of the tickets, executed with the TicketData of the risk
+type Templates struct {
+	Title string `yaml:"title,omitempty" json:"title,omitempty"`
+	Body  string `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// TicketData is what the templates of a ticket can refer to, texts of the risk category are plain text
+type TicketData struct {
+	Id          string
+	Title       string
+	Severity    string
+	Likelihood  string
+	Impact      string
+	ModelTitle  string
+	CategoryId  string
+	Category    string
+	CWE         int
+	Description string
+	RiskImpact  string
+	Mitigation  string
+	Check       string
+	ASVS        string
+	CheatSheet  string
+	Assets      []types.TechnicalAsset
+}
+
+var DefaultTemplates = Templates{
+	Title: `[{{.Severity}}] {{.Title}}`,
+	Body: `Threagile identified the risk "{{.Title}}" in the model "{{.ModelTitle}}".
+
+Severity: {{.Severity}} (likelihood: {{.Likelihood}}, impact: {{.Impact}})
+Category: {{.Category}}{{if .CWE}} (CWE-{{.CWE}}){{end}}
+Affected assets: {{range $i, $asset := .Assets}}{{if $i}}, {{end}}{{$asset.Title}}{{end}}
+
+Description:
+{{.Description}}
+
+Mitigation:
+{{.Mitigation}}
+{{if .ASVS}}
+ASVS: {{.ASVS}}
+{{end}}{{if .CheatSheet}}Cheat sheet: {{.CheatSheet}}
+{{end}}
+Check: {{.Check}}
+
+Risk ID: {{.Id}}
+`,
+}
+
+var (
+	lineBreakTags  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	formattingTags = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// LoadTemplates reads templates (yaml with title and body), missing ones are the default templates
+func LoadTemplates(filename string) (Templates, error) {
+	templates := DefaultTemplates
+	if len(filename) == 0 {
+		return templates, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return templates, fmt.Errorf("unable to read ticket template file %q: %w", filename, err)
+	}
+	var loaded Templates
+	err = yaml.Unmarshal(data, &loaded)
+	if err != nil {
+		return templates, fmt.Errorf("unable to parse ticket template file %q: %w", filename, err)
+	}
+	if len(strings.TrimSpace(loaded.Title)) > 0 {
+		templates.Title = loaded.Title
+	}
+	if len(strings.TrimSpace(loaded.Body)) > 0 {
+		templates.Body = loaded.Body
+	}
+	return templates, nil
+}
+
+// Tickets returns a ticket for each risk which is untracked or unchecked and has no ticket yet, most severe first
+func Tickets(parsedModel *types.ParsedModel, templates Templates) ([]Ticket, error) {
+	titleTemplate, err := template.New("title").Parse(templates.Title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket title template: %w", err)
+	}
+	bodyTemplate, err := template.New("body").Parse(templates.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket body template: %w", err)
+	}
+
+	risks := make([]types.Risk, 0)
+	for _, risk := range parsedModel.GeneratedRisksBySyntheticId {
+		tracking := risk.GetRiskTracking(parsedModel)
+		if risk.GetRiskTrackingStatusDefaultingUnchecked(parsedModel) == types.Unchecked && len(tracking.Ticket) == 0 {
+			risks = append(risks, risk)
+		}
+	}
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Severity == risks[j].Severity {
+			return risks[i].SyntheticId < risks[j].SyntheticId
+		}
+		return risks[i].Severity > risks[j].Severity
+	})
+
+	tickets := make([]Ticket, 0)
+	for _, risk := range risks {
+		category := types.GetRiskCategory(parsedModel, risk.CategoryId)
+		if category == nil {
+			continue
+		}
+		data := ticketData(parsedModel, *category, risk)
+
+		var title, body bytes.Buffer
+		if err := titleTemplate.Execute(&title, data); err != nil {
+			return nil, fmt.Errorf("unable to create ticket title of risk %q: %w", risk.SyntheticId, err)
+		}
+		if err := bodyTemplate.Execute(&body, data); err != nil {
+			return nil, fmt.Errorf("unable to create ticket body of risk %q: %w", risk.SyntheticId, err)
+		}
+
+		assignees := make([]string, 0)
+		for _, asset := range data.Assets {
+			if len(asset.Owner) > 0 && !contains(assignees, asset.Owner) {
+				assignees = append(assignees, asset.Owner)
+			}
+		}
+		tickets = append(tickets, Ticket{
+			RiskId:    risk.SyntheticId,
+			Title:     strings.TrimSpace(title.String()),
+			Body:      strings.TrimSpace(body.String()),
+			Severity:  risk.Severity,
+			Labels:    []string{Label, "severity:" + risk.Severity.String()},
+			Assignees: assignees,
+		})
+	}
+	return tickets, nil
+}
+
+func ticketData(parsedModel *types.ParsedModel, category types.RiskCategory, risk types.Risk) TicketData {
+	cwe := category.CWE
+	if risk.CWE > 0 {
+		cwe = risk.CWE
+	}
+	return TicketData{
+		Id:          risk.SyntheticId,
+		Title:       plainText(risk.Title),
+		Severity:    risk.Severity.String(),
+		Likelihood:  risk.ExploitationLikelihood.String(),
+		Impact:      risk.ExploitationImpact.String(),
+		ModelTitle:  parsedModel.Title,
+		CategoryId:  category.Id,
+		Category:    plainText(category.Title),
+		CWE:         cwe,
+		Description: plainText(category.Description),
+		RiskImpact:  plainText(category.Impact),
+		Mitigation:  plainText(category.Mitigation),
+		Check:       plainText(category.Check),
+		ASVS:        plainText(category.ASVS),
+		CheatSheet:  plainText(category.CheatSheet),
+		Assets:      affectedAssets(parsedModel, risk),
+	}
+}
+
+// affectedAssets returns the most relevant technical asset, or the ends of the most relevant communication link,
+// or the technical assets a data breach is possible at
+func affectedAssets(parsedModel *types.ParsedModel, risk types.Risk) []types.TechnicalAsset {
+	ids := make([]string, 0)
+	if len(risk.MostRelevantTechnicalAssetId) > 0 {
+		ids = append(ids, risk.MostRelevantTechnicalAssetId)
+	} else if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok {
+		ids = append(ids, link.SourceId, link.TargetId)
+	} else {
+		ids = append(ids, risk.DataBreachTechnicalAssetIDs...)
+	}
+
+	assets := make([]types.TechnicalAsset, 0)
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if technicalAsset, ok := parsedModel.TechnicalAssets[id]; ok && !seen[id] {
+			seen[id] = true
+			assets = append(assets, technicalAsset)
+		}
+	}
+	return assets
+}
+
+func plainText(text string) string {
+	return strings.TrimSpace(formattingTags.ReplaceAllString(lineBreakTags.ReplaceAllString(text, "\n"), ""))
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package tickets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestTickets(t *testing.T) {
+	parsedModel := &types.ParsedModel{
+		Title: "Shop",
+		TechnicalAssets: map[string]types.TechnicalAsset{
+			"web": {Id: "web", Title: "Web Shop", Owner: "web-team"},
+			"db":  {Id: "db", Title: "Database", Owner: "db-team"},
+		},
+		CommunicationLinks: map[string]types.CommunicationLink{
+			"web>db": {Id: "web>db", SourceId: "web", TargetId: "db"},
+		},
+		IndividualRiskCategories: map[string]types.RiskCategory{
+			"test-risk": {
+				Id:          "test-risk",
+				Title:       "Test <b>Risk</b>",
+				Description: "Line one<br>line two",
+				Mitigation:  "Fix it",
+				CWE:         20,
+			},
+		},
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"test-risk@web": {
+				CategoryId: "test-risk", SyntheticId: "test-risk@web", Title: "<b>Test Risk</b> at <b>Web Shop</b>",
+				Severity: types.MediumSeverity, ExploitationLikelihood: types.Likely, ExploitationImpact: types.LowImpact,
+				MostRelevantTechnicalAssetId: "web",
+			},
+			"test-risk@web>db": {
+				CategoryId: "test-risk", SyntheticId: "test-risk@web>db", Title: "<b>Test Risk</b> at <b>web>db</b>",
+				Severity: types.HighSeverity, CWE: 319, MostRelevantCommunicationLinkId: "web>db",
+			},
+			"test-risk@db": {
+				CategoryId: "test-risk", SyntheticId: "test-risk@db", Title: "tracked and unchecked without ticket",
+				Severity: types.LowSeverity, MostRelevantTechnicalAssetId: "db",
+			},
+			"test-risk@mitigated": {
+				CategoryId: "test-risk", SyntheticId: "test-risk@mitigated", Title: "already tracked",
+				Severity: types.CriticalSeverity, MostRelevantTechnicalAssetId: "db",
+			},
+			"test-risk@ticket": {
+				CategoryId: "test-risk", SyntheticId: "test-risk@ticket", Title: "already has a ticket",
+				Severity: types.CriticalSeverity, MostRelevantTechnicalAssetId: "db",
+			},
+			"unknown-category@web": {
+				CategoryId: "unknown-category", SyntheticId: "unknown-category@web", Title: "no category",
+				Severity: types.CriticalSeverity, MostRelevantTechnicalAssetId: "web",
+			},
+		},
+		RiskTracking: map[string]types.RiskTracking{
+			"test-risk@db":        {SyntheticRiskId: "test-risk@db", Status: types.Unchecked},
+			"test-risk@mitigated": {SyntheticRiskId: "test-risk@mitigated", Status: types.Mitigated},
+			"test-risk@ticket":    {SyntheticRiskId: "test-risk@ticket", Status: types.Unchecked, Ticket: "JIRA-1"},
+		},
+	}
+
+	tickets, err := Tickets(parsedModel, DefaultTemplates)
+	require.NoError(t, err)
+	require.Len(t, tickets, 3)
+
+	assert.Equal(t, "test-risk@web>db", tickets[0].RiskId, "most severe first")
+	assert.Equal(t, "[high] Test Risk at web>db", tickets[0].Title)
+	assert.Equal(t, []string{"web-team", "db-team"}, tickets[0].Assignees, "both ends of the communication link")
+	assert.Contains(t, tickets[0].Body, "Category: Test Risk (CWE-319)")
+	assert.Contains(t, tickets[0].Body, "Affected assets: Web Shop, Database")
+
+	assert.Equal(t, "test-risk@web", tickets[1].RiskId)
+	assert.Equal(t, types.MediumSeverity, tickets[1].Severity)
+	assert.Equal(t, []string{Label, "severity:medium"}, tickets[1].Labels)
+	assert.Equal(t, []string{"web-team"}, tickets[1].Assignees)
+	assert.Contains(t, tickets[1].Body, "Severity: medium (likelihood: likely, impact: low)")
+	assert.Contains(t, tickets[1].Body, "Category: Test Risk (CWE-20)")
+	assert.Contains(t, tickets[1].Body, "Description:\nLine one\nline two")
+	assert.Contains(t, tickets[1].Body, "\n\nRisk ID: test-risk@web")
+
+	assert.Equal(t, "test-risk@db", tickets[2].RiskId, "unchecked risk tracking without ticket")
+}
+
+func TestTicketsCustomTemplates(t *testing.T) {
+	parsedModel := &types.ParsedModel{
+		Title: "Shop",
+		IndividualRiskCategories: map[string]types.RiskCategory{
+			"test-risk": {Id: "test-risk", Title: "Test Risk"},
+		},
+		GeneratedRisksBySyntheticId: map[string]types.Risk{
+			"test-risk@web": {CategoryId: "test-risk", SyntheticId: "test-risk@web", Title: "Test Risk at Web", Severity: types.MediumSeverity},
+		},
+	}
+
+	tickets, err := Tickets(parsedModel, Templates{Title: "{{.ModelTitle}}: {{.Title}}", Body: "{{.Id}}"})
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, "Shop: Test Risk at Web", tickets[0].Title)
+	assert.Equal(t, "test-risk@web", tickets[0].Body)
+
+	_, err = Tickets(parsedModel, Templates{Title: "{{.Title", Body: "{{.Id}}"})
+	assert.Error(t, err)
+}
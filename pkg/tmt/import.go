@@ -0,0 +1,218 @@
+package tmt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+// drawing surfaces are placed next to each other, so shapes of different surfaces never enclose each other
+const surfaceOffset = 1e6
+
+// technologies of the well known stencils of the core template, everything else needs the mapping file
+var technologyOfType = map[string]types.TechnicalAssetTechnology{
+	"SE.P.TMCore.WebApp":         types.WebApplication,
+	"SE.P.TMCore.WebServer":      types.WebServer,
+	"SE.P.TMCore.WebSvc":         types.WebServiceREST,
+	"SE.P.TMCore.ThickClient":    types.Desktop,
+	"SE.P.TMCore.BrowserClient":  types.Browser,
+	"SE.DS.TMCore.SQL":           types.Database,
+	"SE.DS.TMCore.NoSQL":         types.Database,
+	"SE.DS.TMCore.FS":            types.FileServer,
+	"SE.DS.TMCore.CloudStorage":  types.FileServer,
+	"SE.DS.TMCore.ConfigFile":    types.LocalFileSystem,
+	"SE.DS.TMCore.Registry":      types.LocalFileSystem,
+	"SE.EI.TMCore.Browser":       types.Browser,
+	"SE.EI.TMCore.AuthProvider":  types.IdentityProvider,
+	"SE.EI.TMCore.WebApp":        types.WebApplication,
+	"SE.EI.TMCore.WebSvc":        types.WebServiceREST,
+	"SE.EI.TMCore.Megaservice":   types.WebServiceREST,
+	"SE.EI.TMCore.ThickClient":   types.Desktop,
+	"SE.EI.TMCore.BrowserClient": types.Browser,
+}
+
+var protocolOfType = map[string]types.Protocol{
+	"SE.DF.TMCore.HTTP":   types.HTTP,
+	"SE.DF.TMCore.HTTPS":  types.HTTPS,
+	"SE.DF.TMCore.Binary": types.BINARY,
+	"SE.DF.TMCore.SMB":    types.SMB,
+	"SE.DF.TMCore.IPsec":  types.BinaryEncrypted,
+}
+
+func LoadFile(filename string) (*ThreatModel, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read TMT file %q: %w", filename, err)
+	}
+
+	model := new(ThreatModel)
+	err = xml.Unmarshal(data, model)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse TMT file %q: %w", filename, err)
+	}
+
+	return model, nil
+}
+
+// Convert turns all drawing surfaces of a TMT model into one diagram ready to be imported.
+func Convert(model *ThreatModel) *importer.Diagram {
+	diagram := &importer.Diagram{
+		Title:       model.MetaInformation.ThreatModelName,
+		Description: model.MetaInformation.HighLevelSystemDescription,
+		Owner:       model.MetaInformation.Owner,
+		Properties: []importer.Property{
+			{Name: "Contributors", Value: model.MetaInformation.Contributors},
+			{Name: "Reviewer", Value: model.MetaInformation.Reviewer},
+			{Name: "Assumptions", Value: model.MetaInformation.Assumptions},
+			{Name: "External dependencies", Value: model.MetaInformation.ExternalDependencies},
+		},
+	}
+
+	for surfaceIndex, surface := range model.DrawingSurfaces {
+		offset := float64(surfaceIndex) * surfaceOffset
+		for _, stencil := range append(append([]Stencil{}, surface.Borders...), surface.Lines...) {
+			value := stencil.Value
+			name, stencilType, properties := value.properties()
+			if len(stencilType) == 0 {
+				stencilType = value.TypeId
+			}
+
+			switch {
+			case value.GenericTypeId == "GE.P":
+				diagram.Elements = append(diagram.Elements, value.element(name, stencilType, importer.ProcessElement, types.UnknownTechnology, offset, properties))
+			case value.GenericTypeId == "GE.DS":
+				diagram.Elements = append(diagram.Elements, value.element(name, stencilType, importer.DataStoreElement, types.Database, offset, properties))
+			case value.GenericTypeId == "GE.EI":
+				diagram.Elements = append(diagram.Elements, value.element(name, stencilType, importer.ExternalEntityElement, types.ClientSystem, offset, properties))
+			case value.GenericTypeId == "GE.DF":
+				flow := importer.Flow{
+					Id:       value.Guid,
+					Name:     name,
+					SourceId: value.SourceGuid,
+					TargetId: value.TargetGuid,
+					Type:     stencilType,
+					Protocol: protocolOfType[value.TypeId],
+				}
+				flow.OutOfScope, flow.Properties = outOfScope(properties)
+				diagram.Flows = append(diagram.Flows, flow)
+			case strings.HasPrefix(value.GenericTypeId, "GE.TB"):
+				boundary := importer.Boundary{
+					Id:   value.Guid,
+					Name: name,
+					Type: stencilType,
+				}
+				if value.GenericTypeId == "GE.TB.B" {
+					boundary.Bounds = value.bounds(offset)
+				}
+				diagram.Boundaries = append(diagram.Boundaries, boundary)
+			}
+		}
+	}
+
+	for _, instance := range model.ThreatInstances {
+		threat := instance.Value
+		properties := make(map[string]string)
+		for _, property := range threat.Properties {
+			properties[property.Key] = property.Value
+		}
+
+		elementId := threat.FlowGuid
+		if len(elementId) == 0 {
+			elementId = threat.TargetGuid
+		}
+		diagram.Threats = append(diagram.Threats, importer.Threat{
+			ElementId:   elementId,
+			Title:       properties["Title"],
+			Category:    threat.TypeId,
+			State:       threat.State,
+			Description: strings.TrimSpace(threat.StateInfo + "\n" + properties["UserThreatDescription"]),
+		})
+	}
+
+	return diagram
+}
+
+func (what StencilValue) element(name string, stencilType string, kind importer.ElementKind, defaultTechnology types.TechnicalAssetTechnology,
+	offset float64, properties []importer.Property) importer.Element {
+	element := importer.Element{
+		Id:         what.Guid,
+		Name:       name,
+		Kind:       kind,
+		Type:       stencilType,
+		Technology: defaultTechnology,
+		Bounds:     what.bounds(offset),
+	}
+	if technology, ok := technologyOfType[what.TypeId]; ok {
+		element.Technology = technology
+	}
+
+	element.OutOfScope, properties = outOfScope(properties)
+	for _, property := range properties {
+		switch strings.ToLower(property.Name) {
+		case "reason for out of scope":
+			element.OutOfScopeReason = property.Value
+		case "encrypted", "encryption":
+			if strings.EqualFold(property.Value, "yes") || strings.EqualFold(property.Value, "true") {
+				element.Encryption = types.Transparent
+			}
+		default:
+			element.Properties = append(element.Properties, property)
+		}
+	}
+	return element
+}
+
+// properties returns the name, the stencil type (display name of the header) and all remaining set properties
+func (what StencilValue) properties() (string, string, []importer.Property) {
+	var name, stencilType string
+	properties := make([]importer.Property, 0)
+	for _, property := range what.Properties {
+		value := property.value()
+		switch {
+		case strings.HasSuffix(property.Kind, "HeaderDisplayAttribute"):
+			stencilType = property.DisplayName
+		case property.DisplayName == "Name":
+			name = value
+		case len(value) == 0 || strings.EqualFold(value, "Not Selected") || strings.EqualFold(value, "false"):
+			continue
+		default:
+			properties = append(properties, importer.Property{Name: property.DisplayName, Value: value})
+		}
+	}
+	return name, stencilType, properties
+}
+
+func (what StencilValue) bounds(offset float64) *importer.Bounds {
+	if what.Width <= 0 || what.Height <= 0 {
+		return nil
+	}
+	return &importer.Bounds{X: what.Left + offset, Y: what.Top, Width: what.Width, Height: what.Height}
+}
+
+func (what Property) value() string {
+	if len(what.Value.Strings) > 0 {
+		if what.SelectedIndex >= 0 && what.SelectedIndex < len(what.Value.Strings) {
+			return strings.TrimSpace(what.Value.Strings[what.SelectedIndex])
+		}
+		return ""
+	}
+	return strings.TrimSpace(what.Value.Text)
+}
+
+func outOfScope(properties []importer.Property) (bool, []importer.Property) {
+	remaining := make([]importer.Property, 0)
+	isOutOfScope := false
+	for _, property := range properties {
+		if strings.EqualFold(property.Name, "Out Of Scope") {
+			isOutOfScope = strings.EqualFold(property.Value, "true")
+			continue
+		}
+		remaining = append(remaining, property)
+	}
+	return isOutOfScope, remaining
+}
@@ -0,0 +1,73 @@
+package tmt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/threagile/threagile/pkg/importer"
+	"github.com/threagile/threagile/pkg/security/types"
+)
+
+func TestConvert(t *testing.T) {
+	model, err := LoadFile("testdata/model.tm7")
+	require.NoError(t, err)
+
+	diagram := Convert(model)
+
+	assert.Equal(t, "Webshop", diagram.Title)
+	assert.Equal(t, "Small webshop", diagram.Description)
+	assert.Contains(t, diagram.Properties, importer.Property{Name: "Contributors", Value: "John Doe"})
+
+	require.Len(t, diagram.Elements, 3)
+	browser, shop, orders := diagram.Elements[0], diagram.Elements[1], diagram.Elements[2]
+	assert.Equal(t, "Customer Browser", browser.Name)
+	assert.Equal(t, importer.ExternalEntityElement, browser.Kind)
+	assert.Equal(t, types.Browser, browser.Technology)
+	assert.Equal(t, []importer.Property{{Name: "Authenticates Itself", Value: "Yes"}}, browser.Properties)
+	assert.Equal(t, "Shop", shop.Name)
+	assert.Equal(t, "Web Application", shop.Type)
+	assert.Equal(t, types.WebApplication, shop.Technology)
+	assert.False(t, shop.OutOfScope)
+	assert.Empty(t, shop.Properties)
+	assert.Equal(t, importer.DataStoreElement, orders.Kind)
+	assert.Equal(t, types.Database, orders.Technology)
+	assert.Equal(t, types.Transparent, orders.Encryption)
+
+	require.Len(t, diagram.Flows, 2)
+	assert.Equal(t, importer.Flow{Id: "request", Name: "Request", SourceId: "browser", TargetId: "shop", Type: "HTTPS", Protocol: types.HTTPS, Properties: []importer.Property{}}, diagram.Flows[0])
+	assert.Equal(t, types.UnknownProtocol, diagram.Flows[1].Protocol, "needs the mapping file")
+
+	require.Len(t, diagram.Boundaries, 2)
+	assert.Equal(t, &importer.Bounds{X: 200, Y: 0, Width: 400, Height: 200}, diagram.Boundaries[0].Bounds)
+	assert.Nil(t, diagram.Boundaries[1].Bounds, "line boundaries don't enclose anything")
+
+	require.Len(t, diagram.Threats, 1)
+	assert.Equal(t, importer.Threat{ElementId: "query", Title: "SQL Injection on Orders", Category: "TH110", State: "Mitigated",
+		Description: "Prepared statements.\nQueries are built from input."}, diagram.Threats[0])
+}
+
+func TestImport(t *testing.T) {
+	model, err := LoadFile("testdata/model.tm7")
+	require.NoError(t, err)
+
+	mapping := importer.Mapping{
+		importer.MappingProtocol:          {"SQL": types.SqlAccessProtocol.String()},
+		importer.MappingTrustBoundaryType: {"Azure Trust Boundary": types.NetworkCloudProvider.String()},
+		importer.MappingRiskCategory:      {"TH110": "sql-nosql-injection"},
+	}
+	modelInput, report := importer.ImportDiagram(Convert(model), mapping, []string{"sql-nosql-injection"})
+
+	require.Contains(t, modelInput.TrustBoundaries, "Azure")
+	assert.Equal(t, types.NetworkCloudProvider.String(), modelInput.TrustBoundaries["Azure"].Type)
+	assert.Equal(t, []string{"shop", "orders"}, modelInput.TrustBoundaries["Azure"].TechnicalAssetsInside)
+	assert.Equal(t, types.SqlAccessProtocol.String(), modelInput.TechnicalAssets["Shop"].CommunicationLinks["Query"].Protocol)
+	assert.Empty(t, modelInput.RiskTracking)
+	assert.Contains(t, report.Items, "threat 'SQL Injection on Orders' of 'shop>query': risk category \"sql-nosql-injection\" with status \"mitigated\", add the risk tracking once the risk id is known from the analysis")
+}
+
+func TestLoadFileFails(t *testing.T) {
+	_, err := LoadFile("testdata/missing.tm7")
+	assert.Error(t, err)
+}
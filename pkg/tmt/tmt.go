@@ -0,0 +1,85 @@
+package tmt
+
+import "encoding/xml"
+
+// Microsoft Threat Modeling Tool model format (.tm7, a serialized .NET data contract), only the parts needed for the import
+
+type ThreatModel struct {
+	XMLName         xml.Name         `xml:"ThreatModel"`
+	DrawingSurfaces []DrawingSurface `xml:"DrawingSurfaceList>DrawingSurfaceModel"`
+	MetaInformation MetaInformation  `xml:"MetaInformation"`
+	ThreatInstances []ThreatInstance `xml:"ThreatInstances>KeyValueOfstringThreatpc_P0_PhOB"`
+}
+
+type DrawingSurface struct {
+	Guid       string     `xml:"Guid"`
+	Properties []Property `xml:"Properties>anyType"`
+	Borders    []Stencil  `xml:"Borders>KeyValueOfguidanyType"`
+	Lines      []Stencil  `xml:"Lines>KeyValueOfguidanyType"`
+}
+
+type MetaInformation struct {
+	ThreatModelName            string `xml:"ThreatModelName"`
+	Owner                      string `xml:"Owner"`
+	Reviewer                   string `xml:"Reviewer"`
+	Contributors               string `xml:"Contributors"`
+	Assumptions                string `xml:"Assumptions"`
+	ExternalDependencies       string `xml:"ExternalDependencies"`
+	HighLevelSystemDescription string `xml:"HighLevelSystemDescription"`
+}
+
+// Stencil is an element, boundary or connector of a drawing surface
+type Stencil struct {
+	Key   string       `xml:"Key"`
+	Value StencilValue `xml:"Value"`
+}
+
+type StencilValue struct {
+	Kind          string     `xml:"type,attr"` // e.g. StencilRectangle, StencilEllipse, StencilParallelLines, BorderBoundary, LineBoundary, Connector
+	GenericTypeId string     `xml:"GenericTypeId"`
+	TypeId        string     `xml:"TypeId"`
+	Guid          string     `xml:"Guid"`
+	Properties    []Property `xml:"Properties>anyType"`
+	Left          float64    `xml:"Left"`
+	Top           float64    `xml:"Top"`
+	Width         float64    `xml:"Width"`
+	Height        float64    `xml:"Height"`
+	SourceGuid    string     `xml:"SourceGuid"`
+	TargetGuid    string     `xml:"TargetGuid"`
+}
+
+type Property struct {
+	Kind          string        `xml:"type,attr"` // e.g. b:HeaderDisplayAttribute, b:StringDisplayAttribute, b:BooleanDisplayAttribute, b:ListDisplayAttribute
+	DisplayName   string        `xml:"DisplayName"`
+	Name          string        `xml:"Name"`
+	SelectedIndex int           `xml:"SelectedIndex"`
+	Value         PropertyValue `xml:"Value"`
+}
+
+// PropertyValue is either a plain value or (for lists) a number of strings
+type PropertyValue struct {
+	Text    string   `xml:",chardata"`
+	Strings []string `xml:"string"`
+}
+
+type ThreatInstance struct {
+	Key   string      `xml:"Key"`
+	Value ThreatValue `xml:"Value"`
+}
+
+type ThreatValue struct {
+	Id         string           `xml:"Id"`
+	TypeId     string           `xml:"TypeId"`
+	State      string           `xml:"State"`
+	StateInfo  string           `xml:"StateInformation"`
+	Priority   string           `xml:"Priority"`
+	FlowGuid   string           `xml:"FlowGuid"`
+	SourceGuid string           `xml:"SourceGuid"`
+	TargetGuid string           `xml:"TargetGuid"`
+	Properties []ThreatProperty `xml:"Properties>KeyValueOfstringstring"`
+}
+
+type ThreatProperty struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}